@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/domain"
+)
+
+// MinKeyLength is the shortest signing key accepted, HS256 keys shorter than the hash are easier to brute-force.
+const MinKeyLength = 32
+
+var (
+	ErrMissingToken = domain.Unauthenticated("missing_token", "bearer token is missing")
+	ErrInvalidToken = domain.Unauthenticated("invalid_token", "token is malformed or its signature is invalid")
+	ErrExpiredToken = domain.Unauthenticated("expired_token", "token has expired, a new one has to be issued")
+
+	errKeyTooShort = fmt.Errorf("signing key has to be at least %d bytes long", MinKeyLength)
+)
+
+type contextKey struct{}
+
+// claims are the registered claims of the token extended by the role of its subject and the city operators work in.
+type claims struct {
+	jwt.RegisteredClaims
+	Role Role   `json:"role,omitempty"`
+	City string `json:"city,omitempty"`
+}
+
+// Signer issues the tokens of the principals and verifies them offline, the tokens are JWTs signed with HMAC SHA-256
+// by the key the instances share. The subject of the token is the principal's UUID, e.g. the client's one.
+type Signer struct {
+	key    []byte
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewSigner(key []byte, issuer string, ttl time.Duration) (*Signer, error) {
+	if len(key) < MinKeyLength {
+		return nil, errKeyTooShort
+	}
+
+	return &Signer{key: key, issuer: issuer, ttl: ttl, now: time.Now}, nil
+}
+
+// Issue signs the token of the principal, it is valid until the returned time.
+func (s *Signer) Issue(principal *Principal) (string, time.Time, error) {
+	issuedAt := s.now().UTC().Truncate(time.Second)
+	expiresAt := issuedAt.Add(s.ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   principal.Subject.String(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Role: principal.Role,
+		City: principal.City,
+	})
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("signing token: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// Verify checks the signature, the issuer and the expiry of the token and returns the principal it was issued to.
+// Tokens signed by any other algorithm are refused, so the "none" algorithm can't be used to skip the signature.
+// Tokens without a role were issued to riders before the roles were introduced.
+func (s *Signer) Verify(token string) (*Principal, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	var verified claims
+
+	_, err := jwt.ParseWithClaims(
+		token,
+		&verified,
+		func(*jwt.Token) (interface{}, error) { return s.key, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, fmt.Errorf("%w: %w", ErrExpiredToken, err)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := uuid.Parse(verified.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parsing subject: %w", ErrInvalidToken, err)
+	}
+
+	role := verified.Role
+	if role == "" {
+		role = RoleRider
+	}
+
+	principal := &Principal{Subject: subject, Role: role, City: verified.City}
+
+	if err = principal.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return principal, nil
+}
+
+// WithPrincipal returns a copy of the context carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal the context was authenticated as, if it was.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+
+	return principal, ok
+}
@@ -0,0 +1,219 @@
+//go:build unit
+
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKey    = "0123456789abcdef0123456789abcdef"
+	testIssuer = "scootin_aboot"
+	testTTL    = time.Hour
+	testCity   = "Ottawa"
+)
+
+func TestNewSigner(t *testing.T) {
+	_, err := NewSigner([]byte("short"), testIssuer, testTTL)
+	require.ErrorIs(t, err, errKeyTooShort)
+
+	_, err = NewSigner([]byte(testKey), testIssuer, testTTL)
+	require.NoError(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	clientUUID := uuid.New()
+	issuedAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	signer, err := NewSigner([]byte(testKey), testIssuer, testTTL)
+	require.NoError(t, err)
+
+	signer.now = func() time.Time { return issuedAt }
+
+	token, expiresAt, err := signer.Issue(NewRider(clientUUID))
+	require.NoError(t, err)
+	require.Equal(t, issuedAt.Add(testTTL), expiresAt)
+
+	operatorUUID := uuid.New()
+
+	operatorToken, _, err := signer.Issue(NewOperator(operatorUUID, testCity))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		token         func() string
+		verifiedAt    time.Time
+		wantPrincipal *Principal
+		wantErr       error
+	}{
+		"verifying issued token": {
+			token:         func() string { return token },
+			verifiedAt:    issuedAt.Add(testTTL - time.Second),
+			wantPrincipal: NewRider(clientUUID),
+		},
+		"verifying token of operator": {
+			token:         func() string { return operatorToken },
+			verifiedAt:    issuedAt,
+			wantPrincipal: NewOperator(operatorUUID, testCity),
+		},
+		"verifying token issued before roles were introduced": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testKey), jwt.RegisteredClaims{
+					Issuer:    testIssuer,
+					Subject:   clientUUID.String(),
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				})
+			},
+			verifiedAt:    issuedAt,
+			wantPrincipal: NewRider(clientUUID),
+		},
+		"verifying token of unknown role": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testKey), claims{
+					RegisteredClaims: jwt.RegisteredClaims{
+						Issuer:    testIssuer,
+						Subject:   clientUUID.String(),
+						ExpiresAt: jwt.NewNumericDate(expiresAt),
+					},
+					Role: "superuser",
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying token of operator without city": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testKey), claims{
+					RegisteredClaims: jwt.RegisteredClaims{
+						Issuer:    testIssuer,
+						Subject:   operatorUUID.String(),
+						ExpiresAt: jwt.NewNumericDate(expiresAt),
+					},
+					Role: RoleOperator,
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying expired token": {
+			token:      func() string { return token },
+			verifiedAt: issuedAt.Add(testTTL + time.Second),
+			wantErr:    ErrExpiredToken,
+		},
+		"verifying missing token": {
+			token:      func() string { return "" },
+			verifiedAt: issuedAt,
+			wantErr:    ErrMissingToken,
+		},
+		"verifying token with tampered claims": {
+			token: func() string {
+				parts := strings.Split(token, ".")
+				parts[1] = encodedClaims(t, jwt.RegisteredClaims{
+					Issuer:    testIssuer,
+					Subject:   uuid.NewString(),
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				})
+
+				return strings.Join(parts, ".")
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying token signed by another key": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(strings.Repeat("x", MinKeyLength)), jwt.RegisteredClaims{
+					Issuer:    testIssuer,
+					Subject:   clientUUID.String(),
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying unsigned token": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, jwt.RegisteredClaims{
+					Issuer:    testIssuer,
+					Subject:   clientUUID.String(),
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying token of another issuer": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testKey), jwt.RegisteredClaims{
+					Issuer:    "another_service",
+					Subject:   clientUUID.String(),
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying token without expiry": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testKey), jwt.RegisteredClaims{
+					Issuer:  testIssuer,
+					Subject: clientUUID.String(),
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+		"verifying token of unknown subject": {
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testKey), jwt.RegisteredClaims{
+					Issuer:    testIssuer,
+					Subject:   "admin",
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				})
+			},
+			verifiedAt: issuedAt,
+			wantErr:    ErrInvalidToken,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			signer.now = func() time.Time { return tt.verifiedAt }
+
+			got, err := signer.Verify(tt.token())
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantPrincipal, got)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	principal := NewRider(uuid.New())
+
+	_, ok := FromContext(context.Background())
+	require.False(t, ok)
+
+	got, ok := FromContext(WithPrincipal(context.Background(), principal))
+	require.True(t, ok)
+	require.Equal(t, principal, got)
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, tokenClaims jwt.Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, tokenClaims).SignedString(key)
+	require.NoError(t, err)
+
+	return token
+}
+
+// encodedClaims encodes the claims the way they are encoded in the token, without signing them.
+func encodedClaims(t *testing.T, tokenClaims jwt.Claims) string {
+	t.Helper()
+
+	return strings.Split(sign(t, jwt.SigningMethodHS256, []byte("unused"), tokenClaims), ".")[1]
+}
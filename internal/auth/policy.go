@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/domain"
+)
+
+// Role decides what the principal may do, the permissions of every role are listed in rolePermissions.
+type Role string
+
+const (
+	RoleRider    Role = "rider"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var Roles = []Role{RoleRider, RoleOperator, RoleAdmin}
+
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+
+	return ok
+}
+
+// Permission is a group of routes checked at once, before the handler is reached. Cities, their tariffs and zones
+// have none, they are read from files at startup and every instance holds them in memory, so there are no routes
+// managing them.
+type Permission string
+
+const (
+	PermissionSearchScooters  Permission = "search_scooters"  // search and stream the scooters of a city
+	PermissionRide            Permission = "ride"             // reserve, rent and free scooters, follow own rides
+	PermissionOperateScooters Permission = "operate_scooters" // inspect, move and change scooters, see their rides
+	PermissionManageFleet     Permission = "manage_fleet"     // add scooters to the fleet and remove them
+	PermissionManageService   Permission = "manage_service"   // webhooks and status of the instance
+)
+
+var rolePermissions = map[Role]map[Permission]bool{
+	RoleRider: {
+		PermissionSearchScooters: true,
+		PermissionRide:           true,
+	},
+	RoleOperator: {
+		PermissionSearchScooters:  true,
+		PermissionOperateScooters: true,
+	},
+	RoleAdmin: {
+		PermissionSearchScooters:  true,
+		PermissionOperateScooters: true,
+		PermissionManageFleet:     true,
+		PermissionManageService:   true,
+	},
+}
+
+var (
+	ErrRoleNotPermitted = domain.Forbidden("role_not_permitted", "role of the token doesn't permit the operation")
+	ErrForeignCity      = domain.Forbidden("foreign_city", "operators can act only within their own city")
+
+	errUnknownRole         = fmt.Errorf("role has to be one of %v", Roles)
+	errOperatorWithoutCity = errors.New("operator has to be assigned to a city")
+)
+
+// Principal is the authenticated caller, riders are identified by their client's UUID. Operators are scoped to the
+// city they work in.
+type Principal struct {
+	Subject uuid.UUID
+	Role    Role
+	City    string
+}
+
+func NewRider(clientUUID uuid.UUID) *Principal {
+	return &Principal{Subject: clientUUID, Role: RoleRider}
+}
+
+func NewOperator(operatorUUID uuid.UUID, city string) *Principal {
+	return &Principal{Subject: operatorUUID, Role: RoleOperator, City: city}
+}
+
+func NewAdmin(adminUUID uuid.UUID) *Principal {
+	return &Principal{Subject: adminUUID, Role: RoleAdmin}
+}
+
+// RolesWith returns the roles having the permission.
+func RolesWith(permission Permission) []Role {
+	var roles []Role
+
+	for _, role := range Roles {
+		if rolePermissions[role][permission] {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// Authorize checks on the route level that the role of the principal has the permission.
+func (p *Principal) Authorize(permission Permission) error {
+	if !rolePermissions[p.Role][permission] {
+		return fmt.Errorf("%w: %s lacks %s", ErrRoleNotPermitted, p.Role, permission)
+	}
+
+	return nil
+}
+
+// AuthorizeCity checks on the resource level that the principal may act within the city. Only operators are
+// scoped, the riders' access to resources is limited by their ownership instead.
+func (p *Principal) AuthorizeCity(city string) error {
+	if p.CityScoped() && p.City != city {
+		return fmt.Errorf("%w: %s works in %s, not in %s", ErrForeignCity, p.Subject, p.City, city)
+	}
+
+	return nil
+}
+
+// CityScoped tells whether AuthorizeCity has to be consulted, so the city of a resource is looked up only if needed.
+func (p *Principal) CityScoped() bool {
+	return p.Role == RoleOperator
+}
+
+func (p *Principal) validate() error {
+	if !p.Role.IsValid() {
+		return fmt.Errorf("%w, not %q", errUnknownRole, p.Role)
+	}
+
+	if p.Role == RoleOperator && p.City == "" {
+		return errOperatorWithoutCity
+	}
+
+	return nil
+}
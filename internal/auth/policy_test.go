@@ -0,0 +1,67 @@
+//go:build unit
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorize(t *testing.T) {
+	rider, operator, admin := NewRider(uuid.New()), NewOperator(uuid.New(), testCity), NewAdmin(uuid.New())
+
+	// Every role against every permission, a role missing in the row is refused
+	tests := map[Permission]map[*Principal]bool{
+		PermissionSearchScooters:  {rider: true, operator: true, admin: true},
+		PermissionRide:            {rider: true, operator: false, admin: false},
+		PermissionOperateScooters: {rider: false, operator: true, admin: true},
+		PermissionManageFleet:     {rider: false, operator: false, admin: true},
+		PermissionManageService:   {rider: false, operator: false, admin: true},
+	}
+	for permission, allowed := range tests {
+		for principal, wantAllowed := range allowed {
+			t.Run(string(principal.Role)+" "+string(permission), func(t *testing.T) {
+				err := principal.Authorize(permission)
+
+				if wantAllowed {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, ErrRoleNotPermitted)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizeCity(t *testing.T) {
+	tests := map[string]struct {
+		principal *Principal
+		city      string
+		wantErr   error
+	}{
+		"operator within own city": {
+			principal: NewOperator(uuid.New(), testCity),
+			city:      testCity,
+		},
+		"operator within another city": {
+			principal: NewOperator(uuid.New(), testCity),
+			city:      "Montreal",
+			wantErr:   ErrForeignCity,
+		},
+		"admin within any city": {
+			principal: NewAdmin(uuid.New()),
+			city:      "Montreal",
+		},
+		"rider within any city": {
+			principal: NewRider(uuid.New()),
+			city:      "Montreal",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, tt.principal.AuthorizeCity(tt.city), tt.wantErr)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/joho/godotenv"
@@ -9,8 +10,63 @@ import (
 )
 
 type Config struct {
-	HTTP int    `env:"HTTP,required"`
-	Name string `env:"NAME,required"`
+	HTTP       int    `env:"HTTP,required"`
+	Name       string `env:"NAME,required"`
+	AdminToken string `env:"ADMIN_TOKEN"` // bootstraps the tokens of admins through POST /v1/tokens, empty turns it off
+
+	TokenKey        string `env:"TOKEN_KEY,required"` // signs the tokens of the clients, at least 32 bytes, no default
+	TokenTTLMinutes int    `env:"TOKEN_TTL_MINUTES,default=60"`
+	TokenIssuing    bool   `env:"TOKEN_ISSUING,default=false"` // serves POST /v1/tokens, for development only
+
+	LogLevel  string `env:"LOG_LEVEL,default=info"`  // debug, info, warn or error
+	LogFormat string `env:"LOG_FORMAT,default=json"` // json or text
+
+	TracingExporter     string `env:"TRACING_EXPORTER,default=none"` // none, stdout or otlp
+	TracingOTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT,default=localhost:4318"`
+
+	ReadinessTimeoutMilliseconds int `env:"READINESS_TIMEOUT_MILLISECONDS,default=500"` // Redis has to answer within
+	ShutdownGraceSeconds         int `env:"SHUTDOWN_GRACE_SECONDS,default=5"`           // readiness fails that long first
+	ShutdownTimeoutSeconds       int `env:"SHUTDOWN_TIMEOUT_SECONDS,default=30"`        // requests in flight get that long
+
+	ReadHeaderTimeoutSeconds int `env:"READ_HEADER_TIMEOUT_SECONDS,default=5"` // slow clients are cut off
+	ReadTimeoutSeconds       int `env:"READ_TIMEOUT_SECONDS,default=30"`       // headers and body included
+	WriteTimeoutSeconds      int `env:"WRITE_TIMEOUT_SECONDS,default=60"`      // event streams lift it
+	IdleTimeoutSeconds       int `env:"IDLE_TIMEOUT_SECONDS,default=120"`      // of keep-alive connections
+
+	RequestTimeoutMilliseconds int            `env:"REQUEST_TIMEOUT_MILLISECONDS,default=10000"` // 0 turns it off
+	RouteTimeoutsMilliseconds  map[string]int `env:"ROUTE_TIMEOUTS_MILLISECONDS"`                // by operation ID
+
+	MaxBodyBytes int64 `env:"MAX_BODY_BYTES,default=1048576"` // 0 turns the limit off
+	GzipLevel    int   `env:"GZIP_LEVEL,default=5"`           // 1 is the fastest, 9 the smallest
+
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS"` // of the web app, * allows any origin
+	CORSMaxAgeSeconds  int      `env:"CORS_MAX_AGE_SECONDS,default=600"`
+
+	BatteryDrainPerKm   float64 `env:"BATTERY_DRAIN_PER_KM,default=1.5"` // in percents per kilometer
+	LowBatteryThreshold float64 `env:"LOW_BATTERY_THRESHOLD,default=15"` // in percents
+
+	MinSearchRadius float64 `env:"MIN_SEARCH_RADIUS,default=1"`     // in meters
+	MaxSearchRadius float64 `env:"MAX_SEARCH_RADIUS,default=50000"` // in meters
+
+	ReservationMinutes        int `env:"RESERVATION_MINUTES,default=10"`
+	ReservationSweepSeconds   int `env:"RESERVATION_SWEEP_SECONDS,default=1"` // expired ones are released that often
+	IdempotencyKeyHours       int `env:"IDEMPOTENCY_KEY_HOURS,default=24"`
+	IdempotencyPendingSeconds int `env:"IDEMPOTENCY_PENDING_SECONDS,default=60"` // has to outlive the requests
+
+	StreamBufferSize       int `env:"STREAM_BUFFER_SIZE,default=64"` // events a slow subscriber may fall behind by
+	StreamHeartbeatSeconds int `env:"STREAM_HEARTBEAT_SECONDS,default=15"`
+
+	EventsStream          string `env:"EVENTS_STREAM,default=fleet-events"`
+	EventsStreamMaxLength int64  `env:"EVENTS_STREAM_MAX_LENGTH,default=10000"` // newest events the stream keeps, roughly
+
+	WebhookMaxAttempts         int `env:"WEBHOOK_MAX_ATTEMPTS,default=5"`
+	WebhookBackoffMilliseconds int `env:"WEBHOOK_BACKOFF_MILLISECONDS,default=500"` // doubled after every failed attempt
+	WebhookTimeoutSeconds      int `env:"WEBHOOK_TIMEOUT_SECONDS,default=5"`
+
+	TariffsPath string `env:"TARIFFS_PATH,default=internal/config/tariffs.json"`
+	ZonesDir    string `env:"ZONES_DIR,default=internal/config/zones"`
+
+	RateLimitsPath string `env:"RATE_LIMITS_PATH,default=internal/config/rate_limits.json"` // by operation ID
 }
 
 func NewConfig(ctx context.Context, configPath string) (*Config, error) {
@@ -24,5 +80,49 @@ func NewConfig(ctx context.Context, configPath string) (*Config, error) {
 		return nil, fmt.Errorf("processing environment config: %w", err)
 	}
 
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("validating environment config: %w", err)
+	}
+
 	return &c, nil
 }
+
+// validate rejects the values the service can't start with, the variables being present is checked by envconfig.
+func (c *Config) validate() error {
+	if c.TokenKey == "" {
+		return errors.New("TOKEN_KEY has to be set, the tokens can't be signed without it")
+	}
+
+	// Durations and counts of zero would stop tickers and retries, or panic creating them. The ones zero turns off
+	// are left out.
+	for _, setting := range []struct {
+		name  string
+		value int64
+	}{
+		{"TOKEN_TTL_MINUTES", int64(c.TokenTTLMinutes)},
+		{"READINESS_TIMEOUT_MILLISECONDS", int64(c.ReadinessTimeoutMilliseconds)},
+		{"SHUTDOWN_GRACE_SECONDS", int64(c.ShutdownGraceSeconds)},
+		{"SHUTDOWN_TIMEOUT_SECONDS", int64(c.ShutdownTimeoutSeconds)},
+		{"READ_HEADER_TIMEOUT_SECONDS", int64(c.ReadHeaderTimeoutSeconds)},
+		{"READ_TIMEOUT_SECONDS", int64(c.ReadTimeoutSeconds)},
+		{"WRITE_TIMEOUT_SECONDS", int64(c.WriteTimeoutSeconds)},
+		{"IDLE_TIMEOUT_SECONDS", int64(c.IdleTimeoutSeconds)},
+		{"CORS_MAX_AGE_SECONDS", int64(c.CORSMaxAgeSeconds)},
+		{"RESERVATION_MINUTES", int64(c.ReservationMinutes)},
+		{"RESERVATION_SWEEP_SECONDS", int64(c.ReservationSweepSeconds)},
+		{"IDEMPOTENCY_KEY_HOURS", int64(c.IdempotencyKeyHours)},
+		{"IDEMPOTENCY_PENDING_SECONDS", int64(c.IdempotencyPendingSeconds)},
+		{"STREAM_BUFFER_SIZE", int64(c.StreamBufferSize)},
+		{"STREAM_HEARTBEAT_SECONDS", int64(c.StreamHeartbeatSeconds)},
+		{"EVENTS_STREAM_MAX_LENGTH", c.EventsStreamMaxLength},
+		{"WEBHOOK_MAX_ATTEMPTS", int64(c.WebhookMaxAttempts)},
+		{"WEBHOOK_BACKOFF_MILLISECONDS", int64(c.WebhookBackoffMilliseconds)},
+		{"WEBHOOK_TIMEOUT_SECONDS", int64(c.WebhookTimeoutSeconds)},
+	} {
+		if setting.value < 1 {
+			return fmt.Errorf("%s has to be at least 1, got %d", setting.name, setting.value)
+		}
+	}
+
+	return nil
+}
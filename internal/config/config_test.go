@@ -6,6 +6,8 @@ import (
 	"context"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -17,8 +19,63 @@ func TestNewConfig(t *testing.T) {
 		"successful run": {
 			configPath: "test_vars/valid_vars.env",
 			want: &Config{
-				HTTP: 8081,
-				Name: "scootin_aboot",
+				HTTP:       8081,
+				Name:       "scootin_aboot",
+				AdminToken: "test_admin_token",
+
+				TokenKey:        "test_token_key_of_at_least_32_bytes",
+				TokenTTLMinutes: 15,
+				TokenIssuing:    true,
+
+				LogLevel:  "debug",
+				LogFormat: "text",
+
+				TracingExporter:     "stdout",
+				TracingOTLPEndpoint: "collector:4318",
+
+				ReadinessTimeoutMilliseconds: 200,
+				ShutdownGraceSeconds:         1,
+				ShutdownTimeoutSeconds:       3,
+
+				ReadHeaderTimeoutSeconds: 2,
+				ReadTimeoutSeconds:       10,
+				WriteTimeoutSeconds:      20,
+				IdleTimeoutSeconds:       30,
+
+				RequestTimeoutMilliseconds: 3000,
+				RouteTimeoutsMilliseconds:  map[string]int{"getScooters": 1000, "createScooter": 5000},
+
+				MaxBodyBytes: 4096,
+				GzipLevel:    1,
+
+				CORSAllowedOrigins: []string{"https://app.scootinaboot.test", "http://localhost:3000"},
+				CORSMaxAgeSeconds:  60,
+
+				BatteryDrainPerKm:   2,
+				LowBatteryThreshold: 15,
+
+				MinSearchRadius: 10,
+				MaxSearchRadius: 20000,
+
+				ReservationMinutes:        5,
+				ReservationSweepSeconds:   2,
+				IdempotencyKeyHours:       12,
+				IdempotencyPendingSeconds: 30,
+
+				StreamBufferSize:       8,
+				StreamHeartbeatSeconds: 5,
+
+				EventsStream:          "test-events",
+				EventsStreamMaxLength: 100,
+
+				WebhookMaxAttempts:         3,
+				WebhookBackoffMilliseconds: 100,
+				WebhookTimeoutSeconds:      2,
+
+				TariffsPath: "test_tariffs.json",
+				ZonesDir:    "test_zones",
+
+				RateLimitsPath: "test_rate_limits.json",
 			},
 			wantErr: false,
 		},
@@ -42,3 +99,33 @@ func TestNewConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfigRequiresTokenKey(t *testing.T) {
+	// Variables set already aren't overridden by the file, the key is left empty like in default.env
+	t.Setenv("TOKEN_KEY", "")
+
+	_, err := NewConfig(context.Background(), "test_vars/valid_vars.env")
+	require.ErrorContains(t, err, "TOKEN_KEY")
+}
+
+func TestNewConfigRejectsNonPositiveSettings(t *testing.T) {
+	tests := map[string]struct {
+		name  string
+		value string
+	}{
+		"heartbeat of zero":            {name: "STREAM_HEARTBEAT_SECONDS", value: "0"},
+		"negative heartbeat":           {name: "STREAM_HEARTBEAT_SECONDS", value: "-1"},
+		"sweep of zero":                {name: "RESERVATION_SWEEP_SECONDS", value: "0"},
+		"stream buffer of zero":        {name: "STREAM_BUFFER_SIZE", value: "0"},
+		"no webhook attempts":          {name: "WEBHOOK_MAX_ATTEMPTS", value: "0"},
+		"events stream without length": {name: "EVENTS_STREAM_MAX_LENGTH", value: "0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+
+			_, err := NewConfig(context.Background(), "test_vars/valid_vars.env")
+			require.ErrorContains(t, err, tt.name+" has to be at least 1")
+		})
+	}
+}
@@ -1,35 +1,29 @@
 package customer
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"github.com/google/uuid"
-	"log"
+	"log/slog"
 	"math/rand"
 	"net/http"
-	"net/url"
-	"scootinAboot/internal/model"
-	"strconv"
+	"os"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/model"
+	sdk "scootinAboot/pkg/client"
 )
 
 const (
-	base         = "http://localhost:8081"
-	version      = "/v1"
-	scootersPath = "/scooters"
-	rentPath     = "/rent"
-	freePath     = "/free"
+	base = "http://localhost:8081"
 
 	numberOfScooterRentals = 5
 	timeOfScooterRentals   = 10
 )
 
-var errUnexpectedResponseStatus = errors.New("received unexpected response status")
-
 type Client struct {
 	ClientUUID uuid.UUID
 	Longitude  float64
@@ -43,185 +37,85 @@ type ClientService interface {
 }
 
 type clientService struct {
-	logger *log.Logger
-	client *http.Client
+	logger  *slog.Logger
+	signer  *auth.Signer
+	client  *http.Client
+	baseURL string
 }
 
-func NewClientService(logger *log.Logger) *clientService {
+func NewClientService(logger *slog.Logger, signer *auth.Signer) *clientService {
 	return &clientService{
-		logger: logger,
-		client: http.DefaultClient,
+		logger:  logger,
+		signer:  signer,
+		client:  http.DefaultClient,
+		baseURL: base,
 	}
 }
 
 func (c *clientService) UseScooterAboot(client *Client, waitGroup *sync.WaitGroup) {
 	defer waitGroup.Done()
 
+	ctx := context.Background()
+	logger := c.logger.With(logging.KeyClientUUID, client.ClientUUID)
+
+	// The simulated clients run in the process of the API, so they sign their tokens themselves
+	token, _, err := c.signer.Issue(auth.NewRider(client.ClientUUID))
+	if err != nil {
+		logger.Error("issuing token failed", logging.KeyError, err)
+		os.Exit(1)
+	}
+
+	api := sdk.NewClient(c.baseURL, sdk.WithHTTPClient(c.client), sdk.WithClientToken(token))
+
 	for i := 1; i <= numberOfScooterRentals; i++ {
-		scooters, err := c.getScooters(client)
+		scooters, err := api.GetScooters(ctx, sdk.ScootersQuery{
+			Longitude: &client.Longitude,
+			Latitude:  &client.Latitude,
+			Radius:    client.Radius,
+			City:      client.City,
+		})
 		if err != nil {
-			c.logger.Fatal(err.Error())
+			logger.Error("getting scooters failed", logging.KeyError, err)
+			os.Exit(1)
 		}
 
 		availableScooters := filterAvailableScooters(scooters)
 		if len(availableScooters) == 0 {
-			c.logger.Println("no available scooters")
+			logger.Info("no available scooters")
 			i--
 			continue
 		}
 
 		j := rand.Intn(len(availableScooters))
 
-		innerErr := c.rentScooter(client, &availableScooters[j], client.City)
+		innerErr := api.RentScooter(ctx, sdk.ScooterPost{
+			UUID:      availableScooters[j].UUID,
+			Longitude: availableScooters[j].Longitude,
+			Latitude:  availableScooters[j].Latitude,
+			City:      client.City,
+		})
 		if innerErr != nil {
+			// Somebody else was faster, pick another scooter
+			logger.Info("renting scooter failed",
+				logging.KeyScooterUUID, availableScooters[j].UUID, logging.KeyError, innerErr)
 			i--
 			continue
 		}
 
+		logger.Info("rented scooter successfully", logging.KeyScooterUUID, availableScooters[j].UUID)
+
 		time.Sleep(timeOfScooterRentals * time.Second)
 
-		innerErr = c.freeScooter(client, availableScooters[j].UUID)
+		rental, innerErr := api.FreeScooter(ctx, availableScooters[j].UUID)
 		if innerErr != nil {
-			c.logger.Fatal(innerErr.Error())
-		}
-	}
-}
-
-func (c *clientService) getScooters(client *Client) ([]model.ScooterGet, error) {
-	requestScooters, err := buildRequest(client, scootersPath, http.MethodGet, &bytes.Buffer{})
-	if err != nil {
-		return nil, fmt.Errorf("building request: %w", err)
-	}
-
-	validURLQuery := &url.Values{}
-	validURLQuery.Add("longitude", strconv.FormatFloat(client.Longitude, 'f', -1, 64))
-	validURLQuery.Add("latitude", strconv.FormatFloat(client.Latitude, 'f', -1, 64))
-	validURLQuery.Add("radius", strconv.FormatFloat(client.Radius, 'f', -1, 64))
-	validURLQuery.Add("city", client.City)
-
-	requestScooters.URL.RawQuery = validURLQuery.Encode()
-
-	response, err := c.client.Do(requestScooters)
-	if err != nil {
-		return nil, fmt.Errorf("requesting scooters: %w", err)
-	}
-
-	defer func() {
-		if err = response.Body.Close(); err != nil {
-			c.logger.Fatal(err.Error())
-		}
-	}()
-
-	switch response.StatusCode {
-	case http.StatusOK:
-		var scooters []model.ScooterGet
-		if err = json.NewDecoder(response.Body).Decode(&scooters); err != nil {
-			return nil, fmt.Errorf("decoding response body: %w", err)
-		}
-
-		return scooters, nil
-	default:
-		return nil, fmt.Errorf(
-			"receiving response with status %s: %w",
-			response.Status,
-			errUnexpectedResponseStatus,
-		)
-	}
-}
-
-func (c *clientService) rentScooter(client *Client, scooter *model.ScooterGet, city string) error {
-	scooterPost := model.ScooterPost{
-		UUID:         scooter.UUID,
-		Longitude:    scooter.Longitude,
-		Latitude:     scooter.Latitude,
-		Availability: scooter.Availability,
-		City:         city,
-	}
-
-	scooterJSON, err := json.Marshal(scooterPost)
-	if err != nil {
-		return fmt.Errorf("marshaling scooter to JSON: %w", err)
-	}
-	requestRental, err := buildRequest(client, rentPath, http.MethodPost, bytes.NewBuffer(scooterJSON))
-	if err != nil {
-		return fmt.Errorf("building request: %w", err)
-	}
-
-	response, err := c.client.Do(requestRental)
-	if err != nil {
-		return fmt.Errorf("requesting rental of a scooter: %w", err)
-	}
-
-	defer func() {
-		if err = response.Body.Close(); err != nil {
-			c.logger.Fatal(err.Error())
-		}
-	}()
-
-	switch response.StatusCode {
-	case http.StatusNoContent:
-		c.logger.Println("rented scooter successfully", scooter.UUID)
-
-		return nil
-	default:
-		return fmt.Errorf(
-			"receiving response with status %s: %w",
-			response.Status,
-			errUnexpectedResponseStatus,
-		)
-	}
-}
-
-func (c *clientService) freeScooter(client *Client, scooterUUID uuid.UUID) error {
-	scooterUUIDJSON, err := json.Marshal(scooterUUID)
-	if err != nil {
-		return fmt.Errorf("marshaling scooterUUID to JSON: %w", err)
-	}
-	requestFreeingScooter, err := buildRequest(client, freePath, http.MethodPost, bytes.NewBuffer(scooterUUIDJSON))
-	if err != nil {
-		return fmt.Errorf("building request: %w", err)
-	}
-
-	response, err := c.client.Do(requestFreeingScooter)
-	if err != nil {
-		return fmt.Errorf("requesting freeing of the scooter: %w", err)
-	}
-
-	defer func() {
-		if err = response.Body.Close(); err != nil {
-			c.logger.Fatal(err.Error())
+			logger.Error("freeing scooter failed",
+				logging.KeyScooterUUID, availableScooters[j].UUID, logging.KeyError, innerErr)
+			os.Exit(1)
 		}
-	}()
-
-	switch response.StatusCode {
-	case http.StatusNoContent:
-		c.logger.Println("freed scooter successfully", scooterUUID)
-
-		return nil
-	default:
-		return fmt.Errorf(
-			"receiving response with status %s: %w",
-			response.Status,
-			errUnexpectedResponseStatus,
-		)
-	}
-}
-
-func buildRequest(c *Client, path string, method string, body *bytes.Buffer) (*http.Request, error) {
 
-	request, err := http.NewRequestWithContext(
-		context.Background(),
-		method,
-		base+version+path,
-		body,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("creating new request: %w", err)
+		logger.Info("freed scooter successfully", logging.KeyScooterUUID, rental.ScooterUUID,
+			logging.KeyRentalID, rental.ID, "fare", rental.Fare, "currency", rental.Currency)
 	}
-
-	request.Header.Set("clientUUID", c.ClientUUID.String())
-
-	return request, nil
 }
 
 func filterAvailableScooters(scooters []model.ScooterGet) []model.ScooterGet {
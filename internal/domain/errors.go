@@ -0,0 +1,89 @@
+package domain
+
+import "errors"
+
+// Kind classifies failures, so transports can react to them without knowing the module they come from.
+type Kind string
+
+const (
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindForbidden       Kind = "forbidden"
+	KindUnauthenticated Kind = "unauthenticated"
+	KindValidation      Kind = "validation"
+	KindUnavailable     Kind = "unavailable"
+	KindRateLimited     Kind = "rate_limited"
+	KindTimeout         Kind = "timeout"
+)
+
+const (
+	codeUnavailable    = "service_unavailable"
+	messageUnavailable = "service is temporarily unavailable, try again later"
+)
+
+// Error is a failure known to the domain. Code is stable and machine-readable, Message is safe to show to clients.
+// The cause is kept for logs only.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	cause   error
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Unauthenticated marks the request whose caller couldn't be identified, e.g. by a missing or expired token.
+func Unauthenticated(code, message string) *Error {
+	return &Error{Kind: KindUnauthenticated, Code: code, Message: message}
+}
+
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// RateLimited marks the request of a caller who sent too many of them, it may be retried later.
+func RateLimited(code, message string) *Error {
+	return &Error{Kind: KindRateLimited, Code: code, Message: message}
+}
+
+// Timeout marks the request that wasn't handled within its deadline, it may be retried.
+func Timeout(code, message string) *Error {
+	return &Error{Kind: KindTimeout, Code: code, Message: message}
+}
+
+// Unavailable marks the failure of a dependency the service can't work without, e.g. the storage.
+func Unavailable(cause error) *Error {
+	return &Error{Kind: KindUnavailable, Code: codeUnavailable, Message: messageUnavailable, cause: cause}
+}
+
+// As finds the first domain error in the chain of err.
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,60 @@
+//go:build unit
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAs(t *testing.T) {
+	errNotFound := NotFound("thing_not_found", "thing was not found")
+	errOutage := errors.New("connection refused")
+
+	tests := map[string]struct {
+		err      error
+		wantKind Kind
+		wantCode string
+		wantOk   bool
+	}{
+		"finding wrapped domain error": {
+			err:      fmt.Errorf("getting thing: %w", errNotFound),
+			wantKind: KindNotFound,
+			wantCode: "thing_not_found",
+			wantOk:   true,
+		},
+		"finding unavailable dependency": {
+			err:      fmt.Errorf("getting thing: %w", Unavailable(errOutage)),
+			wantKind: KindUnavailable,
+			wantCode: codeUnavailable,
+			wantOk:   true,
+		},
+		"not finding domain error in plain error": {
+			err:    fmt.Errorf("getting thing: %w", errOutage),
+			wantOk: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := As(tt.err)
+			require.Equal(t, tt.wantOk, ok)
+
+			if ok {
+				require.Equal(t, tt.wantKind, got.Kind)
+				require.Equal(t, tt.wantCode, got.Code)
+			}
+		})
+	}
+}
+
+func TestUnavailableKeepsCause(t *testing.T) {
+	errOutage := errors.New("connection refused")
+
+	err := fmt.Errorf("getting thing: %w", Unavailable(errOutage))
+
+	require.ErrorIs(t, err, errOutage)
+	require.Equal(t, "getting thing: "+messageUnavailable+": connection refused", err.Error())
+}
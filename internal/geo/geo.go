@@ -0,0 +1,18 @@
+package geo
+
+import "math"
+
+const earthRadiusInMeters = 6371000.0
+
+// DistanceInMeters calculates the great-circle distance between two points using the haversine formula.
+func DistanceInMeters(fromLongitude, fromLatitude, toLongitude, toLatitude float64) float64 {
+	fromLatitudeRad := fromLatitude * math.Pi / 180
+	toLatitudeRad := toLatitude * math.Pi / 180
+	deltaLatitude := (toLatitude - fromLatitude) * math.Pi / 180
+	deltaLongitude := (toLongitude - fromLongitude) * math.Pi / 180
+
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(fromLatitudeRad)*math.Cos(toLatitudeRad)*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+
+	return 2 * earthRadiusInMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
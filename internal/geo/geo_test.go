@@ -0,0 +1,52 @@
+//go:build unit
+
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceInMeters(t *testing.T) {
+	tests := map[string]struct {
+		fromLongitude float64
+		fromLatitude  float64
+		toLongitude   float64
+		toLatitude    float64
+		want          float64
+		tolerance     float64
+	}{
+		"distance between the same points is zero": {
+			fromLongitude: 73.5673,
+			fromLatitude:  45.5017,
+			toLongitude:   73.5673,
+			toLatitude:    45.5017,
+			want:          0,
+			tolerance:     1e-9,
+		},
+		"distance of one degree of latitude": {
+			fromLongitude: 73.5673,
+			fromLatitude:  45,
+			toLongitude:   73.5673,
+			toLatitude:    46,
+			want:          111195,
+			tolerance:     1,
+		},
+		"distance of one degree of longitude shrinks away from the equator": {
+			fromLongitude: 73,
+			fromLatitude:  60,
+			toLongitude:   74,
+			toLatitude:    60,
+			want:          55597,
+			tolerance:     1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := DistanceInMeters(tt.fromLongitude, tt.fromLatitude, tt.toLongitude, tt.toLatitude)
+			if math.Abs(got-tt.want) > tt.tolerance {
+				t.Errorf("DistanceInMeters() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Keys of the attributes correlating the records of a request or a ride across the modules.
+const (
+	KeyRequestID   = "request_id"
+	KeyScooterUUID = "scooter_uuid"
+	KeyClientUUID  = "client_uuid"
+	KeyRole        = "role"
+	KeyRentalID    = "rental_id"
+	KeyTraceID     = "trace_id"
+	KeyError       = "error"
+)
+
+type contextKey struct{}
+
+// NewLogger builds the logger of the service writing records of at least the level in the format, every record
+// logged with a context carries the attributes added to it by With.
+func NewLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var minLevel slog.Level
+
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("parsing log level: %w", err)
+	}
+
+	options := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	return slog.New(NewContextHandler(handler)), nil
+}
+
+// NewContextHandler wraps the handler, so it adds the attributes of the record's context.
+func NewContextHandler(handler slog.Handler) slog.Handler {
+	return &contextHandler{handler: handler}
+}
+
+// With returns a copy of the context carrying the attributes besides the ones it already has, given as
+// alternating keys and values like to slog.Logger.With.
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+
+	attrs := append([]slog.Attr{}, attrsFrom(ctx)...)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	return context.WithValue(ctx, contextKey{}, attrs)
+}
+
+// Attr returns the value of the context's attribute with the key, if it has one.
+func Attr(ctx context.Context, key string) (slog.Value, bool) {
+	attrs := attrsFrom(ctx)
+
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value, true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+
+	return attrs
+}
+
+type contextHandler struct {
+	handler slog.Handler
+}
+
+func (ch *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return ch.handler.Enabled(ctx, level)
+}
+
+func (ch *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := attrsFrom(ctx); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+
+	return ch.handler.Handle(ctx, record)
+}
+
+func (ch *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{handler: ch.handler.WithAttrs(attrs)}
+}
+
+func (ch *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{handler: ch.handler.WithGroup(name)}
+}
+
+// Discard returns a logger dropping every record, for the tests and tools that don't want the output.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := map[string]struct {
+		level      string
+		format     string
+		wantErr    bool
+		wantOutput string
+	}{
+		"json logger of info level": {
+			level:      "info",
+			format:     FormatJSON,
+			wantOutput: `"msg":"scooter rented"`,
+		},
+		"text logger of warn level drops info records": {
+			level:      "warn",
+			format:     FormatText,
+			wantOutput: "",
+		},
+		"unknown level": {
+			level:   "loud",
+			format:  FormatJSON,
+			wantErr: true,
+		},
+		"unknown format": {
+			level:   "debug",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			logger, err := NewLogger(&output, tt.level, tt.format)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			logger.Info("scooter rented")
+
+			if tt.wantOutput == "" {
+				require.Empty(t, output.String())
+
+				return
+			}
+
+			require.Contains(t, output.String(), tt.wantOutput)
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	var output bytes.Buffer
+
+	logger, err := NewLogger(&output, "info", FormatJSON)
+	require.NoError(t, err)
+
+	ctx := With(context.Background(), KeyRequestID, "request-1")
+	ctx = With(ctx, KeyScooterUUID, "scooter-1", KeyClientUUID, "client-1")
+
+	logger.With(KeyRentalID, "rental-1").InfoContext(ctx, "scooter freed")
+	logger.InfoContext(context.Background(), "scooter rented")
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var record map[string]any
+
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	require.Equal(t, "request-1", record[KeyRequestID])
+	require.Equal(t, "scooter-1", record[KeyScooterUUID])
+	require.Equal(t, "client-1", record[KeyClientUUID])
+	require.Equal(t, "rental-1", record[KeyRentalID])
+
+	record = nil
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	require.NotContains(t, record, KeyRequestID)
+
+	value, ok := Attr(ctx, KeyScooterUUID)
+	require.True(t, ok)
+	require.Equal(t, "scooter-1", value.String())
+
+	_, ok = Attr(ctx, KeyRentalID)
+	require.False(t, ok)
+}
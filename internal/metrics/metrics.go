@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"scootinAboot/internal/domain"
+)
+
+const namespace = "scootin"
+
+const (
+	ActionRent = "rent"
+	ActionFree = "free"
+
+	OutcomeSuccess = "success"
+	// OutcomeError stands for failures without a domain code, like Redis being down.
+	OutcomeError = "error"
+)
+
+// The collectors are shared by the whole service, they count even when not registered, so tests can read them
+// without serving /metrics.
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by route template, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests, by route template and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	RedisCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Time taken by the Redis repository operations, by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command"})
+
+	RedisCommandErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_errors_total",
+		Help:      "Redis repository operations that failed, by operation.",
+	}, []string{"command"})
+
+	ActiveRides = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tracker",
+		Name:      "active_rides",
+		Help:      "Rides the tracker is following right now, one goroutine each.",
+	})
+
+	TrackerTickErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tracker",
+		Name:      "tick_errors_total",
+		Help:      "Failed updates of rented scooters made by the tracker.",
+	})
+
+	RentalOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rental",
+		Name:      "outcomes_total",
+		Help:      "Rents and frees of scooters, by action and outcome: success or the error code.",
+	}, []string{"action", "outcome"})
+)
+
+// Register adds every collector of the service to the registerer.
+func Register(registerer prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		HTTPRequests,
+		HTTPRequestDuration,
+		RedisCommandDuration,
+		RedisCommandErrors,
+		ActiveRides,
+		TrackerTickErrors,
+		RentalOutcomes,
+	}
+
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return fmt.Errorf("registering metrics: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Outcome labels the result of an action, domain errors by their code so the label values stay few.
+func Outcome(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	if domainErr, ok := domain.As(err); ok && domainErr.Code != "" {
+		return domainErr.Code
+	}
+
+	return OutcomeError
+}
@@ -0,0 +1,64 @@
+//go:build unit
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"success without error": {
+			err:  nil,
+			want: OutcomeSuccess,
+		},
+		"wrapped domain error is labelled by its code": {
+			err:  fmt.Errorf("renting scooter: %w", domain.Conflict("scooter_reserved", "scooter is reserved")),
+			want: "scooter_reserved",
+		},
+		"unavailable dependency is labelled by its code": {
+			err:  domain.Unavailable(errors.New("redis: client is closed")),
+			want: "service_unavailable",
+		},
+		"unknown error is labelled as error": {
+			err:  errors.New("tracker went away"),
+			want: OutcomeError,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, Outcome(tt.err))
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	require.NoError(t, Register(registry))
+
+	HTTPRequests.WithLabelValues("/v1/scooters", "GET", "200").Inc()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+
+	require.Contains(t, names, "scootin_http_requests_total")
+	require.Contains(t, names, "scootin_tracker_active_rides")
+
+	require.Error(t, Register(registry), "registering the collectors twice")
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthStatusOK       HealthStatus = "ok"
+	HealthStatusReady    HealthStatus = "ready"
+	HealthStatusNotReady HealthStatus = "not_ready"
+)
+
+// HealthStatuses lists every status the health endpoints report, e.g. to describe them in the API specification.
+var HealthStatuses = []HealthStatus{HealthStatusOK, HealthStatusReady, HealthStatusNotReady}
+
+type HealthGet struct {
+	Status HealthStatus `json:"status"`
+}
+
+// ReadinessGet lists the checks of the instance, each of them is ok or tells why it failed.
+type ReadinessGet struct {
+	Status HealthStatus      `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// StatusGet describes the instance to operators, RedisLatency is the time the ping took in milliseconds.
+type StatusGet struct {
+	Status       HealthStatus      `json:"status"`
+	Checks       map[string]string `json:"checks"`
+	RedisLatency float64           `json:"redisLatencyMs"`
+	ActiveRides  int               `json:"activeRides"`
+	StartedAt    time.Time         `json:"startedAt"`
+	Build        BuildGet          `json:"build"`
+}
+
+// BuildGet tells what the instance runs, the revision is known when it was built from a git checkout.
+type BuildGet struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
@@ -0,0 +1,32 @@
+package model
+
+// Problem is the RFC 7807 description of an error, Code, ScooterUUID, RequestID and InvalidParams are its extension
+// members.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Code        string `json:"code"`
+	ScooterUUID string `json:"scooterUUID,omitempty"`
+	RequestID   string `json:"requestId,omitempty"`
+
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
+}
+
+// InvalidParam tells which field of the request was rejected and why, Name is the field's JSON name.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// LegacyError is the error body sent before problem details, it is kept for clients that ask for plain JSON.
+type LegacyError struct {
+	Code    string `json:"code"`
+	Error   string `json:"Error"`
+	Message string `json:"Message"`
+
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RentalGet describes a ride, distance is in meters and fare in rental's currency.
+type RentalGet struct {
+	ID              uuid.UUID        `json:"id"`
+	ClientUUID      uuid.UUID        `json:"clientUUID"`
+	ScooterUUID     uuid.UUID        `json:"scooterUUID"`
+	City            string           `json:"city"`
+	StartedAt       time.Time        `json:"startedAt"`
+	EndedAt         time.Time        `json:"endedAt"`
+	DurationSeconds int64            `json:"durationSeconds"`
+	Distance        float64          `json:"distance"`
+	Fare            float64          `json:"fare"`
+	Currency        string           `json:"currency"`
+	Events          []RentalEventGet `json:"events"`
+}
+
+// RentalEventGet is something noteworthy that happened during the ride, like entering a slow zone.
+type RentalEventGet struct {
+	Type       string    `json:"type"`
+	Zone       string    `json:"zone,omitempty"`
+	Longitude  float64   `json:"longitude"`
+	Latitude   float64   `json:"latitude"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// RentalsGet is a page of the ride history, NextCursor is passed as the cursor to get the next page.
+type RentalsGet struct {
+	Rentals    []RentalGet `json:"rentals"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// ActiveRentalsGet are the rides going on in the city, the ones started first come first.
+type ActiveRentalsGet struct {
+	Rentals []RentalGet `json:"rentals"`
+}
+
+// ActiveRentalsQueryParams choose the city, operators may leave it out for their own.
+type ActiveRentalsQueryParams struct {
+	City string `json:"city"`
+}
+
+// RentalsQueryParams filter the ride history by the start of the ride, From and To are RFC 3339 timestamps.
+type RentalsQueryParams struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
+}
+
+// RideProgressGet is the state of the ongoing ride, fare is what the ride would cost if it ended now. The last
+// progress of the ride is marked as ended and carries the charged distance and fare.
+type RideProgressGet struct {
+	ID             uuid.UUID `json:"id"`
+	ScooterUUID    uuid.UUID `json:"scooterUUID"`
+	Longitude      float64   `json:"longitude"`
+	Latitude       float64   `json:"latitude"`
+	ElapsedSeconds int64     `json:"elapsedSeconds"`
+	Distance       float64   `json:"distance"`
+	Fare           float64   `json:"fare"`
+	Currency       string    `json:"currency,omitempty"`
+	Ended          bool      `json:"ended"`
+	At             time.Time `json:"at"`
+}
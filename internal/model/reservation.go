@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationPost struct {
+	ScooterUUID uuid.UUID `json:"scooterUUID"`
+}
+
+type ReservationGet struct {
+	ScooterUUID uuid.UUID `json:"scooterUUID"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
@@ -4,13 +4,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// ScooterGet carries availability derived from status for clients that don't know statuses yet.
 type ScooterGet struct {
 	UUID         uuid.UUID `json:"UUID"`
 	Longitude    float64   `json:"longitude"`
 	Latitude     float64   `json:"latitude"`
+	Status       string    `json:"status"`
+	Battery      float64   `json:"battery"`
 	Availability bool      `json:"availability"`
 }
 
+// ScooterPost still accepts availability sent by old clients, it is not taken into account.
 type ScooterPost struct {
 	UUID         uuid.UUID `json:"UUID"`
 	Longitude    float64   `json:"longitude"`
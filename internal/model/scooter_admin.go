@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type ScooterAdminGet struct {
+	UUID         uuid.UUID `json:"UUID"`
+	Longitude    float64   `json:"longitude"`
+	Latitude     float64   `json:"latitude"`
+	City         string    `json:"city"`
+	Status       string    `json:"status"`
+	Battery      float64   `json:"battery"`
+	Availability bool      `json:"availability"`
+}
+
+type ScooterAdminPost struct {
+	UUID      uuid.UUID `json:"UUID"`
+	Longitude float64   `json:"longitude"`
+	Latitude  float64   `json:"latitude"`
+	City      string    `json:"city"`
+	Status    string    `json:"status"`
+	Battery   *float64  `json:"battery,omitempty"`
+}
+
+type ScooterLocationPut struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+	City      string  `json:"city"`
+}
+
+type ScooterStatusPut struct {
+	Status string `json:"status"`
+}
+
+type ScooterBatteryPut struct {
+	Battery float64 `json:"battery"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScooterStreamQueryParams selects the area to follow, a circle given by longitude, latitude and radius or
+// a bounding box given by its corners.
+type ScooterStreamQueryParams struct {
+	City         string   `json:"city"`
+	Longitude    *float64 `json:"longitude"`
+	Latitude     *float64 `json:"latitude"`
+	Radius       *float64 `json:"radius"`
+	MinLongitude *float64 `json:"minLongitude"`
+	MinLatitude  *float64 `json:"minLatitude"`
+	MaxLongitude *float64 `json:"maxLongitude"`
+	MaxLatitude  *float64 `json:"maxLatitude"`
+}
+
+// ScooterEventGet is a change of the scooter within the followed area, it is sent as the server-sent event named
+// after its type.
+type ScooterEventGet struct {
+	Type         string    `json:"type"`
+	UUID         uuid.UUID `json:"UUID"`
+	Longitude    float64   `json:"longitude"`
+	Latitude     float64   `json:"latitude"`
+	Status       string    `json:"status"`
+	Battery      float64   `json:"battery"`
+	Availability bool      `json:"availability"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const TokenTypeBearer = "Bearer"
+
+// TokenPost asks for the token of the client, a new client is made up when ClientUUID is left out. Tokens are issued
+// to riders unless another role is given, operators need the city they work in.
+type TokenPost struct {
+	ClientUUID *uuid.UUID `json:"clientUUID,omitempty"`
+	Role       string     `json:"role,omitempty"`
+	City       string     `json:"city,omitempty"`
+}
+
+// TokenGet is the issued token, ClientUUID is its subject, the operator or the admin for the tokens of their roles.
+type TokenGet struct {
+	Token      string    `json:"token"`
+	TokenType  string    `json:"tokenType"`
+	ClientUUID uuid.UUID `json:"clientUUID"`
+	Role       string    `json:"role"`
+	City       string    `json:"city,omitempty"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
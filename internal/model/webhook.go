@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookPost struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+// WebhookGet leaves out the secret, it is known only to whoever created the webhook.
+type WebhookGet struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type WebhooksGet struct {
+	Webhooks []WebhookGet `json:"webhooks"`
+}
+
+// WebhookDeadLetterGet is a delivery that was given up on, payload is the body that was POSTed to the webhook.
+type WebhookDeadLetterGet struct {
+	ID        uuid.UUID       `json:"id"`
+	WebhookID uuid.UUID       `json:"webhookID"`
+	URL       string          `json:"url"`
+	EventType string          `json:"eventType"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"lastError"`
+	FailedAt  time.Time       `json:"failedAt"`
+}
+
+type WebhookDeadLettersGet struct {
+	DeadLetters []WebhookDeadLetterGet `json:"deadLetters"`
+}
+
+type WebhookDeadLettersQueryParams struct {
+	Limit int `json:"limit"`
+}
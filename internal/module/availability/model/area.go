@@ -0,0 +1,76 @@
+package model
+
+import (
+	"math"
+
+	"scootinAboot/internal/geo"
+)
+
+// Area is the part of the city a subscriber watches, either a circle around the centre or a bounding box.
+type Area struct {
+	City string
+
+	// Circle
+	Longitude float64
+	Latitude  float64
+	Radius    float64 // in meters, zero for bounding box
+
+	// Bounding box
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+func NewCircleArea(city string, longitude, latitude, radius float64) *Area {
+	return &Area{
+		City:      city,
+		Longitude: longitude,
+		Latitude:  latitude,
+		Radius:    radius,
+	}
+}
+
+func NewBoxArea(city string, minLongitude, minLatitude, maxLongitude, maxLatitude float64) *Area {
+	return &Area{
+		City:         city,
+		Longitude:    (minLongitude + maxLongitude) / 2,
+		Latitude:     (minLatitude + maxLatitude) / 2,
+		MinLongitude: minLongitude,
+		MinLatitude:  minLatitude,
+		MaxLongitude: maxLongitude,
+		MaxLatitude:  maxLatitude,
+	}
+}
+
+func (a *Area) IsBox() bool {
+	return a.Radius == 0
+}
+
+// Contains reports whether the point lies within the area, boundary included.
+func (a *Area) Contains(longitude, latitude float64) bool {
+	if a.IsBox() {
+		return longitude >= a.MinLongitude && longitude <= a.MaxLongitude &&
+			latitude >= a.MinLatitude && latitude <= a.MaxLatitude
+	}
+
+	return geo.DistanceInMeters(a.Longitude, a.Latitude, longitude, latitude) <= a.Radius
+}
+
+// BoundingRadius is the radius of the smallest circle around the centre covering the whole area, so the area can be
+// looked up with a radius search and filtered with Contains.
+func (a *Area) BoundingRadius() float64 {
+	if !a.IsBox() {
+		return a.Radius
+	}
+
+	radius := 0.0
+
+	for _, longitude := range []float64{a.MinLongitude, a.MaxLongitude} {
+		for _, latitude := range []float64{a.MinLatitude, a.MaxLatitude} {
+			radius = math.Max(radius, geo.DistanceInMeters(a.Longitude, a.Latitude, longitude, latitude))
+		}
+	}
+
+	return radius
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	model "scootinAboot/internal/module/availability/model"
+	transfer "scootinAboot/internal/module/availability/transfer"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAvailabilityService is a mock of AvailabilityService interface.
+type MockAvailabilityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAvailabilityServiceMockRecorder
+}
+
+// MockAvailabilityServiceMockRecorder is the mock recorder for MockAvailabilityService.
+type MockAvailabilityServiceMockRecorder struct {
+	mock *MockAvailabilityService
+}
+
+// NewMockAvailabilityService creates a new mock instance.
+func NewMockAvailabilityService(ctrl *gomock.Controller) *MockAvailabilityService {
+	mock := &MockAvailabilityService{ctrl: ctrl}
+	mock.recorder = &MockAvailabilityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAvailabilityService) EXPECT() *MockAvailabilityServiceMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockAvailabilityService) Subscribe(ctx context.Context, area *model.Area) (*transfer.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, area)
+	ret0, _ := ret[0].(*transfer.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockAvailabilityServiceMockRecorder) Subscribe(ctx, area interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockAvailabilityService)(nil).Subscribe), ctx, area)
+}
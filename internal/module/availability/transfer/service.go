@@ -0,0 +1,256 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/availability/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redis "scootinAboot/internal/module/redis/transfer"
+)
+
+var (
+	ErrSubscriberLagged = domain.Conflict(
+		"subscriber_lagged",
+		"subscriber fell too far behind the changes, it has to subscribe again",
+	)
+	ErrFeedClosed = domain.Unavailable(errors.New("scooter events feed closed"))
+)
+
+//go:generate mockgen -source=service.go -destination=mock/availability_mock.go -package=mock
+type AvailabilityService interface {
+	Subscribe(ctx context.Context, area *model.Area) (*Subscription, error)
+}
+
+// Subscription delivers changes of the scooters in the area until the subscriber's context is done. Updates is
+// closed when the subscription ends, Err tells why then: it stays nil when the subscriber left, it is
+// ErrSubscriberLagged when the subscriber couldn't keep up and ErrFeedClosed when Redis stopped delivering events.
+// Err may be read once Updates is closed only.
+type Subscription struct {
+	Snapshot []*redismodel.ScooterEvent
+	Updates  <-chan *redismodel.ScooterEvent
+	Err      error
+}
+
+type availabilityService struct {
+	logger       *slog.Logger
+	redisService redis.RedisService
+	bufferSize   int
+	mu           sync.Mutex
+	feeds        map[string]*cityFeed
+}
+
+// cityFeed shares one Redis subscription between all subscribers of the city.
+type cityFeed struct {
+	cancel      context.CancelFunc
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	area         *model.Area
+	updates      chan *redismodel.ScooterEvent
+	seen         map[uuid.UUID]struct{} // scooters the subscriber knows to be within the area
+	subscription *Subscription
+}
+
+func NewAvailabilityService(logger *slog.Logger, service redis.RedisService, bufferSize int) *availabilityService {
+	return &availabilityService{
+		logger:       logger,
+		redisService: service,
+		bufferSize:   bufferSize,
+		feeds:        make(map[string]*cityFeed),
+	}
+}
+
+// Subscribe starts with the scooters currently within the area and follows their changes. Scooters entering the
+// area are delivered as they come, a scooter leaving it is delivered as removed.
+func (as *availabilityService) Subscribe(ctx context.Context, area *model.Area) (*Subscription, error) {
+	updates := make(chan *redismodel.ScooterEvent, as.bufferSize)
+
+	sub := &subscriber{
+		area:         area,
+		updates:      updates,
+		seen:         make(map[uuid.UUID]struct{}),
+		subscription: &Subscription{Updates: updates},
+	}
+
+	// Joining the feed before taking the snapshot, so no change falls in between
+	if err := as.join(area.City, sub); err != nil {
+		return nil, err
+	}
+
+	scooters, err := as.redisService.GetScooters(ctx, area.Longitude, area.Latitude, area.BoundingRadius(), area.City)
+	if err != nil {
+		as.leave(area.City, sub, nil)
+
+		return nil, fmt.Errorf("getting scooters in the area: %w", err)
+	}
+
+	as.mu.Lock()
+
+	for _, scooter := range scooters {
+		scooterUUID, parseErr := uuid.Parse(scooter.Scooter.Name)
+		if parseErr != nil || !area.Contains(scooter.Scooter.Longitude, scooter.Scooter.Latitude) {
+			continue
+		}
+
+		sub.seen[scooterUUID] = struct{}{}
+		sub.subscription.Snapshot = append(sub.subscription.Snapshot, redismodel.NewScooterEvent(
+			redismodel.ScooterAdded,
+			scooterUUID,
+			area.City,
+			scooter.Scooter.Longitude,
+			scooter.Scooter.Latitude,
+			scooter.Status,
+			scooter.Battery,
+		))
+	}
+
+	as.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		as.leave(area.City, sub, nil)
+	}()
+
+	return sub.subscription, nil
+}
+
+func (as *availabilityService) join(city string, sub *subscriber) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if feed, ok := as.feeds[city]; ok {
+		feed.subscribers[sub] = struct{}{}
+
+		return nil
+	}
+
+	feedCtx, cancel := context.WithCancel(context.Background())
+
+	events, err := as.redisService.SubscribeScooterEvents(feedCtx, city)
+	if err != nil {
+		cancel()
+
+		return fmt.Errorf("subscribing to city's scooters: %w", err)
+	}
+
+	feed := &cityFeed{
+		cancel:      cancel,
+		subscribers: map[*subscriber]struct{}{sub: {}},
+	}
+	as.feeds[city] = feed
+
+	go as.dispatch(city, feed, events)
+
+	return nil
+}
+
+// leave ends the subscription with err, the Redis subscription of the city is closed with its last subscriber.
+func (as *availabilityService) leave(city string, sub *subscriber, err error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.remove(city, sub, err)
+}
+
+func (as *availabilityService) remove(city string, sub *subscriber, err error) {
+	feed, ok := as.feeds[city]
+	if !ok {
+		return
+	}
+
+	if _, ok = feed.subscribers[sub]; !ok {
+		return
+	}
+
+	sub.subscription.Err = err
+	close(sub.updates)
+
+	delete(feed.subscribers, sub)
+
+	if len(feed.subscribers) == 0 {
+		feed.cancel()
+		delete(as.feeds, city)
+	}
+}
+
+// dispatch fans the events of the city out. A subscriber whose buffer is full is dropped rather than waited for,
+// so one slow consumer can't hold the others back.
+func (as *availabilityService) dispatch(city string, feed *cityFeed, events <-chan *redismodel.ScooterEvent) {
+	for event := range events {
+		as.mu.Lock()
+
+		for sub := range feed.subscribers {
+			update := sub.filter(event)
+			if update == nil {
+				continue
+			}
+
+			select {
+			case sub.updates <- update:
+			default:
+				as.logger.Warn("dropping lagging subscriber of scooters", "city", city)
+
+				as.remove(city, sub, ErrSubscriberLagged)
+			}
+		}
+
+		as.mu.Unlock()
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	// The feed is cancelled once the last subscriber left, otherwise Redis stopped delivering
+	if as.feeds[city] != feed {
+		return
+	}
+
+	as.logger.Warn("scooter events feed closed", "city", city)
+
+	for sub := range feed.subscribers {
+		as.remove(city, sub, ErrFeedClosed)
+	}
+}
+
+// filter returns the update the subscriber has to get for the event, nil when the event concerns none of its
+// scooters.
+func (s *subscriber) filter(event *redismodel.ScooterEvent) *redismodel.ScooterEvent {
+	_, seen := s.seen[event.ScooterUUID]
+
+	if event.Type == redismodel.ScooterRemoved {
+		if !seen {
+			return nil
+		}
+
+		delete(s.seen, event.ScooterUUID)
+
+		return event
+	}
+
+	if s.area.Contains(event.Longitude, event.Latitude) {
+		s.seen[event.ScooterUUID] = struct{}{}
+
+		return event
+	}
+
+	if !seen {
+		return nil
+	}
+
+	// The scooter left the area, for the subscriber it is gone
+	delete(s.seen, event.ScooterUUID)
+
+	left := *event
+	left.Type = redismodel.ScooterRemoved
+
+	return &left
+}
@@ -0,0 +1,243 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/module/availability/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservicemock "scootinAboot/internal/module/redis/transfer/mock"
+	"scootinAboot/internal/testutil"
+)
+
+const (
+	testCity      = "Montreal"
+	testLongitude = 70.0
+	testLatitude  = 60.0
+	testRadius    = 1000.0
+
+	testBufferSize = 2
+	testTimeout    = time.Second
+)
+
+func TestSubscribe(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	inside, outside, entering := uuid.New(), uuid.New(), uuid.New()
+
+	scooters := []*redismodel.RedisScooter{
+		testScooter(inside, testLongitude, testLatitude),
+		// Found by the radius search of the box, yet outside of it
+		testScooter(outside, testLongitude+0.001, testLatitude+0.001),
+	}
+
+	tests := map[string]struct {
+		area         *model.Area
+		events       []*redismodel.ScooterEvent
+		wantSnapshot []uuid.UUID
+		wantUpdates  []*redismodel.ScooterEvent
+	}{
+		"circle follows scooters entering and leaving it": {
+			area: model.NewCircleArea(testCity, testLongitude, testLatitude, testRadius),
+			events: []*redismodel.ScooterEvent{
+				testEvent(redismodel.ScooterMoved, entering, testLongitude+0.001, testLatitude),
+				testEvent(redismodel.ScooterMoved, uuid.New(), testLongitude+1, testLatitude),
+				testEvent(redismodel.ScooterMoved, inside, testLongitude+1, testLatitude),
+				testEvent(redismodel.ScooterRemoved, inside, testLongitude+1, testLatitude),
+			},
+			wantSnapshot: []uuid.UUID{inside, outside},
+			wantUpdates: []*redismodel.ScooterEvent{
+				testEvent(redismodel.ScooterMoved, entering, testLongitude+0.001, testLatitude),
+				testEvent(redismodel.ScooterRemoved, inside, testLongitude+1, testLatitude),
+			},
+		},
+		"bounding box leaves out scooters of its bounding circle": {
+			area: model.NewBoxArea(testCity, testLongitude-0.0005, testLatitude-0.0005,
+				testLongitude+0.0005, testLatitude+0.0005),
+			events: []*redismodel.ScooterEvent{
+				testEvent(redismodel.ScooterStatusChanged, outside, testLongitude+0.001, testLatitude+0.001),
+				testEvent(redismodel.ScooterStatusChanged, inside, testLongitude, testLatitude),
+			},
+			wantSnapshot: []uuid.UUID{inside},
+			wantUpdates: []*redismodel.ScooterEvent{
+				testEvent(redismodel.ScooterStatusChanged, inside, testLongitude, testLatitude),
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+			mockRedisService.EXPECT().SubscribeScooterEvents(gomock.Any(), testCity).
+				DoAndReturn(func(ctx context.Context, city string) (<-chan *redismodel.ScooterEvent, error) {
+					return testutil.ScooterEventFeed(ctx, tt.events), nil
+				}).Times(1)
+			mockRedisService.EXPECT().GetScooters(gomock.Any(), tt.area.Longitude, tt.area.Latitude, gomock.Any(), testCity).
+				Return(scooters, nil).Times(1)
+
+			as := NewAvailabilityService(logger, mockRedisService, testBufferSize)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			subscription, err := as.Subscribe(ctx, tt.area)
+			require.NoError(t, err)
+
+			snapshot := make([]uuid.UUID, len(subscription.Snapshot))
+			for i, event := range subscription.Snapshot {
+				snapshot[i] = event.ScooterUUID
+			}
+
+			require.ElementsMatch(t, tt.wantSnapshot, snapshot)
+
+			for _, want := range tt.wantUpdates {
+				got := receive(t, subscription.Updates)
+				require.Equal(t, want.Type, got.Type)
+				require.Equal(t, want.ScooterUUID, got.ScooterUUID)
+			}
+
+			cancel()
+
+			requireClosed(t, subscription.Updates)
+			require.NoError(t, subscription.Err)
+		})
+	}
+}
+
+func TestSubscribeBackpressure(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	events := make(chan *redismodel.ScooterEvent)
+
+	var feedCtx context.Context
+
+	mockRedisService := redisservicemock.NewMockRedisService(controller)
+	mockRedisService.EXPECT().SubscribeScooterEvents(gomock.Any(), testCity).
+		DoAndReturn(func(ctx context.Context, city string) (<-chan *redismodel.ScooterEvent, error) {
+			feedCtx = ctx
+
+			return events, nil
+		}).Times(1)
+	mockRedisService.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
+		Return(nil, nil).Times(2)
+
+	as := NewAvailabilityService(logger, mockRedisService, testBufferSize)
+	area := model.NewCircleArea(testCity, testLongitude, testLatitude, testRadius)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slow, err := as.Subscribe(ctx, area)
+	require.NoError(t, err)
+
+	fast, err := as.Subscribe(ctx, area)
+	require.NoError(t, err)
+
+	for i := 0; i <= testBufferSize; i++ {
+		event := testEvent(redismodel.ScooterMoved, uuid.New(), testLongitude, testLatitude)
+
+		events <- event
+
+		require.Equal(t, event, receive(t, fast.Updates))
+	}
+
+	// The slow subscriber never read, the event over its buffer dropped it
+	for i := 0; i < testBufferSize; i++ {
+		receive(t, slow.Updates)
+	}
+
+	requireClosed(t, slow.Updates)
+	require.ErrorIs(t, slow.Err, ErrSubscriberLagged)
+
+	// The Redis subscription is shared, it ends with the last subscriber only
+	require.NoError(t, feedCtx.Err())
+
+	close(events)
+
+	requireClosed(t, fast.Updates)
+	require.ErrorIs(t, fast.Err, ErrFeedClosed)
+}
+
+func TestSubscribeFailed(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRedisService := redisservicemock.NewMockRedisService(controller)
+	mockRedisService.EXPECT().SubscribeScooterEvents(gomock.Any(), testCity).Return(nil, redis.ErrClosed).Times(1)
+
+	as := NewAvailabilityService(logger, mockRedisService, testBufferSize)
+
+	_, err := as.Subscribe(context.Background(), model.NewCircleArea(testCity, testLongitude, testLatitude, testRadius))
+	if !errors.Is(err, redis.ErrClosed) {
+		t.Errorf("Subscribe() error = %v, wantErr %v", err, redis.ErrClosed)
+	}
+}
+
+func testScooter(scooterUUID uuid.UUID, longitude, latitude float64) *redismodel.RedisScooter {
+	return redismodel.NewRedisScooter(
+		&redis.GeoLocation{Name: scooterUUID.String()},
+		&redis.GeoPos{Longitude: longitude, Latitude: latitude},
+		redismodel.StatusAvailable,
+		redismodel.FullBattery,
+	)
+}
+
+func testEvent(
+	eventType redismodel.ScooterEventType,
+	scooterUUID uuid.UUID,
+	longitude, latitude float64,
+) *redismodel.ScooterEvent {
+	return redismodel.NewScooterEvent(
+		eventType,
+		scooterUUID,
+		testCity,
+		longitude,
+		latitude,
+		redismodel.StatusAvailable,
+		redismodel.FullBattery,
+	)
+}
+
+func receive(t *testing.T, updates <-chan *redismodel.ScooterEvent) *redismodel.ScooterEvent {
+	t.Helper()
+
+	select {
+	case update, ok := <-updates:
+		require.True(t, ok, "updates closed too early")
+
+		return update
+	case <-time.After(testTimeout):
+		t.Fatal("no update received")
+
+		return nil
+	}
+}
+
+func requireClosed(t *testing.T, updates <-chan *redismodel.ScooterEvent) {
+	t.Helper()
+
+	select {
+	case _, ok := <-updates:
+		require.False(t, ok, "updates not closed")
+	case <-time.After(testTimeout):
+		t.Fatal("updates not closed")
+	}
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/domain"
+)
+
+type EventType string
+
+const (
+	TypeScooterRented        EventType = "scooter_rented"
+	TypeScooterFreed         EventType = "scooter_freed"
+	TypeScooterMoved         EventType = "scooter_moved"
+	TypeScooterStatusChanged EventType = "scooter_status_changed"
+	TypeRideErrored          EventType = "ride_errored"
+)
+
+var ErrUnknownEventType = domain.Validation("unknown_event_type", "event type is not known")
+
+// EventTypes lists every type of event published on the bus.
+var EventTypes = []EventType{
+	TypeScooterRented,
+	TypeScooterFreed,
+	TypeScooterMoved,
+	TypeScooterStatusChanged,
+	TypeRideErrored,
+}
+
+// IsValid reports whether events of the type are ever published.
+func (t EventType) IsValid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event is something that happened to the fleet, consumers tell the events apart by their type.
+type Event interface {
+	Type() EventType
+}
+
+// ScooterRented is published once the ride started and the scooter is tracked.
+type ScooterRented struct {
+	RentalID    uuid.UUID `json:"rentalID"`
+	ClientUUID  uuid.UUID `json:"clientUUID"`
+	ScooterUUID uuid.UUID `json:"scooterUUID"`
+	City        string    `json:"city"`
+	Longitude   float64   `json:"longitude"`
+	Latitude    float64   `json:"latitude"`
+	Battery     float64   `json:"battery"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+func NewScooterRented(
+	rentalID, clientUUID, scooterUUID uuid.UUID,
+	city string,
+	longitude, latitude, battery float64,
+	occurredAt time.Time,
+) *ScooterRented {
+	return &ScooterRented{
+		RentalID:    rentalID,
+		ClientUUID:  clientUUID,
+		ScooterUUID: scooterUUID,
+		City:        city,
+		Longitude:   longitude,
+		Latitude:    latitude,
+		Battery:     battery,
+		OccurredAt:  occurredAt,
+	}
+}
+
+func (e *ScooterRented) Type() EventType {
+	return TypeScooterRented
+}
+
+// ScooterFreed is published once the ride ended and was charged.
+type ScooterFreed struct {
+	RentalID    uuid.UUID     `json:"rentalID"`
+	ClientUUID  uuid.UUID     `json:"clientUUID"`
+	ScooterUUID uuid.UUID     `json:"scooterUUID"`
+	City        string        `json:"city"`
+	Duration    time.Duration `json:"duration"`
+	Distance    float64       `json:"distance"` // in meters
+	Fare        float64       `json:"fare"`
+	Currency    string        `json:"currency"`
+	OccurredAt  time.Time     `json:"occurredAt"`
+}
+
+func NewScooterFreed(
+	rentalID, clientUUID, scooterUUID uuid.UUID,
+	city string,
+	duration time.Duration,
+	distance, fare float64,
+	currency string,
+	occurredAt time.Time,
+) *ScooterFreed {
+	return &ScooterFreed{
+		RentalID:    rentalID,
+		ClientUUID:  clientUUID,
+		ScooterUUID: scooterUUID,
+		City:        city,
+		Duration:    duration,
+		Distance:    distance,
+		Fare:        fare,
+		Currency:    currency,
+		OccurredAt:  occurredAt,
+	}
+}
+
+func (e *ScooterFreed) Type() EventType {
+	return TypeScooterFreed
+}
+
+// ScooterMoved is published by the tracker on every move of the rented scooter.
+type ScooterMoved struct {
+	ScooterUUID uuid.UUID `json:"scooterUUID"`
+	City        string    `json:"city"`
+	Longitude   float64   `json:"longitude"`
+	Latitude    float64   `json:"latitude"`
+	Distance    float64   `json:"distance"` // in meters since the previous move
+	Battery     float64   `json:"battery"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+func NewScooterMoved(
+	scooterUUID uuid.UUID,
+	city string,
+	longitude, latitude, distance, battery float64,
+	occurredAt time.Time,
+) *ScooterMoved {
+	return &ScooterMoved{
+		ScooterUUID: scooterUUID,
+		City:        city,
+		Longitude:   longitude,
+		Latitude:    latitude,
+		Distance:    distance,
+		Battery:     battery,
+		OccurredAt:  occurredAt,
+	}
+}
+
+func (e *ScooterMoved) Type() EventType {
+	return TypeScooterMoved
+}
+
+// ScooterStatusChanged is published when renting, reserving or charging moves the scooter to another status.
+type ScooterStatusChanged struct {
+	ScooterUUID uuid.UUID `json:"scooterUUID"`
+	Status      string    `json:"status"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+func NewScooterStatusChanged(scooterUUID uuid.UUID, status string, occurredAt time.Time) *ScooterStatusChanged {
+	return &ScooterStatusChanged{
+		ScooterUUID: scooterUUID,
+		Status:      status,
+		OccurredAt:  occurredAt,
+	}
+}
+
+func (e *ScooterStatusChanged) Type() EventType {
+	return TypeScooterStatusChanged
+}
+
+// RideErrored is published when the tracker fails to store a move of the rented scooter.
+type RideErrored struct {
+	ScooterUUID uuid.UUID `json:"scooterUUID"`
+	Error       string    `json:"error"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+func NewRideErrored(scooterUUID uuid.UUID, err error, occurredAt time.Time) *RideErrored {
+	return &RideErrored{
+		ScooterUUID: scooterUUID,
+		Error:       err.Error(),
+		OccurredAt:  occurredAt,
+	}
+}
+
+func (e *RideErrored) Type() EventType {
+	return TypeRideErrored
+}
+
+// Encode turns the event into JSON, its type travels next to it.
+func Encode(event Event) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s event: %w", event.Type(), err)
+	}
+
+	return data, nil
+}
+
+// Decode builds the event of the given type back from its JSON.
+func Decode(eventType EventType, data []byte) (Event, error) {
+	var event Event
+
+	switch eventType {
+	case TypeScooterRented:
+		event = &ScooterRented{}
+	case TypeScooterFreed:
+		event = &ScooterFreed{}
+	case TypeScooterMoved:
+		event = &ScooterMoved{}
+	case TypeScooterStatusChanged:
+		event = &ScooterStatusChanged{}
+	case TypeRideErrored:
+		event = &RideErrored{}
+	default:
+		return nil, fmt.Errorf("decoding %q event: %w", eventType, ErrUnknownEventType)
+	}
+
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("decoding %s event: %w", eventType, err)
+	}
+
+	return event, nil
+}
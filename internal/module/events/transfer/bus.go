@@ -0,0 +1,20 @@
+package transfer
+
+import (
+	"context"
+
+	"scootinAboot/internal/module/events/model"
+)
+
+// Handler processes the event delivered to the consumer, the event is acknowledged when it returns no error.
+type Handler func(ctx context.Context, event model.Event) error
+
+//go:generate mockgen -source=bus.go -destination=mock/bus_mock.go -package=mock
+type EventBus interface {
+	// Publish records the event of a change that already happened, so it goes on even when ctx is canceled, ctx only
+	// relates the event to the request or ride that caused it.
+	Publish(ctx context.Context, event model.Event) error
+	// Subscribe delivers every event published after the group was created to one consumer of the group, until ctx
+	// is done. Each group gets all events, consumers of the same group share them.
+	Subscribe(ctx context.Context, group, consumer string, handler Handler) error
+}
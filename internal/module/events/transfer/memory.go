@@ -0,0 +1,81 @@
+package transfer
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/events/model"
+)
+
+const memoryGroupBufferSize = 64
+
+// memoryBus keeps the events within the process, it stands in for Redis in tests and remembers what was published.
+type memoryBus struct {
+	logger    *slog.Logger
+	mu        sync.Mutex
+	groups    map[string]chan model.Event
+	published []model.Event
+}
+
+func NewMemoryBus(logger *slog.Logger) *memoryBus {
+	return &memoryBus{
+		logger: logger,
+		groups: make(map[string]chan model.Event),
+	}
+}
+
+// Publish hands the event to every group, a group that fell memoryGroupBufferSize events behind misses it.
+func (mb *memoryBus) Publish(ctx context.Context, event model.Event) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	mb.published = append(mb.published, event)
+
+	for group, events := range mb.groups {
+		select {
+		case events <- event:
+		default:
+			mb.logger.Warn("dropping event, the buffer of its group is full", "event_type", event.Type(), "group", group)
+		}
+	}
+
+	return nil
+}
+
+func (mb *memoryBus) Subscribe(ctx context.Context, group, consumer string, handler Handler) error {
+	mb.mu.Lock()
+
+	events, ok := mb.groups[group]
+	if !ok {
+		events = make(chan model.Event, memoryGroupBufferSize)
+		mb.groups[group] = events
+	}
+
+	mb.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				if err := handler(ctx, event); err != nil {
+					mb.logger.Error("handling event failed", "group", group, "consumer", consumer,
+						"event_type", event.Type(), logging.KeyError, err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Published returns the events published so far in their order.
+func (mb *memoryBus) Published() []model.Event {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	return append([]model.Event(nil), mb.published...)
+}
@@ -0,0 +1,80 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/module/events/model"
+)
+
+const testTimeout = time.Second
+
+func TestMemoryBus(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mb := NewMemoryBus(logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	webhooks := make(chan model.Event, 2)
+	metrics := make(chan model.Event, 2)
+
+	collect := func(events chan model.Event) Handler {
+		return func(ctx context.Context, event model.Event) error {
+			events <- event
+
+			return nil
+		}
+	}
+
+	// Consumers of the same group share its events, each group gets all of them
+	require.NoError(t, mb.Subscribe(ctx, "webhooks", "first", collect(webhooks)))
+	require.NoError(t, mb.Subscribe(ctx, "webhooks", "second", collect(webhooks)))
+	require.NoError(t, mb.Subscribe(ctx, "metrics", "first", collect(metrics)))
+	require.NoError(t, mb.Subscribe(ctx, "failing", "first", func(ctx context.Context, event model.Event) error {
+		return errors.New("handler failed")
+	}))
+
+	rented := model.NewScooterRented(uuid.New(), uuid.New(), uuid.New(), "Montreal", 70, 60, 80, time.Now())
+	moved := model.NewScooterMoved(rented.ScooterUUID, "Montreal", 70, 60.001, 111, 79.8, time.Now())
+
+	require.NoError(t, mb.Publish(context.Background(), rented))
+	require.NoError(t, mb.Publish(context.Background(), moved))
+
+	for _, events := range []chan model.Event{webhooks, metrics} {
+		got := []model.Event{receive(t, events), receive(t, events)}
+
+		require.ElementsMatch(t, []model.Event{rented, moved}, got)
+	}
+
+	select {
+	case event := <-webhooks:
+		t.Fatalf("event %s delivered to the group twice", event.Type())
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.Equal(t, []model.Event{rented, moved}, mb.Published())
+}
+
+func receive(t *testing.T, events <-chan model.Event) model.Event {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(testTimeout):
+		t.Fatal("no event received")
+
+		return nil
+	}
+}
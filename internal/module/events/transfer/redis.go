@@ -0,0 +1,179 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/events/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redis "scootinAboot/internal/module/redis/transfer"
+)
+
+const (
+	readCount  = 10
+	readBlock  = 5 * time.Second
+	retryDelay = time.Second
+
+	// claimInterval is how often the messages of the consumers that are gone are claimed, claimIdle how long they
+	// have to wait unacknowledged first. It is well beyond the time a handler takes.
+	claimInterval = 30 * time.Second
+	claimIdle     = 5 * time.Minute
+)
+
+// redisBus keeps the events in a Redis stream, so consumers of other instances and services read them through
+// consumer groups.
+type redisBus struct {
+	logger    *slog.Logger
+	service   redis.RedisService
+	stream    string
+	maxLength int64
+}
+
+func NewRedisBus(logger *slog.Logger, service redis.RedisService, stream string, maxLength int64) *redisBus {
+	return &redisBus{
+		logger:    logger,
+		service:   service,
+		stream:    stream,
+		maxLength: maxLength,
+	}
+}
+
+func (rb *redisBus) Publish(ctx context.Context, event model.Event) error {
+	data, err := model.Encode(event)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	if _, err = rb.service.AppendToStream(ctx, rb.stream, rb.maxLength, string(event.Type()), data); err != nil {
+		return fmt.Errorf("publishing %s event: %w", event.Type(), err)
+	}
+
+	return nil
+}
+
+// Subscribe creates the group unless it exists and reads it in the background. An event whose handler failed is
+// left pending in the group, the consumer handles its own pending events again when it starts and claims the ones
+// idle for claimIdle, its own or of consumers that are gone, every claimInterval.
+func (rb *redisBus) Subscribe(ctx context.Context, group, consumer string, handler Handler) error {
+	if err := rb.service.CreateStreamGroup(ctx, rb.stream, group); err != nil {
+		return fmt.Errorf("subscribing to events: %w", err)
+	}
+
+	go rb.consume(ctx, group, consumer, handler)
+
+	return nil
+}
+
+func (rb *redisBus) consume(ctx context.Context, group, consumer string, handler Handler) {
+	rb.handlePending(ctx, group, consumer, handler)
+
+	claimed := time.Now()
+
+	for ctx.Err() == nil {
+		if time.Since(claimed) >= claimInterval {
+			rb.claimIdle(ctx, group, consumer, handler)
+
+			claimed = time.Now()
+		}
+
+		messages, err := rb.service.ReadStreamGroup(ctx, rb.stream, group, consumer, readCount, readBlock)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			rb.logger.Error("reading events failed", "group", group, "consumer", consumer, logging.KeyError, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+
+			continue
+		}
+
+		for _, message := range messages {
+			rb.handle(ctx, group, consumer, handler, message)
+		}
+	}
+}
+
+// handlePending handles the events the consumer read before it was restarted and never acknowledged.
+func (rb *redisBus) handlePending(ctx context.Context, group, consumer string, handler Handler) {
+	var from string
+
+	for ctx.Err() == nil {
+		messages, err := rb.service.ReadPendingStreamGroup(ctx, rb.stream, group, consumer, from, readCount)
+		if err != nil {
+			// They are claimed once they are idle long enough
+			rb.logger.Error("reading pending events failed", "group", group, "consumer", consumer, logging.KeyError, err)
+
+			return
+		}
+
+		if len(messages) == 0 {
+			return
+		}
+
+		for _, message := range messages {
+			rb.handle(ctx, group, consumer, handler, message)
+		}
+
+		from = messages[len(messages)-1].ID
+	}
+}
+
+// claimIdle takes over and handles the events that wait unacknowledged for claimIdle.
+func (rb *redisBus) claimIdle(ctx context.Context, group, consumer string, handler Handler) {
+	var from string
+
+	for ctx.Err() == nil {
+		messages, next, err := rb.service.ClaimStreamGroup(ctx, rb.stream, group, consumer, from, claimIdle, readCount)
+		if err != nil {
+			rb.logger.Error("claiming idle events failed", "group", group, "consumer", consumer, logging.KeyError, err)
+
+			return
+		}
+
+		for _, message := range messages {
+			rb.handle(ctx, group, consumer, handler, message)
+		}
+
+		if next == "" {
+			return
+		}
+
+		from = next
+	}
+}
+
+func (rb *redisBus) handle(
+	ctx context.Context,
+	group, consumer string,
+	handler Handler,
+	message *redismodel.StreamMessage,
+) {
+	event, err := model.Decode(model.EventType(message.Type), message.Data)
+	if err != nil {
+		// Handling it again won't help, it is acknowledged so it doesn't stay pending forever
+		rb.logger.Warn("skipping undecodable message", "group", group, "consumer", consumer,
+			"message_id", message.ID, logging.KeyError, err)
+	} else if err = handler(ctx, event); err != nil {
+		rb.logger.Error("handling event failed", "group", group, "consumer", consumer,
+			"event_type", event.Type(), "message_id", message.ID, logging.KeyError, err)
+
+		return
+	}
+
+	// The event was handled, so it is acknowledged even when the consumer is being stopped
+	if err = rb.service.AckStreamMessage(context.WithoutCancel(ctx), rb.stream, group, message.ID); err != nil {
+		rb.logger.Error("acknowledging message failed", "group", group, "consumer", consumer,
+			"message_id", message.ID, logging.KeyError, err)
+	}
+}
@@ -0,0 +1,211 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/module/events/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservicemock "scootinAboot/internal/module/redis/transfer/mock"
+)
+
+const (
+	testStream    = "fleet-events"
+	testMaxLength = 1000
+	testGroup     = "webhooks"
+	testConsumer  = "instance-1"
+)
+
+func TestRedisBusPublish(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	event := model.NewScooterStatusChanged(uuid.New(), "low_battery", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	data, err := model.Encode(event)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *redisservicemock.MockRedisService)
+		wantErr                 error
+	}{
+		"successfully published event": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().AppendToStream(gomock.Any(), testStream, int64(testMaxLength), "scooter_status_changed", data).
+					Return("1700000000000-0", nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"publishing event failed because redis is unavailable": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().AppendToStream(gomock.Any(), testStream, int64(testMaxLength), "scooter_status_changed", data).
+					Return("", redis.ErrClosed).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			rb := NewRedisBus(logger, mockRedisService, testStream, testMaxLength)
+
+			if err := rb.Publish(context.Background(), event); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisBusSubscribe(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	pending := model.NewScooterStatusChanged(uuid.New(), "rented", time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC))
+	handled := model.NewScooterStatusChanged(uuid.New(), "available", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	failed := model.NewScooterStatusChanged(uuid.New(), "reserved", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	message := func(id string, event model.Event) *redismodel.StreamMessage {
+		data, err := model.Encode(event)
+		require.NoError(t, err)
+
+		return redismodel.NewStreamMessage(id, string(event.Type()), data)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+
+	mockRedisService := redisservicemock.NewMockRedisService(controller)
+	mockRedisService.EXPECT().CreateStreamGroup(gomock.Any(), testStream, testGroup).Return(nil).Times(1)
+	gomock.InOrder(
+		// The event read before the restart is handled first
+		mockRedisService.EXPECT().ReadPendingStreamGroup(gomock.Any(), testStream, testGroup, testConsumer, "",
+			gomock.Any()).Return([]*redismodel.StreamMessage{message("0-1", pending)}, nil).Times(1),
+		mockRedisService.EXPECT().ReadPendingStreamGroup(gomock.Any(), testStream, testGroup, testConsumer, "0-1",
+			gomock.Any()).Return(nil, nil).Times(1),
+		mockRedisService.EXPECT().ReadStreamGroup(gomock.Any(), testStream, testGroup, testConsumer, gomock.Any(),
+			gomock.Any()).Return([]*redismodel.StreamMessage{
+			message("1-0", handled),
+			message("2-0", failed),
+			redismodel.NewStreamMessage("3-0", "unknown", []byte("{}")),
+		}, nil).Times(1),
+		// The next read waits for new events until the subscriber leaves
+		mockRedisService.EXPECT().ReadStreamGroup(gomock.Any(), testStream, testGroup, testConsumer, gomock.Any(),
+			gomock.Any()).DoAndReturn(func(
+			ctx context.Context,
+			stream, group, consumer string,
+			count int64,
+			block time.Duration,
+		) ([]*redismodel.StreamMessage, error) {
+			close(done)
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}).Times(1),
+	)
+	// The failed event stays pending, the one that can't be decoded is acknowledged to be skipped
+	mockRedisService.EXPECT().AckStreamMessage(gomock.Any(), testStream, testGroup, "0-1").Return(nil).Times(1)
+	mockRedisService.EXPECT().AckStreamMessage(gomock.Any(), testStream, testGroup, "1-0").Return(nil).Times(1)
+	mockRedisService.EXPECT().AckStreamMessage(gomock.Any(), testStream, testGroup, "3-0").Return(nil).Times(1)
+
+	var got []model.Event
+
+	rb := NewRedisBus(logger, mockRedisService, testStream, testMaxLength)
+
+	err := rb.Subscribe(ctx, testGroup, testConsumer, func(ctx context.Context, event model.Event) error {
+		got = append(got, event)
+
+		if event.(*model.ScooterStatusChanged).Status == "reserved" {
+			return errors.New("handler failed")
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(testTimeout):
+		t.Fatal("messages not consumed")
+	}
+
+	require.Equal(t, []model.Event{pending, handled, failed}, got)
+}
+
+func TestRedisBusClaimIdle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	first := model.NewScooterStatusChanged(uuid.New(), "available", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	second := model.NewScooterStatusChanged(uuid.New(), "reserved", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	message := func(id string, event model.Event) *redismodel.StreamMessage {
+		data, err := model.Encode(event)
+		require.NoError(t, err)
+
+		return redismodel.NewStreamMessage(id, string(event.Type()), data)
+	}
+
+	// The pending messages of the group are gone through page by page
+	mockRedisService := redisservicemock.NewMockRedisService(controller)
+	gomock.InOrder(
+		mockRedisService.EXPECT().ClaimStreamGroup(gomock.Any(), testStream, testGroup, testConsumer, "", claimIdle,
+			gomock.Any()).Return([]*redismodel.StreamMessage{message("1-0", first)}, "2-0", nil).Times(1),
+		mockRedisService.EXPECT().ClaimStreamGroup(gomock.Any(), testStream, testGroup, testConsumer, "2-0", claimIdle,
+			gomock.Any()).Return([]*redismodel.StreamMessage{message("2-0", second)}, "", nil).Times(1),
+	)
+	mockRedisService.EXPECT().AckStreamMessage(gomock.Any(), testStream, testGroup, "1-0").Return(nil).Times(1)
+	mockRedisService.EXPECT().AckStreamMessage(gomock.Any(), testStream, testGroup, "2-0").Return(nil).Times(1)
+
+	var got []model.Event
+
+	rb := NewRedisBus(logger, mockRedisService, testStream, testMaxLength)
+
+	rb.claimIdle(context.Background(), testGroup, testConsumer, func(ctx context.Context, event model.Event) error {
+		got = append(got, event)
+
+		return nil
+	})
+
+	require.Equal(t, []model.Event{first, second}, got)
+}
+
+func TestRedisBusSubscribeFailed(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRedisService := redisservicemock.NewMockRedisService(controller)
+	mockRedisService.EXPECT().CreateStreamGroup(gomock.Any(), testStream, testGroup).Return(redis.ErrClosed).Times(1)
+
+	rb := NewRedisBus(logger, mockRedisService, testStream, testMaxLength)
+
+	err := rb.Subscribe(context.Background(), testGroup, testConsumer, func(context.Context, model.Event) error {
+		return nil
+	})
+	if !errors.Is(err, redis.ErrClosed) {
+		t.Errorf("Subscribe() error = %v, wantErr %v", err, redis.ErrClosed)
+	}
+}
@@ -0,0 +1,117 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"scootinAboot/internal/domain"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redis "scootinAboot/internal/module/redis/transfer"
+)
+
+var (
+	ErrRequestInProgress = domain.Conflict(
+		"request_in_progress",
+		"request with the same idempotency key is still being handled",
+	)
+	ErrKeyReused = domain.Validation(
+		"idempotency_key_reused",
+		"idempotency key was already used for a different request",
+	)
+)
+
+//go:generate mockgen -source=service.go -destination=mock/idempotency_mock.go -package=mock
+type IdempotencyService interface {
+	Begin(ctx context.Context, key, fingerprint string) (*redismodel.IdempotentResponse, error)
+	Complete(ctx context.Context, key string, response *redismodel.IdempotentResponse) error
+	Abandon(ctx context.Context, key string) error
+}
+
+type idempotencyService struct {
+	logger       *slog.Logger
+	redisService redis.RedisService
+	ttl          time.Duration
+	pendingTTL   time.Duration // the key of a request that never completes is freed after it
+}
+
+func NewIdempotencyService(
+	logger *slog.Logger,
+	service redis.RedisService,
+	ttl, pendingTTL time.Duration,
+) *idempotencyService {
+	return &idempotencyService{
+		logger:       logger,
+		redisService: service,
+		ttl:          ttl,
+		pendingTTL:   pendingTTL,
+	}
+}
+
+// Begin claims the key for the request identified by the fingerprint. It returns nil when the request has to be
+// handled and the stored response when the same request was already handled. The claim expires after the pending
+// TTL, only the completed response is kept for the whole TTL.
+func (is *idempotencyService) Begin(
+	ctx context.Context,
+	key, fingerprint string) (*redismodel.IdempotentResponse, error,
+) {
+	claimed, err := is.redisService.ClaimIdempotencyKey(ctx, key, fingerprint, is.pendingTTL)
+	if err != nil {
+		return nil, fmt.Errorf("beginning idempotent request: %w", err)
+	}
+
+	if claimed {
+		return nil, nil
+	}
+
+	stored, err := is.redisService.GetIdempotentResponse(ctx, key)
+	if errors.Is(err, redismodel.ErrIdempotentResponseNotFound) {
+		// The key expired or was abandoned in the meantime, so it is claimed once more
+		claimed, err = is.redisService.ClaimIdempotencyKey(ctx, key, fingerprint, is.pendingTTL)
+		if err != nil {
+			return nil, fmt.Errorf("beginning idempotent request: %w", err)
+		}
+
+		if claimed {
+			return nil, nil
+		}
+
+		// Another request with the same key was quicker
+		return nil, ErrRequestInProgress
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("beginning idempotent request: %w", err)
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+
+	if !stored.Completed {
+		return nil, ErrRequestInProgress
+	}
+
+	return stored, nil
+}
+
+// Complete stores the response of the request so it is replayed for the requests with the same key, the key is kept
+// for the whole TTL from now on.
+func (is *idempotencyService) Complete(ctx context.Context, key string, response *redismodel.IdempotentResponse) error {
+	if err := is.redisService.SaveIdempotentResponse(ctx, key, response, is.ttl); err != nil {
+		return fmt.Errorf("completing idempotent request: %w", err)
+	}
+
+	return nil
+}
+
+// Abandon frees the key of the request that could not be handled, so the client can retry it.
+func (is *idempotencyService) Abandon(ctx context.Context, key string) error {
+	if err := is.redisService.ReleaseIdempotencyKey(ctx, key); err != nil {
+		return fmt.Errorf("abandoning idempotent request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservicemock "scootinAboot/internal/module/redis/transfer/mock"
+)
+
+const (
+	testKey         = "client:retry-1"
+	testFingerprint = "fingerprint"
+	testTTL         = 24 * time.Hour
+	testPendingTTL  = time.Minute
+)
+
+func TestBegin(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	completed := redismodel.NewIdempotentResponse(testFingerprint, 200, "application/json", []byte(`{}`))
+
+	tests := map[string]struct {
+		logger                  *slog.Logger
+		fingerprint             string
+		mockRedisServiceHandler func(mock *redisservicemock.MockRedisService)
+		want                    *redismodel.IdempotentResponse
+		wantErr                 error
+	}{
+		"successfully claiming new key": {
+			logger:      logger,
+			fingerprint: testFingerprint,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+					Return(true, nil).Times(1)
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		"successfully getting response of already handled request": {
+			logger:      logger,
+			fingerprint: testFingerprint,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+					Return(false, nil).Times(1)
+				mock.EXPECT().GetIdempotentResponse(gomock.Any(), testKey).Return(completed, nil).Times(1)
+			},
+			want:    completed,
+			wantErr: nil,
+		},
+		"failed beginning request because key was used for different request": {
+			logger:      logger,
+			fingerprint: "other",
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, "other", testPendingTTL).
+					Return(false, nil).Times(1)
+				mock.EXPECT().GetIdempotentResponse(gomock.Any(), testKey).Return(completed, nil).Times(1)
+			},
+			want:    nil,
+			wantErr: ErrKeyReused,
+		},
+		"failed beginning request because the first one is still being handled": {
+			logger:      logger,
+			fingerprint: testFingerprint,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+					Return(false, nil).Times(1)
+				mock.EXPECT().GetIdempotentResponse(gomock.Any(), testKey).
+					Return(redismodel.NewPendingResponse(testFingerprint), nil).Times(1)
+			},
+			want:    nil,
+			wantErr: ErrRequestInProgress,
+		},
+		"successfully claiming key expired after claim attempt": {
+			logger:      logger,
+			fingerprint: testFingerprint,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				gomock.InOrder(
+					mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+						Return(false, nil).Times(1),
+					mock.EXPECT().GetIdempotentResponse(gomock.Any(), testKey).
+						Return(nil, redismodel.ErrIdempotentResponseNotFound).Times(1),
+					mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+						Return(true, nil).Times(1),
+				)
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		"failed beginning request because key expired and was claimed by another request": {
+			logger:      logger,
+			fingerprint: testFingerprint,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+					Return(false, nil).Times(2)
+				mock.EXPECT().GetIdempotentResponse(gomock.Any(), testKey).
+					Return(nil, redismodel.ErrIdempotentResponseNotFound).Times(1)
+			},
+			want:    nil,
+			wantErr: ErrRequestInProgress,
+		},
+		"failed beginning request because redis service threw error": {
+			logger:      logger,
+			fingerprint: testFingerprint,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ClaimIdempotencyKey(gomock.Any(), testKey, testFingerprint, testPendingTTL).
+					Return(false, redis.ErrClosed).Times(1)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			is := NewIdempotencyService(tt.logger, mockRedisService, testTTL, testPendingTTL)
+
+			got, err := is.Begin(context.Background(), testKey, tt.fingerprint)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Begin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Begin() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+// Tariff holds the prices of a ride in a city, zero MaximumFare means the fare is not capped.
+type Tariff struct {
+	UnlockFee   float64 `json:"unlockFee"`
+	PerMinute   float64 `json:"perMinute"`
+	PerKm       float64 `json:"perKm"`
+	MinimumFare float64 `json:"minimumFare"`
+	MaximumFare float64 `json:"maximumFare"`
+	Currency    string  `json:"currency"`
+}
+
+// Fare is the breakdown of the price charged for a ride.
+type Fare struct {
+	UnlockFee    float64
+	TimeCost     float64
+	DistanceCost float64
+	Total        float64
+	Currency     string
+}
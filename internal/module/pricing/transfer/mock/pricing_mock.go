@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	model "scootinAboot/internal/module/pricing/model"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPricingService is a mock of PricingService interface.
+type MockPricingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingServiceMockRecorder
+}
+
+// MockPricingServiceMockRecorder is the mock recorder for MockPricingService.
+type MockPricingServiceMockRecorder struct {
+	mock *MockPricingService
+}
+
+// NewMockPricingService creates a new mock instance.
+func NewMockPricingService(ctrl *gomock.Controller) *MockPricingService {
+	mock := &MockPricingService{ctrl: ctrl}
+	mock.recorder = &MockPricingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricingService) EXPECT() *MockPricingServiceMockRecorder {
+	return m.recorder
+}
+
+// CalculateFare mocks base method.
+func (m *MockPricingService) CalculateFare(city string, duration time.Duration, distanceInMeters float64) (*model.Fare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateFare", city, duration, distanceInMeters)
+	ret0, _ := ret[0].(*model.Fare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalculateFare indicates an expected call of CalculateFare.
+func (mr *MockPricingServiceMockRecorder) CalculateFare(city, duration, distanceInMeters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateFare", reflect.TypeOf((*MockPricingService)(nil).CalculateFare), city, duration, distanceInMeters)
+}
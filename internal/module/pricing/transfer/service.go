@@ -0,0 +1,94 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"scootinAboot/internal/module/pricing/model"
+)
+
+const (
+	// DefaultTariff is the key of the tariff applied in cities without their own one.
+	DefaultTariff = "default"
+
+	metersInKilometer = 1000.0
+	centsInUnit       = 100.0
+)
+
+var ErrTariffNotFound = errors.New("there is no tariff for given city")
+
+//go:generate mockgen -source=service.go -destination=mock/pricing_mock.go -package=mock
+type PricingService interface {
+	CalculateFare(city string, duration time.Duration, distanceInMeters float64) (*model.Fare, error)
+}
+
+type pricingService struct {
+	logger  *slog.Logger
+	tariffs map[string]*model.Tariff
+}
+
+func NewPricingService(logger *slog.Logger, tariffs map[string]*model.Tariff) *pricingService {
+	return &pricingService{
+		logger:  logger,
+		tariffs: tariffs,
+	}
+}
+
+// CalculateFare prices the ride with city's tariff. Every started minute is charged, the total is raised to
+// the minimum fare and lowered to the maximum fare when the tariff has one.
+func (ps *pricingService) CalculateFare(city string, duration time.Duration, distanceInMeters float64) (*model.Fare, error) {
+	tariff, err := ps.tariff(city)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration < 0 {
+		duration = 0
+	}
+
+	if distanceInMeters < 0 {
+		distanceInMeters = 0
+	}
+
+	minutes := math.Ceil(duration.Minutes())
+
+	fare := &model.Fare{
+		UnlockFee:    roundToCents(tariff.UnlockFee),
+		TimeCost:     roundToCents(minutes * tariff.PerMinute),
+		DistanceCost: roundToCents(distanceInMeters / metersInKilometer * tariff.PerKm),
+		Currency:     tariff.Currency,
+	}
+
+	total := fare.UnlockFee + fare.TimeCost + fare.DistanceCost
+
+	if total < tariff.MinimumFare {
+		total = tariff.MinimumFare
+	}
+
+	if tariff.MaximumFare > 0 && total > tariff.MaximumFare {
+		total = tariff.MaximumFare
+	}
+
+	fare.Total = roundToCents(total)
+
+	return fare, nil
+}
+
+func (ps *pricingService) tariff(city string) (*model.Tariff, error) {
+	if tariff, ok := ps.tariffs[city]; ok {
+		return tariff, nil
+	}
+
+	if tariff, ok := ps.tariffs[DefaultTariff]; ok {
+		return tariff, nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", city, ErrTariffNotFound)
+}
+
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*centsInUnit) / centsInUnit
+}
@@ -0,0 +1,161 @@
+//go:build unit
+
+package transfer
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"scootinAboot/internal/module/pricing/model"
+)
+
+const (
+	testCity          = "Montreal"
+	testUnknownCity   = "Toronto"
+	testCurrency      = "CAD"
+	testOtherCurrency = "EUR"
+)
+
+func TestCalculateFare(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tariffs := map[string]*model.Tariff{
+		testCity: {
+			UnlockFee:   1,
+			PerMinute:   0.35,
+			PerKm:       0.1,
+			MinimumFare: 2,
+			MaximumFare: 30,
+			Currency:    testCurrency,
+		},
+		DefaultTariff: {
+			UnlockFee: 0.5,
+			PerMinute: 0.5,
+			Currency:  testOtherCurrency,
+		},
+	}
+
+	tests := map[string]struct {
+		tariffs  map[string]*model.Tariff
+		city     string
+		duration time.Duration
+		distance float64
+		want     *model.Fare
+		wantErr  error
+	}{
+		"ride is charged for time and distance": {
+			tariffs:  tariffs,
+			city:     testCity,
+			duration: 10 * time.Minute,
+			distance: 2500,
+			want: &model.Fare{
+				UnlockFee:    1,
+				TimeCost:     3.5,
+				DistanceCost: 0.25,
+				Total:        4.75,
+				Currency:     testCurrency,
+			},
+		},
+		"every started minute is charged": {
+			tariffs:  tariffs,
+			city:     testCity,
+			duration: 10*time.Minute + time.Second,
+			distance: 0,
+			want: &model.Fare{
+				UnlockFee: 1,
+				TimeCost:  3.85,
+				Total:     4.85,
+				Currency:  testCurrency,
+			},
+		},
+		"zero-length ride is charged with minimum fare": {
+			tariffs:  tariffs,
+			city:     testCity,
+			duration: 0,
+			distance: 0,
+			want: &model.Fare{
+				UnlockFee: 1,
+				Total:     2,
+				Currency:  testCurrency,
+			},
+		},
+		"negative duration and distance are treated as zero": {
+			tariffs:  tariffs,
+			city:     testCity,
+			duration: -time.Minute,
+			distance: -100,
+			want: &model.Fare{
+				UnlockFee: 1,
+				Total:     2,
+				Currency:  testCurrency,
+			},
+		},
+		"long ride is capped with maximum fare": {
+			tariffs:  tariffs,
+			city:     testCity,
+			duration: 3 * time.Hour,
+			distance: 40000,
+			want: &model.Fare{
+				UnlockFee:    1,
+				TimeCost:     63,
+				DistanceCost: 4,
+				Total:        30,
+				Currency:     testCurrency,
+			},
+		},
+		"costs are rounded to cents": {
+			tariffs:  tariffs,
+			city:     testCity,
+			duration: 5 * time.Minute,
+			distance: 1234,
+			want: &model.Fare{
+				UnlockFee:    1,
+				TimeCost:     1.75,
+				DistanceCost: 0.12,
+				Total:        2.87,
+				Currency:     testCurrency,
+			},
+		},
+		"city without own tariff is charged with default one without cap": {
+			tariffs:  tariffs,
+			city:     testUnknownCity,
+			duration: 100 * time.Minute,
+			distance: 1000,
+			want: &model.Fare{
+				UnlockFee: 0.5,
+				TimeCost:  50,
+				Total:     50.5,
+				Currency:  testOtherCurrency,
+			},
+		},
+		"calculating fare failed, because there is no tariff for the city": {
+			tariffs: map[string]*model.Tariff{
+				testCity: tariffs[testCity],
+			},
+			city:     testUnknownCity,
+			duration: time.Minute,
+			distance: 0,
+			want:     nil,
+			wantErr:  ErrTariffNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps := NewPricingService(logger, tt.tariffs)
+
+			got, err := ps.CalculateFare(tt.city, tt.duration, tt.distance)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CalculateFare() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CalculateFare() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"scootinAboot/internal/module/pricing/model"
+)
+
+var ErrInvalidTariff = errors.New("tariff can't have negative prices or minimum fare above maximum fare")
+
+// LoadTariffs reads tariffs keyed by city from JSON file, DefaultTariff key holds the fallback tariff.
+func LoadTariffs(path string) (map[string]*model.Tariff, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading tariffs file: %w", err)
+	}
+
+	var tariffs map[string]*model.Tariff
+
+	if err = json.Unmarshal(content, &tariffs); err != nil {
+		return nil, fmt.Errorf("decoding tariffs: %w", err)
+	}
+
+	for city, tariff := range tariffs {
+		if err = validateTariff(tariff); err != nil {
+			return nil, fmt.Errorf("validating tariff of %s: %w", city, err)
+		}
+	}
+
+	return tariffs, nil
+}
+
+func validateTariff(tariff *model.Tariff) error {
+	if tariff == nil {
+		return ErrInvalidTariff
+	}
+
+	if tariff.UnlockFee < 0 || tariff.PerMinute < 0 || tariff.PerKm < 0 ||
+		tariff.MinimumFare < 0 || tariff.MaximumFare < 0 {
+		return ErrInvalidTariff
+	}
+
+	if tariff.MaximumFare > 0 && tariff.MinimumFare > tariff.MaximumFare {
+		return ErrInvalidTariff
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build unit
+
+package transfer
+
+import (
+	"reflect"
+	"testing"
+
+	"scootinAboot/internal/module/pricing/model"
+)
+
+func TestLoadTariffs(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		want    map[string]*model.Tariff
+		wantErr bool
+	}{
+		"successfully loading tariffs": {
+			path: "test_tariffs/valid_tariffs.json",
+			want: map[string]*model.Tariff{
+				DefaultTariff: {
+					UnlockFee: 1,
+					PerMinute: 0.35,
+					Currency:  testCurrency,
+				},
+				testCity: {
+					UnlockFee:   1,
+					PerMinute:   0.35,
+					PerKm:       0.05,
+					MinimumFare: 1.5,
+					MaximumFare: 30,
+					Currency:    testCurrency,
+				},
+			},
+			wantErr: false,
+		},
+		"loading tariffs failed, because file does not exist": {
+			path:    "test_tariffs/missing_tariffs.json",
+			want:    nil,
+			wantErr: true,
+		},
+		"loading tariffs failed, because file is not a valid json": {
+			path:    "test_tariffs/invalid_tariffs.json",
+			want:    nil,
+			wantErr: true,
+		},
+		"loading tariffs failed, because tariff has negative price": {
+			path:    "test_tariffs/negative_tariffs.json",
+			want:    nil,
+			wantErr: true,
+		},
+		"loading tariffs failed, because minimum fare is above maximum fare": {
+			path:    "test_tariffs/inverted_fares_tariffs.json",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadTariffs(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadTariffs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadTariffs() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
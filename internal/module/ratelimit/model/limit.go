@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Limit allows Requests requests per WindowSeconds to every caller of a route, bursts of up to Requests requests
+// included. Zero Requests turns the limit of the route off.
+type Limit struct {
+	Requests      int `json:"requests"`
+	WindowSeconds int `json:"windowSeconds"`
+}
+
+func (l *Limit) Window() time.Duration {
+	return time.Duration(l.WindowSeconds) * time.Second
+}
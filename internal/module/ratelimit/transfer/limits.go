@@ -0,0 +1,47 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"scootinAboot/internal/module/ratelimit/model"
+)
+
+var ErrInvalidLimit = errors.New("limit can't allow negative requests and has to have a positive window")
+
+// LoadLimits reads limits keyed by the operation ID of the route from JSON file, DefaultLimit key holds the limit of
+// the routes without their own one.
+func LoadLimits(path string) (map[string]*model.Limit, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading limits file: %w", err)
+	}
+
+	var limits map[string]*model.Limit
+
+	if err = json.Unmarshal(content, &limits); err != nil {
+		return nil, fmt.Errorf("decoding limits: %w", err)
+	}
+
+	for route, limit := range limits {
+		if err = validateLimit(limit); err != nil {
+			return nil, fmt.Errorf("validating limit of %s: %w", route, err)
+		}
+	}
+
+	return limits, nil
+}
+
+func validateLimit(limit *model.Limit) error {
+	if limit == nil || limit.Requests < 0 {
+		return ErrInvalidLimit
+	}
+
+	if limit.Requests > 0 && limit.WindowSeconds <= 0 {
+		return ErrInvalidLimit
+	}
+
+	return nil
+}
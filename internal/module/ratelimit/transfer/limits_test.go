@@ -0,0 +1,61 @@
+//go:build unit
+
+package transfer
+
+import (
+	"reflect"
+	"testing"
+
+	"scootinAboot/internal/module/ratelimit/model"
+)
+
+func TestLoadLimits(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		want    map[string]*model.Limit
+		wantErr bool
+	}{
+		"successfully loading limits": {
+			path: "test_limits/valid_limits.json",
+			want: map[string]*model.Limit{
+				DefaultLimit:     {Requests: 60, WindowSeconds: 60},
+				testRoute:        {Requests: 10, WindowSeconds: 1},
+				"streamScooters": {Requests: 0},
+			},
+			wantErr: false,
+		},
+		"loading limits failed, because file does not exist": {
+			path:    "test_limits/missing_limits.json",
+			want:    nil,
+			wantErr: true,
+		},
+		"loading limits failed, because file is not a valid json": {
+			path:    "test_limits/invalid_limits.json",
+			want:    nil,
+			wantErr: true,
+		},
+		"loading limits failed, because limit allows negative requests": {
+			path:    "test_limits/negative_limits.json",
+			want:    nil,
+			wantErr: true,
+		},
+		"loading limits failed, because limit has no window": {
+			path:    "test_limits/windowless_limits.json",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadLimits(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadLimits() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadLimits() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
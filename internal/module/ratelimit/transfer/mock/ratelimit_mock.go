@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	model "scootinAboot/internal/module/redis/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRateLimitService is a mock of RateLimitService interface.
+type MockRateLimitService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitServiceMockRecorder
+}
+
+// MockRateLimitServiceMockRecorder is the mock recorder for MockRateLimitService.
+type MockRateLimitServiceMockRecorder struct {
+	mock *MockRateLimitService
+}
+
+// NewMockRateLimitService creates a new mock instance.
+func NewMockRateLimitService(ctrl *gomock.Controller) *MockRateLimitService {
+	mock := &MockRateLimitService{ctrl: ctrl}
+	mock.recorder = &MockRateLimitServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitService) EXPECT() *MockRateLimitServiceMockRecorder {
+	return m.recorder
+}
+
+// Take mocks base method.
+func (m *MockRateLimitService) Take(ctx context.Context, route, caller string) (*model.RateLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, route, caller)
+	ret0, _ := ret[0].(*model.RateLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockRateLimitServiceMockRecorder) Take(ctx, route, caller interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockRateLimitService)(nil).Take), ctx, route, caller)
+}
@@ -0,0 +1,60 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/ratelimit/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redis "scootinAboot/internal/module/redis/transfer"
+)
+
+// DefaultLimit is the key of the limit applied to the routes without their own one.
+const DefaultLimit = "default"
+
+var ErrRateLimited = domain.RateLimited("rate_limited", "too many requests, retry after the time in Retry-After")
+
+//go:generate mockgen -source=service.go -destination=mock/ratelimit_mock.go -package=mock
+type RateLimitService interface {
+	Take(ctx context.Context, route, caller string) (*redismodel.RateLimit, error)
+}
+
+type rateLimitService struct {
+	logger       *slog.Logger
+	redisService redis.RedisService
+	limits       map[string]*model.Limit
+}
+
+func NewRateLimitService(
+	logger *slog.Logger,
+	service redis.RedisService,
+	limits map[string]*model.Limit,
+) *rateLimitService {
+	return &rateLimitService{
+		logger:       logger,
+		redisService: service,
+		limits:       limits,
+	}
+}
+
+// Take takes a token from the bucket the caller has for the route, every caller gets its own bucket for every route.
+// It returns nil when the route isn't limited.
+func (rs *rateLimitService) Take(ctx context.Context, route, caller string) (*redismodel.RateLimit, error) {
+	limit, ok := rs.limits[route]
+	if !ok {
+		limit, ok = rs.limits[DefaultLimit]
+	}
+
+	if !ok || limit.Requests == 0 {
+		return nil, nil
+	}
+
+	rateLimit, err := rs.redisService.TakeRateLimitToken(ctx, route+":"+caller, limit.Requests, limit.Window())
+	if err != nil {
+		return nil, fmt.Errorf("taking token of %s: %w", route, err)
+	}
+
+	return rateLimit, nil
+}
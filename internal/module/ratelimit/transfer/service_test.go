@@ -0,0 +1,95 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/ratelimit/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservicemock "scootinAboot/internal/module/redis/transfer/mock"
+)
+
+const (
+	testRoute  = "getScooters"
+	testCaller = "0b9e7210-aad2-4af8-8b23-a1853631f053"
+)
+
+func TestTake(t *testing.T) {
+	allowed := &redismodel.RateLimit{Allowed: true, Limit: 10, Remaining: 9, Window: time.Second}
+
+	tests := map[string]struct {
+		limits                  map[string]*model.Limit
+		mockRedisServiceHandler func(mock *redisservicemock.MockRedisService)
+		want                    *redismodel.RateLimit
+		wantErr                 error
+	}{
+		"taking token by the limit of the route": {
+			limits: map[string]*model.Limit{
+				DefaultLimit: {Requests: 60, WindowSeconds: 60},
+				testRoute:    {Requests: 10, WindowSeconds: 1},
+			},
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().TakeRateLimitToken(gomock.Any(), testRoute+":"+testCaller, 10, time.Second).
+					Return(allowed, nil).Times(1)
+			},
+			want: allowed,
+		},
+		"taking token by the default limit": {
+			limits: map[string]*model.Limit{
+				DefaultLimit: {Requests: 60, WindowSeconds: 60},
+			},
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().TakeRateLimitToken(gomock.Any(), testRoute+":"+testCaller, 60, time.Minute).
+					Return(allowed, nil).Times(1)
+			},
+			want: allowed,
+		},
+		"not taking token, because the limit of the route is turned off": {
+			limits: map[string]*model.Limit{
+				DefaultLimit: {Requests: 60, WindowSeconds: 60},
+				testRoute:    {Requests: 0},
+			},
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {},
+			want:                    nil,
+		},
+		"not taking token, because there are no limits": {
+			limits:                  map[string]*model.Limit{},
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {},
+			want:                    nil,
+		},
+		"taking token failed, because redis is unavailable": {
+			limits: map[string]*model.Limit{
+				DefaultLimit: {Requests: 60, WindowSeconds: 60},
+			},
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().TakeRateLimitToken(gomock.Any(), testRoute+":"+testCaller, 60, time.Minute).
+					Return(nil, domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			rs := NewRateLimitService(logging.Discard(), mockRedisService, tt.limits)
+
+			got, err := rs.Take(context.Background(), testRoute, testCaller)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
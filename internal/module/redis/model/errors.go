@@ -0,0 +1,20 @@
+package model
+
+import "scootinAboot/internal/domain"
+
+var (
+	ErrScooterNotFound     = domain.NotFound("scooter_not_found", "scooter with given UUID was not found")
+	ErrReservationNotFound = domain.NotFound("reservation_not_found", "reservation for given scooter was not found")
+	ErrRentalNotFound      = domain.NotFound("rental_not_found", "rental was not found")
+	ErrInvalidCursor       = domain.Validation("invalid_cursor", "pagination cursor is malformed")
+
+	ErrWebhookSubscriptionNotFound = domain.NotFound(
+		"webhook_subscription_not_found",
+		"webhook subscription with given ID was not found",
+	)
+
+	ErrIdempotentResponseNotFound = domain.NotFound(
+		"idempotent_response_not_found",
+		"response for given idempotency key was not found",
+	)
+)
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScooterEventType tells what happened to the scooter.
+type ScooterEventType string
+
+const (
+	ScooterAdded         ScooterEventType = "added"
+	ScooterMoved         ScooterEventType = "moved"
+	ScooterStatusChanged ScooterEventType = "status_changed"
+	ScooterRemoved       ScooterEventType = "removed"
+)
+
+// ScooterEvent is published whenever scooter's location or status changes, it carries the state of the scooter
+// after the change.
+type ScooterEvent struct {
+	Type        ScooterEventType `json:"type"`
+	ScooterUUID uuid.UUID        `json:"scooterUUID"`
+	City        string           `json:"city"`
+	Longitude   float64          `json:"longitude"`
+	Latitude    float64          `json:"latitude"`
+	Status      ScooterStatus    `json:"status,omitempty"`
+	Battery     float64          `json:"battery"`
+	OccurredAt  time.Time        `json:"occurredAt"`
+}
+
+func NewScooterEvent(
+	eventType ScooterEventType,
+	scooterUUID uuid.UUID,
+	city string,
+	longitude, latitude float64,
+	status ScooterStatus,
+	battery float64,
+) *ScooterEvent {
+	return &ScooterEvent{
+		Type:        eventType,
+		ScooterUUID: scooterUUID,
+		City:        city,
+		Longitude:   longitude,
+		Latitude:    latitude,
+		Status:      status,
+		Battery:     battery,
+		OccurredAt:  time.Now().UTC(),
+	}
+}
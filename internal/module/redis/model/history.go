@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// RentalsQuery narrows the ride history down to the rides started within [From, To], zero times leave the range open.
+type RentalsQuery struct {
+	From   time.Time
+	To     time.Time
+	Cursor string
+	Limit  int
+}
+
+func NewRentalsQuery(from, to time.Time, cursor string, limit int) *RentalsQuery {
+	return &RentalsQuery{
+		From:   from,
+		To:     to,
+		Cursor: cursor,
+		Limit:  limit,
+	}
+}
+
+// RentalsPage is a single page of the ride history, newest ride first, NextCursor is empty on the last page.
+type RentalsPage struct {
+	Rentals    []*Rental
+	NextCursor string
+}
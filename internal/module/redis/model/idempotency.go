@@ -0,0 +1,27 @@
+package model
+
+// IdempotentResponse is the response stored under an idempotency key. It is pending until the first request
+// carrying the key is handled, Fingerprint identifies that request so the key can't be reused for a different one.
+type IdempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+func NewPendingResponse(fingerprint string) *IdempotentResponse {
+	return &IdempotentResponse{
+		Fingerprint: fingerprint,
+	}
+}
+
+func NewIdempotentResponse(fingerprint string, statusCode int, contentType string, body []byte) *IdempotentResponse {
+	return &IdempotentResponse{
+		Fingerprint: fingerprint,
+		Completed:   true,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+	}
+}
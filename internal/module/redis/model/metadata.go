@@ -0,0 +1,61 @@
+package model
+
+// ScooterStatus describes the state of a scooter within the fleet.
+type ScooterStatus string
+
+const (
+	StatusAvailable    ScooterStatus = "available"
+	StatusRented       ScooterStatus = "rented"
+	StatusReserved     ScooterStatus = "reserved"
+	StatusMaintenance  ScooterStatus = "maintenance"
+	StatusLowBattery   ScooterStatus = "low_battery"
+	StatusOutOfService ScooterStatus = "out_of_service"
+	StatusRetired      ScooterStatus = "retired"
+	StatusLost         ScooterStatus = "lost"
+)
+
+// ScooterStatuses lists every known status, e.g. to describe them in the API specification.
+var ScooterStatuses = []ScooterStatus{
+	StatusAvailable, StatusRented, StatusReserved, StatusMaintenance,
+	StatusLowBattery, StatusOutOfService, StatusRetired, StatusLost,
+}
+
+// IsValid reports whether the status is one of the known statuses.
+func (s ScooterStatus) IsValid() bool {
+	switch s {
+	case StatusAvailable, StatusRented, StatusReserved, StatusMaintenance,
+		StatusLowBattery, StatusOutOfService, StatusRetired, StatusLost:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAssignable reports whether the status can be set by fleet management, rented and reserved statuses are
+// owned by the rental process.
+func (s ScooterStatus) IsAssignable() bool {
+	return s.IsValid() && s != StatusRented && s != StatusReserved
+}
+
+// IsAvailable reports whether the scooter can be rented, it is what old clients know as availability.
+func (s ScooterStatus) IsAvailable() bool {
+	return s == StatusAvailable
+}
+
+// FullBattery is the battery level, in percents, of a fully charged scooter.
+const FullBattery = 100.0
+
+// ScooterMetadata holds the fleet information stored alongside scooter's location.
+type ScooterMetadata struct {
+	City    string
+	Status  ScooterStatus
+	Battery float64 // in percents
+}
+
+func NewScooterMetadata(city string, status ScooterStatus, battery float64) *ScooterMetadata {
+	return &ScooterMetadata{
+		City:    city,
+		Status:  status,
+		Battery: battery,
+	}
+}
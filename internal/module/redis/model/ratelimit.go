@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// RateLimit is the state of the bucket a request took its token from. Limit tokens are refilled over Window, Reset is
+// how long it takes to refill the bucket completely and RetryAfter how long the refused request has to wait.
+type RateLimit struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Window     time.Duration
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RentalEvent is something noteworthy that happened during the ride, e.g. entering a slow zone.
+type RentalEvent struct {
+	Type       string    `json:"type"`
+	Zone       string    `json:"zone,omitempty"`
+	Longitude  float64   `json:"longitude"`
+	Latitude   float64   `json:"latitude"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// Rental is the record of a ride, end of the ride, distance and fare are filled once the scooter is freed.
+type Rental struct {
+	ID          uuid.UUID
+	ClientUUID  uuid.UUID
+	ScooterUUID uuid.UUID
+	City        string
+	StartedAt   time.Time
+	EndedAt     time.Time
+	Distance    float64 // in meters
+	Fare        float64
+	Currency    string
+	Events      []RentalEvent
+}
+
+func NewRental(id, clientUUID, scooterUUID uuid.UUID, city string, startedAt time.Time) *Rental {
+	return &Rental{
+		ID:          id,
+		ClientUUID:  clientUUID,
+		ScooterUUID: scooterUUID,
+		City:        city,
+		StartedAt:   startedAt,
+	}
+}
+
+// Duration returns how long the ride took, it is zero for the ride that did not end yet.
+func (r *Rental) Duration() time.Duration {
+	if r.EndedAt.IsZero() {
+		return 0
+	}
+
+	return r.EndedAt.Sub(r.StartedAt)
+}
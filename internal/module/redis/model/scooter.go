@@ -3,17 +3,45 @@ package model
 import "github.com/redis/go-redis/v9"
 
 type RedisScooter struct {
-	Scooter      *redis.GeoLocation
-	Availability bool
+	Scooter *redis.GeoLocation
+	Status  ScooterStatus
+	Battery float64
 }
 
-func NewRedisScooter(location *redis.GeoLocation, coords *redis.GeoPos, availability bool) *RedisScooter {
+func NewRedisScooter(
+	location *redis.GeoLocation,
+	coords *redis.GeoPos,
+	status ScooterStatus,
+	battery float64,
+) *RedisScooter {
 	return &RedisScooter{
 		Scooter: &redis.GeoLocation{
 			Name:      location.Name,
 			Longitude: coords.Longitude,
 			Latitude:  coords.Latitude,
 		},
-		Availability: availability,
+		Status:  status,
+		Battery: battery,
+	}
+}
+
+// FleetScooter is the full view of a scooter used for fleet management.
+type FleetScooter struct {
+	Scooter *redis.GeoLocation
+	City    string
+	Status  ScooterStatus
+	Battery float64
+}
+
+func NewFleetScooter(location *redis.GeoLocation, coords *redis.GeoPos, metadata *ScooterMetadata) *FleetScooter {
+	return &FleetScooter{
+		Scooter: &redis.GeoLocation{
+			Name:      location.Name,
+			Longitude: coords.Longitude,
+			Latitude:  coords.Latitude,
+		},
+		City:    metadata.City,
+		Status:  metadata.Status,
+		Battery: metadata.Battery,
 	}
 }
@@ -0,0 +1,16 @@
+package model
+
+// StreamMessage is an entry of a Redis stream, Type tells the consumer how to decode Data.
+type StreamMessage struct {
+	ID   string
+	Type string
+	Data []byte
+}
+
+func NewStreamMessage(id, messageType string, data []byte) *StreamMessage {
+	return &StreamMessage{
+		ID:   id,
+		Type: messageType,
+		Data: data,
+	}
+}
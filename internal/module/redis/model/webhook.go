@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription sends the events of given types to the URL, deliveries are signed with the secret.
+type WebhookSubscription struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func NewWebhookSubscription(
+	id uuid.UUID,
+	url, secret string,
+	eventTypes []string,
+	createdAt time.Time,
+) *WebhookSubscription {
+	return &WebhookSubscription{
+		ID:         id,
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		CreatedAt:  createdAt,
+	}
+}
+
+// Wants reports whether the subscription asked for events of the type.
+func (ws *WebhookSubscription) Wants(eventType string) bool {
+	for _, wanted := range ws.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDeadLetter is a delivery the endpoint of the subscription refused for good or didn't accept within the
+// retries, the payload is kept so it can be looked into and sent again by hand.
+type WebhookDeadLetter struct {
+	ID             uuid.UUID `json:"id"`
+	SubscriptionID uuid.UUID `json:"subscriptionID"`
+	URL            string    `json:"url"`
+	EventType      string    `json:"eventType"`
+	Payload        []byte    `json:"payload"`
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"lastError"`
+	FailedAt       time.Time `json:"failedAt"`
+}
+
+func NewWebhookDeadLetter(
+	id uuid.UUID,
+	subscription *WebhookSubscription,
+	eventType string,
+	payload []byte,
+	attempts int,
+	lastError string,
+	failedAt time.Time,
+) *WebhookDeadLetter {
+	return &WebhookDeadLetter{
+		ID:             id,
+		SubscriptionID: subscription.ID,
+		URL:            subscription.URL,
+		EventType:      eventType,
+		Payload:        payload,
+		Attempts:       attempts,
+		LastError:      lastError,
+		FailedAt:       failedAt,
+	}
+}
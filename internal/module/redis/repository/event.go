@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const scooterEventsChannelPrefix = "scooter-events:"
+
+// PublishScooterEvent sends the event to the subscribers of scooter's city.
+func (rr *redisRepository) PublishScooterEvent(ctx context.Context, event *model.ScooterEvent) error {
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding scooter event: %w", err)
+	}
+
+	if err = rr.client.Publish(ctx, scooterEventsChannel(event.City), encoded).Err(); err != nil {
+		return fmt.Errorf("publishing scooter event: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+// SubscribeScooterEvents streams events published for the city until ctx is done, the channel is closed then.
+// Messages that can't be decoded are skipped.
+func (rr *redisRepository) SubscribeScooterEvents(
+	ctx context.Context,
+	city string,
+) (<-chan *model.ScooterEvent, error) {
+	pubSub := rr.client.Subscribe(ctx, scooterEventsChannel(city))
+
+	// Subscribing is confirmed by the first reply, so a broken connection is reported to the caller right away
+	if _, err := pubSub.Receive(ctx); err != nil {
+		_ = pubSub.Close()
+
+		return nil, fmt.Errorf("subscribing to scooter events: %w", domain.Unavailable(err))
+	}
+
+	events := make(chan *model.ScooterEvent)
+
+	go func() {
+		defer close(events)
+
+		defer func() {
+			if err := pubSub.Close(); err != nil {
+				rr.logger.Warn("closing scooter events subscription failed", logging.KeyError, err)
+			}
+		}()
+
+		messages := pubSub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event model.ScooterEvent
+
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					rr.logger.Warn("decoding scooter event failed", logging.KeyError, err)
+
+					continue
+				}
+
+				select {
+				case events <- &event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func scooterEventsChannel(city string) string {
+	return scooterEventsChannelPrefix + city
+}
@@ -0,0 +1,70 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+func TestPublishScooterEvent(t *testing.T) {
+	logger := logging.Discard()
+
+	event := &model.ScooterEvent{
+		Type:        model.ScooterMoved,
+		ScooterUUID: uuid.New(),
+		City:        "Montreal",
+		Longitude:   70,
+		Latitude:    60,
+		Status:      model.StatusRented,
+		Battery:     model.FullBattery,
+		OccurredAt:  time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	encoded, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockPublish func(mock redismock.ClientMock)
+		wantErr     error
+	}{
+		"publishing scooter event successfully": {
+			mockPublish: func(mock redismock.ClientMock) {
+				mock.ExpectPublish(scooterEventsChannelPrefix+"Montreal", encoded).SetVal(1)
+			},
+			wantErr: nil,
+		},
+		"publishing scooter event failed, because of redis Publish error": {
+			mockPublish: func(mock redismock.ClientMock) {
+				mock.ExpectPublish(scooterEventsChannelPrefix+"Montreal", encoded).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockPublish(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			if err := rr.PublishScooterEvent(context.Background(), event); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PublishScooterEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"scootinAboot/internal/domain"
+)
+
+func (rr *redisRepository) Ping(ctx context.Context) error {
+	if err := rr.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("pinging redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+)
+
+func TestPing(t *testing.T) {
+	tests := map[string]struct {
+		mockPing        func(mock redismock.ClientMock)
+		wantErr         error
+		wantUnavailable bool
+	}{
+		"redis answers the ping": {
+			mockPing: func(mock redismock.ClientMock) {
+				mock.ExpectPing().SetVal("PONG")
+			},
+			wantErr: nil,
+		},
+		"redis doesn't answer the ping": {
+			mockPing: func(mock redismock.ClientMock) {
+				mock.ExpectPing().SetErr(redis.ErrClosed)
+			},
+			wantErr:         redis.ErrClosed,
+			wantUnavailable: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockPing(mock)
+
+			rr := NewRedisRepository(logging.Discard(), db)
+
+			err := rr.Ping(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			domainErr, ok := domain.As(err)
+			require.Equal(t, tt.wantUnavailable, ok && domainErr.Kind == domain.KindUnavailable)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// SetIdempotentResponse stores the response under the key unless there already is one, the key expires after ttl.
+// It reports false when the key is already taken.
+func (rr *redisRepository) SetIdempotentResponse(
+	ctx context.Context,
+	key string, response *model.IdempotentResponse, ttl time.Duration) (bool, error,
+) {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return false, fmt.Errorf("encoding idempotent response: %w", err)
+	}
+
+	ok, err := rr.client.SetNX(ctx, idempotencyKey(key), encoded, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("setting idempotent response in redis: %w", domain.Unavailable(err))
+	}
+
+	return ok, nil
+}
+
+// ReplaceIdempotentResponse overwrites the response stored under the key, the key expires after ttl.
+func (rr *redisRepository) ReplaceIdempotentResponse(
+	ctx context.Context,
+	key string, response *model.IdempotentResponse, ttl time.Duration,
+) error {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("encoding idempotent response: %w", err)
+	}
+
+	if err = rr.client.Set(ctx, idempotencyKey(key), encoded, ttl).Err(); err != nil {
+		return fmt.Errorf("replacing idempotent response in redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+func (rr *redisRepository) GetIdempotentResponse(ctx context.Context, key string) (*model.IdempotentResponse, error) {
+	encoded, err := rr.client.Get(ctx, idempotencyKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, model.ErrIdempotentResponseNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("getting idempotent response from redis: %w", domain.Unavailable(err))
+	}
+
+	var response model.IdempotentResponse
+
+	if err = json.Unmarshal(encoded, &response); err != nil {
+		return nil, fmt.Errorf("decoding idempotent response: %w", err)
+	}
+
+	return &response, nil
+}
+
+func (rr *redisRepository) DeleteIdempotentResponse(ctx context.Context, key string) error {
+	if err := rr.client.Del(ctx, idempotencyKey(key)).Err(); err != nil {
+		return fmt.Errorf("deleting idempotent response from redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+func idempotencyKey(key string) string {
+	return idempotencyKeyPrefix + key
+}
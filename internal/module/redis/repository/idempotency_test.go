@@ -0,0 +1,147 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	testIdempotencyKey = "client:retry-1"
+	testIdempotencyTTL = 24 * time.Hour
+	testFingerprint    = "fingerprint"
+)
+
+func TestSetIdempotentResponse(t *testing.T) {
+	logger := logging.Discard()
+
+	pending := model.NewPendingResponse(testFingerprint)
+
+	encoded, err := json.Marshal(pending)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		logger    *slog.Logger
+		mockSetNX func(mock redismock.ClientMock)
+		want      bool
+		wantErr   bool
+	}{
+		"setting idempotent response successfully": {
+			logger: logger,
+			mockSetNX: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX(idempotencyKeyPrefix+testIdempotencyKey, encoded, testIdempotencyTTL).SetVal(true)
+			},
+			want:    true,
+			wantErr: false,
+		},
+		"setting idempotent response refused, because key is already taken": {
+			logger: logger,
+			mockSetNX: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX(idempotencyKeyPrefix+testIdempotencyKey, encoded, testIdempotencyTTL).SetVal(false)
+			},
+			want:    false,
+			wantErr: false,
+		},
+		"setting idempotent response failed, because of redis SetNX error": {
+			logger: logger,
+			mockSetNX: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX(idempotencyKeyPrefix+testIdempotencyKey, encoded, testIdempotencyTTL).
+					SetErr(redis.ErrClosed)
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockSetNX(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.SetIdempotentResponse(context.Background(), testIdempotencyKey, pending, testIdempotencyTTL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetIdempotentResponse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("SetIdempotentResponse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIdempotentResponse(t *testing.T) {
+	logger := logging.Discard()
+
+	response := model.NewIdempotentResponse(testFingerprint, 200, "application/json", []byte(`{"id":"1"}`))
+
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		logger  *slog.Logger
+		mockGet func(mock redismock.ClientMock)
+		want    *model.IdempotentResponse
+		wantErr error
+	}{
+		"getting idempotent response successfully": {
+			logger: logger,
+			mockGet: func(mock redismock.ClientMock) {
+				mock.ExpectGet(idempotencyKeyPrefix + testIdempotencyKey).SetVal(string(encoded))
+			},
+			want:    response,
+			wantErr: nil,
+		},
+		"getting idempotent response failed, because key expired or never existed": {
+			logger: logger,
+			mockGet: func(mock redismock.ClientMock) {
+				mock.ExpectGet(idempotencyKeyPrefix + testIdempotencyKey).RedisNil()
+			},
+			want:    nil,
+			wantErr: model.ErrIdempotentResponseNotFound,
+		},
+		"getting idempotent response failed, because of redis Get error": {
+			logger: logger,
+			mockGet: func(mock redismock.ClientMock) {
+				mock.ExpectGet(idempotencyKeyPrefix + testIdempotencyKey).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockGet(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.GetIdempotentResponse(context.Background(), testIdempotencyKey)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetIdempotentResponse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetIdempotentResponse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+// takeTokenScript keeps the bucket as the time it is full again (GCRA). Every request pushes that time by the window
+// divided by the limit and is refused when it would push it more than the window ahead. The clock of Redis is used,
+// so the instances don't have to agree on the time. It answers whether the token was taken, the tokens remaining and
+// the milliseconds until the bucket is full and until the next token, the key expires once the bucket is full.
+var takeTokenScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local interval = window / limit
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local full = tonumber(redis.call("GET", KEYS[1])) or now
+if full < now then
+	full = now
+end
+
+local taken = full + interval
+if taken - now > window then
+	return {0, 0, math.ceil(full - now), math.ceil(taken - window - now)}
+end
+
+redis.call("SET", KEYS[1], tostring(taken), "PX", math.ceil(taken - now))
+
+return {1, math.floor((window - (taken - now)) / interval), math.ceil(taken - now), 0}
+`)
+
+// TakeRateLimitToken takes a token from the bucket under the key, it holds limit tokens refilled over the window.
+func (rr *redisRepository) TakeRateLimitToken(
+	ctx context.Context,
+	key string, limit int, window time.Duration,
+) (*model.RateLimit, error) {
+	result, err := takeTokenScript.Run(ctx, rr.client, []string{rateLimitKey(key)}, limit, window.Milliseconds()).
+		Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("taking rate limit token in redis: %w", domain.Unavailable(err))
+	}
+
+	if len(result) != 4 {
+		return nil, fmt.Errorf("taking rate limit token in redis: unexpected result %v", result)
+	}
+
+	return &model.RateLimit{
+		Allowed:    result[0] == 1,
+		Limit:      limit,
+		Remaining:  int(result[1]),
+		Window:     window,
+		Reset:      time.Duration(result[2]) * time.Millisecond,
+		RetryAfter: time.Duration(result[3]) * time.Millisecond,
+	}, nil
+}
+
+func rateLimitKey(key string) string {
+	return rateLimitKeyPrefix + key
+}
@@ -0,0 +1,88 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	testRateLimitKey    = "getScooters:client"
+	testRateLimit       = 10
+	testRateLimitWindow = time.Minute
+)
+
+func TestTakeRateLimitToken(t *testing.T) {
+	tests := map[string]struct {
+		mockEval func(mock redismock.ClientMock)
+		want     *model.RateLimit
+		wantErr  error
+	}{
+		"taking rate limit token successfully": {
+			mockEval: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(takeTokenScript.Hash(), []string{rateLimitKeyPrefix + testRateLimitKey},
+					testRateLimit, testRateLimitWindow.Milliseconds()).
+					SetVal([]interface{}{int64(1), int64(9), int64(6000), int64(0)})
+			},
+			want: &model.RateLimit{
+				Allowed:   true,
+				Limit:     testRateLimit,
+				Remaining: 9,
+				Window:    testRateLimitWindow,
+				Reset:     6 * time.Second,
+			},
+		},
+		"taking rate limit token refused, because the bucket is empty": {
+			mockEval: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(takeTokenScript.Hash(), []string{rateLimitKeyPrefix + testRateLimitKey},
+					testRateLimit, testRateLimitWindow.Milliseconds()).
+					SetVal([]interface{}{int64(0), int64(0), int64(59500), int64(5500)})
+			},
+			want: &model.RateLimit{
+				Allowed:    false,
+				Limit:      testRateLimit,
+				Window:     testRateLimitWindow,
+				Reset:      59500 * time.Millisecond,
+				RetryAfter: 5500 * time.Millisecond,
+			},
+		},
+		"taking rate limit token failed, because of redis EvalSha error": {
+			mockEval: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(takeTokenScript.Hash(), []string{rateLimitKeyPrefix + testRateLimitKey},
+					testRateLimit, testRateLimitWindow.Milliseconds()).
+					SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockEval(mock)
+
+			rr := NewRedisRepository(logging.Discard(), db)
+
+			got, err := rr.TakeRateLimitToken(context.Background(), testRateLimitKey, testRateLimit, testRateLimitWindow)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+
+			if tt.wantErr != nil {
+				_, unavailable := domain.As(err)
+				require.True(t, unavailable)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
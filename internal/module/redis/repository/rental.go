@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	rentalKeyPrefix            = "rental:"
+	activeRentalKeyPrefix      = "rental:active:"
+	cityActiveRentalsKeyPrefix = "rentals:active:"
+
+	rentalClientField    = "client"
+	rentalScooterField   = "scooter"
+	rentalCityField      = "city"
+	rentalStartedAtField = "started_at"
+	rentalEndedAtField   = "ended_at"
+	rentalDistanceField  = "distance"
+	rentalFareField      = "fare"
+	rentalCurrencyField  = "currency"
+	rentalEventsField    = "events"
+)
+
+// AddRental stores the rental and marks it as the active one of its scooter and of its city.
+func (rr *redisRepository) AddRental(ctx context.Context, rental *model.Rental) error {
+	_, err := rr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, rentalKey(rental.ID),
+			rentalClientField, rental.ClientUUID.String(),
+			rentalScooterField, rental.ScooterUUID.String(),
+			rentalCityField, rental.City,
+			rentalStartedAtField, rental.StartedAt.Format(time.RFC3339Nano),
+		)
+		pipe.Set(ctx, activeRentalKey(rental.ScooterUUID), rental.ID.String(), 0)
+		pipe.SAdd(ctx, cityActiveRentalsKey(rental.City), rental.ID.String())
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("adding rental to redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+func (rr *redisRepository) GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error) {
+	rentalIDAsString, err := rr.client.Get(ctx, activeRentalKey(scooterUUID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, model.ErrRentalNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("getting active rental from redis: %w", domain.Unavailable(err))
+	}
+
+	rentalID, err := uuid.Parse(rentalIDAsString)
+	if err != nil {
+		return nil, fmt.Errorf("parsing rental's id: %w", err)
+	}
+
+	return rr.GetRental(ctx, rentalID)
+}
+
+func (rr *redisRepository) GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error) {
+	fields, err := rr.client.HGetAll(ctx, rentalKey(rentalID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting rental from redis: %w", domain.Unavailable(err))
+	}
+
+	if len(fields) == 0 {
+		return nil, model.ErrRentalNotFound
+	}
+
+	return parseRental(rentalID, fields)
+}
+
+// GetActiveRentals returns the rides going on in the city, the ones started first come first.
+func (rr *redisRepository) GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error) {
+	rentalIDs, err := rr.client.SMembers(ctx, cityActiveRentalsKey(city)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting active rentals from redis: %w", domain.Unavailable(err))
+	}
+
+	rentals := make([]*model.Rental, 0, len(rentalIDs))
+
+	for _, rentalIDAsString := range rentalIDs {
+		rentalID, err := uuid.Parse(rentalIDAsString)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rental's id: %w", err)
+		}
+
+		rental, err := rr.GetRental(ctx, rentalID)
+		if errors.Is(err, model.ErrRentalNotFound) {
+			rr.logger.Warn("skipping missing active rental", logging.KeyRentalID, rentalID, "city", city)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rentals = append(rentals, rental)
+	}
+
+	sort.Slice(rentals, func(i, j int) bool {
+		return rentals[i].StartedAt.Before(rentals[j].StartedAt)
+	})
+
+	return rentals, nil
+}
+
+// FinishRental stores the end of the ride, its price and events and adds the ride to client's and scooter's history,
+// the scooter has no active rental afterwards.
+func (rr *redisRepository) FinishRental(ctx context.Context, rental *model.Rental) error {
+	events, err := json.Marshal(rental.Events)
+	if err != nil {
+		return fmt.Errorf("encoding rental's events: %w", err)
+	}
+
+	_, err = rr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, rentalKey(rental.ID),
+			rentalEndedAtField, rental.EndedAt.Format(time.RFC3339Nano),
+			rentalDistanceField, rental.Distance,
+			rentalFareField, rental.Fare,
+			rentalCurrencyField, rental.Currency,
+			rentalEventsField, string(events),
+		)
+		pipe.Del(ctx, activeRentalKey(rental.ScooterUUID))
+		pipe.SRem(ctx, cityActiveRentalsKey(rental.City), rental.ID.String())
+
+		historyEntry := redis.Z{Score: rentalScore(rental.StartedAt), Member: rental.ID.String()}
+		pipe.ZAdd(ctx, clientRentalsKey(rental.ClientUUID), historyEntry)
+		pipe.ZAdd(ctx, scooterRentalsKey(rental.ScooterUUID), historyEntry)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("finishing rental in redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+func parseRental(rentalID uuid.UUID, fields map[string]string) (*model.Rental, error) {
+	clientUUID, err := uuid.Parse(fields[rentalClientField])
+	if err != nil {
+		return nil, fmt.Errorf("parsing rental's clientUUID: %w", err)
+	}
+
+	scooterUUID, err := uuid.Parse(fields[rentalScooterField])
+	if err != nil {
+		return nil, fmt.Errorf("parsing rental's scooterUUID: %w", err)
+	}
+
+	startedAt, err := time.Parse(time.RFC3339Nano, fields[rentalStartedAtField])
+	if err != nil {
+		return nil, fmt.Errorf("parsing rental's start: %w", err)
+	}
+
+	rental := model.NewRental(rentalID, clientUUID, scooterUUID, fields[rentalCityField], startedAt)
+
+	// Fields below are present only once the ride ended
+	if endedAt, ok := fields[rentalEndedAtField]; ok {
+		if rental.EndedAt, err = time.Parse(time.RFC3339Nano, endedAt); err != nil {
+			return nil, fmt.Errorf("parsing rental's end: %w", err)
+		}
+	}
+
+	if distance, ok := fields[rentalDistanceField]; ok {
+		if rental.Distance, err = strconv.ParseFloat(distance, 64); err != nil {
+			return nil, fmt.Errorf("parsing rental's distance: %w", err)
+		}
+	}
+
+	if fare, ok := fields[rentalFareField]; ok {
+		if rental.Fare, err = strconv.ParseFloat(fare, 64); err != nil {
+			return nil, fmt.Errorf("parsing rental's fare: %w", err)
+		}
+	}
+
+	rental.Currency = fields[rentalCurrencyField]
+
+	if events, ok := fields[rentalEventsField]; ok {
+		if err = json.Unmarshal([]byte(events), &rental.Events); err != nil {
+			return nil, fmt.Errorf("parsing rental's events: %w", err)
+		}
+	}
+
+	return rental, nil
+}
+
+func rentalKey(rentalID uuid.UUID) string {
+	return rentalKeyPrefix + rentalID.String()
+}
+
+func activeRentalKey(scooterUUID uuid.UUID) string {
+	return activeRentalKeyPrefix + scooterUUID.String()
+}
+
+func cityActiveRentalsKey(city string) string {
+	return cityActiveRentalsKeyPrefix + city
+}
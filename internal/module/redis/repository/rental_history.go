@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	clientRentalsKeyPrefix  = "rentals:client:"
+	scooterRentalsKeyPrefix = "rentals:scooter:"
+
+	cursorSeparator = ":"
+)
+
+// historyCursor points right after the last ride of the page. Rides are ordered by their start, rides started
+// in the same millisecond share the score, so the cursor also counts how many of them were already returned.
+type historyCursor struct {
+	score int64
+	skip  int64
+}
+
+func (rr *redisRepository) GetClientRentals(
+	ctx context.Context,
+	clientUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error,
+) {
+	return rr.getRentals(ctx, clientRentalsKey(clientUUID), query)
+}
+
+func (rr *redisRepository) GetScooterRentals(
+	ctx context.Context,
+	scooterUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error,
+) {
+	return rr.getRentals(ctx, scooterRentalsKey(scooterUUID), query)
+}
+
+// getRentals reads a page of the ride history kept in the sorted set under given key, newest ride first.
+func (rr *redisRepository) getRentals(
+	ctx context.Context,
+	key string, query *model.RentalsQuery) (*model.RentalsPage, error,
+) {
+	minScore, maxScore := "-inf", "+inf"
+
+	if !query.From.IsZero() {
+		minScore = strconv.FormatInt(query.From.UnixMilli(), 10)
+	}
+
+	if !query.To.IsZero() {
+		maxScore = strconv.FormatInt(query.To.UnixMilli(), 10)
+	}
+
+	var cursor *historyCursor
+
+	if query.Cursor != "" {
+		var err error
+
+		if cursor, err = decodeCursor(query.Cursor); err != nil {
+			return nil, err
+		}
+
+		maxScore = strconv.FormatInt(cursor.score, 10)
+	}
+
+	args := redis.ZRangeArgs{
+		Key:     key,
+		Start:   minScore,
+		Stop:    maxScore,
+		ByScore: true,
+		Rev:     true,
+		Count:   int64(query.Limit) + 1, // one more to know whether there is a next page
+	}
+
+	if cursor != nil {
+		args.Offset = cursor.skip
+	}
+
+	entries, err := rr.client.ZRangeArgsWithScores(ctx, args).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting rentals history from redis: %w", domain.Unavailable(err))
+	}
+
+	page := &model.RentalsPage{}
+
+	if len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+		page.NextCursor = encodeCursor(nextCursor(entries, cursor))
+	}
+
+	page.Rentals = make([]*model.Rental, 0, len(entries))
+
+	for _, entry := range entries {
+		member, ok := entry.Member.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected rentals history member: %v", entry.Member)
+		}
+
+		rentalID, innerErr := uuid.Parse(member)
+		if innerErr != nil {
+			return nil, fmt.Errorf("parsing rental's id: %w", innerErr)
+		}
+
+		rental, innerErr := rr.GetRental(ctx, rentalID)
+		if innerErr != nil {
+			return nil, fmt.Errorf("getting rental from history: %w", innerErr)
+		}
+
+		page.Rentals = append(page.Rentals, rental)
+	}
+
+	return page, nil
+}
+
+// nextCursor points after the last entry of the page, entries sharing its score are skipped on the next page.
+func nextCursor(entries []redis.Z, previous *historyCursor) *historyCursor {
+	last := int64(entries[len(entries)-1].Score)
+	next := &historyCursor{score: last}
+
+	for _, entry := range entries {
+		if int64(entry.Score) == last {
+			next.skip++
+		}
+	}
+
+	// The page could consist only of rides sharing the score of the previous cursor
+	if previous != nil && previous.score == last {
+		next.skip += previous.skip
+	}
+
+	return next
+}
+
+func encodeCursor(cursor *historyCursor) string {
+	raw := strconv.FormatInt(cursor.score, 10) + cursorSeparator + strconv.FormatInt(cursor.skip, 10)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(encoded string) (*historyCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, model.ErrInvalidCursor
+	}
+
+	scoreAsString, skipAsString, found := strings.Cut(string(raw), cursorSeparator)
+	if !found {
+		return nil, model.ErrInvalidCursor
+	}
+
+	score, err := strconv.ParseInt(scoreAsString, 10, 64)
+	if err != nil {
+		return nil, model.ErrInvalidCursor
+	}
+
+	skip, err := strconv.ParseInt(skipAsString, 10, 64)
+	if err != nil || skip < 0 {
+		return nil, model.ErrInvalidCursor
+	}
+
+	return &historyCursor{score: score, skip: skip}, nil
+}
+
+func rentalScore(startedAt time.Time) float64 {
+	return float64(startedAt.UnixMilli())
+}
+
+func clientRentalsKey(clientUUID uuid.UUID) string {
+	return clientRentalsKeyPrefix + clientUUID.String()
+}
+
+func scooterRentalsKey(scooterUUID uuid.UUID) string {
+	return scooterRentalsKeyPrefix + scooterUUID.String()
+}
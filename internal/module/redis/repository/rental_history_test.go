@@ -0,0 +1,200 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+func TestGetClientRentals(t *testing.T) {
+	logger := logging.Discard()
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	newerStart := time.Date(2024, time.January, 2, 12, 0, 0, 0, time.UTC)
+	olderStart := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	newer := model.NewRental(uuid.New(), clientUUID, scooterUUID, testCity, newerStart)
+	older := model.NewRental(uuid.New(), clientUUID, scooterUUID, testCity, olderStart)
+
+	key := clientRentalsKeyPrefix + clientUUID.String()
+	from := olderStart.Add(-time.Hour)
+	to := newerStart.Add(time.Hour)
+
+	expectRental := func(mock redismock.ClientMock, rental *model.Rental) {
+		mock.ExpectHGetAll(rentalKeyPrefix + rental.ID.String()).SetVal(map[string]string{
+			rentalClientField:    rental.ClientUUID.String(),
+			rentalScooterField:   rental.ScooterUUID.String(),
+			rentalCityField:      rental.City,
+			rentalStartedAtField: rental.StartedAt.Format(time.RFC3339Nano),
+		})
+	}
+
+	tests := map[string]struct {
+		logger    *slog.Logger
+		query     *model.RentalsQuery
+		mockReads func(mock redismock.ClientMock)
+		want      *model.RentalsPage
+		wantErr   error
+	}{
+		"getting the only page of rentals successfully": {
+			logger: logger,
+			query:  model.NewRentalsQuery(time.Time{}, time.Time{}, "", 10),
+			mockReads: func(mock redismock.ClientMock) {
+				mock.ExpectZRangeArgsWithScores(redis.ZRangeArgs{
+					Key: key, Start: "-inf", Stop: "+inf", ByScore: true, Rev: true, Count: 11,
+				}).SetVal([]redis.Z{
+					{Score: float64(newerStart.UnixMilli()), Member: newer.ID.String()},
+					{Score: float64(olderStart.UnixMilli()), Member: older.ID.String()},
+				})
+				expectRental(mock, newer)
+				expectRental(mock, older)
+			},
+			want:    &model.RentalsPage{Rentals: []*model.Rental{newer, older}},
+			wantErr: nil,
+		},
+		"getting first page of rentals within time range successfully": {
+			logger: logger,
+			query:  model.NewRentalsQuery(from, to, "", 1),
+			mockReads: func(mock redismock.ClientMock) {
+				mock.ExpectZRangeArgsWithScores(redis.ZRangeArgs{
+					Key:     key,
+					Start:   "1704106800000",
+					Stop:    "1704200400000",
+					ByScore: true,
+					Rev:     true,
+					Count:   2,
+				}).SetVal([]redis.Z{
+					{Score: float64(newerStart.UnixMilli()), Member: newer.ID.String()},
+					{Score: float64(olderStart.UnixMilli()), Member: older.ID.String()},
+				})
+				expectRental(mock, newer)
+			},
+			want: &model.RentalsPage{
+				Rentals:    []*model.Rental{newer},
+				NextCursor: encodeCursor(&historyCursor{score: newerStart.UnixMilli(), skip: 1}),
+			},
+			wantErr: nil,
+		},
+		"getting next page of rentals successfully": {
+			logger: logger,
+			query: model.NewRentalsQuery(time.Time{}, time.Time{},
+				encodeCursor(&historyCursor{score: newerStart.UnixMilli(), skip: 1}), 1),
+			mockReads: func(mock redismock.ClientMock) {
+				mock.ExpectZRangeArgsWithScores(redis.ZRangeArgs{
+					Key:     key,
+					Start:   "-inf",
+					Stop:    "1704196800000",
+					ByScore: true,
+					Rev:     true,
+					Offset:  1,
+					Count:   2,
+				}).SetVal([]redis.Z{
+					{Score: float64(olderStart.UnixMilli()), Member: older.ID.String()},
+				})
+				expectRental(mock, older)
+			},
+			want:    &model.RentalsPage{Rentals: []*model.Rental{older}},
+			wantErr: nil,
+		},
+		"getting rentals failed, because of malformed cursor": {
+			logger:    logger,
+			query:     model.NewRentalsQuery(time.Time{}, time.Time{}, "not a cursor", 10),
+			mockReads: func(mock redismock.ClientMock) {},
+			want:      nil,
+			wantErr:   model.ErrInvalidCursor,
+		},
+		"getting rentals failed, because of redis ZRange error": {
+			logger: logger,
+			query:  model.NewRentalsQuery(time.Time{}, time.Time{}, "", 10),
+			mockReads: func(mock redismock.ClientMock) {
+				mock.ExpectZRangeArgsWithScores(redis.ZRangeArgs{
+					Key: key, Start: "-inf", Stop: "+inf", ByScore: true, Rev: true, Count: 11,
+				}).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+		"getting rentals failed, because indexed rental is missing": {
+			logger: logger,
+			query:  model.NewRentalsQuery(time.Time{}, time.Time{}, "", 10),
+			mockReads: func(mock redismock.ClientMock) {
+				mock.ExpectZRangeArgsWithScores(redis.ZRangeArgs{
+					Key: key, Start: "-inf", Stop: "+inf", ByScore: true, Rev: true, Count: 11,
+				}).SetVal([]redis.Z{
+					{Score: float64(newerStart.UnixMilli()), Member: newer.ID.String()},
+				})
+				mock.ExpectHGetAll(rentalKeyPrefix + newer.ID.String()).SetVal(map[string]string{})
+			},
+			want:    nil,
+			wantErr: model.ErrRentalNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockReads(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.GetClientRentals(context.Background(), clientUUID, tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetClientRentals() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetClientRentals() got = %v, want %v", got, tt.want)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	tests := map[string]struct {
+		entries  []redis.Z
+		previous *historyCursor
+		want     *historyCursor
+	}{
+		"cursor skips rides sharing the score of the last one": {
+			entries:  []redis.Z{{Score: 30}, {Score: 20}, {Score: 20}},
+			previous: nil,
+			want:     &historyCursor{score: 20, skip: 2},
+		},
+		"cursor ignores the skip of the previous cursor with different score": {
+			entries:  []redis.Z{{Score: 20}, {Score: 10}},
+			previous: &historyCursor{score: 20, skip: 3},
+			want:     &historyCursor{score: 10, skip: 1},
+		},
+		"cursor adds up skips when the page shares the score of the previous cursor": {
+			entries:  []redis.Z{{Score: 20}, {Score: 20}},
+			previous: &historyCursor{score: 20, skip: 3},
+			want:     &historyCursor{score: 20, skip: 5},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, nextCursor(tt.entries, tt.previous))
+		})
+	}
+}
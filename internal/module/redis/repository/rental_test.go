@@ -0,0 +1,337 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+func TestAddRental(t *testing.T) {
+	logger := logging.Discard()
+
+	rental := model.NewRental(uuid.New(), uuid.New(), uuid.New(), testCity, time.Now().UTC())
+
+	tests := map[string]struct {
+		logger       *slog.Logger
+		mockPipeline func(mock redismock.ClientMock)
+		wantErr      bool
+	}{
+		"adding rental successfully": {
+			logger: logger,
+			mockPipeline: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectHSet(rentalKeyPrefix+rental.ID.String(),
+					rentalClientField, rental.ClientUUID.String(),
+					rentalScooterField, rental.ScooterUUID.String(),
+					rentalCityField, rental.City,
+					rentalStartedAtField, rental.StartedAt.Format(time.RFC3339Nano),
+				).SetVal(4)
+				mock.ExpectSet(activeRentalKeyPrefix+rental.ScooterUUID.String(), rental.ID.String(), 0).SetVal("OK")
+				mock.ExpectSAdd(cityActiveRentalsKeyPrefix+rental.City, rental.ID.String()).SetVal(1)
+				mock.ExpectTxPipelineExec()
+			},
+			wantErr: false,
+		},
+		"adding rental failed, because of redis pipeline error": {
+			logger: logger,
+			mockPipeline: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectHSet(rentalKeyPrefix+rental.ID.String(),
+					rentalClientField, rental.ClientUUID.String(),
+					rentalScooterField, rental.ScooterUUID.String(),
+					rentalCityField, rental.City,
+					rentalStartedAtField, rental.StartedAt.Format(time.RFC3339Nano),
+				).SetErr(redis.ErrClosed)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockPipeline(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			if err := rr.AddRental(context.Background(), rental); (err != nil) != tt.wantErr {
+				t.Errorf("AddRental() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetActiveRental(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	rentalID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	startedAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	endedAt := startedAt.Add(10 * time.Minute)
+
+	finishedRental := model.NewRental(rentalID, clientUUID, scooterUUID, testCity, startedAt)
+	finishedRental.EndedAt = endedAt
+	finishedRental.Distance = 1200.5
+	finishedRental.Fare = 4.75
+	finishedRental.Currency = "CAD"
+	finishedRental.Events = []model.RentalEvent{
+		{Type: "entered_slow_zone", Zone: "Downtown", Longitude: 70, Latitude: 60, OccurredAt: startedAt},
+	}
+
+	tests := map[string]struct {
+		logger   *slog.Logger
+		mockGets func(mock redismock.ClientMock)
+		want     *model.Rental
+		wantErr  error
+	}{
+		"getting active rental successfully": {
+			logger: logger,
+			mockGets: func(mock redismock.ClientMock) {
+				mock.ExpectGet(activeRentalKeyPrefix + scooterUUID.String()).SetVal(rentalID.String())
+				mock.ExpectHGetAll(rentalKeyPrefix + rentalID.String()).SetVal(map[string]string{
+					rentalClientField:    clientUUID.String(),
+					rentalScooterField:   scooterUUID.String(),
+					rentalCityField:      testCity,
+					rentalStartedAtField: startedAt.Format(time.RFC3339Nano),
+				})
+			},
+			want:    model.NewRental(rentalID, clientUUID, scooterUUID, testCity, startedAt),
+			wantErr: nil,
+		},
+		"getting rental with end, distance and fare successfully": {
+			logger: logger,
+			mockGets: func(mock redismock.ClientMock) {
+				mock.ExpectGet(activeRentalKeyPrefix + scooterUUID.String()).SetVal(rentalID.String())
+				mock.ExpectHGetAll(rentalKeyPrefix + rentalID.String()).SetVal(map[string]string{
+					rentalClientField:    clientUUID.String(),
+					rentalScooterField:   scooterUUID.String(),
+					rentalCityField:      testCity,
+					rentalStartedAtField: startedAt.Format(time.RFC3339Nano),
+					rentalEndedAtField:   endedAt.Format(time.RFC3339Nano),
+					rentalDistanceField:  "1200.5",
+					rentalFareField:      "4.75",
+					rentalCurrencyField:  "CAD",
+					rentalEventsField: `[{"type":"entered_slow_zone","zone":"Downtown","longitude":70,` +
+						`"latitude":60,"occurredAt":"2024-01-01T12:00:00Z"}]`,
+				})
+			},
+			want:    finishedRental,
+			wantErr: nil,
+		},
+		"getting active rental failed, because scooter has no active rental": {
+			logger: logger,
+			mockGets: func(mock redismock.ClientMock) {
+				mock.ExpectGet(activeRentalKeyPrefix + scooterUUID.String()).RedisNil()
+			},
+			want:    nil,
+			wantErr: model.ErrRentalNotFound,
+		},
+		"getting active rental failed, because rental record is missing": {
+			logger: logger,
+			mockGets: func(mock redismock.ClientMock) {
+				mock.ExpectGet(activeRentalKeyPrefix + scooterUUID.String()).SetVal(rentalID.String())
+				mock.ExpectHGetAll(rentalKeyPrefix + rentalID.String()).SetVal(map[string]string{})
+			},
+			want:    nil,
+			wantErr: model.ErrRentalNotFound,
+		},
+		"getting active rental failed, because of redis Get error": {
+			logger: logger,
+			mockGets: func(mock redismock.ClientMock) {
+				mock.ExpectGet(activeRentalKeyPrefix + scooterUUID.String()).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockGets(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.GetActiveRental(context.Background(), scooterUUID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetActiveRental() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetActiveRental() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetActiveRentals(t *testing.T) {
+	logger := logging.Discard()
+
+	earlier := model.NewRental(uuid.New(), uuid.New(), uuid.New(), testCity, time.Now().UTC().Add(-time.Minute))
+	later := model.NewRental(uuid.New(), uuid.New(), uuid.New(), testCity, time.Now().UTC())
+	key := cityActiveRentalsKeyPrefix + testCity
+
+	fields := func(rental *model.Rental) map[string]string {
+		return map[string]string{
+			rentalClientField:    rental.ClientUUID.String(),
+			rentalScooterField:   rental.ScooterUUID.String(),
+			rentalCityField:      rental.City,
+			rentalStartedAtField: rental.StartedAt.Format(time.RFC3339Nano),
+		}
+	}
+
+	tests := map[string]struct {
+		logger  *slog.Logger
+		mock    func(mock redismock.ClientMock)
+		want    []*model.Rental
+		wantErr bool
+	}{
+		"getting active rentals ordered by their start": {
+			logger: logger,
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers(key).SetVal([]string{later.ID.String(), earlier.ID.String()})
+				mock.ExpectHGetAll(rentalKeyPrefix + later.ID.String()).SetVal(fields(later))
+				mock.ExpectHGetAll(rentalKeyPrefix + earlier.ID.String()).SetVal(fields(earlier))
+			},
+			want:    []*model.Rental{earlier, later},
+			wantErr: false,
+		},
+		"getting active rentals of city without rides": {
+			logger: logger,
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers(key).SetVal([]string{})
+			},
+			want:    []*model.Rental{},
+			wantErr: false,
+		},
+		"getting active rentals skips missing rental": {
+			logger: logger,
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers(key).SetVal([]string{earlier.ID.String(), later.ID.String()})
+				mock.ExpectHGetAll(rentalKeyPrefix + earlier.ID.String()).SetVal(map[string]string{})
+				mock.ExpectHGetAll(rentalKeyPrefix + later.ID.String()).SetVal(fields(later))
+			},
+			want:    []*model.Rental{later},
+			wantErr: false,
+		},
+		"getting active rentals failed, because redis is unavailable": {
+			logger: logger,
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers(key).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mock(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.GetActiveRentals(context.Background(), testCity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetActiveRentals() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFinishRental(t *testing.T) {
+	logger := logging.Discard()
+
+	rental := model.NewRental(uuid.New(), uuid.New(), uuid.New(), testCity, time.Now().UTC())
+	rental.EndedAt = rental.StartedAt.Add(5 * time.Minute)
+	rental.Distance = 800
+	rental.Fare = 2.75
+	rental.Currency = "CAD"
+	rental.Events = []model.RentalEvent{
+		{Type: "left_operating_area", Longitude: 70, Latitude: 60, OccurredAt: rental.EndedAt},
+	}
+
+	events, err := json.Marshal(rental.Events)
+	require.NoError(t, err)
+
+	historyEntry := redis.Z{Score: float64(rental.StartedAt.UnixMilli()), Member: rental.ID.String()}
+
+	tests := map[string]struct {
+		logger       *slog.Logger
+		mockPipeline func(mock redismock.ClientMock)
+		wantErr      bool
+	}{
+		"finishing rental successfully": {
+			logger: logger,
+			mockPipeline: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectHSet(rentalKeyPrefix+rental.ID.String(),
+					rentalEndedAtField, rental.EndedAt.Format(time.RFC3339Nano),
+					rentalDistanceField, rental.Distance,
+					rentalFareField, rental.Fare,
+					rentalCurrencyField, rental.Currency,
+					rentalEventsField, string(events),
+				).SetVal(4)
+				mock.ExpectDel(activeRentalKeyPrefix + rental.ScooterUUID.String()).SetVal(1)
+				mock.ExpectSRem(cityActiveRentalsKeyPrefix+rental.City, rental.ID.String()).SetVal(1)
+				mock.ExpectZAdd(clientRentalsKeyPrefix+rental.ClientUUID.String(), historyEntry).SetVal(1)
+				mock.ExpectZAdd(scooterRentalsKeyPrefix+rental.ScooterUUID.String(), historyEntry).SetVal(1)
+				mock.ExpectTxPipelineExec()
+			},
+			wantErr: false,
+		},
+		"finishing rental failed, because of redis pipeline error": {
+			logger: logger,
+			mockPipeline: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectHSet(rentalKeyPrefix+rental.ID.String(),
+					rentalEndedAtField, rental.EndedAt.Format(time.RFC3339Nano),
+					rentalDistanceField, rental.Distance,
+					rentalFareField, rental.Fare,
+					rentalCurrencyField, rental.Currency,
+					rentalEventsField, string(events),
+				).SetErr(redis.ErrClosed)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockPipeline(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			if err := rr.FinishRental(context.Background(), rental); (err != nil) != tt.wantErr {
+				t.Errorf("FinishRental() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	reservationKeyPrefix = "reservation:"
+	// reservationExpiriesKey sorts the reserved scooters by the expiry of their reservation in unix milliseconds, the
+	// reservation key expires on its own, the scooter's status is released by the sweeper reading it
+	reservationExpiriesKey = "reservations:expiries"
+)
+
+// reserveScript takes the reservation only while the scooter is available and not reserved yet, the check, the
+// reservation key, its expiry and the status are written at once, so two riders or a rider and the fleet management
+// can't both win. It answers the status the scooter had, reserved when the key is already taken, nil when the scooter
+// doesn't exist.
+var reserveScript = redis.NewScript(`
+local status = redis.call("HGET", KEYS[1], ARGV[1])
+if not status then
+	return false
+end
+
+if redis.call("EXISTS", KEYS[2]) == 1 then
+	return ARGV[3]
+end
+
+if status ~= ARGV[2] then
+	return status
+end
+
+redis.call("SET", KEYS[2], ARGV[4], "PX", ARGV[5])
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+
+local now = redis.call("TIME")
+redis.call("ZADD", KEYS[3], now[1] * 1000 + math.floor(now[2] / 1000) + ARGV[5], ARGV[6])
+
+return status
+`)
+
+// ReserveScooter stores the client holding the scooter and marks it reserved, the key expires on its own after ttl.
+// It answers the status the scooter had, the scooter is reserved only when it was available.
+func (rr *redisRepository) ReserveScooter(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID, ttl time.Duration) (model.ScooterStatus, error,
+) {
+	status, err := reserveScript.Run(
+		ctx,
+		rr.client,
+		[]string{metadataKey(scooterUUID), reservationKey(scooterUUID), reservationExpiriesKey},
+		metadataStatusField,
+		string(model.StatusAvailable),
+		string(model.StatusReserved),
+		clientUUID.String(),
+		ttl.Milliseconds(),
+		scooterUUID.String(),
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", model.ErrScooterNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("reserving scooter in redis: %w", domain.Unavailable(err))
+	}
+
+	return model.ScooterStatus(status), nil
+}
+
+func (rr *redisRepository) GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error) {
+	clientUUIDAsString, err := rr.client.Get(ctx, reservationKey(scooterUUID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return uuid.Nil, model.ErrReservationNotFound
+	}
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("getting reservation from redis: %w", domain.Unavailable(err))
+	}
+
+	clientUUID, err := uuid.Parse(clientUUIDAsString)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parsing reservation's clientUUID: %w", err)
+	}
+
+	return clientUUID, nil
+}
+
+// releaseScript drops the reservation only while the client still holds it, the scooter goes back to available only
+// while it is still reserved. Checking the holder and the status in the same step keeps a reservation taken over by
+// another client or a scooter rented in the meantime untouched. It answers the holder and the status the scooter
+// had, nil when there is no reservation.
+var releaseScript = redis.NewScript(`
+local holder = redis.call("GET", KEYS[2])
+if not holder then
+	return false
+end
+
+local status = redis.call("HGET", KEYS[1], ARGV[1]) or ""
+if holder ~= ARGV[4] then
+	return {holder, status}
+end
+
+redis.call("DEL", KEYS[2])
+
+if status == ARGV[2] then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+end
+
+return {holder, status}
+`)
+
+// ReleaseReservation drops the reservation held by the client and puts the reserved scooter back to available. It
+// answers the holder of the reservation, nothing is changed when it isn't the client, and the status the scooter had.
+func (rr *redisRepository) ReleaseReservation(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID) (uuid.UUID, model.ScooterStatus, error,
+) {
+	released, err := releaseScript.Run(
+		ctx,
+		rr.client,
+		[]string{metadataKey(scooterUUID), reservationKey(scooterUUID)},
+		metadataStatusField,
+		string(model.StatusReserved),
+		string(model.StatusAvailable),
+		clientUUID.String(),
+	).StringSlice()
+	if errors.Is(err, redis.Nil) {
+		return uuid.Nil, "", model.ErrReservationNotFound
+	}
+
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("releasing reservation in redis: %w", domain.Unavailable(err))
+	}
+
+	holderUUID, err := uuid.Parse(released[0])
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("parsing reservation's clientUUID: %w", err)
+	}
+
+	return holderUUID, model.ScooterStatus(released[1]), nil
+}
+
+// ExpiredReservations lists the scooters whose reservation expired before the given time, count of them at most. A
+// scooter stays listed until its reservation is released by ReleaseExpiredReservation.
+func (rr *redisRepository) ExpiredReservations(
+	ctx context.Context,
+	before time.Time, count int64,
+) ([]uuid.UUID, error) {
+	members, err := rr.client.ZRangeByScore(ctx, reservationExpiriesKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.UnixMilli(), 10),
+		Count: count,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting expired reservations from redis: %w", domain.Unavailable(err))
+	}
+
+	scooterUUIDs := make([]uuid.UUID, len(members))
+
+	for i, member := range members {
+		scooterUUIDs[i], err = uuid.Parse(member)
+		if err != nil {
+			return nil, fmt.Errorf("parsing expired reservation's scooterUUID: %w", err)
+		}
+	}
+
+	return scooterUUIDs, nil
+}
+
+// releaseExpiredScript forgets the expiry once the reservation key is gone and puts the scooter back to available
+// while it is still reserved, a reservation taken again in the meantime is left for its own expiry. It answers 1
+// when the scooter went back to available, 0 otherwise.
+var releaseExpiredScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[2]) == 1 then
+	return 0
+end
+
+redis.call("ZREM", KEYS[3], ARGV[4])
+
+if redis.call("HGET", KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+
+return 1
+`)
+
+// ReleaseExpiredReservation puts the scooter back to available once its reservation expired. It reports whether the
+// scooter went back to available, a scooter rented, changed by the fleet management or reserved again stays as is.
+func (rr *redisRepository) ReleaseExpiredReservation(ctx context.Context, scooterUUID uuid.UUID) (bool, error) {
+	released, err := releaseExpiredScript.Run(
+		ctx,
+		rr.client,
+		[]string{metadataKey(scooterUUID), reservationKey(scooterUUID), reservationExpiriesKey},
+		metadataStatusField,
+		string(model.StatusReserved),
+		string(model.StatusAvailable),
+		scooterUUID.String(),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("releasing expired reservation in redis: %w", domain.Unavailable(err))
+	}
+
+	return released == 1, nil
+}
+
+func (rr *redisRepository) DeleteReservation(ctx context.Context, scooterUUID uuid.UUID) error {
+	if err := rr.client.Del(ctx, reservationKey(scooterUUID)).Err(); err != nil {
+		return fmt.Errorf("deleting reservation from redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+func reservationKey(scooterUUID uuid.UUID) string {
+	return reservationKeyPrefix + scooterUUID.String()
+}
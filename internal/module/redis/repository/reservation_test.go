@@ -0,0 +1,441 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	testReservationTTL           = 10 * time.Minute
+	testExpiredReservationsCount = 100
+)
+
+func TestReserveScooter(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	expectEval := func(mock redismock.ClientMock) *redismock.ExpectedCmd {
+		return mock.ExpectEvalSha(
+			reserveScript.Hash(),
+			[]string{
+				metadataKeyPrefix + scooterUUID.String(),
+				reservationKeyPrefix + scooterUUID.String(),
+				reservationExpiriesKey,
+			},
+			metadataStatusField,
+			string(model.StatusAvailable),
+			string(model.StatusReserved),
+			clientUUID.String(),
+			testReservationTTL.Milliseconds(),
+			scooterUUID.String(),
+		)
+	}
+
+	tests := map[string]struct {
+		logger   *slog.Logger
+		mockEval func(mock redismock.ClientMock)
+		want     model.ScooterStatus
+		wantErr  error
+	}{
+		"reserving scooter successfully": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(string(model.StatusAvailable))
+			},
+			want: model.StatusAvailable,
+		},
+		"reserving scooter refused, because scooter is already reserved": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(string(model.StatusReserved))
+			},
+			want: model.StatusReserved,
+		},
+		"reserving scooter refused, because scooter is rented": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(string(model.StatusRented))
+			},
+			want: model.StatusRented,
+		},
+		"reserving scooter failed, because scooter doesn't exist": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).RedisNil()
+			},
+			wantErr: model.ErrScooterNotFound,
+		},
+		"reserving scooter failed, because of redis EvalSha error": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockEval(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.ReserveScooter(context.Background(), scooterUUID, clientUUID, testReservationTTL)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetReservation(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		logger  *slog.Logger
+		mockGet func(mock redismock.ClientMock)
+		want    uuid.UUID
+		wantErr error
+	}{
+		"getting reservation successfully": {
+			logger: logger,
+			mockGet: func(mock redismock.ClientMock) {
+				mock.ExpectGet(reservationKeyPrefix + scooterUUID.String()).SetVal(clientUUID.String())
+			},
+			want:    clientUUID,
+			wantErr: nil,
+		},
+		"getting reservation failed, because reservation expired or never existed": {
+			logger: logger,
+			mockGet: func(mock redismock.ClientMock) {
+				mock.ExpectGet(reservationKeyPrefix + scooterUUID.String()).RedisNil()
+			},
+			want:    uuid.Nil,
+			wantErr: model.ErrReservationNotFound,
+		},
+		"getting reservation failed, because of redis Get error": {
+			logger: logger,
+			mockGet: func(mock redismock.ClientMock) {
+				mock.ExpectGet(reservationKeyPrefix + scooterUUID.String()).SetErr(redis.ErrClosed)
+			},
+			want:    uuid.Nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockGet(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.GetReservation(context.Background(), scooterUUID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetReservation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("GetReservation() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseReservation(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	anotherClientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	expectEval := func(mock redismock.ClientMock) *redismock.ExpectedCmd {
+		return mock.ExpectEvalSha(
+			releaseScript.Hash(),
+			[]string{metadataKeyPrefix + scooterUUID.String(), reservationKeyPrefix + scooterUUID.String()},
+			metadataStatusField,
+			string(model.StatusReserved),
+			string(model.StatusAvailable),
+			clientUUID.String(),
+		)
+	}
+
+	tests := map[string]struct {
+		logger       *slog.Logger
+		mockEval     func(mock redismock.ClientMock)
+		wantHolder   uuid.UUID
+		wantPrevious model.ScooterStatus
+		wantErr      error
+	}{
+		"releasing reservation successfully": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal([]interface{}{clientUUID.String(), string(model.StatusReserved)})
+			},
+			wantHolder:   clientUUID,
+			wantPrevious: model.StatusReserved,
+		},
+		"releasing reservation of rented scooter keeps its status": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal([]interface{}{clientUUID.String(), string(model.StatusRented)})
+			},
+			wantHolder:   clientUUID,
+			wantPrevious: model.StatusRented,
+		},
+		"releasing reservation refused, because it belongs to another client": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal([]interface{}{anotherClientUUID.String(), string(model.StatusReserved)})
+			},
+			wantHolder:   anotherClientUUID,
+			wantPrevious: model.StatusReserved,
+		},
+		"releasing reservation failed, because reservation expired or never existed": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).RedisNil()
+			},
+			wantErr: model.ErrReservationNotFound,
+		},
+		"releasing reservation failed, because of redis EvalSha error": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockEval(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			holder, previous, err := rr.ReleaseReservation(context.Background(), scooterUUID, clientUUID)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantHolder, holder)
+			require.Equal(t, tt.wantPrevious, previous)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExpiredReservations(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	before := time.Now()
+
+	expectZRange := func(mock redismock.ClientMock) *redismock.ExpectedStringSlice {
+		return mock.ExpectZRangeByScore(reservationExpiriesKey, &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   strconv.FormatInt(before.UnixMilli(), 10),
+			Count: testExpiredReservationsCount,
+		})
+	}
+
+	tests := map[string]struct {
+		logger     *slog.Logger
+		mockZRange func(mock redismock.ClientMock)
+		want       []uuid.UUID
+		wantErr    error
+	}{
+		"getting expired reservations successfully": {
+			logger: logger,
+			mockZRange: func(mock redismock.ClientMock) {
+				expectZRange(mock).SetVal([]string{scooterUUID.String()})
+			},
+			want: []uuid.UUID{scooterUUID},
+		},
+		"getting expired reservations successfully, when none expired": {
+			logger: logger,
+			mockZRange: func(mock redismock.ClientMock) {
+				expectZRange(mock).SetVal([]string{})
+			},
+			want: []uuid.UUID{},
+		},
+		"getting expired reservations failed, because of redis ZRangeByScore error": {
+			logger: logger,
+			mockZRange: func(mock redismock.ClientMock) {
+				expectZRange(mock).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockZRange(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.ExpiredReservations(context.Background(), before, testExpiredReservationsCount)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReleaseExpiredReservation(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	expectEval := func(mock redismock.ClientMock) *redismock.ExpectedCmd {
+		return mock.ExpectEvalSha(
+			releaseExpiredScript.Hash(),
+			[]string{
+				metadataKeyPrefix + scooterUUID.String(),
+				reservationKeyPrefix + scooterUUID.String(),
+				reservationExpiriesKey,
+			},
+			metadataStatusField,
+			string(model.StatusReserved),
+			string(model.StatusAvailable),
+			scooterUUID.String(),
+		)
+	}
+
+	tests := map[string]struct {
+		logger   *slog.Logger
+		mockEval func(mock redismock.ClientMock)
+		want     bool
+		wantErr  error
+	}{
+		"releasing expired reservation successfully": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(int64(1))
+			},
+			want: true,
+		},
+		"releasing expired reservation keeps status scooter got in the meantime": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(int64(0))
+			},
+			want: false,
+		},
+		"releasing expired reservation failed, because of redis EvalSha error": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockEval(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.ReleaseExpiredReservation(context.Background(), scooterUUID)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteReservation(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		logger  *slog.Logger
+		mockDel func(mock redismock.ClientMock)
+		wantErr bool
+	}{
+		"deleting reservation successfully": {
+			logger: logger,
+			mockDel: func(mock redismock.ClientMock) {
+				mock.ExpectDel(reservationKeyPrefix + scooterUUID.String()).SetVal(1)
+			},
+			wantErr: false,
+		},
+		"deleting reservation failed, because of redis Del error": {
+			logger: logger,
+			mockDel: func(mock redismock.ClientMock) {
+				mock.ExpectDel(reservationKeyPrefix + scooterUUID.String()).SetErr(redis.ErrClosed)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockDel(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			if err = rr.DeleteReservation(context.Background(), scooterUUID); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteReservation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisFailureIsUnavailable(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	mock.ExpectGet(reservationKeyPrefix + scooterUUID.String()).SetErr(redis.ErrClosed)
+
+	rr := NewRedisRepository(logging.Discard(), db)
+
+	_, err = rr.GetReservation(context.Background(), scooterUUID)
+
+	domainErr, ok := domain.As(err)
+	require.True(t, ok)
+	require.Equal(t, domain.KindUnavailable, domainErr.Kind)
+	require.ErrorIs(t, err, redis.ErrClosed)
+}
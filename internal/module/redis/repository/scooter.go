@@ -2,90 +2,243 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
 )
 
-const unitOfLength = "m" // in meters
+const (
+	unitOfLength = "m" // in meters
 
-var errNotFoundGivenScooter = errors.New("scooter with given UUID was not found")
+	metadataKeyPrefix    = "scooter:"
+	metadataCityField    = "city"
+	metadataStatusField  = "status"
+	metadataBatteryField = "battery"
+)
 
 type redisRepository struct {
-	logger *log.Logger
+	logger *slog.Logger
 	client *redis.Client
 }
 
-func NewRedisRepository(logger *log.Logger, client *redis.Client) *redisRepository {
+func NewRedisRepository(logger *slog.Logger, client *redis.Client) *redisRepository {
 	return &redisRepository{
 		logger: logger,
 		client: client,
 	}
 }
 
-func (rr *redisRepository) GetScooters(long, lat, radius float64, city string) ([]redis.GeoLocation, error) {
+func (rr *redisRepository) GetScooters(
+	ctx context.Context,
+	long, lat, radius float64, city string) ([]redis.GeoLocation, error,
+) {
 	// Perform the GeoRadius search
-	result, err := rr.client.GeoRadius(context.Background(), city, long, lat, &redis.GeoRadiusQuery{
+	result, err := rr.client.GeoRadius(ctx, city, long, lat, &redis.GeoRadiusQuery{
 		Radius: radius,
 		Unit:   unitOfLength,
 	}).Result()
 	if err != nil {
-		return nil, fmt.Errorf("getting scooters from redis using geo index: %w", err)
+		return nil, fmt.Errorf("getting scooters from redis using geo index: %w", domain.Unavailable(err))
 	}
 
 	return result, err
 }
 
-func (rr *redisRepository) GetScooterLocation(scooterUUID uuid.UUID, city string) (*redis.GeoPos, error) {
-	coords, err := rr.client.GeoPos(context.Background(), city, scooterUUID.String()).Result()
+func (rr *redisRepository) GetScooterLocation(
+	ctx context.Context,
+	scooterUUID uuid.UUID, city string) (*redis.GeoPos, error,
+) {
+	coords, err := rr.client.GeoPos(ctx, city, scooterUUID.String()).Result()
 	if err != nil {
-		return nil, fmt.Errorf("retrieving coordinates: %w", err)
+		return nil, fmt.Errorf("retrieving coordinates: %w", domain.Unavailable(err))
 	}
 
-	if len(coords) == 0 {
-		return nil, errNotFoundGivenScooter
+	if len(coords) == 0 || coords[0] == nil {
+		return nil, model.ErrScooterNotFound
 	}
 
 	return coords[0], nil
 }
 
-func (rr *redisRepository) GetScooterAvailability(scooterUUID uuid.UUID) (bool, error) {
-	// Retrieve the scooter directly using its UUID
-	scooterJSON, err := rr.client.Get(context.Background(), scooterUUID.String()).Result()
+func (rr *redisRepository) UpdateScooterLocation(ctx context.Context, scooter *redis.GeoLocation, city string) error {
+	// Update the Geo index with scooter information
+	if _, err := rr.client.GeoAdd(ctx, city, scooter).Result(); err != nil {
+		return fmt.Errorf("adding scooter's location to redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+func (rr *redisRepository) GetScooterMetadata(
+	ctx context.Context,
+	scooterUUID uuid.UUID) (*model.ScooterMetadata, error,
+) {
+	metadata, err := rr.client.HGetAll(ctx, metadataKey(scooterUUID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter's metadata from redis: %w", domain.Unavailable(err))
+	}
+
+	if len(metadata) == 0 {
+		return nil, model.ErrScooterNotFound
+	}
+
+	// Scooters registered before battery tracking have no battery stored, they are treated as charged
+	battery := model.FullBattery
+
+	if batteryAsString, ok := metadata[metadataBatteryField]; ok {
+		battery, err = strconv.ParseFloat(batteryAsString, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing scooter's battery: %w", err)
+		}
+	}
+
+	return model.NewScooterMetadata(
+		metadata[metadataCityField],
+		model.ScooterStatus(metadata[metadataStatusField]),
+		battery,
+	), nil
+}
+
+// addScooterScript registers the scooter only while its metadata doesn't exist yet, the check, the metadata and the
+// location are written at once, so two registrations of the same scooter can't both win and the scooter is never half
+// registered. It answers 1 when the scooter was added, 0 when it already exists.
+var addScooterScript = redis.NewScript(`
+if redis.call("HSETNX", KEYS[1], ARGV[1], ARGV[2]) == 0 then
+	return 0
+end
+
+redis.call("HSET", KEYS[1], ARGV[3], ARGV[4], ARGV[5], ARGV[6])
+redis.call("GEOADD", KEYS[2], ARGV[7], ARGV[8], ARGV[9])
+
+return 1
+`)
+
+// AddScooter registers the scooter with its location and metadata. It reports whether the scooter was added, an
+// existing scooter is left as is.
+func (rr *redisRepository) AddScooter(
+	ctx context.Context,
+	scooter *redis.GeoLocation, metadata *model.ScooterMetadata,
+) (bool, error) {
+	added, err := addScooterScript.Run(
+		ctx,
+		rr.client,
+		[]string{metadataKeyPrefix + scooter.Name, metadata.City},
+		metadataCityField, metadata.City,
+		metadataStatusField, string(metadata.Status),
+		metadataBatteryField, metadata.Battery,
+		scooter.Longitude, scooter.Latitude, scooter.Name,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("adding scooter to redis: %w", domain.Unavailable(err))
+	}
+
+	return added == 1, nil
+}
+
+func (rr *redisRepository) MoveScooter(ctx context.Context, scooter *redis.GeoLocation, fromCity, toCity string) error {
+	_, err := rr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if fromCity != toCity {
+			pipe.ZRem(ctx, fromCity, scooter.Name)
+		}
+
+		pipe.GeoAdd(ctx, toCity, scooter)
+		pipe.HSet(ctx, metadataKeyPrefix+scooter.Name, metadataCityField, toCity)
+
+		return nil
+	})
 	if err != nil {
-		return false, fmt.Errorf("getting scooters availability from redis: %w", err)
+		return fmt.Errorf("moving scooter in redis: %w", domain.Unavailable(err))
 	}
 
-	var availabilityAsInt int
+	return nil
+}
 
-	err = json.Unmarshal([]byte(scooterJSON), &availabilityAsInt)
+func (rr *redisRepository) UpdateScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	err := rr.client.HSet(ctx, metadataKey(scooterUUID), metadataStatusField, string(status)).Err()
 	if err != nil {
-		return false, fmt.Errorf("unmarshaling scooters availability: %w", err)
+		return fmt.Errorf("updating scooter's status in redis: %w", domain.Unavailable(err))
 	}
 
-	availability := availabilityAsInt == 1
+	return nil
+}
 
-	return availability, nil
+// swapStatusScript writes the status only while the current one is among the expected, so the check can't be
+// interleaved with another change of the status. It answers the status the scooter had, nil when the scooter doesn't
+// exist.
+var swapStatusScript = redis.NewScript(`
+local status = redis.call("HGET", KEYS[1], ARGV[1])
+if not status then
+	return false
+end
+
+for i = 3, #ARGV do
+	if status == ARGV[i] then
+		redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+		break
+	end
+end
+
+return status
+`)
+
+// SwapScooterStatus sets the status when the scooter is in one of the expected statuses, it answers the status
+// the scooter had, so the caller can tell whether it was swapped.
+func (rr *redisRepository) SwapScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+) (model.ScooterStatus, error) {
+	args := make([]interface{}, 0, len(expected)+2)
+	args = append(args, metadataStatusField, string(status))
+
+	for _, s := range expected {
+		args = append(args, string(s))
+	}
+
+	previous, err := swapStatusScript.Run(ctx, rr.client, []string{metadataKey(scooterUUID)}, args...).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", model.ErrScooterNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("swapping scooter's status in redis: %w", domain.Unavailable(err))
+	}
+
+	return model.ScooterStatus(previous), nil
 }
 
-func (rr *redisRepository) UpdateScooterLocation(scooter *redis.GeoLocation, city string) error {
-	// Update the Geo index with scooter information
-	if _, err := rr.client.GeoAdd(context.Background(), city, scooter).Result(); err != nil {
-		return fmt.Errorf("adding scooter's location to redis: %w", err)
+func (rr *redisRepository) UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	err := rr.client.HSet(ctx, metadataKey(scooterUUID), metadataBatteryField, battery).Err()
+	if err != nil {
+		return fmt.Errorf("updating scooter's battery in redis: %w", domain.Unavailable(err))
 	}
 
 	return nil
 }
 
-func (rr *redisRepository) UpdateScooterAvailability(scooterUUID uuid.UUID, availability bool) error {
-	// Store the additional data as a string in Redis
-	if err := rr.client.Set(context.Background(), scooterUUID.String(), availability, 0).Err(); err != nil {
-		return fmt.Errorf("setting scooter's availability in redis: %w", err)
+func (rr *redisRepository) RemoveScooter(ctx context.Context, scooterUUID uuid.UUID, city string) error {
+	_, err := rr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, city, scooterUUID.String())
+		pipe.Del(ctx, metadataKey(scooterUUID), reservationKey(scooterUUID))
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("removing scooter from redis: %w", domain.Unavailable(err))
 	}
 
 	return nil
 }
+
+func metadataKey(scooterUUID uuid.UUID) string {
+	return metadataKeyPrefix + scooterUUID.String()
+}
@@ -3,8 +3,8 @@
 package repository
 
 import (
-	"encoding/json"
-	"log"
+	"context"
+	"log/slog"
 	"os"
 	"reflect"
 	"testing"
@@ -13,6 +13,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
 )
 
 const (
@@ -24,7 +27,7 @@ const (
 )
 
 func TestGetScooters(t *testing.T) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -42,7 +45,7 @@ func TestGetScooters(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		logger        *log.Logger
+		logger        *slog.Logger
 		mockGeoRadius func(mock redismock.ClientMock)
 		want          []redis.GeoLocation
 		wantErr       bool
@@ -82,7 +85,7 @@ func TestGetScooters(t *testing.T) {
 
 			rr := NewRedisRepository(tt.logger, db)
 
-			got, err := rr.GetScooters(testLongitude, testLatitude, testRadius, testCity)
+			got, err := rr.GetScooters(context.Background(), testLongitude, testLatitude, testRadius, testCity)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetScooters() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -95,7 +98,7 @@ func TestGetScooters(t *testing.T) {
 }
 
 func TestGetScooterLocation(t *testing.T) {
-	logger := &log.Logger{}
+	logger := logging.Discard()
 
 	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -108,7 +111,7 @@ func TestGetScooterLocation(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		logger     *log.Logger
+		logger     *slog.Logger
 		mockGeoPos func(mock redismock.ClientMock)
 		want       *redis.GeoPos
 		wantErr    bool
@@ -146,7 +149,7 @@ func TestGetScooterLocation(t *testing.T) {
 
 			rr := NewRedisRepository(tt.logger, db)
 
-			got, err := rr.GetScooterLocation(scooterUUID, testCity)
+			got, err := rr.GetScooterLocation(context.Background(), scooterUUID, testCity)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetScooterLocation() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -158,47 +161,267 @@ func TestGetScooterLocation(t *testing.T) {
 	}
 }
 
-func TestGetScooterAvailability(t *testing.T) {
-	logger := &log.Logger{}
+func TestUpdateScooterLocation(t *testing.T) {
+	logger := logging.Discard()
 
 	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
-	scooterAvailabilityAsInt := 1
-	scooterAvailabilityAsJSON, err := json.Marshal(scooterAvailabilityAsInt)
+	scooter := &redis.GeoLocation{
+		Name:      scooterUUID.String(),
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+	}
+
+	tests := map[string]struct {
+		logger     *slog.Logger
+		mockGeoAdd func(mock redismock.ClientMock)
+		wantErr    bool
+	}{
+		"updating scooter's location successfully": {
+			logger: logger,
+			mockGeoAdd: func(mock redismock.ClientMock) {
+				mock.ExpectGeoAdd(testCity, scooter).SetVal(1)
+			},
+			wantErr: false,
+		},
+		"updating scooter's location failed, because of GeoAdd error": {
+			logger: logger,
+			mockGeoAdd: func(mock redismock.ClientMock) {
+				mock.ExpectGeoAdd(testCity, scooter).SetErr(redis.ErrClosed)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockGeoAdd(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			if err = rr.UpdateScooterLocation(context.Background(), scooter, testCity); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateScooterLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateScooterStatus(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
-	scooterAvailabilityIncorrectJSON, err := json.Marshal([]int{})
+	tests := map[string]struct {
+		logger   *slog.Logger
+		mockHSet func(mock redismock.ClientMock)
+		wantErr  bool
+	}{
+		"updating scooter's status successfully": {
+			logger: logger,
+			mockHSet: func(mock redismock.ClientMock) {
+				mock.ExpectHSet(
+					metadataKeyPrefix+scooterUUID.String(),
+					metadataStatusField, string(model.StatusRented),
+				).SetVal(0)
+			},
+			wantErr: false,
+		},
+		"updating scooter's status failed, because of redis HSet error": {
+			logger: logger,
+			mockHSet: func(mock redismock.ClientMock) {
+				mock.ExpectHSet(
+					metadataKeyPrefix+scooterUUID.String(),
+					metadataStatusField, string(model.StatusRented),
+				).SetErr(redis.ErrClosed)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockHSet(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			if err = rr.UpdateScooterStatus(context.Background(), scooterUUID, model.StatusRented); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateScooterStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSwapScooterStatus(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
+	expectEval := func(mock redismock.ClientMock) *redismock.ExpectedCmd {
+		return mock.ExpectEvalSha(
+			swapStatusScript.Hash(),
+			[]string{metadataKeyPrefix + scooterUUID.String()},
+			metadataStatusField,
+			string(model.StatusRented),
+			string(model.StatusAvailable),
+			string(model.StatusReserved),
+		)
+	}
+
 	tests := map[string]struct {
-		logger  *log.Logger
-		mockGet func(mock redismock.ClientMock)
-		want    bool
-		wantErr bool
+		logger   *slog.Logger
+		mockEval func(mock redismock.ClientMock)
+		want     model.ScooterStatus
+		wantErr  error
 	}{
-		"getting scooter's availability successfully": {
+		"swapping scooter's status successfully": {
 			logger: logger,
-			mockGet: func(mock redismock.ClientMock) {
-				mock.ExpectGet(scooterUUID.String()).SetVal(string(scooterAvailabilityAsJSON))
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(string(model.StatusReserved))
+			},
+			want: model.StatusReserved,
+		},
+		"swapping scooter's status refused, because the status isn't expected": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(string(model.StatusMaintenance))
+			},
+			want: model.StatusMaintenance,
+		},
+		"swapping scooter's status failed, because scooter doesn't exist": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).RedisNil()
+			},
+			wantErr: model.ErrScooterNotFound,
+		},
+		"swapping scooter's status failed, because of redis EvalSha error": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockEval(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			got, err := rr.SwapScooterStatus(
+				context.Background(),
+				scooterUUID, model.StatusRented, model.StatusAvailable, model.StatusReserved,
+			)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateScooterBattery(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		logger   *slog.Logger
+		mockHSet func(mock redismock.ClientMock)
+		wantErr  bool
+	}{
+		"updating scooter's battery successfully": {
+			logger: logger,
+			mockHSet: func(mock redismock.ClientMock) {
+				mock.ExpectHSet(
+					metadataKeyPrefix+scooterUUID.String(),
+					metadataBatteryField, 42.5,
+				).SetVal(0)
 			},
-			want:    true,
 			wantErr: false,
 		},
-		"getting scooter's availability failed, because of redisGet error": {
+		"updating scooter's battery failed, because of redis HSet error": {
 			logger: logger,
-			mockGet: func(mock redismock.ClientMock) {
-				mock.ExpectGet(scooterUUID.String()).SetErr(redis.ErrClosed)
+			mockHSet: func(mock redismock.ClientMock) {
+				mock.ExpectHSet(
+					metadataKeyPrefix+scooterUUID.String(),
+					metadataBatteryField, 42.5,
+				).SetErr(redis.ErrClosed)
 			},
-			want:    false,
 			wantErr: true,
 		},
-		"getting scooter's availability failed, because of incorrect redis data": {
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockHSet(mock)
+
+			rr := NewRedisRepository(tt.logger, db)
+
+			if err = rr.UpdateScooterBattery(context.Background(), scooterUUID, 42.5); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateScooterBattery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetScooterMetadata(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		logger      *slog.Logger
+		mockHGetAll func(mock redismock.ClientMock)
+		want        *model.ScooterMetadata
+		wantErr     bool
+	}{
+		"getting scooter's metadata successfully": {
+			logger: logger,
+			mockHGetAll: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(metadataKeyPrefix + scooterUUID.String()).SetVal(map[string]string{
+					metadataCityField:    testCity,
+					metadataStatusField:  string(model.StatusMaintenance),
+					metadataBatteryField: "42.5",
+				})
+			},
+			want:    model.NewScooterMetadata(testCity, model.StatusMaintenance, 42.5),
+			wantErr: false,
+		},
+		"getting scooter's metadata successfully, when scooter has no battery stored": {
+			logger: logger,
+			mockHGetAll: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(metadataKeyPrefix + scooterUUID.String()).SetVal(map[string]string{
+					metadataCityField:   testCity,
+					metadataStatusField: string(model.StatusAvailable),
+				})
+			},
+			want:    model.NewScooterMetadata(testCity, model.StatusAvailable, model.FullBattery),
+			wantErr: false,
+		},
+		"getting scooter's metadata failed, because scooter does not exist": {
 			logger: logger,
-			mockGet: func(mock redismock.ClientMock) {
-				mock.ExpectGet(scooterUUID.String()).SetVal(string(scooterAvailabilityIncorrectJSON))
+			mockHGetAll: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(metadataKeyPrefix + scooterUUID.String()).SetVal(map[string]string{})
 			},
-			want:    false,
+			want:    nil,
+			wantErr: true,
+		},
+		"getting scooter's metadata failed, because of redis HGetAll error": {
+			logger: logger,
+			mockHGetAll: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(metadataKeyPrefix + scooterUUID.String()).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
 			wantErr: true,
 		},
 	}
@@ -206,24 +429,24 @@ func TestGetScooterAvailability(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			db, mock := redismock.NewClientMock()
 
-			tt.mockGet(mock)
+			tt.mockHGetAll(mock)
 
 			rr := NewRedisRepository(tt.logger, db)
 
-			got, err := rr.GetScooterAvailability(scooterUUID)
+			got, err := rr.GetScooterMetadata(context.Background(), scooterUUID)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("GetScooterAvailability() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("GetScooterMetadata() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("GetScooterAvailability() got = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetScooterMetadata() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestUpdateScooterLocation(t *testing.T) {
-	logger := &log.Logger{}
+func TestAddScooter(t *testing.T) {
+	logger := logging.Discard()
 
 	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -234,65 +457,92 @@ func TestUpdateScooterLocation(t *testing.T) {
 		Latitude:  testLatitude,
 	}
 
+	expectEval := func(mock redismock.ClientMock) *redismock.ExpectedCmd {
+		return mock.ExpectEvalSha(
+			addScooterScript.Hash(),
+			[]string{metadataKeyPrefix + scooterUUID.String(), testCity},
+			metadataCityField, testCity,
+			metadataStatusField, string(model.StatusAvailable),
+			metadataBatteryField, model.FullBattery,
+			testLongitude, testLatitude, scooterUUID.String(),
+		)
+	}
+
 	tests := map[string]struct {
-		logger     *log.Logger
-		mockGeoAdd func(mock redismock.ClientMock)
-		wantErr    bool
+		logger   *slog.Logger
+		mockEval func(mock redismock.ClientMock)
+		want     bool
+		wantErr  error
 	}{
-		"updating scooter's location successfully": {
+		"adding scooter successfully": {
 			logger: logger,
-			mockGeoAdd: func(mock redismock.ClientMock) {
-				mock.ExpectGeoAdd(testCity, scooter).SetVal(1)
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(int64(1))
 			},
-			wantErr: false,
+			want: true,
 		},
-		"updating scooter's location failed, because of GeoAdd error": {
+		"adding scooter refused, because scooter already exists": {
 			logger: logger,
-			mockGeoAdd: func(mock redismock.ClientMock) {
-				mock.ExpectGeoAdd(testCity, scooter).SetErr(redis.ErrClosed)
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetVal(int64(0))
 			},
-			wantErr: true,
+			want: false,
+		},
+		"adding scooter failed, because of redis EvalSha error": {
+			logger: logger,
+			mockEval: func(mock redismock.ClientMock) {
+				expectEval(mock).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
 		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			db, mock := redismock.NewClientMock()
 
-			tt.mockGeoAdd(mock)
+			tt.mockEval(mock)
 
 			rr := NewRedisRepository(tt.logger, db)
 
-			if err = rr.UpdateScooterLocation(scooter, testCity); (err != nil) != tt.wantErr {
-				t.Errorf("UpdateScooterLocation() error = %v, wantErr %v", err, tt.wantErr)
-			}
+			metadata := model.NewScooterMetadata(testCity, model.StatusAvailable, model.FullBattery)
+
+			got, err := rr.AddScooter(context.Background(), scooter, metadata)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestUpdateScooterAvailability(t *testing.T) {
-	logger := &log.Logger{}
+func TestRemoveScooter(t *testing.T) {
+	logger := logging.Discard()
 
 	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
-	scooterAvailability := true
-
 	tests := map[string]struct {
-		logger  *log.Logger
-		mockSet func(mock redismock.ClientMock)
-		wantErr bool
+		logger       *slog.Logger
+		mockPipeline func(mock redismock.ClientMock)
+		wantErr      bool
 	}{
-		"updating scooter's availability successfully": {
+		"removing scooter successfully": {
 			logger: logger,
-			mockSet: func(mock redismock.ClientMock) {
-				mock.ExpectSet(scooterUUID.String(), scooterAvailability, 0).SetVal("status")
+			mockPipeline: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectZRem(testCity, scooterUUID.String()).SetVal(1)
+				mock.ExpectDel(
+					metadataKeyPrefix+scooterUUID.String(),
+					reservationKeyPrefix+scooterUUID.String(),
+				).SetVal(1)
+				mock.ExpectTxPipelineExec()
 			},
 			wantErr: false,
 		},
-		"updating scooter's availability failed, because of redis Set error": {
+		"removing scooter failed, because of redis transaction error": {
 			logger: logger,
-			mockSet: func(mock redismock.ClientMock) {
-				mock.ExpectSet(scooterUUID.String(), scooterAvailability, 0).SetErr(redis.ErrClosed)
+			mockPipeline: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectZRem(testCity, scooterUUID.String()).SetErr(redis.ErrClosed)
 			},
 			wantErr: true,
 		},
@@ -301,12 +551,12 @@ func TestUpdateScooterAvailability(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			db, mock := redismock.NewClientMock()
 
-			tt.mockSet(mock)
+			tt.mockPipeline(mock)
 
 			rr := NewRedisRepository(tt.logger, db)
 
-			if err = rr.UpdateScooterAvailability(scooterUUID, scooterAvailability); (err != nil) != tt.wantErr {
-				t.Errorf("UpdateScooterAvailability() error = %v, wantErr %v", err, tt.wantErr)
+			if err = rr.RemoveScooter(context.Background(), scooterUUID, testCity); (err != nil) != tt.wantErr {
+				t.Errorf("RemoveScooter() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
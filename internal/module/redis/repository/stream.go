@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	streamTypeField = "type"
+	streamDataField = "data"
+
+	// New groups start with the messages added after them, like subscribers of a channel do
+	streamGroupStart = "$"
+	streamNewOnly    = ">"
+	// Pending messages are read from the beginning, XAUTOCLAIM answers with it once it went through all of them
+	streamPendingStart = "0-0"
+
+	busyGroupPrefix = "BUSYGROUP"
+)
+
+// AppendToStream adds the message to the stream, which is trimmed to about maxLength of the newest messages.
+func (rr *redisRepository) AppendToStream(
+	ctx context.Context,
+	stream string,
+	maxLength int64,
+	messageType string,
+	data []byte,
+) (string, error) {
+	id, err := rr.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLength,
+		Approx: true,
+		Values: []interface{}{streamTypeField, messageType, streamDataField, data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("appending to stream %s: %w", stream, domain.Unavailable(err))
+	}
+
+	return id, nil
+}
+
+// CreateStreamGroup creates the consumer group of the stream unless it exists already, the stream is created with it.
+func (rr *redisRepository) CreateStreamGroup(ctx context.Context, stream, group string) error {
+	err := rr.client.XGroupCreateMkStream(ctx, stream, group, streamGroupStart).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), busyGroupPrefix) {
+		return fmt.Errorf("creating group %s of stream %s: %w", group, stream, domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+// ReadStreamGroup returns up to count messages no other consumer of the group got yet, waiting up to block for
+// them. No messages within block are not an error.
+func (rr *redisRepository) ReadStreamGroup(
+	ctx context.Context,
+	stream, group, consumer string,
+	count int64,
+	block time.Duration,
+) ([]*model.StreamMessage, error) {
+	streams, err := rr.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, streamNewOnly},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading group %s of stream %s: %w", group, stream, domain.Unavailable(err))
+	}
+
+	var messages []*model.StreamMessage
+
+	for _, result := range streams {
+		messages = append(messages, streamMessages(result.Messages)...)
+	}
+
+	return messages, nil
+}
+
+// ReadPendingStreamGroup returns up to count messages the consumer got but didn't acknowledge, the ones left over
+// when it stopped before handling them. It starts after the from ID, or at the beginning when from is empty. Messages
+// trimmed from the stream in the meantime come without data.
+func (rr *redisRepository) ReadPendingStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	count int64,
+) ([]*model.StreamMessage, error) {
+	if from == "" {
+		from = streamPendingStart
+	}
+
+	streams, err := rr.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, from},
+		Count:    count,
+		Block:    -1, // the pending messages are answered right away
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading pending of group %s of stream %s: %w", group, stream, domain.Unavailable(err))
+	}
+
+	var messages []*model.StreamMessage
+
+	for _, result := range streams {
+		messages = append(messages, streamMessages(result.Messages)...)
+	}
+
+	return messages, nil
+}
+
+// ClaimStreamGroup hands the consumer up to count messages other consumers of the group got but didn't acknowledge
+// within minIdle, most likely because they are gone. It starts at the from ID, or at the beginning when from is
+// empty, and returns the ID to continue from, which is empty once all pending messages were gone through.
+func (rr *redisRepository) ClaimStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	minIdle time.Duration,
+	count int64,
+) ([]*model.StreamMessage, string, error) {
+	if from == "" {
+		from = streamPendingStart
+	}
+
+	claimed, next, err := rr.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    from,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("claiming from group %s of stream %s: %w", group, stream, domain.Unavailable(err))
+	}
+
+	if next == streamPendingStart {
+		next = ""
+	}
+
+	return streamMessages(claimed), next, nil
+}
+
+func streamMessages(messages []redis.XMessage) []*model.StreamMessage {
+	converted := make([]*model.StreamMessage, 0, len(messages))
+
+	for _, message := range messages {
+		messageType, _ := message.Values[streamTypeField].(string)
+		data, _ := message.Values[streamDataField].(string)
+
+		converted = append(converted, model.NewStreamMessage(message.ID, messageType, []byte(data)))
+	}
+
+	return converted
+}
+
+// AckStreamMessage marks the message as processed by the group.
+func (rr *redisRepository) AckStreamMessage(ctx context.Context, stream, group, id string) error {
+	if err := rr.client.XAck(ctx, stream, group, id).Err(); err != nil {
+		return fmt.Errorf("acknowledging message %s of stream %s: %w", id, stream, domain.Unavailable(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,363 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	testStream    = "fleet-events"
+	testGroup     = "webhooks"
+	testConsumer  = "instance-1"
+	testMessageID = "1700000000000-0"
+)
+
+func TestAppendToStream(t *testing.T) {
+	logger := logging.Discard()
+
+	data := []byte(`{"scooterUUID":"9c3c3f5e-2f4b-4bd5-9a36-1d1a2c8a4a11"}`)
+
+	args := &redis.XAddArgs{
+		Stream: testStream,
+		MaxLen: 1000,
+		Approx: true,
+		Values: []interface{}{streamTypeField, "scooter_moved", streamDataField, data},
+	}
+
+	tests := map[string]struct {
+		mockXAdd func(mock redismock.ClientMock)
+		wantID   string
+		wantErr  error
+	}{
+		"appending to stream successfully": {
+			mockXAdd: func(mock redismock.ClientMock) {
+				mock.ExpectXAdd(args).SetVal(testMessageID)
+			},
+			wantID:  testMessageID,
+			wantErr: nil,
+		},
+		"appending to stream failed, because of redis XAdd error": {
+			mockXAdd: func(mock redismock.ClientMock) {
+				mock.ExpectXAdd(args).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockXAdd(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			id, err := rr.AppendToStream(context.Background(), testStream, 1000, "scooter_moved", data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AppendToStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.wantID, id)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateStreamGroup(t *testing.T) {
+	logger := logging.Discard()
+
+	tests := map[string]struct {
+		mockXGroupCreate func(mock redismock.ClientMock)
+		wantErr          error
+	}{
+		"creating stream group successfully": {
+			mockXGroupCreate: func(mock redismock.ClientMock) {
+				mock.ExpectXGroupCreateMkStream(testStream, testGroup, streamGroupStart).SetVal("OK")
+			},
+			wantErr: nil,
+		},
+		"creating stream group that already exists": {
+			mockXGroupCreate: func(mock redismock.ClientMock) {
+				mock.ExpectXGroupCreateMkStream(testStream, testGroup, streamGroupStart).
+					SetErr(errors.New("BUSYGROUP Consumer Group name already exists"))
+			},
+			wantErr: nil,
+		},
+		"creating stream group failed, because of redis XGroupCreateMkStream error": {
+			mockXGroupCreate: func(mock redismock.ClientMock) {
+				mock.ExpectXGroupCreateMkStream(testStream, testGroup, streamGroupStart).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockXGroupCreate(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			if err := rr.CreateStreamGroup(context.Background(), testStream, testGroup); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateStreamGroup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReadStreamGroup(t *testing.T) {
+	logger := logging.Discard()
+
+	args := &redis.XReadGroupArgs{
+		Group:    testGroup,
+		Consumer: testConsumer,
+		Streams:  []string{testStream, streamNewOnly},
+		Count:    10,
+		Block:    time.Second,
+	}
+
+	tests := map[string]struct {
+		mockXReadGroup func(mock redismock.ClientMock)
+		want           []*model.StreamMessage
+		wantErr        error
+	}{
+		"reading stream group successfully": {
+			mockXReadGroup: func(mock redismock.ClientMock) {
+				mock.ExpectXReadGroup(args).SetVal([]redis.XStream{{
+					Stream: testStream,
+					Messages: []redis.XMessage{{
+						ID:     testMessageID,
+						Values: map[string]interface{}{streamTypeField: "scooter_moved", streamDataField: "{}"},
+					}},
+				}})
+			},
+			want:    []*model.StreamMessage{model.NewStreamMessage(testMessageID, "scooter_moved", []byte("{}"))},
+			wantErr: nil,
+		},
+		"reading stream group without new messages": {
+			mockXReadGroup: func(mock redismock.ClientMock) {
+				mock.ExpectXReadGroup(args).RedisNil()
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		"reading stream group failed, because of redis XReadGroup error": {
+			mockXReadGroup: func(mock redismock.ClientMock) {
+				mock.ExpectXReadGroup(args).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockXReadGroup(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			messages, err := rr.ReadStreamGroup(context.Background(), testStream, testGroup, testConsumer, 10, time.Second)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadStreamGroup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.want, messages)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReadPendingStreamGroup(t *testing.T) {
+	logger := logging.Discard()
+
+	args := func(from string) *redis.XReadGroupArgs {
+		return &redis.XReadGroupArgs{
+			Group:    testGroup,
+			Consumer: testConsumer,
+			Streams:  []string{testStream, from},
+			Count:    10,
+			Block:    -1,
+		}
+	}
+
+	tests := map[string]struct {
+		from           string
+		mockXReadGroup func(mock redismock.ClientMock)
+		want           []*model.StreamMessage
+		wantErr        error
+	}{
+		"reading pending messages from the beginning successfully": {
+			from: "",
+			mockXReadGroup: func(mock redismock.ClientMock) {
+				mock.ExpectXReadGroup(args(streamPendingStart)).SetVal([]redis.XStream{{
+					Stream: testStream,
+					Messages: []redis.XMessage{{
+						ID:     testMessageID,
+						Values: map[string]interface{}{streamTypeField: "scooter_moved", streamDataField: "{}"},
+					}},
+				}})
+			},
+			want:    []*model.StreamMessage{model.NewStreamMessage(testMessageID, "scooter_moved", []byte("{}"))},
+			wantErr: nil,
+		},
+		"reading pending messages after the last one successfully": {
+			from: testMessageID,
+			mockXReadGroup: func(mock redismock.ClientMock) {
+				mock.ExpectXReadGroup(args(testMessageID)).SetVal([]redis.XStream{{Stream: testStream}})
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		"reading pending messages failed, because of redis XReadGroup error": {
+			from: "",
+			mockXReadGroup: func(mock redismock.ClientMock) {
+				mock.ExpectXReadGroup(args(streamPendingStart)).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockXReadGroup(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			messages, err := rr.ReadPendingStreamGroup(context.Background(), testStream, testGroup, testConsumer, tt.from, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadPendingStreamGroup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.want, messages)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestClaimStreamGroup(t *testing.T) {
+	logger := logging.Discard()
+
+	args := func(from string) *redis.XAutoClaimArgs {
+		return &redis.XAutoClaimArgs{
+			Stream:   testStream,
+			Group:    testGroup,
+			Consumer: testConsumer,
+			MinIdle:  time.Minute,
+			Start:    from,
+			Count:    10,
+		}
+	}
+
+	claimed := []redis.XMessage{{
+		ID:     testMessageID,
+		Values: map[string]interface{}{streamTypeField: "scooter_moved", streamDataField: "{}"},
+	}}
+
+	tests := map[string]struct {
+		from       string
+		mockXClaim func(mock redismock.ClientMock)
+		want       []*model.StreamMessage
+		wantNext   string
+		wantErr    error
+	}{
+		"claiming messages with more to go through successfully": {
+			from: "",
+			mockXClaim: func(mock redismock.ClientMock) {
+				mock.ExpectXAutoClaim(args(streamPendingStart)).SetVal(claimed, "1700000000001-0")
+			},
+			want:     []*model.StreamMessage{model.NewStreamMessage(testMessageID, "scooter_moved", []byte("{}"))},
+			wantNext: "1700000000001-0",
+			wantErr:  nil,
+		},
+		"claiming the last messages successfully": {
+			from: "1700000000001-0",
+			mockXClaim: func(mock redismock.ClientMock) {
+				mock.ExpectXAutoClaim(args("1700000000001-0")).SetVal(nil, streamPendingStart)
+			},
+			want:     []*model.StreamMessage{},
+			wantNext: "",
+			wantErr:  nil,
+		},
+		"claiming messages failed, because of redis XAutoClaim error": {
+			from: "",
+			mockXClaim: func(mock redismock.ClientMock) {
+				mock.ExpectXAutoClaim(args(streamPendingStart)).SetErr(redis.ErrClosed)
+			},
+			want:     nil,
+			wantNext: "",
+			wantErr:  redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockXClaim(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			messages, next, err := rr.ClaimStreamGroup(context.Background(), testStream, testGroup, testConsumer, tt.from,
+				time.Minute, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ClaimStreamGroup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.want, messages)
+			require.Equal(t, tt.wantNext, next)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAckStreamMessage(t *testing.T) {
+	logger := logging.Discard()
+
+	tests := map[string]struct {
+		mockXAck func(mock redismock.ClientMock)
+		wantErr  error
+	}{
+		"acknowledging stream message successfully": {
+			mockXAck: func(mock redismock.ClientMock) {
+				mock.ExpectXAck(testStream, testGroup, testMessageID).SetVal(1)
+			},
+			wantErr: nil,
+		},
+		"acknowledging stream message failed, because of redis XAck error": {
+			mockXAck: func(mock redismock.ClientMock) {
+				mock.ExpectXAck(testStream, testGroup, testMessageID).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockXAck(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			err := rr.AckStreamMessage(context.Background(), testStream, testGroup, testMessageID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AckStreamMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/tracing"
+)
+
+const pipelineLengthKey = attribute.Key("db.redis.pipeline_length")
+
+// tracingHook spans every command the client sends, under the span of the operation that sent it. The arguments of
+// the commands are left out, they hold client data.
+type tracingHook struct{}
+
+func NewTracingHook() *tracingHook {
+	return &tracingHook{}
+}
+
+func (th *tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (th *tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := tracing.Tracer().Start(
+			ctx,
+			cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperation(cmd.Name())),
+		)
+
+		err := next(ctx, cmd)
+
+		endCommandSpan(span, err)
+
+		return err
+	}
+}
+
+// ProcessPipelineHook spans the pipeline and transaction as a whole, naming its commands in the order they are sent.
+func (th *tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+
+		for _, cmd := range cmds {
+			names = append(names, cmd.Name())
+		}
+
+		ctx, span := tracing.Tracer().Start(
+			ctx,
+			"pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemRedis,
+				semconv.DBOperation(strings.Join(names, " ")),
+				pipelineLengthKey.Int(len(cmds)),
+			),
+		)
+
+		err := next(ctx, cmds)
+
+		endCommandSpan(span, err)
+
+		return err
+	}
+}
+
+// endCommandSpan ends the span of the command, failing it unless the command succeeded or only found no key.
+func endCommandSpan(span trace.Span, err error) {
+	defer span.End()
+
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,115 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/tracing"
+)
+
+func TestTracingHook(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		cmds          []redis.Cmder
+		err           error
+		wantName      string
+		wantOperation string
+		wantStatus    codes.Code
+	}{
+		"command succeeded": {
+			cmds:          []redis.Cmder{redis.NewMapStringStringCmd(ctx, "hgetall", "scooter:1")},
+			err:           nil,
+			wantName:      "hgetall",
+			wantOperation: "hgetall",
+			wantStatus:    codes.Unset,
+		},
+		"command found no key": {
+			cmds:          []redis.Cmder{redis.NewStringCmd(ctx, "get", "rental:active:1")},
+			err:           redis.Nil,
+			wantName:      "get",
+			wantOperation: "get",
+			wantStatus:    codes.Unset,
+		},
+		"command failed": {
+			cmds:          []redis.Cmder{redis.NewMapStringStringCmd(ctx, "hgetall", "scooter:1")},
+			err:           redis.ErrClosed,
+			wantName:      "hgetall",
+			wantOperation: "hgetall",
+			wantStatus:    codes.Error,
+		},
+		"pipeline succeeded": {
+			cmds: []redis.Cmder{
+				redis.NewIntCmd(ctx, "hset", "rental:1", "city", "Ottawa"),
+				redis.NewStatusCmd(ctx, "set", "rental:active:1", "1"),
+			},
+			err:           nil,
+			wantName:      "pipeline",
+			wantOperation: "hset set",
+			wantStatus:    codes.Unset,
+		},
+		"pipeline failed": {
+			cmds: []redis.Cmder{
+				redis.NewIntCmd(ctx, "hset", "rental:1", "city", "Ottawa"),
+				redis.NewStatusCmd(ctx, "set", "rental:active:1", "1"),
+			},
+			err:           redis.ErrClosed,
+			wantName:      "pipeline",
+			wantOperation: "hset set",
+			wantStatus:    codes.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tracing.Register(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			hook := NewTracingHook()
+
+			parentCtx, parent := tracing.Start(ctx, "RedisService.Operation")
+
+			// The command is sent within its span
+			var sentIn trace.SpanContext
+
+			if len(tt.cmds) == 1 {
+				err := hook.ProcessHook(func(ctx context.Context, _ redis.Cmder) error {
+					sentIn = trace.SpanContextFromContext(ctx)
+
+					return tt.err
+				})(parentCtx, tt.cmds[0])
+				require.Equal(t, tt.err, err)
+			} else {
+				err := hook.ProcessPipelineHook(func(ctx context.Context, _ []redis.Cmder) error {
+					sentIn = trace.SpanContextFromContext(ctx)
+
+					return tt.err
+				})(parentCtx, tt.cmds)
+				require.Equal(t, tt.err, err)
+			}
+
+			parent.End()
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 2)
+
+			command := spans[0]
+			require.Equal(t, tt.wantName, command.Name())
+			require.Equal(t, parent.SpanContext().SpanID(), command.Parent().SpanID())
+			require.Equal(t, command.SpanContext().SpanID(), sentIn.SpanID())
+			require.Equal(t, trace.SpanKindClient, command.SpanKind())
+			require.Equal(t, tt.wantStatus, command.Status().Code)
+			require.Contains(t, command.Attributes(), semconv.DBSystemRedis)
+			require.Contains(t, command.Attributes(), semconv.DBOperation(tt.wantOperation))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+const (
+	webhookSubscriptionsKey = "webhook:subscriptions"
+	webhookDeadLettersKey   = "webhook:dead-letters"
+	// webhookDeliveredPrefix keys the set of the subscriptions each delivery reached, by the delivery ID.
+	webhookDeliveredPrefix = "webhook:delivered:"
+)
+
+// SaveWebhookSubscription stores the subscription under its ID, replacing the one stored before.
+func (rr *redisRepository) SaveWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	encoded, err := json.Marshal(subscription)
+	if err != nil {
+		return fmt.Errorf("encoding webhook subscription: %w", err)
+	}
+
+	err = rr.client.HSet(ctx, webhookSubscriptionsKey, subscription.ID.String(), encoded).Err()
+	if err != nil {
+		return fmt.Errorf("saving webhook subscription in redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+// GetWebhookSubscriptions returns every subscription, the ones that can't be decoded are skipped.
+func (rr *redisRepository) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	encoded, err := rr.client.HGetAll(ctx, webhookSubscriptionsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook subscriptions from redis: %w", domain.Unavailable(err))
+	}
+
+	subscriptions := make([]*model.WebhookSubscription, 0, len(encoded))
+
+	for id, value := range encoded {
+		var subscription model.WebhookSubscription
+
+		if err = json.Unmarshal([]byte(value), &subscription); err != nil {
+			rr.logger.Warn("decoding webhook subscription failed", "webhook_id", id, logging.KeyError, err)
+
+			continue
+		}
+
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func (rr *redisRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	deleted, err := rr.client.HDel(ctx, webhookSubscriptionsKey, id.String()).Result()
+	if err != nil {
+		return fmt.Errorf("deleting webhook subscription from redis: %w", domain.Unavailable(err))
+	}
+
+	if deleted == 0 {
+		return model.ErrWebhookSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// AddWebhookDeadLetter puts the letter first on the list, which keeps maxLength of the newest letters.
+func (rr *redisRepository) AddWebhookDeadLetter(
+	ctx context.Context,
+	letter *model.WebhookDeadLetter, maxLength int64,
+) error {
+	encoded, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("encoding webhook dead letter: %w", err)
+	}
+
+	_, err = rr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, webhookDeadLettersKey, encoded)
+		pipe.LTrim(ctx, webhookDeadLettersKey, 0, maxLength-1)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("adding webhook dead letter to redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+// GetWebhookDeadLetters returns up to limit of the newest letters, newest first.
+func (rr *redisRepository) GetWebhookDeadLetters(ctx context.Context, limit int64) ([]*model.WebhookDeadLetter, error) {
+	encoded, err := rr.client.LRange(ctx, webhookDeadLettersKey, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook dead letters from redis: %w", domain.Unavailable(err))
+	}
+
+	letters := make([]*model.WebhookDeadLetter, 0, len(encoded))
+
+	for _, value := range encoded {
+		var letter model.WebhookDeadLetter
+
+		if err = json.Unmarshal([]byte(value), &letter); err != nil {
+			rr.logger.Warn("decoding webhook dead letter failed", logging.KeyError, err)
+
+			continue
+		}
+
+		letters = append(letters, &letter)
+	}
+
+	return letters, nil
+}
+
+// MarkWebhookDelivered records that the subscription is done with the delivery, the record expires after ttl.
+func (rr *redisRepository) MarkWebhookDelivered(
+	ctx context.Context,
+	deliveryID, subscriptionID uuid.UUID,
+	ttl time.Duration,
+) error {
+	key := webhookDeliveredPrefix + deliveryID.String()
+
+	_, err := rr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, subscriptionID.String())
+		pipe.Expire(ctx, key, ttl)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("marking webhook delivered in redis: %w", domain.Unavailable(err))
+	}
+
+	return nil
+}
+
+// GetWebhookDelivered returns the subscriptions done with the delivery, the IDs that can't be parsed are skipped.
+func (rr *redisRepository) GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error) {
+	members, err := rr.client.SMembers(ctx, webhookDeliveredPrefix+deliveryID.String()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook delivered from redis: %w", domain.Unavailable(err))
+	}
+
+	subscriptionIDs := make([]uuid.UUID, 0, len(members))
+
+	for _, member := range members {
+		subscriptionID, err := uuid.Parse(member)
+		if err != nil {
+			rr.logger.Warn("parsing delivered webhook id failed", "webhook_id", member, logging.KeyError, err)
+
+			continue
+		}
+
+		subscriptionIDs = append(subscriptionIDs, subscriptionID)
+	}
+
+	return subscriptionIDs, nil
+}
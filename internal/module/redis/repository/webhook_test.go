@@ -0,0 +1,280 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+func testWebhookSubscription(t *testing.T) (*model.WebhookSubscription, string) {
+	t.Helper()
+
+	subscription := model.NewWebhookSubscription(
+		uuid.New(),
+		"https://billing.example.com/hooks",
+		"webhook_secret",
+		[]string{"scooter_rented", "scooter_freed"},
+		time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+	)
+
+	encoded, err := json.Marshal(subscription)
+	require.NoError(t, err)
+
+	return subscription, string(encoded)
+}
+
+func TestSaveWebhookSubscription(t *testing.T) {
+	logger := logging.Discard()
+
+	subscription, encoded := testWebhookSubscription(t)
+
+	tests := map[string]struct {
+		mockHSet func(mock redismock.ClientMock)
+		wantErr  error
+	}{
+		"saving webhook subscription successfully": {
+			mockHSet: func(mock redismock.ClientMock) {
+				mock.ExpectHSet(webhookSubscriptionsKey, subscription.ID.String(), []byte(encoded)).SetVal(1)
+			},
+			wantErr: nil,
+		},
+		"saving webhook subscription failed, because of redis HSet error": {
+			mockHSet: func(mock redismock.ClientMock) {
+				mock.ExpectHSet(webhookSubscriptionsKey, subscription.ID.String(), []byte(encoded)).
+					SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockHSet(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			if err := rr.SaveWebhookSubscription(context.Background(), subscription); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SaveWebhookSubscription() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetWebhookSubscriptions(t *testing.T) {
+	logger := logging.Discard()
+
+	subscription, encoded := testWebhookSubscription(t)
+
+	tests := map[string]struct {
+		mockHGetAll func(mock redismock.ClientMock)
+		want        []*model.WebhookSubscription
+		wantErr     error
+	}{
+		"getting webhook subscriptions successfully, skipping the broken one": {
+			mockHGetAll: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(webhookSubscriptionsKey).SetVal(map[string]string{
+					subscription.ID.String(): encoded,
+					uuid.NewString():         "{broken",
+				})
+			},
+			want:    []*model.WebhookSubscription{subscription},
+			wantErr: nil,
+		},
+		"getting webhook subscriptions failed, because of redis HGetAll error": {
+			mockHGetAll: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(webhookSubscriptionsKey).SetErr(redis.ErrClosed)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockHGetAll(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			subscriptions, err := rr.GetWebhookSubscriptions(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetWebhookSubscriptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.want, subscriptions)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteWebhookSubscription(t *testing.T) {
+	logger := logging.Discard()
+
+	id := uuid.New()
+
+	tests := map[string]struct {
+		mockHDel func(mock redismock.ClientMock)
+		wantErr  error
+	}{
+		"deleting webhook subscription successfully": {
+			mockHDel: func(mock redismock.ClientMock) {
+				mock.ExpectHDel(webhookSubscriptionsKey, id.String()).SetVal(1)
+			},
+			wantErr: nil,
+		},
+		"deleting webhook subscription failed, because it does not exist": {
+			mockHDel: func(mock redismock.ClientMock) {
+				mock.ExpectHDel(webhookSubscriptionsKey, id.String()).SetVal(0)
+			},
+			wantErr: model.ErrWebhookSubscriptionNotFound,
+		},
+		"deleting webhook subscription failed, because of redis HDel error": {
+			mockHDel: func(mock redismock.ClientMock) {
+				mock.ExpectHDel(webhookSubscriptionsKey, id.String()).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockHDel(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			if err := rr.DeleteWebhookSubscription(context.Background(), id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteWebhookSubscription() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWebhookDeadLetters(t *testing.T) {
+	logger := logging.Discard()
+
+	subscription, _ := testWebhookSubscription(t)
+
+	letter := model.NewWebhookDeadLetter(
+		uuid.New(),
+		subscription,
+		"scooter_freed",
+		[]byte(`{"type":"scooter_freed"}`),
+		5,
+		"endpoint responded with 500 Internal Server Error",
+		time.Date(2024, time.January, 1, 12, 5, 0, 0, time.UTC),
+	)
+
+	encoded, err := json.Marshal(letter)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedis func(mock redismock.ClientMock)
+		wantErr   error
+	}{
+		"adding and getting webhook dead letters successfully": {
+			mockRedis: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectLPush(webhookDeadLettersKey, encoded).SetVal(1)
+				mock.ExpectLTrim(webhookDeadLettersKey, 0, 99).SetVal("OK")
+				mock.ExpectTxPipelineExec()
+				mock.ExpectLRange(webhookDeadLettersKey, 0, 9).SetVal([]string{string(encoded)})
+			},
+			wantErr: nil,
+		},
+		"adding webhook dead letter failed, because of redis pipeline error": {
+			mockRedis: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectLPush(webhookDeadLettersKey, encoded).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockRedis(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			err := rr.AddWebhookDeadLetter(context.Background(), letter, 100)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddWebhookDeadLetter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				letters, getErr := rr.GetWebhookDeadLetters(context.Background(), 10)
+				require.NoError(t, getErr)
+				require.Equal(t, []*model.WebhookDeadLetter{letter}, letters)
+			}
+		})
+	}
+}
+
+func TestWebhookDelivered(t *testing.T) {
+	logger := logging.Discard()
+
+	deliveryID, subscriptionID := uuid.New(), uuid.New()
+	key := webhookDeliveredPrefix + deliveryID.String()
+
+	tests := map[string]struct {
+		mockRedis func(mock redismock.ClientMock)
+		wantErr   error
+	}{
+		"marking and getting webhook delivered successfully": {
+			mockRedis: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectSAdd(key, subscriptionID.String()).SetVal(1)
+				mock.ExpectExpire(key, time.Hour).SetVal(true)
+				mock.ExpectTxPipelineExec()
+				mock.ExpectSMembers(key).SetVal([]string{subscriptionID.String(), "not-a-uuid"})
+			},
+			wantErr: nil,
+		},
+		"marking webhook delivered failed, because of redis pipeline error": {
+			mockRedis: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectSAdd(key, subscriptionID.String()).SetErr(redis.ErrClosed)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+
+			tt.mockRedis(mock)
+
+			rr := NewRedisRepository(logger, db)
+
+			err := rr.MarkWebhookDelivered(context.Background(), deliveryID, subscriptionID, time.Hour)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("MarkWebhookDelivered() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				subscriptionIDs, getErr := rr.GetWebhookDelivered(context.Background(), deliveryID)
+				require.NoError(t, getErr)
+				require.Equal(t, []uuid.UUID{subscriptionID}, subscriptionIDs)
+			}
+		})
+	}
+}
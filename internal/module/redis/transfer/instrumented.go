@@ -0,0 +1,549 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/metrics"
+	"scootinAboot/internal/module/redis/model"
+)
+
+// instrumentedRedisRepository measures how long every operation of the repository takes and counts the failed ones.
+// SubscribeScooterEvents and ReadStreamGroup are passed through, they wait for messages, so their time says nothing
+// about Redis.
+type instrumentedRedisRepository struct {
+	RedisRepository
+}
+
+func NewInstrumentedRedisRepository(repository RedisRepository) *instrumentedRedisRepository {
+	return &instrumentedRedisRepository{RedisRepository: repository}
+}
+
+func (ir *instrumentedRedisRepository) GetScooters(
+	ctx context.Context,
+	longitude, latitude, radius float64, city string,
+) ([]redis.GeoLocation, error) {
+	start := time.Now()
+
+	scooters, err := ir.RedisRepository.GetScooters(ctx, longitude, latitude, radius, city)
+
+	observe("GetScooters", start, err)
+
+	return scooters, err
+}
+
+func (ir *instrumentedRedisRepository) GetScooterLocation(
+	ctx context.Context,
+	scooterUUID uuid.UUID, city string) (*redis.GeoPos, error,
+) {
+	start := time.Now()
+
+	location, err := ir.RedisRepository.GetScooterLocation(ctx, scooterUUID, city)
+
+	observe("GetScooterLocation", start, err)
+
+	return location, err
+}
+
+func (ir *instrumentedRedisRepository) GetScooterMetadata(
+	ctx context.Context,
+	scooterUUID uuid.UUID) (*model.ScooterMetadata, error,
+) {
+	start := time.Now()
+
+	metadata, err := ir.RedisRepository.GetScooterMetadata(ctx, scooterUUID)
+
+	observe("GetScooterMetadata", start, err)
+
+	return metadata, err
+}
+
+func (ir *instrumentedRedisRepository) UpdateScooterLocation(
+	ctx context.Context,
+	scooter *redis.GeoLocation, city string,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.UpdateScooterLocation(ctx, scooter, city)
+
+	observe("UpdateScooterLocation", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) AddScooter(
+	ctx context.Context,
+	scooter *redis.GeoLocation, metadata *model.ScooterMetadata,
+) (bool, error) {
+	start := time.Now()
+
+	added, err := ir.RedisRepository.AddScooter(ctx, scooter, metadata)
+
+	observe("AddScooter", start, err)
+
+	return added, err
+}
+
+func (ir *instrumentedRedisRepository) MoveScooter(
+	ctx context.Context,
+	scooter *redis.GeoLocation, fromCity, toCity string,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.MoveScooter(ctx, scooter, fromCity, toCity)
+
+	observe("MoveScooter", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) UpdateScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.UpdateScooterStatus(ctx, scooterUUID, status)
+
+	observe("UpdateScooterStatus", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) SwapScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+) (model.ScooterStatus, error) {
+	start := time.Now()
+
+	previous, err := ir.RedisRepository.SwapScooterStatus(ctx, scooterUUID, status, expected...)
+
+	observe("SwapScooterStatus", start, err)
+
+	return previous, err
+}
+
+func (ir *instrumentedRedisRepository) UpdateScooterBattery(
+	ctx context.Context,
+	scooterUUID uuid.UUID, battery float64,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.UpdateScooterBattery(ctx, scooterUUID, battery)
+
+	observe("UpdateScooterBattery", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) RemoveScooter(ctx context.Context, scooterUUID uuid.UUID, city string) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.RemoveScooter(ctx, scooterUUID, city)
+
+	observe("RemoveScooter", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) ReserveScooter(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID, ttl time.Duration,
+) (model.ScooterStatus, error) {
+	start := time.Now()
+
+	previous, err := ir.RedisRepository.ReserveScooter(ctx, scooterUUID, clientUUID, ttl)
+
+	observe("ReserveScooter", start, err)
+
+	return previous, err
+}
+
+func (ir *instrumentedRedisRepository) GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error) {
+	start := time.Now()
+
+	clientUUID, err := ir.RedisRepository.GetReservation(ctx, scooterUUID)
+
+	observe("GetReservation", start, err)
+
+	return clientUUID, err
+}
+
+func (ir *instrumentedRedisRepository) ReleaseReservation(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID,
+) (uuid.UUID, model.ScooterStatus, error) {
+	start := time.Now()
+
+	holderUUID, previous, err := ir.RedisRepository.ReleaseReservation(ctx, scooterUUID, clientUUID)
+
+	observe("ReleaseReservation", start, err)
+
+	return holderUUID, previous, err
+}
+
+func (ir *instrumentedRedisRepository) ExpiredReservations(
+	ctx context.Context,
+	before time.Time, count int64,
+) ([]uuid.UUID, error) {
+	start := time.Now()
+
+	scooterUUIDs, err := ir.RedisRepository.ExpiredReservations(ctx, before, count)
+
+	observe("ExpiredReservations", start, err)
+
+	return scooterUUIDs, err
+}
+
+func (ir *instrumentedRedisRepository) ReleaseExpiredReservation(
+	ctx context.Context,
+	scooterUUID uuid.UUID,
+) (bool, error) {
+	start := time.Now()
+
+	released, err := ir.RedisRepository.ReleaseExpiredReservation(ctx, scooterUUID)
+
+	observe("ReleaseExpiredReservation", start, err)
+
+	return released, err
+}
+
+func (ir *instrumentedRedisRepository) DeleteReservation(ctx context.Context, scooterUUID uuid.UUID) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.DeleteReservation(ctx, scooterUUID)
+
+	observe("DeleteReservation", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) AddRental(ctx context.Context, rental *model.Rental) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.AddRental(ctx, rental)
+
+	observe("AddRental", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) GetActiveRental(
+	ctx context.Context,
+	scooterUUID uuid.UUID) (*model.Rental, error,
+) {
+	start := time.Now()
+
+	rental, err := ir.RedisRepository.GetActiveRental(ctx, scooterUUID)
+
+	observe("GetActiveRental", start, err)
+
+	return rental, err
+}
+
+func (ir *instrumentedRedisRepository) GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error) {
+	start := time.Now()
+
+	rental, err := ir.RedisRepository.GetRental(ctx, rentalID)
+
+	observe("GetRental", start, err)
+
+	return rental, err
+}
+
+func (ir *instrumentedRedisRepository) FinishRental(ctx context.Context, rental *model.Rental) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.FinishRental(ctx, rental)
+
+	observe("FinishRental", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error) {
+	start := time.Now()
+
+	rentals, err := ir.RedisRepository.GetActiveRentals(ctx, city)
+
+	observe("GetActiveRentals", start, err)
+
+	return rentals, err
+}
+
+func (ir *instrumentedRedisRepository) GetClientRentals(
+	ctx context.Context,
+	clientUUID uuid.UUID, query *model.RentalsQuery,
+) (*model.RentalsPage, error) {
+	start := time.Now()
+
+	page, err := ir.RedisRepository.GetClientRentals(ctx, clientUUID, query)
+
+	observe("GetClientRentals", start, err)
+
+	return page, err
+}
+
+func (ir *instrumentedRedisRepository) GetScooterRentals(
+	ctx context.Context,
+	scooterUUID uuid.UUID, query *model.RentalsQuery,
+) (*model.RentalsPage, error) {
+	start := time.Now()
+
+	page, err := ir.RedisRepository.GetScooterRentals(ctx, scooterUUID, query)
+
+	observe("GetScooterRentals", start, err)
+
+	return page, err
+}
+
+func (ir *instrumentedRedisRepository) SetIdempotentResponse(
+	ctx context.Context,
+	key string, response *model.IdempotentResponse, ttl time.Duration,
+) (bool, error) {
+	start := time.Now()
+
+	set, err := ir.RedisRepository.SetIdempotentResponse(ctx, key, response, ttl)
+
+	observe("SetIdempotentResponse", start, err)
+
+	return set, err
+}
+
+func (ir *instrumentedRedisRepository) ReplaceIdempotentResponse(
+	ctx context.Context,
+	key string, response *model.IdempotentResponse, ttl time.Duration,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.ReplaceIdempotentResponse(ctx, key, response, ttl)
+
+	observe("ReplaceIdempotentResponse", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) GetIdempotentResponse(
+	ctx context.Context,
+	key string) (*model.IdempotentResponse, error,
+) {
+	start := time.Now()
+
+	response, err := ir.RedisRepository.GetIdempotentResponse(ctx, key)
+
+	observe("GetIdempotentResponse", start, err)
+
+	return response, err
+}
+
+func (ir *instrumentedRedisRepository) DeleteIdempotentResponse(ctx context.Context, key string) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.DeleteIdempotentResponse(ctx, key)
+
+	observe("DeleteIdempotentResponse", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) TakeRateLimitToken(
+	ctx context.Context,
+	key string, limit int, window time.Duration,
+) (*model.RateLimit, error) {
+	start := time.Now()
+
+	rateLimit, err := ir.RedisRepository.TakeRateLimitToken(ctx, key, limit, window)
+
+	observe("TakeRateLimitToken", start, err)
+
+	return rateLimit, err
+}
+
+func (ir *instrumentedRedisRepository) PublishScooterEvent(ctx context.Context, event *model.ScooterEvent) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.PublishScooterEvent(ctx, event)
+
+	observe("PublishScooterEvent", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) AppendToStream(
+	ctx context.Context,
+	stream string, maxLength int64, messageType string, data []byte,
+) (string, error) {
+	start := time.Now()
+
+	id, err := ir.RedisRepository.AppendToStream(ctx, stream, maxLength, messageType, data)
+
+	observe("AppendToStream", start, err)
+
+	return id, err
+}
+
+func (ir *instrumentedRedisRepository) CreateStreamGroup(ctx context.Context, stream, group string) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.CreateStreamGroup(ctx, stream, group)
+
+	observe("CreateStreamGroup", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) ReadPendingStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	count int64,
+) ([]*model.StreamMessage, error) {
+	start := time.Now()
+
+	messages, err := ir.RedisRepository.ReadPendingStreamGroup(ctx, stream, group, consumer, from, count)
+
+	observe("ReadPendingStreamGroup", start, err)
+
+	return messages, err
+}
+
+func (ir *instrumentedRedisRepository) ClaimStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	minIdle time.Duration,
+	count int64,
+) ([]*model.StreamMessage, string, error) {
+	start := time.Now()
+
+	messages, next, err := ir.RedisRepository.ClaimStreamGroup(ctx, stream, group, consumer, from, minIdle, count)
+
+	observe("ClaimStreamGroup", start, err)
+
+	return messages, next, err
+}
+
+func (ir *instrumentedRedisRepository) AckStreamMessage(ctx context.Context, stream, group, id string) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.AckStreamMessage(ctx, stream, group, id)
+
+	observe("AckStreamMessage", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) SaveWebhookSubscription(
+	ctx context.Context,
+	subscription *model.WebhookSubscription,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.SaveWebhookSubscription(ctx, subscription)
+
+	observe("SaveWebhookSubscription", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) GetWebhookSubscriptions(
+	ctx context.Context,
+) ([]*model.WebhookSubscription, error) {
+	start := time.Now()
+
+	subscriptions, err := ir.RedisRepository.GetWebhookSubscriptions(ctx)
+
+	observe("GetWebhookSubscriptions", start, err)
+
+	return subscriptions, err
+}
+
+func (ir *instrumentedRedisRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.DeleteWebhookSubscription(ctx, id)
+
+	observe("DeleteWebhookSubscription", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) AddWebhookDeadLetter(
+	ctx context.Context,
+	letter *model.WebhookDeadLetter, maxLength int64,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.AddWebhookDeadLetter(ctx, letter, maxLength)
+
+	observe("AddWebhookDeadLetter", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) GetWebhookDeadLetters(
+	ctx context.Context,
+	limit int64) ([]*model.WebhookDeadLetter, error,
+) {
+	start := time.Now()
+
+	letters, err := ir.RedisRepository.GetWebhookDeadLetters(ctx, limit)
+
+	observe("GetWebhookDeadLetters", start, err)
+
+	return letters, err
+}
+
+func (ir *instrumentedRedisRepository) MarkWebhookDelivered(
+	ctx context.Context,
+	deliveryID, subscriptionID uuid.UUID,
+	ttl time.Duration,
+) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.MarkWebhookDelivered(ctx, deliveryID, subscriptionID, ttl)
+
+	observe("MarkWebhookDelivered", start, err)
+
+	return err
+}
+
+func (ir *instrumentedRedisRepository) GetWebhookDelivered(
+	ctx context.Context,
+	deliveryID uuid.UUID,
+) ([]uuid.UUID, error) {
+	start := time.Now()
+
+	subscriptionIDs, err := ir.RedisRepository.GetWebhookDelivered(ctx, deliveryID)
+
+	observe("GetWebhookDelivered", start, err)
+
+	return subscriptionIDs, err
+}
+
+func (ir *instrumentedRedisRepository) Ping(ctx context.Context) error {
+	start := time.Now()
+
+	err := ir.RedisRepository.Ping(ctx)
+
+	observe("Ping", start, err)
+
+	return err
+}
+
+// observe records the operation, domain errors other than Unavailable are answers of Redis, not its failures.
+func observe(command string, start time.Time, err error) {
+	metrics.RedisCommandDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+
+	if err == nil {
+		return
+	}
+
+	if domainErr, ok := domain.As(err); ok && domainErr.Kind != domain.KindUnavailable {
+		return
+	}
+
+	metrics.RedisCommandErrors.WithLabelValues(command).Inc()
+}
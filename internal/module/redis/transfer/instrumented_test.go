@@ -0,0 +1,83 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/metrics"
+	"scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/redis/transfer/mock"
+)
+
+func TestInstrumentedRedisRepository(t *testing.T) {
+	scooterUUID := uuid.New()
+
+	metadata := model.NewScooterMetadata(testCity, model.StatusAvailable, model.FullBattery)
+
+	tests := map[string]struct {
+		err            error
+		wantErrorCount float64
+	}{
+		"getting metadata successfully": {
+			err:            nil,
+			wantErrorCount: 0,
+		},
+		"getting metadata of missing scooter is not a redis failure": {
+			err:            model.ErrScooterNotFound,
+			wantErrorCount: 0,
+		},
+		"getting metadata failed, because redis is unavailable": {
+			err:            fmt.Errorf("getting scooter metadata: %w", domain.Unavailable(redis.ErrClosed)),
+			wantErrorCount: 1,
+		},
+		"getting metadata failed, because of plain redis error": {
+			err:            redis.ErrClosed,
+			wantErrorCount: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+			mockRedisRepository.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(metadata, tt.err).Times(1)
+
+			failures := metrics.RedisCommandErrors.WithLabelValues("GetScooterMetadata")
+			errorsBefore := testutil.ToFloat64(failures)
+			observationsBefore := observations(t, "GetScooterMetadata")
+
+			got, err := NewInstrumentedRedisRepository(mockRedisRepository).GetScooterMetadata(context.Background(), scooterUUID)
+
+			require.Equal(t, tt.err, err)
+			require.Equal(t, metadata, got)
+			require.Equal(t, errorsBefore+tt.wantErrorCount, testutil.ToFloat64(failures))
+			require.Equal(t, observationsBefore+1, observations(t, "GetScooterMetadata"))
+		})
+	}
+}
+
+// observations returns how many times the duration of the command was observed.
+func observations(t *testing.T, command string) uint64 {
+	t.Helper()
+
+	var metric dto.Metric
+
+	histogram, ok := metrics.RedisCommandDuration.WithLabelValues(command).(prometheus.Metric)
+	require.True(t, ok)
+	require.NoError(t, histogram.Write(&metric))
+
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -5,7 +5,10 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
+	model "scootinAboot/internal/module/redis/model"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -35,75 +38,651 @@ func (m *MockRedisRepository) EXPECT() *MockRedisRepositoryMockRecorder {
 	return m.recorder
 }
 
-// GetScooterAvailability mocks base method.
-func (m *MockRedisRepository) GetScooterAvailability(scooterUUID uuid.UUID) (bool, error) {
+// AckStreamMessage mocks base method.
+func (m *MockRedisRepository) AckStreamMessage(ctx context.Context, stream, group, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScooterAvailability", scooterUUID)
+	ret := m.ctrl.Call(m, "AckStreamMessage", ctx, stream, group, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AckStreamMessage indicates an expected call of AckStreamMessage.
+func (mr *MockRedisRepositoryMockRecorder) AckStreamMessage(ctx, stream, group, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckStreamMessage", reflect.TypeOf((*MockRedisRepository)(nil).AckStreamMessage), ctx, stream, group, id)
+}
+
+// AddRental mocks base method.
+func (m *MockRedisRepository) AddRental(ctx context.Context, rental *model.Rental) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRental", ctx, rental)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRental indicates an expected call of AddRental.
+func (mr *MockRedisRepositoryMockRecorder) AddRental(ctx, rental interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRental", reflect.TypeOf((*MockRedisRepository)(nil).AddRental), ctx, rental)
+}
+
+// AddScooter mocks base method.
+func (m *MockRedisRepository) AddScooter(ctx context.Context, scooter *redis.GeoLocation, metadata *model.ScooterMetadata) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddScooter", ctx, scooter, metadata)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetScooterAvailability indicates an expected call of GetScooterAvailability.
-func (mr *MockRedisRepositoryMockRecorder) GetScooterAvailability(scooterUUID interface{}) *gomock.Call {
+// AddScooter indicates an expected call of AddScooter.
+func (mr *MockRedisRepositoryMockRecorder) AddScooter(ctx, scooter, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScooter", reflect.TypeOf((*MockRedisRepository)(nil).AddScooter), ctx, scooter, metadata)
+}
+
+// AddWebhookDeadLetter mocks base method.
+func (m *MockRedisRepository) AddWebhookDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter, maxLength int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWebhookDeadLetter", ctx, letter, maxLength)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWebhookDeadLetter indicates an expected call of AddWebhookDeadLetter.
+func (mr *MockRedisRepositoryMockRecorder) AddWebhookDeadLetter(ctx, letter, maxLength interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhookDeadLetter", reflect.TypeOf((*MockRedisRepository)(nil).AddWebhookDeadLetter), ctx, letter, maxLength)
+}
+
+// AppendToStream mocks base method.
+func (m *MockRedisRepository) AppendToStream(ctx context.Context, stream string, maxLength int64, messageType string, data []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendToStream", ctx, stream, maxLength, messageType, data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendToStream indicates an expected call of AppendToStream.
+func (mr *MockRedisRepositoryMockRecorder) AppendToStream(ctx, stream, maxLength, messageType, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendToStream", reflect.TypeOf((*MockRedisRepository)(nil).AppendToStream), ctx, stream, maxLength, messageType, data)
+}
+
+// ClaimStreamGroup mocks base method.
+func (m *MockRedisRepository) ClaimStreamGroup(ctx context.Context, stream, group, consumer, from string, minIdle time.Duration, count int64) ([]*model.StreamMessage, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimStreamGroup", ctx, stream, group, consumer, from, minIdle, count)
+	ret0, _ := ret[0].([]*model.StreamMessage)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimStreamGroup indicates an expected call of ClaimStreamGroup.
+func (mr *MockRedisRepositoryMockRecorder) ClaimStreamGroup(ctx, stream, group, consumer, from, minIdle, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimStreamGroup", reflect.TypeOf((*MockRedisRepository)(nil).ClaimStreamGroup), ctx, stream, group, consumer, from, minIdle, count)
+}
+
+// CreateStreamGroup mocks base method.
+func (m *MockRedisRepository) CreateStreamGroup(ctx context.Context, stream, group string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStreamGroup", ctx, stream, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStreamGroup indicates an expected call of CreateStreamGroup.
+func (mr *MockRedisRepositoryMockRecorder) CreateStreamGroup(ctx, stream, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStreamGroup", reflect.TypeOf((*MockRedisRepository)(nil).CreateStreamGroup), ctx, stream, group)
+}
+
+// DeleteIdempotentResponse mocks base method.
+func (m *MockRedisRepository) DeleteIdempotentResponse(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotentResponse", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotentResponse indicates an expected call of DeleteIdempotentResponse.
+func (mr *MockRedisRepositoryMockRecorder) DeleteIdempotentResponse(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotentResponse", reflect.TypeOf((*MockRedisRepository)(nil).DeleteIdempotentResponse), ctx, key)
+}
+
+// DeleteReservation mocks base method.
+func (m *MockRedisRepository) DeleteReservation(ctx context.Context, scooterUUID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReservation", ctx, scooterUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReservation indicates an expected call of DeleteReservation.
+func (mr *MockRedisRepositoryMockRecorder) DeleteReservation(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReservation", reflect.TypeOf((*MockRedisRepository)(nil).DeleteReservation), ctx, scooterUUID)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockRedisRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockRedisRepositoryMockRecorder) DeleteWebhookSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockRedisRepository)(nil).DeleteWebhookSubscription), ctx, id)
+}
+
+// ExpiredReservations mocks base method.
+func (m *MockRedisRepository) ExpiredReservations(ctx context.Context, before time.Time, count int64) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpiredReservations", ctx, before, count)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpiredReservations indicates an expected call of ExpiredReservations.
+func (mr *MockRedisRepositoryMockRecorder) ExpiredReservations(ctx, before, count interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterAvailability", reflect.TypeOf((*MockRedisRepository)(nil).GetScooterAvailability), scooterUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpiredReservations", reflect.TypeOf((*MockRedisRepository)(nil).ExpiredReservations), ctx, before, count)
+}
+
+// FinishRental mocks base method.
+func (m *MockRedisRepository) FinishRental(ctx context.Context, rental *model.Rental) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRental", ctx, rental)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRental indicates an expected call of FinishRental.
+func (mr *MockRedisRepositoryMockRecorder) FinishRental(ctx, rental interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRental", reflect.TypeOf((*MockRedisRepository)(nil).FinishRental), ctx, rental)
+}
+
+// GetActiveRental mocks base method.
+func (m *MockRedisRepository) GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRental", ctx, scooterUUID)
+	ret0, _ := ret[0].(*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRental indicates an expected call of GetActiveRental.
+func (mr *MockRedisRepositoryMockRecorder) GetActiveRental(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRental", reflect.TypeOf((*MockRedisRepository)(nil).GetActiveRental), ctx, scooterUUID)
+}
+
+// GetActiveRentals mocks base method.
+func (m *MockRedisRepository) GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRentals", ctx, city)
+	ret0, _ := ret[0].([]*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRentals indicates an expected call of GetActiveRentals.
+func (mr *MockRedisRepositoryMockRecorder) GetActiveRentals(ctx, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRentals", reflect.TypeOf((*MockRedisRepository)(nil).GetActiveRentals), ctx, city)
+}
+
+// GetClientRentals mocks base method.
+func (m *MockRedisRepository) GetClientRentals(ctx context.Context, clientUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientRentals", ctx, clientUUID, query)
+	ret0, _ := ret[0].(*model.RentalsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientRentals indicates an expected call of GetClientRentals.
+func (mr *MockRedisRepositoryMockRecorder) GetClientRentals(ctx, clientUUID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientRentals", reflect.TypeOf((*MockRedisRepository)(nil).GetClientRentals), ctx, clientUUID, query)
+}
+
+// GetIdempotentResponse mocks base method.
+func (m *MockRedisRepository) GetIdempotentResponse(ctx context.Context, key string) (*model.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotentResponse", ctx, key)
+	ret0, _ := ret[0].(*model.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotentResponse indicates an expected call of GetIdempotentResponse.
+func (mr *MockRedisRepositoryMockRecorder) GetIdempotentResponse(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotentResponse", reflect.TypeOf((*MockRedisRepository)(nil).GetIdempotentResponse), ctx, key)
+}
+
+// GetRental mocks base method.
+func (m *MockRedisRepository) GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRental", ctx, rentalID)
+	ret0, _ := ret[0].(*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRental indicates an expected call of GetRental.
+func (mr *MockRedisRepositoryMockRecorder) GetRental(ctx, rentalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRental", reflect.TypeOf((*MockRedisRepository)(nil).GetRental), ctx, rentalID)
+}
+
+// GetReservation mocks base method.
+func (m *MockRedisRepository) GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", ctx, scooterUUID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockRedisRepositoryMockRecorder) GetReservation(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockRedisRepository)(nil).GetReservation), ctx, scooterUUID)
 }
 
 // GetScooterLocation mocks base method.
-func (m *MockRedisRepository) GetScooterLocation(scooterUUID uuid.UUID, city string) (*redis.GeoPos, error) {
+func (m *MockRedisRepository) GetScooterLocation(ctx context.Context, scooterUUID uuid.UUID, city string) (*redis.GeoPos, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScooterLocation", scooterUUID, city)
+	ret := m.ctrl.Call(m, "GetScooterLocation", ctx, scooterUUID, city)
 	ret0, _ := ret[0].(*redis.GeoPos)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScooterLocation indicates an expected call of GetScooterLocation.
-func (mr *MockRedisRepositoryMockRecorder) GetScooterLocation(scooterUUID, city interface{}) *gomock.Call {
+func (mr *MockRedisRepositoryMockRecorder) GetScooterLocation(ctx, scooterUUID, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterLocation", reflect.TypeOf((*MockRedisRepository)(nil).GetScooterLocation), ctx, scooterUUID, city)
+}
+
+// GetScooterMetadata mocks base method.
+func (m *MockRedisRepository) GetScooterMetadata(ctx context.Context, scooterUUID uuid.UUID) (*model.ScooterMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScooterMetadata", ctx, scooterUUID)
+	ret0, _ := ret[0].(*model.ScooterMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScooterMetadata indicates an expected call of GetScooterMetadata.
+func (mr *MockRedisRepositoryMockRecorder) GetScooterMetadata(ctx, scooterUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterLocation", reflect.TypeOf((*MockRedisRepository)(nil).GetScooterLocation), scooterUUID, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterMetadata", reflect.TypeOf((*MockRedisRepository)(nil).GetScooterMetadata), ctx, scooterUUID)
+}
+
+// GetScooterRentals mocks base method.
+func (m *MockRedisRepository) GetScooterRentals(ctx context.Context, scooterUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScooterRentals", ctx, scooterUUID, query)
+	ret0, _ := ret[0].(*model.RentalsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScooterRentals indicates an expected call of GetScooterRentals.
+func (mr *MockRedisRepositoryMockRecorder) GetScooterRentals(ctx, scooterUUID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterRentals", reflect.TypeOf((*MockRedisRepository)(nil).GetScooterRentals), ctx, scooterUUID, query)
 }
 
 // GetScooters mocks base method.
-func (m *MockRedisRepository) GetScooters(longitude, latitude, radius float64, city string) ([]redis.GeoLocation, error) {
+func (m *MockRedisRepository) GetScooters(ctx context.Context, longitude, latitude, radius float64, city string) ([]redis.GeoLocation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScooters", longitude, latitude, radius, city)
+	ret := m.ctrl.Call(m, "GetScooters", ctx, longitude, latitude, radius, city)
 	ret0, _ := ret[0].([]redis.GeoLocation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScooters indicates an expected call of GetScooters.
-func (mr *MockRedisRepositoryMockRecorder) GetScooters(longitude, latitude, radius, city interface{}) *gomock.Call {
+func (mr *MockRedisRepositoryMockRecorder) GetScooters(ctx, longitude, latitude, radius, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooters", reflect.TypeOf((*MockRedisRepository)(nil).GetScooters), ctx, longitude, latitude, radius, city)
+}
+
+// GetWebhookDeadLetters mocks base method.
+func (m *MockRedisRepository) GetWebhookDeadLetters(ctx context.Context, limit int64) ([]*model.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeadLetters", ctx, limit)
+	ret0, _ := ret[0].([]*model.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeadLetters indicates an expected call of GetWebhookDeadLetters.
+func (mr *MockRedisRepositoryMockRecorder) GetWebhookDeadLetters(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeadLetters", reflect.TypeOf((*MockRedisRepository)(nil).GetWebhookDeadLetters), ctx, limit)
+}
+
+// GetWebhookDelivered mocks base method.
+func (m *MockRedisRepository) GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivered", ctx, deliveryID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivered indicates an expected call of GetWebhookDelivered.
+func (mr *MockRedisRepositoryMockRecorder) GetWebhookDelivered(ctx, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivered", reflect.TypeOf((*MockRedisRepository)(nil).GetWebhookDelivered), ctx, deliveryID)
+}
+
+// GetWebhookSubscriptions mocks base method.
+func (m *MockRedisRepository) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscriptions", ctx)
+	ret0, _ := ret[0].([]*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscriptions indicates an expected call of GetWebhookSubscriptions.
+func (mr *MockRedisRepositoryMockRecorder) GetWebhookSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscriptions", reflect.TypeOf((*MockRedisRepository)(nil).GetWebhookSubscriptions), ctx)
+}
+
+// MarkWebhookDelivered mocks base method.
+func (m *MockRedisRepository) MarkWebhookDelivered(ctx context.Context, deliveryID, subscriptionID uuid.UUID, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDelivered", ctx, deliveryID, subscriptionID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDelivered indicates an expected call of MarkWebhookDelivered.
+func (mr *MockRedisRepositoryMockRecorder) MarkWebhookDelivered(ctx, deliveryID, subscriptionID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDelivered", reflect.TypeOf((*MockRedisRepository)(nil).MarkWebhookDelivered), ctx, deliveryID, subscriptionID, ttl)
+}
+
+// MoveScooter mocks base method.
+func (m *MockRedisRepository) MoveScooter(ctx context.Context, scooter *redis.GeoLocation, fromCity, toCity string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveScooter", ctx, scooter, fromCity, toCity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveScooter indicates an expected call of MoveScooter.
+func (mr *MockRedisRepositoryMockRecorder) MoveScooter(ctx, scooter, fromCity, toCity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveScooter", reflect.TypeOf((*MockRedisRepository)(nil).MoveScooter), ctx, scooter, fromCity, toCity)
+}
+
+// Ping mocks base method.
+func (m *MockRedisRepository) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRedisRepositoryMockRecorder) Ping(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooters", reflect.TypeOf((*MockRedisRepository)(nil).GetScooters), longitude, latitude, radius, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRedisRepository)(nil).Ping), ctx)
 }
 
-// UpdateScooterAvailability mocks base method.
-func (m *MockRedisRepository) UpdateScooterAvailability(scooterUUID uuid.UUID, availability bool) error {
+// PublishScooterEvent mocks base method.
+func (m *MockRedisRepository) PublishScooterEvent(ctx context.Context, event *model.ScooterEvent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScooterAvailability", scooterUUID, availability)
+	ret := m.ctrl.Call(m, "PublishScooterEvent", ctx, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateScooterAvailability indicates an expected call of UpdateScooterAvailability.
-func (mr *MockRedisRepositoryMockRecorder) UpdateScooterAvailability(scooterUUID, availability interface{}) *gomock.Call {
+// PublishScooterEvent indicates an expected call of PublishScooterEvent.
+func (mr *MockRedisRepositoryMockRecorder) PublishScooterEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScooterEvent", reflect.TypeOf((*MockRedisRepository)(nil).PublishScooterEvent), ctx, event)
+}
+
+// ReadPendingStreamGroup mocks base method.
+func (m *MockRedisRepository) ReadPendingStreamGroup(ctx context.Context, stream, group, consumer, from string, count int64) ([]*model.StreamMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPendingStreamGroup", ctx, stream, group, consumer, from, count)
+	ret0, _ := ret[0].([]*model.StreamMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadPendingStreamGroup indicates an expected call of ReadPendingStreamGroup.
+func (mr *MockRedisRepositoryMockRecorder) ReadPendingStreamGroup(ctx, stream, group, consumer, from, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPendingStreamGroup", reflect.TypeOf((*MockRedisRepository)(nil).ReadPendingStreamGroup), ctx, stream, group, consumer, from, count)
+}
+
+// ReadStreamGroup mocks base method.
+func (m *MockRedisRepository) ReadStreamGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]*model.StreamMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStreamGroup", ctx, stream, group, consumer, count, block)
+	ret0, _ := ret[0].([]*model.StreamMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStreamGroup indicates an expected call of ReadStreamGroup.
+func (mr *MockRedisRepositoryMockRecorder) ReadStreamGroup(ctx, stream, group, consumer, count, block interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterAvailability", reflect.TypeOf((*MockRedisRepository)(nil).UpdateScooterAvailability), scooterUUID, availability)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStreamGroup", reflect.TypeOf((*MockRedisRepository)(nil).ReadStreamGroup), ctx, stream, group, consumer, count, block)
+}
+
+// ReleaseExpiredReservation mocks base method.
+func (m *MockRedisRepository) ReleaseExpiredReservation(ctx context.Context, scooterUUID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservation", ctx, scooterUUID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredReservation indicates an expected call of ReleaseExpiredReservation.
+func (mr *MockRedisRepositoryMockRecorder) ReleaseExpiredReservation(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservation", reflect.TypeOf((*MockRedisRepository)(nil).ReleaseExpiredReservation), ctx, scooterUUID)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockRedisRepository) ReleaseReservation(ctx context.Context, scooterUUID, clientUUID uuid.UUID) (uuid.UUID, model.ScooterStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", ctx, scooterUUID, clientUUID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(model.ScooterStatus)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockRedisRepositoryMockRecorder) ReleaseReservation(ctx, scooterUUID, clientUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockRedisRepository)(nil).ReleaseReservation), ctx, scooterUUID, clientUUID)
+}
+
+// RemoveScooter mocks base method.
+func (m *MockRedisRepository) RemoveScooter(ctx context.Context, scooterUUID uuid.UUID, city string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveScooter", ctx, scooterUUID, city)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveScooter indicates an expected call of RemoveScooter.
+func (mr *MockRedisRepositoryMockRecorder) RemoveScooter(ctx, scooterUUID, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveScooter", reflect.TypeOf((*MockRedisRepository)(nil).RemoveScooter), ctx, scooterUUID, city)
+}
+
+// ReplaceIdempotentResponse mocks base method.
+func (m *MockRedisRepository) ReplaceIdempotentResponse(ctx context.Context, key string, response *model.IdempotentResponse, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceIdempotentResponse", ctx, key, response, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceIdempotentResponse indicates an expected call of ReplaceIdempotentResponse.
+func (mr *MockRedisRepositoryMockRecorder) ReplaceIdempotentResponse(ctx, key, response, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceIdempotentResponse", reflect.TypeOf((*MockRedisRepository)(nil).ReplaceIdempotentResponse), ctx, key, response, ttl)
+}
+
+// ReserveScooter mocks base method.
+func (m *MockRedisRepository) ReserveScooter(ctx context.Context, scooterUUID, clientUUID uuid.UUID, ttl time.Duration) (model.ScooterStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveScooter", ctx, scooterUUID, clientUUID, ttl)
+	ret0, _ := ret[0].(model.ScooterStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveScooter indicates an expected call of ReserveScooter.
+func (mr *MockRedisRepositoryMockRecorder) ReserveScooter(ctx, scooterUUID, clientUUID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveScooter", reflect.TypeOf((*MockRedisRepository)(nil).ReserveScooter), ctx, scooterUUID, clientUUID, ttl)
+}
+
+// SaveWebhookSubscription mocks base method.
+func (m *MockRedisRepository) SaveWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebhookSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhookSubscription indicates an expected call of SaveWebhookSubscription.
+func (mr *MockRedisRepositoryMockRecorder) SaveWebhookSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhookSubscription", reflect.TypeOf((*MockRedisRepository)(nil).SaveWebhookSubscription), ctx, subscription)
+}
+
+// SetIdempotentResponse mocks base method.
+func (m *MockRedisRepository) SetIdempotentResponse(ctx context.Context, key string, response *model.IdempotentResponse, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIdempotentResponse", ctx, key, response, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetIdempotentResponse indicates an expected call of SetIdempotentResponse.
+func (mr *MockRedisRepositoryMockRecorder) SetIdempotentResponse(ctx, key, response, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIdempotentResponse", reflect.TypeOf((*MockRedisRepository)(nil).SetIdempotentResponse), ctx, key, response, ttl)
+}
+
+// SubscribeScooterEvents mocks base method.
+func (m *MockRedisRepository) SubscribeScooterEvents(ctx context.Context, city string) (<-chan *model.ScooterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeScooterEvents", ctx, city)
+	ret0, _ := ret[0].(<-chan *model.ScooterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeScooterEvents indicates an expected call of SubscribeScooterEvents.
+func (mr *MockRedisRepositoryMockRecorder) SubscribeScooterEvents(ctx, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeScooterEvents", reflect.TypeOf((*MockRedisRepository)(nil).SubscribeScooterEvents), ctx, city)
+}
+
+// SwapScooterStatus mocks base method.
+func (m *MockRedisRepository) SwapScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus) (model.ScooterStatus, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, scooterUUID, status}
+	for _, a := range expected {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SwapScooterStatus", varargs...)
+	ret0, _ := ret[0].(model.ScooterStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwapScooterStatus indicates an expected call of SwapScooterStatus.
+func (mr *MockRedisRepositoryMockRecorder) SwapScooterStatus(ctx, scooterUUID, status interface{}, expected ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, scooterUUID, status}, expected...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapScooterStatus", reflect.TypeOf((*MockRedisRepository)(nil).SwapScooterStatus), varargs...)
+}
+
+// TakeRateLimitToken mocks base method.
+func (m *MockRedisRepository) TakeRateLimitToken(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", ctx, key, limit, window)
+	ret0, _ := ret[0].(*model.RateLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockRedisRepositoryMockRecorder) TakeRateLimitToken(ctx, key, limit, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockRedisRepository)(nil).TakeRateLimitToken), ctx, key, limit, window)
+}
+
+// UpdateScooterBattery mocks base method.
+func (m *MockRedisRepository) UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScooterBattery", ctx, scooterUUID, battery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScooterBattery indicates an expected call of UpdateScooterBattery.
+func (mr *MockRedisRepositoryMockRecorder) UpdateScooterBattery(ctx, scooterUUID, battery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterBattery", reflect.TypeOf((*MockRedisRepository)(nil).UpdateScooterBattery), ctx, scooterUUID, battery)
 }
 
 // UpdateScooterLocation mocks base method.
-func (m *MockRedisRepository) UpdateScooterLocation(scooter *redis.GeoLocation, city string) error {
+func (m *MockRedisRepository) UpdateScooterLocation(ctx context.Context, scooter *redis.GeoLocation, city string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScooterLocation", scooter, city)
+	ret := m.ctrl.Call(m, "UpdateScooterLocation", ctx, scooter, city)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateScooterLocation indicates an expected call of UpdateScooterLocation.
-func (mr *MockRedisRepositoryMockRecorder) UpdateScooterLocation(scooter, city interface{}) *gomock.Call {
+func (mr *MockRedisRepositoryMockRecorder) UpdateScooterLocation(ctx, scooter, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterLocation", reflect.TypeOf((*MockRedisRepository)(nil).UpdateScooterLocation), ctx, scooter, city)
+}
+
+// UpdateScooterStatus mocks base method.
+func (m *MockRedisRepository) UpdateScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScooterStatus", ctx, scooterUUID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScooterStatus indicates an expected call of UpdateScooterStatus.
+func (mr *MockRedisRepositoryMockRecorder) UpdateScooterStatus(ctx, scooterUUID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterLocation", reflect.TypeOf((*MockRedisRepository)(nil).UpdateScooterLocation), scooter, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterStatus", reflect.TypeOf((*MockRedisRepository)(nil).UpdateScooterStatus), ctx, scooterUUID, status)
 }
@@ -5,8 +5,10 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	model "scootinAboot/internal/module/redis/model"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,59 +38,633 @@ func (m *MockRedisService) EXPECT() *MockRedisServiceMockRecorder {
 	return m.recorder
 }
 
+// AckStreamMessage mocks base method.
+func (m *MockRedisService) AckStreamMessage(ctx context.Context, stream, group, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AckStreamMessage", ctx, stream, group, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AckStreamMessage indicates an expected call of AckStreamMessage.
+func (mr *MockRedisServiceMockRecorder) AckStreamMessage(ctx, stream, group, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckStreamMessage", reflect.TypeOf((*MockRedisService)(nil).AckStreamMessage), ctx, stream, group, id)
+}
+
+// AddWebhookDeadLetter mocks base method.
+func (m *MockRedisService) AddWebhookDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter, maxLength int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWebhookDeadLetter", ctx, letter, maxLength)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWebhookDeadLetter indicates an expected call of AddWebhookDeadLetter.
+func (mr *MockRedisServiceMockRecorder) AddWebhookDeadLetter(ctx, letter, maxLength interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhookDeadLetter", reflect.TypeOf((*MockRedisService)(nil).AddWebhookDeadLetter), ctx, letter, maxLength)
+}
+
+// AppendToStream mocks base method.
+func (m *MockRedisService) AppendToStream(ctx context.Context, stream string, maxLength int64, messageType string, data []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendToStream", ctx, stream, maxLength, messageType, data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendToStream indicates an expected call of AppendToStream.
+func (mr *MockRedisServiceMockRecorder) AppendToStream(ctx, stream, maxLength, messageType, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendToStream", reflect.TypeOf((*MockRedisService)(nil).AppendToStream), ctx, stream, maxLength, messageType, data)
+}
+
+// ChangeScooterStatus mocks base method.
+func (m *MockRedisService) ChangeScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeScooterStatus", ctx, scooterUUID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeScooterStatus indicates an expected call of ChangeScooterStatus.
+func (mr *MockRedisServiceMockRecorder) ChangeScooterStatus(ctx, scooterUUID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeScooterStatus", reflect.TypeOf((*MockRedisService)(nil).ChangeScooterStatus), ctx, scooterUUID, status)
+}
+
+// ClaimIdempotencyKey mocks base method.
+func (m *MockRedisService) ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimIdempotencyKey", ctx, key, fingerprint, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimIdempotencyKey indicates an expected call of ClaimIdempotencyKey.
+func (mr *MockRedisServiceMockRecorder) ClaimIdempotencyKey(ctx, key, fingerprint, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimIdempotencyKey", reflect.TypeOf((*MockRedisService)(nil).ClaimIdempotencyKey), ctx, key, fingerprint, ttl)
+}
+
+// ClaimStreamGroup mocks base method.
+func (m *MockRedisService) ClaimStreamGroup(ctx context.Context, stream, group, consumer, from string, minIdle time.Duration, count int64) ([]*model.StreamMessage, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimStreamGroup", ctx, stream, group, consumer, from, minIdle, count)
+	ret0, _ := ret[0].([]*model.StreamMessage)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimStreamGroup indicates an expected call of ClaimStreamGroup.
+func (mr *MockRedisServiceMockRecorder) ClaimStreamGroup(ctx, stream, group, consumer, from, minIdle, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimStreamGroup", reflect.TypeOf((*MockRedisService)(nil).ClaimStreamGroup), ctx, stream, group, consumer, from, minIdle, count)
+}
+
+// CreateScooter mocks base method.
+func (m *MockRedisService) CreateScooter(ctx context.Context, scooter *redis.GeoLocation, metadata *model.ScooterMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScooter", ctx, scooter, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScooter indicates an expected call of CreateScooter.
+func (mr *MockRedisServiceMockRecorder) CreateScooter(ctx, scooter, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScooter", reflect.TypeOf((*MockRedisService)(nil).CreateScooter), ctx, scooter, metadata)
+}
+
+// CreateStreamGroup mocks base method.
+func (m *MockRedisService) CreateStreamGroup(ctx context.Context, stream, group string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStreamGroup", ctx, stream, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStreamGroup indicates an expected call of CreateStreamGroup.
+func (mr *MockRedisServiceMockRecorder) CreateStreamGroup(ctx, stream, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStreamGroup", reflect.TypeOf((*MockRedisService)(nil).CreateStreamGroup), ctx, stream, group)
+}
+
+// DeleteScooter mocks base method.
+func (m *MockRedisService) DeleteScooter(ctx context.Context, scooterUUID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScooter", ctx, scooterUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScooter indicates an expected call of DeleteScooter.
+func (mr *MockRedisServiceMockRecorder) DeleteScooter(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScooter", reflect.TypeOf((*MockRedisService)(nil).DeleteScooter), ctx, scooterUUID)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockRedisService) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockRedisServiceMockRecorder) DeleteWebhookSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockRedisService)(nil).DeleteWebhookSubscription), ctx, id)
+}
+
+// FinishRental mocks base method.
+func (m *MockRedisService) FinishRental(ctx context.Context, rental *model.Rental) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRental", ctx, rental)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRental indicates an expected call of FinishRental.
+func (mr *MockRedisServiceMockRecorder) FinishRental(ctx, rental interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRental", reflect.TypeOf((*MockRedisService)(nil).FinishRental), ctx, rental)
+}
+
+// GetActiveRental mocks base method.
+func (m *MockRedisService) GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRental", ctx, scooterUUID)
+	ret0, _ := ret[0].(*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRental indicates an expected call of GetActiveRental.
+func (mr *MockRedisServiceMockRecorder) GetActiveRental(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRental", reflect.TypeOf((*MockRedisService)(nil).GetActiveRental), ctx, scooterUUID)
+}
+
+// GetActiveRentals mocks base method.
+func (m *MockRedisService) GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRentals", ctx, city)
+	ret0, _ := ret[0].([]*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRentals indicates an expected call of GetActiveRentals.
+func (mr *MockRedisServiceMockRecorder) GetActiveRentals(ctx, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRentals", reflect.TypeOf((*MockRedisService)(nil).GetActiveRentals), ctx, city)
+}
+
+// GetClientRentals mocks base method.
+func (m *MockRedisService) GetClientRentals(ctx context.Context, clientUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientRentals", ctx, clientUUID, query)
+	ret0, _ := ret[0].(*model.RentalsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientRentals indicates an expected call of GetClientRentals.
+func (mr *MockRedisServiceMockRecorder) GetClientRentals(ctx, clientUUID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientRentals", reflect.TypeOf((*MockRedisService)(nil).GetClientRentals), ctx, clientUUID, query)
+}
+
+// GetIdempotentResponse mocks base method.
+func (m *MockRedisService) GetIdempotentResponse(ctx context.Context, key string) (*model.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotentResponse", ctx, key)
+	ret0, _ := ret[0].(*model.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotentResponse indicates an expected call of GetIdempotentResponse.
+func (mr *MockRedisServiceMockRecorder) GetIdempotentResponse(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotentResponse", reflect.TypeOf((*MockRedisService)(nil).GetIdempotentResponse), ctx, key)
+}
+
+// GetRental mocks base method.
+func (m *MockRedisService) GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRental", ctx, rentalID)
+	ret0, _ := ret[0].(*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRental indicates an expected call of GetRental.
+func (mr *MockRedisServiceMockRecorder) GetRental(ctx, rentalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRental", reflect.TypeOf((*MockRedisService)(nil).GetRental), ctx, rentalID)
+}
+
+// GetReservation mocks base method.
+func (m *MockRedisService) GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", ctx, scooterUUID)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockRedisServiceMockRecorder) GetReservation(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockRedisService)(nil).GetReservation), ctx, scooterUUID)
+}
+
+// GetScooter mocks base method.
+func (m *MockRedisService) GetScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.FleetScooter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScooter", ctx, scooterUUID)
+	ret0, _ := ret[0].(*model.FleetScooter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScooter indicates an expected call of GetScooter.
+func (mr *MockRedisServiceMockRecorder) GetScooter(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooter", reflect.TypeOf((*MockRedisService)(nil).GetScooter), ctx, scooterUUID)
+}
+
+// GetScooterMetadata mocks base method.
+func (m *MockRedisService) GetScooterMetadata(ctx context.Context, scooterUUID uuid.UUID) (*model.ScooterMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScooterMetadata", ctx, scooterUUID)
+	ret0, _ := ret[0].(*model.ScooterMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScooterMetadata indicates an expected call of GetScooterMetadata.
+func (mr *MockRedisServiceMockRecorder) GetScooterMetadata(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterMetadata", reflect.TypeOf((*MockRedisService)(nil).GetScooterMetadata), ctx, scooterUUID)
+}
+
+// GetScooterRentals mocks base method.
+func (m *MockRedisService) GetScooterRentals(ctx context.Context, scooterUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScooterRentals", ctx, scooterUUID, query)
+	ret0, _ := ret[0].(*model.RentalsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScooterRentals indicates an expected call of GetScooterRentals.
+func (mr *MockRedisServiceMockRecorder) GetScooterRentals(ctx, scooterUUID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooterRentals", reflect.TypeOf((*MockRedisService)(nil).GetScooterRentals), ctx, scooterUUID, query)
+}
+
 // GetScooters mocks base method.
-func (m *MockRedisService) GetScooters(longitude, latitude, radius float64, city string) ([]*model.RedisScooter, error) {
+func (m *MockRedisService) GetScooters(ctx context.Context, longitude, latitude, radius float64, city string) ([]*model.RedisScooter, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScooters", longitude, latitude, radius, city)
+	ret := m.ctrl.Call(m, "GetScooters", ctx, longitude, latitude, radius, city)
 	ret0, _ := ret[0].([]*model.RedisScooter)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScooters indicates an expected call of GetScooters.
-func (mr *MockRedisServiceMockRecorder) GetScooters(longitude, latitude, radius, city interface{}) *gomock.Call {
+func (mr *MockRedisServiceMockRecorder) GetScooters(ctx, longitude, latitude, radius, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooters", reflect.TypeOf((*MockRedisService)(nil).GetScooters), ctx, longitude, latitude, radius, city)
+}
+
+// GetWebhookDeadLetters mocks base method.
+func (m *MockRedisService) GetWebhookDeadLetters(ctx context.Context, limit int64) ([]*model.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeadLetters", ctx, limit)
+	ret0, _ := ret[0].([]*model.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeadLetters indicates an expected call of GetWebhookDeadLetters.
+func (mr *MockRedisServiceMockRecorder) GetWebhookDeadLetters(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeadLetters", reflect.TypeOf((*MockRedisService)(nil).GetWebhookDeadLetters), ctx, limit)
+}
+
+// GetWebhookDelivered mocks base method.
+func (m *MockRedisService) GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivered", ctx, deliveryID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivered indicates an expected call of GetWebhookDelivered.
+func (mr *MockRedisServiceMockRecorder) GetWebhookDelivered(ctx, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivered", reflect.TypeOf((*MockRedisService)(nil).GetWebhookDelivered), ctx, deliveryID)
+}
+
+// GetWebhookSubscriptions mocks base method.
+func (m *MockRedisService) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscriptions", ctx)
+	ret0, _ := ret[0].([]*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscriptions indicates an expected call of GetWebhookSubscriptions.
+func (mr *MockRedisServiceMockRecorder) GetWebhookSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscriptions", reflect.TypeOf((*MockRedisService)(nil).GetWebhookSubscriptions), ctx)
+}
+
+// MarkWebhookDelivered mocks base method.
+func (m *MockRedisService) MarkWebhookDelivered(ctx context.Context, deliveryID, subscriptionID uuid.UUID, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDelivered", ctx, deliveryID, subscriptionID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDelivered indicates an expected call of MarkWebhookDelivered.
+func (mr *MockRedisServiceMockRecorder) MarkWebhookDelivered(ctx, deliveryID, subscriptionID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDelivered", reflect.TypeOf((*MockRedisService)(nil).MarkWebhookDelivered), ctx, deliveryID, subscriptionID, ttl)
+}
+
+// MoveScooter mocks base method.
+func (m *MockRedisService) MoveScooter(ctx context.Context, scooter *redis.GeoLocation, city string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveScooter", ctx, scooter, city)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveScooter indicates an expected call of MoveScooter.
+func (mr *MockRedisServiceMockRecorder) MoveScooter(ctx, scooter, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveScooter", reflect.TypeOf((*MockRedisService)(nil).MoveScooter), ctx, scooter, city)
+}
+
+// Ping mocks base method.
+func (m *MockRedisService) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRedisServiceMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRedisService)(nil).Ping), ctx)
+}
+
+// ReadPendingStreamGroup mocks base method.
+func (m *MockRedisService) ReadPendingStreamGroup(ctx context.Context, stream, group, consumer, from string, count int64) ([]*model.StreamMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPendingStreamGroup", ctx, stream, group, consumer, from, count)
+	ret0, _ := ret[0].([]*model.StreamMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadPendingStreamGroup indicates an expected call of ReadPendingStreamGroup.
+func (mr *MockRedisServiceMockRecorder) ReadPendingStreamGroup(ctx, stream, group, consumer, from, count interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScooters", reflect.TypeOf((*MockRedisService)(nil).GetScooters), longitude, latitude, radius, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPendingStreamGroup", reflect.TypeOf((*MockRedisService)(nil).ReadPendingStreamGroup), ctx, stream, group, consumer, from, count)
+}
+
+// ReadStreamGroup mocks base method.
+func (m *MockRedisService) ReadStreamGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]*model.StreamMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStreamGroup", ctx, stream, group, consumer, count, block)
+	ret0, _ := ret[0].([]*model.StreamMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStreamGroup indicates an expected call of ReadStreamGroup.
+func (mr *MockRedisServiceMockRecorder) ReadStreamGroup(ctx, stream, group, consumer, count, block interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStreamGroup", reflect.TypeOf((*MockRedisService)(nil).ReadStreamGroup), ctx, stream, group, consumer, count, block)
+}
+
+// ReleaseExpiredReservations mocks base method.
+func (m *MockRedisService) ReleaseExpiredReservations(ctx context.Context, before time.Time, count int64) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", ctx, before, count)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockRedisServiceMockRecorder) ReleaseExpiredReservations(ctx, before, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockRedisService)(nil).ReleaseExpiredReservations), ctx, before, count)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockRedisService) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockRedisServiceMockRecorder) ReleaseIdempotencyKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockRedisService)(nil).ReleaseIdempotencyKey), ctx, key)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockRedisService) ReleaseReservation(ctx context.Context, scooterUUID, clientUUID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", ctx, scooterUUID, clientUUID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockRedisServiceMockRecorder) ReleaseReservation(ctx, scooterUUID, clientUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockRedisService)(nil).ReleaseReservation), ctx, scooterUUID, clientUUID)
+}
+
+// ReserveScooter mocks base method.
+func (m *MockRedisService) ReserveScooter(ctx context.Context, scooterUUID, clientUUID uuid.UUID, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveScooter", ctx, scooterUUID, clientUUID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveScooter indicates an expected call of ReserveScooter.
+func (mr *MockRedisServiceMockRecorder) ReserveScooter(ctx, scooterUUID, clientUUID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveScooter", reflect.TypeOf((*MockRedisService)(nil).ReserveScooter), ctx, scooterUUID, clientUUID, ttl)
+}
+
+// SaveIdempotentResponse mocks base method.
+func (m *MockRedisService) SaveIdempotentResponse(ctx context.Context, key string, response *model.IdempotentResponse, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", ctx, key, response, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse.
+func (mr *MockRedisServiceMockRecorder) SaveIdempotentResponse(ctx, key, response, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockRedisService)(nil).SaveIdempotentResponse), ctx, key, response, ttl)
+}
+
+// SaveWebhookSubscription mocks base method.
+func (m *MockRedisService) SaveWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebhookSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhookSubscription indicates an expected call of SaveWebhookSubscription.
+func (mr *MockRedisServiceMockRecorder) SaveWebhookSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhookSubscription", reflect.TypeOf((*MockRedisService)(nil).SaveWebhookSubscription), ctx, subscription)
+}
+
+// StartRental mocks base method.
+func (m *MockRedisService) StartRental(ctx context.Context, rental *model.Rental) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRental", ctx, rental)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartRental indicates an expected call of StartRental.
+func (mr *MockRedisServiceMockRecorder) StartRental(ctx, rental interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRental", reflect.TypeOf((*MockRedisService)(nil).StartRental), ctx, rental)
+}
+
+// SubscribeScooterEvents mocks base method.
+func (m *MockRedisService) SubscribeScooterEvents(ctx context.Context, city string) (<-chan *model.ScooterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeScooterEvents", ctx, city)
+	ret0, _ := ret[0].(<-chan *model.ScooterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeScooterEvents indicates an expected call of SubscribeScooterEvents.
+func (mr *MockRedisServiceMockRecorder) SubscribeScooterEvents(ctx, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeScooterEvents", reflect.TypeOf((*MockRedisService)(nil).SubscribeScooterEvents), ctx, city)
+}
+
+// SwapScooterStatus mocks base method.
+func (m *MockRedisService) SwapScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus) (model.ScooterStatus, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, scooterUUID, status}
+	for _, a := range expected {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SwapScooterStatus", varargs...)
+	ret0, _ := ret[0].(model.ScooterStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwapScooterStatus indicates an expected call of SwapScooterStatus.
+func (mr *MockRedisServiceMockRecorder) SwapScooterStatus(ctx, scooterUUID, status interface{}, expected ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, scooterUUID, status}, expected...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapScooterStatus", reflect.TypeOf((*MockRedisService)(nil).SwapScooterStatus), varargs...)
+}
+
+// TakeRateLimitToken mocks base method.
+func (m *MockRedisService) TakeRateLimitToken(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", ctx, key, limit, window)
+	ret0, _ := ret[0].(*model.RateLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockRedisServiceMockRecorder) TakeRateLimitToken(ctx, key, limit, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockRedisService)(nil).TakeRateLimitToken), ctx, key, limit, window)
 }
 
 // UpdateScooter mocks base method.
-func (m *MockRedisService) UpdateScooter(scooter *model.RedisScooter, city string) error {
+func (m *MockRedisService) UpdateScooter(ctx context.Context, scooter *model.RedisScooter, city string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScooter", scooter, city)
+	ret := m.ctrl.Call(m, "UpdateScooter", ctx, scooter, city)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateScooter indicates an expected call of UpdateScooter.
-func (mr *MockRedisServiceMockRecorder) UpdateScooter(scooter, city interface{}) *gomock.Call {
+func (mr *MockRedisServiceMockRecorder) UpdateScooter(ctx, scooter, city interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooter", reflect.TypeOf((*MockRedisService)(nil).UpdateScooter), scooter, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooter", reflect.TypeOf((*MockRedisService)(nil).UpdateScooter), ctx, scooter, city)
 }
 
-// UpdateScooterAvailability mocks base method.
-func (m *MockRedisService) UpdateScooterAvailability(scooterUUID uuid.UUID, availability bool) error {
+// UpdateScooterBattery mocks base method.
+func (m *MockRedisService) UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScooterAvailability", scooterUUID, availability)
+	ret := m.ctrl.Call(m, "UpdateScooterBattery", ctx, scooterUUID, battery)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateScooterAvailability indicates an expected call of UpdateScooterAvailability.
-func (mr *MockRedisServiceMockRecorder) UpdateScooterAvailability(scooterUUID, availability interface{}) *gomock.Call {
+// UpdateScooterBattery indicates an expected call of UpdateScooterBattery.
+func (mr *MockRedisServiceMockRecorder) UpdateScooterBattery(ctx, scooterUUID, battery interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterAvailability", reflect.TypeOf((*MockRedisService)(nil).UpdateScooterAvailability), scooterUUID, availability)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterBattery", reflect.TypeOf((*MockRedisService)(nil).UpdateScooterBattery), ctx, scooterUUID, battery)
 }
 
 // UpdateScooterLocation mocks base method.
-func (m *MockRedisService) UpdateScooterLocation(scooter *redis.GeoLocation, city string) error {
+func (m *MockRedisService) UpdateScooterLocation(ctx context.Context, scooter *redis.GeoLocation, city string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScooterLocation", scooter, city)
+	ret := m.ctrl.Call(m, "UpdateScooterLocation", ctx, scooter, city)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateScooterLocation indicates an expected call of UpdateScooterLocation.
-func (mr *MockRedisServiceMockRecorder) UpdateScooterLocation(scooter, city interface{}) *gomock.Call {
+func (mr *MockRedisServiceMockRecorder) UpdateScooterLocation(ctx, scooter, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterLocation", reflect.TypeOf((*MockRedisService)(nil).UpdateScooterLocation), ctx, scooter, city)
+}
+
+// UpdateScooterStatus mocks base method.
+func (m *MockRedisService) UpdateScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScooterStatus", ctx, scooterUUID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScooterStatus indicates an expected call of UpdateScooterStatus.
+func (mr *MockRedisServiceMockRecorder) UpdateScooterStatus(ctx, scooterUUID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterLocation", reflect.TypeOf((*MockRedisService)(nil).UpdateScooterLocation), scooter, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScooterStatus", reflect.TypeOf((*MockRedisService)(nil).UpdateScooterStatus), ctx, scooterUUID, status)
 }
@@ -0,0 +1,244 @@
+package transfer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/module/redis/model"
+)
+
+// publishingRedisService publishes a scooter event after every change of scooter's location or status, so
+// subscribers of the city don't have to poll Redis. Publishing is best effort: the change already happened, a failed
+// publish is logged and doesn't fail the call.
+type publishingRedisService struct {
+	RedisService
+
+	logger *slog.Logger
+	repo   RedisRepository
+}
+
+func NewPublishingRedisService(
+	logger *slog.Logger,
+	service RedisService,
+	repository RedisRepository,
+) *publishingRedisService {
+	return &publishingRedisService{
+		RedisService: service,
+		logger:       logger,
+		repo:         repository,
+	}
+}
+
+func (ps *publishingRedisService) UpdateScooter(ctx context.Context, scooter *model.RedisScooter, city string) error {
+	if err := ps.RedisService.UpdateScooter(ctx, scooter, city); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterMoved, scooter.Scooter.Name)
+
+	return nil
+}
+
+func (ps *publishingRedisService) UpdateScooterLocation(
+	ctx context.Context,
+	location *redis.GeoLocation, city string,
+) error {
+	if err := ps.RedisService.UpdateScooterLocation(ctx, location, city); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterMoved, location.Name)
+
+	return nil
+}
+
+func (ps *publishingRedisService) UpdateScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	if err := ps.RedisService.UpdateScooterStatus(ctx, scooterUUID, status); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterStatusChanged, scooterUUID.String())
+
+	return nil
+}
+
+func (ps *publishingRedisService) SwapScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+) (model.ScooterStatus, error) {
+	previous, err := ps.RedisService.SwapScooterStatus(ctx, scooterUUID, status, expected...)
+	if err != nil {
+		return previous, err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterStatusChanged, scooterUUID.String())
+
+	return previous, nil
+}
+
+func (ps *publishingRedisService) CreateScooter(
+	ctx context.Context,
+	scooter *redis.GeoLocation, metadata *model.ScooterMetadata,
+) error {
+	if err := ps.RedisService.CreateScooter(ctx, scooter, metadata); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterAdded, scooter.Name)
+
+	return nil
+}
+
+func (ps *publishingRedisService) MoveScooter(ctx context.Context, scooter *redis.GeoLocation, city string) error {
+	previous := ps.find(ctx, scooter.Name)
+
+	if err := ps.RedisService.MoveScooter(ctx, scooter, city); err != nil {
+		return err
+	}
+
+	// Subscribers of the city the scooter left won't see it in the new one
+	if previous != nil && previous.City != city {
+		ps.publish(ctx, newFleetScooterEvent(model.ScooterRemoved, previous))
+	}
+
+	ps.publishCurrent(ctx, model.ScooterMoved, scooter.Name)
+
+	return nil
+}
+
+func (ps *publishingRedisService) ChangeScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	if err := ps.RedisService.ChangeScooterStatus(ctx, scooterUUID, status); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterStatusChanged, scooterUUID.String())
+
+	return nil
+}
+
+func (ps *publishingRedisService) DeleteScooter(ctx context.Context, scooterUUID uuid.UUID) error {
+	previous := ps.find(ctx, scooterUUID.String())
+
+	if err := ps.RedisService.DeleteScooter(ctx, scooterUUID); err != nil {
+		return err
+	}
+
+	if previous != nil {
+		ps.publish(ctx, newFleetScooterEvent(model.ScooterRemoved, previous))
+	}
+
+	return nil
+}
+
+func (ps *publishingRedisService) ReserveScooter(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID, ttl time.Duration,
+) error {
+	if err := ps.RedisService.ReserveScooter(ctx, scooterUUID, clientUUID, ttl); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterStatusChanged, scooterUUID.String())
+
+	return nil
+}
+
+// ReleaseReservation publishes the scooter only when it went back to available, a scooter rented or changed by the
+// fleet management in the meantime was published by that change.
+func (ps *publishingRedisService) ReleaseReservation(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID,
+) (bool, error) {
+	released, err := ps.RedisService.ReleaseReservation(ctx, scooterUUID, clientUUID)
+	if err != nil || !released {
+		return released, err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterStatusChanged, scooterUUID.String())
+
+	return true, nil
+}
+
+// FinishRental publishes the scooter once more, its status changed when it was freed already, yet followers of
+// the ride learn from this event that the ride was charged.
+func (ps *publishingRedisService) FinishRental(ctx context.Context, rental *model.Rental) error {
+	if err := ps.RedisService.FinishRental(ctx, rental); err != nil {
+		return err
+	}
+
+	ps.publishCurrent(ctx, model.ScooterStatusChanged, rental.ScooterUUID.String())
+
+	return nil
+}
+
+// publishCurrent reads the scooter back, so the event carries its whole state and the city it is in.
+// ReleaseExpiredReservations publishes every scooter that went back to available, including those released before
+// an error.
+func (ps *publishingRedisService) ReleaseExpiredReservations(
+	ctx context.Context,
+	before time.Time, count int64,
+) ([]uuid.UUID, error) {
+	released, err := ps.RedisService.ReleaseExpiredReservations(ctx, before, count)
+
+	for _, scooterUUID := range released {
+		ps.publishCurrent(ctx, model.ScooterStatusChanged, scooterUUID.String())
+	}
+
+	return released, err
+}
+
+func (ps *publishingRedisService) publishCurrent(ctx context.Context, eventType model.ScooterEventType, name string) {
+	if fleetScooter := ps.find(ctx, name); fleetScooter != nil {
+		ps.publish(ctx, newFleetScooterEvent(eventType, fleetScooter))
+	}
+}
+
+func (ps *publishingRedisService) find(ctx context.Context, name string) *model.FleetScooter {
+	scooterUUID, err := uuid.Parse(name)
+	if err != nil {
+		ps.logger.Warn("parsing scooter's uuid for event failed", logging.KeyScooterUUID, name, logging.KeyError, err)
+
+		return nil
+	}
+
+	fleetScooter, err := ps.RedisService.GetScooter(ctx, scooterUUID)
+	if err != nil {
+		ps.logger.Warn("getting scooter for event failed", logging.KeyScooterUUID, name, logging.KeyError, err)
+
+		return nil
+	}
+
+	return fleetScooter
+}
+
+func (ps *publishingRedisService) publish(ctx context.Context, event *model.ScooterEvent) {
+	if err := ps.repo.PublishScooterEvent(ctx, event); err != nil {
+		ps.logger.Error("publishing scooter event failed",
+			"event_type", event.Type, logging.KeyScooterUUID, event.ScooterUUID, logging.KeyError, err)
+	}
+}
+
+func newFleetScooterEvent(eventType model.ScooterEventType, fleetScooter *model.FleetScooter) *model.ScooterEvent {
+	scooterUUID, _ := uuid.Parse(fleetScooter.Scooter.Name)
+
+	return model.NewScooterEvent(
+		eventType,
+		scooterUUID,
+		fleetScooter.City,
+		fleetScooter.Scooter.Longitude,
+		fleetScooter.Scooter.Latitude,
+		fleetScooter.Status,
+		fleetScooter.Battery,
+	)
+}
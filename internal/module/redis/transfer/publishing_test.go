@@ -0,0 +1,261 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/redis/transfer/mock"
+)
+
+const testOtherCity = "Ottawa"
+
+func TestPublishingRedisService(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	location := &redis.GeoLocation{Name: scooterUUID.String(), Longitude: testLongitude, Latitude: testLatitude}
+
+	fleetScooter := func(city string, status model.ScooterStatus) *model.FleetScooter {
+		return &model.FleetScooter{
+			Scooter: &redis.GeoLocation{Name: scooterUUID.String(), Longitude: testLongitude, Latitude: testLatitude},
+			City:    city,
+			Status:  status,
+			Battery: model.FullBattery,
+		}
+	}
+
+	tests := map[string]struct {
+		call                    func(ps *publishingRedisService) error
+		mockRedisServiceHandler func(mock *mock.MockRedisService)
+		wantEvents              []model.ScooterEventType
+		wantCities              []string
+		wantErr                 error
+		mockPublishErr          error
+	}{
+		"moving scooter by tracker publishes moved event": {
+			call: func(ps *publishingRedisService) error {
+				return ps.UpdateScooterLocation(context.Background(), location, testCity)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), location, testCity).Return(nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(fleetScooter(testCity, model.StatusRented), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterMoved},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"creating scooter publishes added event": {
+			call: func(ps *publishingRedisService) error {
+				metadata := model.NewScooterMetadata(testCity, model.StatusAvailable, 100)
+
+				return ps.CreateScooter(context.Background(), location, metadata)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().CreateScooter(gomock.Any(), location, gomock.Any()).Return(nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+					Return(fleetScooter(testCity, model.StatusAvailable), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterAdded},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"moving scooter to another city publishes removed event in the old city": {
+			call: func(ps *publishingRedisService) error {
+				return ps.MoveScooter(context.Background(), location, testOtherCity)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				gomock.InOrder(
+					mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(fleetScooter(testCity, model.StatusAvailable), nil),
+					mock.EXPECT().MoveScooter(gomock.Any(), location, testOtherCity).Return(nil),
+					mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+						Return(fleetScooter(testOtherCity, model.StatusAvailable), nil),
+				)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterRemoved, model.ScooterMoved},
+			wantCities: []string{testCity, testOtherCity},
+			wantErr:    nil,
+		},
+		"reserving scooter publishes status change": {
+			call: func(ps *publishingRedisService) error {
+				return ps.ReserveScooter(context.Background(), scooterUUID, uuid.Nil, testReservationTTL)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, uuid.Nil, testReservationTTL).Return(nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+					Return(fleetScooter(testCity, model.StatusReserved), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterStatusChanged},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"swapping scooter's status publishes status change": {
+			call: func(ps *publishingRedisService) error {
+				_, err := ps.SwapScooterStatus(context.Background(), scooterUUID, model.StatusRented, model.StatusAvailable)
+
+				return err
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusRented, model.StatusAvailable).
+					Return(model.StatusAvailable, nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(fleetScooter(testCity, model.StatusRented), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterStatusChanged},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"refused swap of scooter's status publishes nothing": {
+			call: func(ps *publishingRedisService) error {
+				_, err := ps.SwapScooterStatus(context.Background(), scooterUUID, model.StatusRented, model.StatusAvailable)
+
+				return err
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusRented, model.StatusAvailable).
+					Return(model.StatusReserved, ErrScooterStatusChanged).Times(1)
+			},
+			wantEvents: nil,
+			wantErr:    ErrScooterStatusChanged,
+		},
+		"releasing reservation publishes status change": {
+			call: func(ps *publishingRedisService) error {
+				_, err := ps.ReleaseReservation(context.Background(), scooterUUID, uuid.Nil)
+
+				return err
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, uuid.Nil).Return(true, nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+					Return(fleetScooter(testCity, model.StatusAvailable), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterStatusChanged},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"releasing reservation of scooter rented in the meantime publishes nothing": {
+			call: func(ps *publishingRedisService) error {
+				_, err := ps.ReleaseReservation(context.Background(), scooterUUID, uuid.Nil)
+
+				return err
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, uuid.Nil).Return(false, nil).Times(1)
+			},
+			wantEvents: nil,
+			wantErr:    nil,
+		},
+		"releasing expired reservations publishes status change of released scooters": {
+			call: func(ps *publishingRedisService) error {
+				_, err := ps.ReleaseExpiredReservations(context.Background(), time.Time{}, testExpiredReservationsCount)
+
+				return err
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().ReleaseExpiredReservations(gomock.Any(), time.Time{}, int64(testExpiredReservationsCount)).
+					Return([]uuid.UUID{scooterUUID}, redis.ErrClosed).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+					Return(fleetScooter(testCity, model.StatusAvailable), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterStatusChanged},
+			wantCities: []string{testCity},
+			wantErr:    redis.ErrClosed,
+		},
+		"deleting scooter publishes removed event": {
+			call: func(ps *publishingRedisService) error {
+				return ps.DeleteScooter(context.Background(), scooterUUID)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+					Return(fleetScooter(testCity, model.StatusRetired), nil).Times(1)
+				mock.EXPECT().DeleteScooter(gomock.Any(), scooterUUID).Return(nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterRemoved},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"finishing rental publishes status change": {
+			call: func(ps *publishingRedisService) error {
+				rental := model.NewRental(uuid.Nil, uuid.Nil, scooterUUID, testCity, time.Time{})
+
+				return ps.FinishRental(context.Background(), rental)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().FinishRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+					Return(fleetScooter(testCity, model.StatusAvailable), nil).Times(1)
+			},
+			wantEvents: []model.ScooterEventType{model.ScooterStatusChanged},
+			wantCities: []string{testCity},
+			wantErr:    nil,
+		},
+		"failed change publishes nothing": {
+			call: func(ps *publishingRedisService) error {
+				return ps.ChangeScooterStatus(context.Background(), scooterUUID, model.StatusMaintenance)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().ChangeScooterStatus(gomock.Any(), scooterUUID, model.StatusMaintenance).
+					Return(ErrScooterInUse).Times(1)
+			},
+			wantEvents: nil,
+			wantErr:    ErrScooterInUse,
+		},
+		"failed publish doesn't fail the change": {
+			call: func(ps *publishingRedisService) error {
+				return ps.UpdateScooterStatus(context.Background(), scooterUUID, model.StatusRented)
+			},
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().UpdateScooterStatus(gomock.Any(), scooterUUID, model.StatusRented).Return(nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(fleetScooter(testCity, model.StatusRented), nil).Times(1)
+			},
+			wantEvents:     []model.ScooterEventType{model.ScooterStatusChanged},
+			wantCities:     []string{testCity},
+			wantErr:        nil,
+			mockPublishErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := mock.NewMockRedisService(controller)
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			var published []*model.ScooterEvent
+
+			mockRedisRepository.EXPECT().PublishScooterEvent(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, event *model.ScooterEvent) error {
+					published = append(published, event)
+
+					return tt.mockPublishErr
+				}).Times(len(tt.wantEvents))
+
+			ps := NewPublishingRedisService(logger, mockRedisService, mockRedisRepository)
+
+			if err := tt.call(ps); !errors.Is(err, tt.wantErr) {
+				t.Errorf("call error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for i, event := range published {
+				require.Equal(t, tt.wantEvents[i], event.Type)
+				require.Equal(t, tt.wantCities[i], event.City)
+				require.Equal(t, scooterUUID, event.ScooterUUID)
+			}
+		})
+	}
+}
@@ -1,15 +1,88 @@
 package transfer
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/module/redis/model"
 )
 
 //go:generate mockgen -source=redis_repository.go -destination=mock/redis_repository_mock.go -package=mock
 type RedisRepository interface {
-	GetScooters(longitude, latitude, radius float64, city string) ([]redis.GeoLocation, error)
-	GetScooterAvailability(scooterUUID uuid.UUID) (bool, error)
-	GetScooterLocation(scooterUUID uuid.UUID, city string) (*redis.GeoPos, error)
-	UpdateScooterLocation(scooter *redis.GeoLocation, city string) error
-	UpdateScooterAvailability(scooterUUID uuid.UUID, availability bool) error
+	GetScooters(ctx context.Context, longitude, latitude, radius float64, city string) ([]redis.GeoLocation, error)
+	GetScooterLocation(ctx context.Context, scooterUUID uuid.UUID, city string) (*redis.GeoPos, error)
+	GetScooterMetadata(ctx context.Context, scooterUUID uuid.UUID) (*model.ScooterMetadata, error)
+	UpdateScooterLocation(ctx context.Context, scooter *redis.GeoLocation, city string) error
+	AddScooter(ctx context.Context, scooter *redis.GeoLocation, metadata *model.ScooterMetadata) (bool, error)
+	MoveScooter(ctx context.Context, scooter *redis.GeoLocation, fromCity, toCity string) error
+	UpdateScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus) error
+	SwapScooterStatus(
+		ctx context.Context,
+		scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+	) (model.ScooterStatus, error)
+	UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error
+	RemoveScooter(ctx context.Context, scooterUUID uuid.UUID, city string) error
+	ReserveScooter(
+		ctx context.Context,
+		scooterUUID, clientUUID uuid.UUID, ttl time.Duration,
+	) (model.ScooterStatus, error)
+	GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error)
+	ReleaseReservation(
+		ctx context.Context,
+		scooterUUID, clientUUID uuid.UUID,
+	) (uuid.UUID, model.ScooterStatus, error)
+	ExpiredReservations(ctx context.Context, before time.Time, count int64) ([]uuid.UUID, error)
+	ReleaseExpiredReservation(ctx context.Context, scooterUUID uuid.UUID) (bool, error)
+	DeleteReservation(ctx context.Context, scooterUUID uuid.UUID) error
+	AddRental(ctx context.Context, rental *model.Rental) error
+	GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error)
+	GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error)
+	GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error)
+	FinishRental(ctx context.Context, rental *model.Rental) error
+	GetClientRentals(ctx context.Context, clientUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error)
+	GetScooterRentals(ctx context.Context, scooterUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error)
+	SetIdempotentResponse(
+		ctx context.Context,
+		key string, response *model.IdempotentResponse, ttl time.Duration,
+	) (bool, error)
+	ReplaceIdempotentResponse(
+		ctx context.Context,
+		key string, response *model.IdempotentResponse, ttl time.Duration,
+	) error
+	GetIdempotentResponse(ctx context.Context, key string) (*model.IdempotentResponse, error)
+	DeleteIdempotentResponse(ctx context.Context, key string) error
+	TakeRateLimitToken(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimit, error)
+	PublishScooterEvent(ctx context.Context, event *model.ScooterEvent) error
+	SubscribeScooterEvents(ctx context.Context, city string) (<-chan *model.ScooterEvent, error)
+	AppendToStream(ctx context.Context, stream string, maxLength int64, messageType string, data []byte) (string, error)
+	CreateStreamGroup(ctx context.Context, stream, group string) error
+	ReadStreamGroup(
+		ctx context.Context,
+		stream, group, consumer string,
+		count int64,
+		block time.Duration,
+	) ([]*model.StreamMessage, error)
+	ReadPendingStreamGroup(
+		ctx context.Context,
+		stream, group, consumer, from string,
+		count int64,
+	) ([]*model.StreamMessage, error)
+	ClaimStreamGroup(
+		ctx context.Context,
+		stream, group, consumer, from string,
+		minIdle time.Duration,
+		count int64,
+	) ([]*model.StreamMessage, string, error)
+	AckStreamMessage(ctx context.Context, stream, group, id string) error
+	SaveWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error
+	AddWebhookDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter, maxLength int64) error
+	GetWebhookDeadLetters(ctx context.Context, limit int64) ([]*model.WebhookDeadLetter, error)
+	MarkWebhookDelivered(ctx context.Context, deliveryID, subscriptionID uuid.UUID, ttl time.Duration) error
+	GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error)
+	Ping(ctx context.Context) error
 }
@@ -1,37 +1,116 @@
 package transfer
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
+	"scootinAboot/internal/domain"
 	"scootinAboot/internal/module/redis/model"
 )
 
+var (
+	ErrScooterAlreadyExists = domain.Conflict("scooter_already_exists", "scooter with given UUID already exists")
+	ErrScooterInUse         = domain.Conflict("scooter_in_use", "scooter is currently rented, it has to be freed first")
+	ErrInvalidScooterStatus = domain.Validation(
+		"invalid_scooter_status",
+		"given scooter status is not supported or can't be assigned",
+	)
+	ErrScooterReserved      = domain.Conflict("scooter_reserved", "scooter is already reserved")
+	ErrReservationNotOwned  = domain.Forbidden("reservation_not_owned", "reservation belongs to another client")
+	ErrScooterNotAvailable  = domain.Conflict("scooter_not_available", "scooter is not available for rental")
+	ErrScooterStatusChanged = domain.Conflict(
+		"scooter_status_changed",
+		"scooter's status was changed in the meantime, try again",
+	)
+)
+
 //go:generate mockgen -source=service.go -destination=mock/redis_service_mock.go -package=mock
 type RedisService interface {
-	GetScooters(longitude, latitude, radius float64, city string) ([]*model.RedisScooter, error)
-	UpdateScooter(scooter *model.RedisScooter, city string) error
-	UpdateScooterLocation(scooter *redis.GeoLocation, city string) error
-	UpdateScooterAvailability(scooterUUID uuid.UUID, availability bool) error
+	GetScooters(ctx context.Context, longitude, latitude, radius float64, city string) ([]*model.RedisScooter, error)
+	GetScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.FleetScooter, error)
+	GetScooterMetadata(ctx context.Context, scooterUUID uuid.UUID) (*model.ScooterMetadata, error)
+	UpdateScooter(ctx context.Context, scooter *model.RedisScooter, city string) error
+	UpdateScooterLocation(ctx context.Context, scooter *redis.GeoLocation, city string) error
+	UpdateScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus) error
+	SwapScooterStatus(
+		ctx context.Context,
+		scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+	) (model.ScooterStatus, error)
+	UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error
+	CreateScooter(ctx context.Context, scooter *redis.GeoLocation, metadata *model.ScooterMetadata) error
+	MoveScooter(ctx context.Context, scooter *redis.GeoLocation, city string) error
+	ChangeScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status model.ScooterStatus) error
+	DeleteScooter(ctx context.Context, scooterUUID uuid.UUID) error
+	ReserveScooter(ctx context.Context, scooterUUID, clientUUID uuid.UUID, ttl time.Duration) error
+	GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error)
+	ReleaseReservation(ctx context.Context, scooterUUID, clientUUID uuid.UUID) (bool, error)
+	ReleaseExpiredReservations(ctx context.Context, before time.Time, count int64) ([]uuid.UUID, error)
+	StartRental(ctx context.Context, rental *model.Rental) error
+	GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error)
+	GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error)
+	GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error)
+	FinishRental(ctx context.Context, rental *model.Rental) error
+	GetClientRentals(ctx context.Context, clientUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error)
+	GetScooterRentals(ctx context.Context, scooterUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error)
+	ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, ttl time.Duration) (bool, error)
+	SaveIdempotentResponse(ctx context.Context, key string, response *model.IdempotentResponse, ttl time.Duration) error
+	GetIdempotentResponse(ctx context.Context, key string) (*model.IdempotentResponse, error)
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	TakeRateLimitToken(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimit, error)
+	SubscribeScooterEvents(ctx context.Context, city string) (<-chan *model.ScooterEvent, error)
+	AppendToStream(ctx context.Context, stream string, maxLength int64, messageType string, data []byte) (string, error)
+	CreateStreamGroup(ctx context.Context, stream, group string) error
+	ReadStreamGroup(
+		ctx context.Context,
+		stream, group, consumer string,
+		count int64,
+		block time.Duration,
+	) ([]*model.StreamMessage, error)
+	ReadPendingStreamGroup(
+		ctx context.Context,
+		stream, group, consumer, from string,
+		count int64,
+	) ([]*model.StreamMessage, error)
+	ClaimStreamGroup(
+		ctx context.Context,
+		stream, group, consumer, from string,
+		minIdle time.Duration,
+		count int64,
+	) ([]*model.StreamMessage, string, error)
+	AckStreamMessage(ctx context.Context, stream, group, id string) error
+	SaveWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error
+	AddWebhookDeadLetter(ctx context.Context, letter *model.WebhookDeadLetter, maxLength int64) error
+	GetWebhookDeadLetters(ctx context.Context, limit int64) ([]*model.WebhookDeadLetter, error)
+	MarkWebhookDelivered(ctx context.Context, deliveryID, subscriptionID uuid.UUID, ttl time.Duration) error
+	GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error)
+	Ping(ctx context.Context) error
 }
 
 type redisService struct {
-	logger *log.Logger
+	logger *slog.Logger
 	repo   RedisRepository
 }
 
-func NewRedisService(logger *log.Logger, repository RedisRepository) *redisService {
+func NewRedisService(logger *slog.Logger, repository RedisRepository) *redisService {
 	return &redisService{
 		logger: logger,
 		repo:   repository,
 	}
 }
 
-func (rs *redisService) GetScooters(longitude, latitude, radius float64, city string) ([]*model.RedisScooter, error) {
-	scooters, err := rs.repo.GetScooters(longitude, latitude, radius, city)
+func (rs *redisService) GetScooters(
+	ctx context.Context,
+	longitude, latitude, radius float64, city string) ([]*model.RedisScooter, error,
+) {
+	scooters, err := rs.repo.GetScooters(ctx, longitude, latitude, radius, city)
 	if err != nil {
 		return nil, fmt.Errorf("getting scooters: %w", err)
 	}
@@ -48,27 +127,27 @@ func (rs *redisService) GetScooters(longitude, latitude, radius float64, city st
 
 		var coords *redis.GeoPos
 
-		coords, err = rs.repo.GetScooterLocation(scooterUUID, city)
+		coords, err = rs.repo.GetScooterLocation(ctx, scooterUUID, city)
 		if err != nil {
 			return nil, fmt.Errorf("getting scooter's coords: %w", err)
 		}
 
-		var availability bool
+		var metadata *model.ScooterMetadata
 
-		availability, err = rs.repo.GetScooterAvailability(scooterUUID)
+		metadata, err = rs.repo.GetScooterMetadata(ctx, scooterUUID)
 		if err != nil {
-			return nil, fmt.Errorf("getting scooter's availability: %w", err)
+			return nil, fmt.Errorf("getting scooter's metadata: %w", err)
 		}
 
-		result := model.NewRedisScooter(&scooters[i], coords, availability)
+		result := model.NewRedisScooter(&scooters[i], coords, metadata.Status, metadata.Battery)
 		results[i] = result
 	}
 
 	return results, nil
 }
 
-func (rs *redisService) UpdateScooter(scooter *model.RedisScooter, city string) error {
-	err := rs.repo.UpdateScooterLocation(scooter.Scooter, city)
+func (rs *redisService) UpdateScooter(ctx context.Context, scooter *model.RedisScooter, city string) error {
+	err := rs.repo.UpdateScooterLocation(ctx, scooter.Scooter, city)
 	if err != nil {
 		return fmt.Errorf("updating scooter's location: %w", err)
 	}
@@ -78,16 +157,16 @@ func (rs *redisService) UpdateScooter(scooter *model.RedisScooter, city string)
 		return fmt.Errorf("parsing scooter's uuid: %w", err)
 	}
 
-	err = rs.repo.UpdateScooterAvailability(scooterUUID, scooter.Availability)
+	err = rs.repo.UpdateScooterStatus(ctx, scooterUUID, scooter.Status)
 	if err != nil {
-		return fmt.Errorf("updating scooter's availability: %w", err)
+		return fmt.Errorf("updating scooter's status: %w", err)
 	}
 
 	return nil
 }
 
-func (rs *redisService) UpdateScooterLocation(location *redis.GeoLocation, city string) error {
-	err := rs.repo.UpdateScooterLocation(location, city)
+func (rs *redisService) UpdateScooterLocation(ctx context.Context, location *redis.GeoLocation, city string) error {
+	err := rs.repo.UpdateScooterLocation(ctx, location, city)
 	if err != nil {
 		return fmt.Errorf("updating scooter's location: %w", err)
 	}
@@ -95,10 +174,510 @@ func (rs *redisService) UpdateScooterLocation(location *redis.GeoLocation, city
 	return nil
 }
 
-func (rs *redisService) UpdateScooterAvailability(scooterUUID uuid.UUID, availability bool) error {
-	err := rs.repo.UpdateScooterAvailability(scooterUUID, availability)
+func (rs *redisService) GetScooterMetadata(ctx context.Context, scooterUUID uuid.UUID) (*model.ScooterMetadata, error) {
+	metadata, err := rs.repo.GetScooterMetadata(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter's metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+func (rs *redisService) UpdateScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	err := rs.repo.UpdateScooterStatus(ctx, scooterUUID, status)
+	if err != nil {
+		return fmt.Errorf("updating scooter's status: %w", err)
+	}
+
+	return nil
+}
+
+// SwapScooterStatus sets the status only when the scooter is in one of the expected statuses, the check and the write
+// are one step in Redis. It answers the status the scooter had and ErrScooterStatusChanged when it wasn't expected.
+func (rs *redisService) SwapScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+) (model.ScooterStatus, error) {
+	previous, err := rs.repo.SwapScooterStatus(ctx, scooterUUID, status, expected...)
+	if err != nil {
+		return "", fmt.Errorf("swapping scooter's status: %w", err)
+	}
+
+	if !slices.Contains(expected, previous) {
+		return previous, fmt.Errorf("scooter is %s: %w", previous, ErrScooterStatusChanged)
+	}
+
+	return previous, nil
+}
+
+func (rs *redisService) UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	err := rs.repo.UpdateScooterBattery(ctx, scooterUUID, battery)
+	if err != nil {
+		return fmt.Errorf("updating scooter's battery: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.FleetScooter, error) {
+	metadata, err := rs.repo.GetScooterMetadata(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter's metadata: %w", err)
+	}
+
+	coords, err := rs.repo.GetScooterLocation(ctx, scooterUUID, metadata.City)
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter's coords: %w", err)
+	}
+
+	return model.NewFleetScooter(&redis.GeoLocation{Name: scooterUUID.String()}, coords, metadata), nil
+}
+
+func (rs *redisService) CreateScooter(
+	ctx context.Context,
+	scooter *redis.GeoLocation, metadata *model.ScooterMetadata,
+) error {
+	if !metadata.Status.IsAssignable() {
+		return ErrInvalidScooterStatus
+	}
+
+	if _, err := uuid.Parse(scooter.Name); err != nil {
+		return fmt.Errorf("parsing scooter's uuid: %w", err)
+	}
+
+	// The existence is checked by the add itself, another registration could win between a check and the add
+	added, err := rs.repo.AddScooter(ctx, scooter, metadata)
+	if err != nil {
+		return fmt.Errorf("adding scooter: %w", err)
+	}
+
+	if !added {
+		return ErrScooterAlreadyExists
+	}
+
+	return nil
+}
+
+func (rs *redisService) MoveScooter(ctx context.Context, scooter *redis.GeoLocation, city string) error {
+	scooterUUID, err := uuid.Parse(scooter.Name)
 	if err != nil {
-		return fmt.Errorf("updating scooter's availability: %w", err)
+		return fmt.Errorf("parsing scooter's uuid: %w", err)
+	}
+
+	fleetScooter, err := rs.GetScooter(ctx, scooterUUID)
+	if err != nil {
+		return err
+	}
+
+	if fleetScooter.Status == model.StatusRented {
+		return ErrScooterInUse
+	}
+
+	if err = rs.repo.MoveScooter(ctx, scooter, fleetScooter.City, city); err != nil {
+		return fmt.Errorf("moving scooter: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) ChangeScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	if !status.IsAssignable() {
+		return ErrInvalidScooterStatus
+	}
+
+	// The status is swapped in one step, so a rider renting the scooter meanwhile isn't overridden
+	previous, err := rs.repo.SwapScooterStatus(ctx, scooterUUID, status, unrentedStatuses()...)
+	if err != nil {
+		return fmt.Errorf("updating scooter's status: %w", err)
+	}
+
+	if previous == model.StatusRented {
+		return ErrScooterInUse
+	}
+
+	// Fleet management overrides rider's reservation
+	if previous == model.StatusReserved {
+		if err = rs.repo.DeleteReservation(ctx, scooterUUID); err != nil {
+			return fmt.Errorf("deleting reservation: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// unrentedStatuses lists every status but rented, fleet management can change the status from any of them.
+func unrentedStatuses() []model.ScooterStatus {
+	return slices.DeleteFunc(slices.Clone(model.ScooterStatuses), func(status model.ScooterStatus) bool {
+		return status == model.StatusRented
+	})
+}
+
+func (rs *redisService) DeleteScooter(ctx context.Context, scooterUUID uuid.UUID) error {
+	fleetScooter, err := rs.GetScooter(ctx, scooterUUID)
+	if err != nil {
+		return err
+	}
+
+	if fleetScooter.Status == model.StatusRented {
+		return ErrScooterInUse
+	}
+
+	if err = rs.repo.RemoveScooter(ctx, scooterUUID, fleetScooter.City); err != nil {
+		return fmt.Errorf("removing scooter: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) ReserveScooter(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID, ttl time.Duration,
+) error {
+	previous, err := rs.repo.ReserveScooter(ctx, scooterUUID, clientUUID, ttl)
+	if err != nil {
+		return fmt.Errorf("reserving scooter: %w", err)
+	}
+
+	switch previous {
+	case model.StatusAvailable:
+		return nil
+	case model.StatusReserved:
+		return ErrScooterReserved
+	default:
+		return fmt.Errorf("scooter is %s: %w", previous, ErrScooterNotAvailable)
+	}
+}
+
+func (rs *redisService) GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error) {
+	clientUUID, err := rs.repo.GetReservation(ctx, scooterUUID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("getting reservation: %w", err)
+	}
+
+	return clientUUID, nil
+}
+
+// ReleaseReservation drops the reservation the client holds, the scooter goes back to available unless its status
+// was changed in the meantime, e.g. by renting it. It reports whether the scooter went back to available.
+func (rs *redisService) ReleaseReservation(ctx context.Context, scooterUUID, clientUUID uuid.UUID) (bool, error) {
+	holderUUID, previous, err := rs.repo.ReleaseReservation(ctx, scooterUUID, clientUUID)
+	if err != nil {
+		return false, fmt.Errorf("releasing reservation: %w", err)
+	}
+
+	if holderUUID != clientUUID {
+		return false, ErrReservationNotOwned
+	}
+
+	return previous == model.StatusReserved, nil
+}
+
+// ReleaseExpiredReservations puts the scooters whose reservation expired before the given time back to available,
+// count of them at most. It answers the scooters that went back to available, those released before an error too.
+func (rs *redisService) ReleaseExpiredReservations(
+	ctx context.Context,
+	before time.Time, count int64,
+) ([]uuid.UUID, error) {
+	scooterUUIDs, err := rs.repo.ExpiredReservations(ctx, before, count)
+	if err != nil {
+		return nil, fmt.Errorf("getting expired reservations: %w", err)
+	}
+
+	var released []uuid.UUID
+
+	for _, scooterUUID := range scooterUUIDs {
+		ok, err := rs.repo.ReleaseExpiredReservation(ctx, scooterUUID)
+		if err != nil {
+			return released, fmt.Errorf("releasing expired reservation of %s: %w", scooterUUID, err)
+		}
+
+		if ok {
+			released = append(released, scooterUUID)
+		}
+	}
+
+	return released, nil
+}
+
+func (rs *redisService) StartRental(ctx context.Context, rental *model.Rental) error {
+	if err := rs.repo.AddRental(ctx, rental); err != nil {
+		return fmt.Errorf("adding rental: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error) {
+	rental, err := rs.repo.GetActiveRental(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active rental: %w", err)
+	}
+
+	return rental, nil
+}
+
+func (rs *redisService) GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error) {
+	rental, err := rs.repo.GetRental(ctx, rentalID)
+	if err != nil {
+		return nil, fmt.Errorf("getting rental: %w", err)
+	}
+
+	return rental, nil
+}
+
+func (rs *redisService) GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error) {
+	rentals, err := rs.repo.GetActiveRentals(ctx, city)
+	if err != nil {
+		return nil, fmt.Errorf("getting active rentals: %w", err)
+	}
+
+	return rentals, nil
+}
+
+func (rs *redisService) FinishRental(ctx context.Context, rental *model.Rental) error {
+	if err := rs.repo.FinishRental(ctx, rental); err != nil {
+		return fmt.Errorf("finishing rental: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetClientRentals(
+	ctx context.Context,
+	clientUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error,
+) {
+	page, err := rs.repo.GetClientRentals(ctx, clientUUID, query)
+	if err != nil {
+		return nil, fmt.Errorf("getting client's rentals: %w", err)
+	}
+
+	return page, nil
+}
+
+func (rs *redisService) GetScooterRentals(
+	ctx context.Context,
+	scooterUUID uuid.UUID, query *model.RentalsQuery) (*model.RentalsPage, error,
+) {
+	page, err := rs.repo.GetScooterRentals(ctx, scooterUUID, query)
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter's rentals: %w", err)
+	}
+
+	return page, nil
+}
+
+// ClaimIdempotencyKey stores a pending response under the key, it reports false when the key is already taken.
+func (rs *redisService) ClaimIdempotencyKey(
+	ctx context.Context,
+	key, fingerprint string, ttl time.Duration) (bool, error,
+) {
+	ok, err := rs.repo.SetIdempotentResponse(ctx, key, model.NewPendingResponse(fingerprint), ttl)
+	if err != nil {
+		return false, fmt.Errorf("claiming idempotency key: %w", err)
+	}
+
+	return ok, nil
+}
+
+func (rs *redisService) SaveIdempotentResponse(
+	ctx context.Context,
+	key string, response *model.IdempotentResponse, ttl time.Duration,
+) error {
+	if err := rs.repo.ReplaceIdempotentResponse(ctx, key, response, ttl); err != nil {
+		return fmt.Errorf("saving idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetIdempotentResponse(ctx context.Context, key string) (*model.IdempotentResponse, error) {
+	response, err := rs.repo.GetIdempotentResponse(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("getting idempotent response: %w", err)
+	}
+
+	return response, nil
+}
+
+func (rs *redisService) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if err := rs.repo.DeleteIdempotentResponse(ctx, key); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// TakeRateLimitToken takes a token from the bucket under the key, it holds limit tokens refilled over the window.
+func (rs *redisService) TakeRateLimitToken(
+	ctx context.Context,
+	key string, limit int, window time.Duration,
+) (*model.RateLimit, error) {
+	rateLimit, err := rs.repo.TakeRateLimitToken(ctx, key, limit, window)
+	if err != nil {
+		return nil, fmt.Errorf("taking rate limit token: %w", err)
+	}
+
+	return rateLimit, nil
+}
+
+// SubscribeScooterEvents streams changes of the scooters in the city until ctx is done.
+func (rs *redisService) SubscribeScooterEvents(ctx context.Context, city string) (<-chan *model.ScooterEvent, error) {
+	events, err := rs.repo.SubscribeScooterEvents(ctx, city)
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to scooter events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (rs *redisService) AppendToStream(
+	ctx context.Context,
+	stream string,
+	maxLength int64,
+	messageType string,
+	data []byte,
+) (string, error) {
+	id, err := rs.repo.AppendToStream(ctx, stream, maxLength, messageType, data)
+	if err != nil {
+		return "", fmt.Errorf("appending to stream: %w", err)
+	}
+
+	return id, nil
+}
+
+func (rs *redisService) CreateStreamGroup(ctx context.Context, stream, group string) error {
+	if err := rs.repo.CreateStreamGroup(ctx, stream, group); err != nil {
+		return fmt.Errorf("creating stream group: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) ReadStreamGroup(
+	ctx context.Context,
+	stream, group, consumer string,
+	count int64,
+	block time.Duration,
+) ([]*model.StreamMessage, error) {
+	messages, err := rs.repo.ReadStreamGroup(ctx, stream, group, consumer, count, block)
+	if err != nil {
+		return nil, fmt.Errorf("reading stream group: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (rs *redisService) ReadPendingStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	count int64,
+) ([]*model.StreamMessage, error) {
+	messages, err := rs.repo.ReadPendingStreamGroup(ctx, stream, group, consumer, from, count)
+	if err != nil {
+		return nil, fmt.Errorf("reading pending stream messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (rs *redisService) ClaimStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	minIdle time.Duration,
+	count int64,
+) ([]*model.StreamMessage, string, error) {
+	messages, next, err := rs.repo.ClaimStreamGroup(ctx, stream, group, consumer, from, minIdle, count)
+	if err != nil {
+		return nil, "", fmt.Errorf("claiming stream messages: %w", err)
+	}
+
+	return messages, next, nil
+}
+
+func (rs *redisService) AckStreamMessage(ctx context.Context, stream, group, id string) error {
+	if err := rs.repo.AckStreamMessage(ctx, stream, group, id); err != nil {
+		return fmt.Errorf("acknowledging stream message: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) SaveWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	if err := rs.repo.SaveWebhookSubscription(ctx, subscription); err != nil {
+		return fmt.Errorf("saving webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	subscriptions, err := rs.repo.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (rs *redisService) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := rs.repo.DeleteWebhookSubscription(ctx, id); err != nil {
+		return fmt.Errorf("deleting webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) AddWebhookDeadLetter(
+	ctx context.Context,
+	letter *model.WebhookDeadLetter, maxLength int64,
+) error {
+	if err := rs.repo.AddWebhookDeadLetter(ctx, letter, maxLength); err != nil {
+		return fmt.Errorf("adding webhook dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetWebhookDeadLetters(ctx context.Context, limit int64) ([]*model.WebhookDeadLetter, error) {
+	letters, err := rs.repo.GetWebhookDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+func (rs *redisService) MarkWebhookDelivered(
+	ctx context.Context,
+	deliveryID, subscriptionID uuid.UUID,
+	ttl time.Duration,
+) error {
+	if err := rs.repo.MarkWebhookDelivered(ctx, deliveryID, subscriptionID, ttl); err != nil {
+		return fmt.Errorf("marking webhook delivered: %w", err)
+	}
+
+	return nil
+}
+
+func (rs *redisService) GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error) {
+	subscriptionIDs, err := rs.repo.GetWebhookDelivered(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook delivered: %w", err)
+	}
+
+	return subscriptionIDs, nil
+}
+
+// Ping checks that Redis answers, the deadline of ctx bounds how long it may take.
+func (rs *redisService) Ping(ctx context.Context) error {
+	if err := rs.repo.Ping(ctx); err != nil {
+		return fmt.Errorf("checking redis: %w", err)
 	}
 
 	return nil
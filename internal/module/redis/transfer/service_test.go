@@ -3,16 +3,20 @@
 package transfer
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 
+	"scootinAboot/internal/logging"
 	"scootinAboot/internal/module/redis/model"
 	"scootinAboot/internal/module/redis/transfer/mock"
 )
@@ -22,10 +26,13 @@ const (
 	testLongitude = 70.0
 	testLatitude  = 60.0
 	testRadius    = 10000.0
+
+	testReservationTTL           = 10 * time.Minute
+	testExpiredReservationsCount = 100
 )
 
 func TestGetScooters(t *testing.T) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -63,7 +70,7 @@ func TestGetScooters(t *testing.T) {
 		},
 	}
 
-	scootersActivities := []bool{true, false, true}
+	scootersStatuses := []model.ScooterStatus{model.StatusAvailable, model.StatusRented, model.StatusMaintenance}
 
 	scooters := []*model.RedisScooter{
 		model.NewRedisScooter(
@@ -74,7 +81,8 @@ func TestGetScooters(t *testing.T) {
 				Longitude: scootersLocations[0].Longitude,
 				Latitude:  scootersLocations[0].Latitude,
 			},
-			scootersActivities[0],
+			scootersStatuses[0],
+			model.FullBattery,
 		),
 		model.NewRedisScooter(
 			&redis.GeoLocation{
@@ -84,7 +92,8 @@ func TestGetScooters(t *testing.T) {
 				Longitude: scootersLocations[1].Longitude,
 				Latitude:  scootersLocations[1].Latitude,
 			},
-			scootersActivities[1],
+			scootersStatuses[1],
+			model.FullBattery,
 		),
 		model.NewRedisScooter(
 			&redis.GeoLocation{
@@ -94,12 +103,13 @@ func TestGetScooters(t *testing.T) {
 				Longitude: scootersLocations[2].Longitude,
 				Latitude:  scootersLocations[2].Latitude,
 			},
-			scootersActivities[2],
+			scootersStatuses[2],
+			model.FullBattery,
 		),
 	}
 
 	tests := map[string]struct {
-		logger                     *log.Logger
+		logger                     *slog.Logger
 		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
 		want                       []*model.RedisScooter
 		wantErr                    bool
@@ -107,16 +117,17 @@ func TestGetScooters(t *testing.T) {
 		"getting scooters successfully": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().GetScooters(testLongitude, testLatitude, testRadius, testCity).
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
 					Return(scootersInRadius, nil).Times(1)
 				for i := range scootersInRadius {
 					scooterUUID, innerErr := uuid.Parse(scootersInRadius[i].Name)
 					require.NoError(t, innerErr)
 
-					mock.EXPECT().GetScooterLocation(scooterUUID, testCity).
+					mock.EXPECT().GetScooterLocation(gomock.Any(), scooterUUID, testCity).
 						Return(scootersLocations[i], nil).Times(1)
 
-					mock.EXPECT().GetScooterAvailability(scooterUUID).Return(scootersActivities[i], nil).Times(1)
+					mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+						Return(model.NewScooterMetadata(testCity, scootersStatuses[i], model.FullBattery), nil).Times(1)
 				}
 			},
 			want:    scooters,
@@ -125,7 +136,7 @@ func TestGetScooters(t *testing.T) {
 		"getting scooters failed, because repository threw an error when getting scooters": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().GetScooters(testLongitude, testLatitude, testRadius, testCity).
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
 					Return(nil, redis.ErrClosed).Times(1)
 			},
 			want:    nil,
@@ -134,31 +145,31 @@ func TestGetScooters(t *testing.T) {
 		"getting scooters failed, because repository threw an error when getting scooter's location": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().GetScooters(testLongitude, testLatitude, testRadius, testCity).
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
 					Return(scootersInRadius, nil).Times(1)
 
 				scooterUUID, innerErr := uuid.Parse(scootersInRadius[0].Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().GetScooterLocation(scooterUUID, testCity).
+				mock.EXPECT().GetScooterLocation(gomock.Any(), scooterUUID, testCity).
 					Return(nil, redis.ErrClosed).Times(1)
 			},
 			want:    nil,
 			wantErr: true,
 		},
-		"getting scooters failed, because repository threw an error when getting scooter's availability": {
+		"getting scooters failed, because repository threw an error when getting scooter's metadata": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().GetScooters(testLongitude, testLatitude, testRadius, testCity).
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
 					Return(scootersInRadius, nil).Times(1)
 
 				scooterUUID, innerErr := uuid.Parse(scootersInRadius[0].Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().GetScooterLocation(scooterUUID, testCity).
+				mock.EXPECT().GetScooterLocation(gomock.Any(), scooterUUID, testCity).
 					Return(scootersLocations[0], nil).Times(1)
 
-				mock.EXPECT().GetScooterAvailability(scooterUUID).Return(false, redis.ErrClosed).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(nil, redis.ErrClosed).Times(1)
 			},
 			want:    nil,
 			wantErr: true,
@@ -174,7 +185,7 @@ func TestGetScooters(t *testing.T) {
 			tt.mockRedisRepositoryHandler(mockRedisRepository)
 
 			rs := NewRedisService(tt.logger, mockRedisRepository)
-			got, err := rs.GetScooters(testLongitude, testLatitude, testRadius, testCity)
+			got, err := rs.GetScooters(context.Background(), testLongitude, testLatitude, testRadius, testCity)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetScooters() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -187,7 +198,7 @@ func TestGetScooters(t *testing.T) {
 }
 
 func TestUpdateScooter(t *testing.T) {
-	logger := &log.Logger{}
+	logger := logging.Discard()
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -198,24 +209,24 @@ func TestUpdateScooter(t *testing.T) {
 			Longitude: testLongitude,
 			Latitude:  testLatitude,
 		},
-		Availability: true,
+		Status: model.StatusAvailable,
 	}
 
 	tests := map[string]struct {
-		logger                     *log.Logger
+		logger                     *slog.Logger
 		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
 		wantErr                    bool
 	}{
 		"updating scooter successfully": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterLocation(scooter.Scooter, testCity).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooter.Scooter, testCity).
 					Return(nil).Times(1)
 
 				scooterUUID, innerErr := uuid.Parse(scooter.Scooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().UpdateScooterAvailability(scooterUUID, scooter.Availability).
+				mock.EXPECT().UpdateScooterStatus(gomock.Any(), scooterUUID, scooter.Status).
 					Return(nil).Times(1)
 			},
 			wantErr: false,
@@ -223,21 +234,21 @@ func TestUpdateScooter(t *testing.T) {
 		"updating scooter failed, because repository threw an error when updating scooter's location": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterLocation(scooter.Scooter, testCity).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooter.Scooter, testCity).
 					Return(redis.ErrClosed).Times(1)
 			},
 			wantErr: true,
 		},
-		"updating scooter failed, because repository threw an error when updating scooter's availability": {
+		"updating scooter failed, because repository threw an error when updating scooter's status": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterLocation(scooter.Scooter, testCity).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooter.Scooter, testCity).
 					Return(nil).Times(1)
 
 				scooterUUID, innerErr := uuid.Parse(scooter.Scooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().UpdateScooterAvailability(scooterUUID, scooter.Availability).
+				mock.EXPECT().UpdateScooterStatus(gomock.Any(), scooterUUID, scooter.Status).
 					Return(redis.ErrClosed).Times(1)
 			},
 			wantErr: true,
@@ -254,7 +265,7 @@ func TestUpdateScooter(t *testing.T) {
 
 			rs := NewRedisService(logger, mockRedisRepository)
 
-			if err := rs.UpdateScooter(&scooter, testCity); (err != nil) != tt.wantErr {
+			if err := rs.UpdateScooter(context.Background(), &scooter, testCity); (err != nil) != tt.wantErr {
 				t.Errorf("UpdateScooter() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -262,7 +273,7 @@ func TestUpdateScooter(t *testing.T) {
 }
 
 func TestUpdateScooterLocation(t *testing.T) {
-	logger := &log.Logger{}
+	logger := logging.Discard()
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -274,14 +285,14 @@ func TestUpdateScooterLocation(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		logger                     *log.Logger
+		logger                     *slog.Logger
 		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
 		wantErr                    bool
 	}{
 		"updating scooter successfully": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterLocation(scooterLocation, testCity).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooterLocation, testCity).
 					Return(nil).Times(1)
 			},
 			wantErr: false,
@@ -289,7 +300,7 @@ func TestUpdateScooterLocation(t *testing.T) {
 		"updating scooter failed, because repository threw an error when updating scooter's location": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterLocation(scooterLocation, testCity).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooterLocation, testCity).
 					Return(redis.ErrClosed).Times(1)
 			},
 			wantErr: true,
@@ -306,42 +317,481 @@ func TestUpdateScooterLocation(t *testing.T) {
 
 			rs := NewRedisService(logger, mockRedisRepository)
 
-			if err := rs.UpdateScooterLocation(scooterLocation, testCity); (err != nil) != tt.wantErr {
+			if err := rs.UpdateScooterLocation(context.Background(), scooterLocation, testCity); (err != nil) != tt.wantErr {
 				t.Errorf("UpdateScooterLocation() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestUpdateScooterAvailability(t *testing.T) {
-	logger := &log.Logger{}
+func TestUpdateScooterStatus(t *testing.T) {
+	logger := logging.Discard()
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
-	scooterAvailability := true
-
 	tests := map[string]struct {
-		logger                     *log.Logger
+		logger                     *slog.Logger
 		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
 		wantErr                    bool
 	}{
 		"updating scooter successfully": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterAvailability(firstScooterUUID, scooterAvailability).
+				mock.EXPECT().UpdateScooterStatus(gomock.Any(), firstScooterUUID, model.StatusRented).
 					Return(nil).Times(1)
 			},
 			wantErr: false,
 		},
-		"updating scooter failed, because repository threw an error when updating scooter's availability": {
+		"updating scooter failed, because repository threw an error when updating scooter's status": {
 			logger: logger,
 			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
-				mock.EXPECT().UpdateScooterAvailability(firstScooterUUID, scooterAvailability).
-					Return(nil).Times(1)
+				mock.EXPECT().UpdateScooterStatus(gomock.Any(), firstScooterUUID, model.StatusRented).
+					Return(redis.ErrClosed).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+			err := rs.UpdateScooterStatus(context.Background(), firstScooterUUID, model.StatusRented)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateScooterStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateScooter(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooterLocation := &redis.GeoLocation{
+		Name:      scooterUUID.String(),
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+	}
+
+	tests := map[string]struct {
+		status                     model.ScooterStatus
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository, metadata *model.ScooterMetadata)
+		wantErr                    error
+	}{
+		"creating scooter successfully": {
+			status: model.StatusAvailable,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository, metadata *model.ScooterMetadata) {
+				mock.EXPECT().AddScooter(gomock.Any(), scooterLocation, metadata).Return(true, nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"creating scooter failed, because scooter already exists": {
+			status: model.StatusAvailable,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository, metadata *model.ScooterMetadata) {
+				mock.EXPECT().AddScooter(gomock.Any(), scooterLocation, metadata).Return(false, nil).Times(1)
+			},
+			wantErr: ErrScooterAlreadyExists,
+		},
+		"creating scooter failed, because of unsupported status": {
+			status:                     model.ScooterStatus("broken"),
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository, metadata *model.ScooterMetadata) {},
+			wantErr:                    ErrInvalidScooterStatus,
+		},
+		"creating scooter failed, because repository threw an error when adding scooter": {
+			status: model.StatusAvailable,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository, metadata *model.ScooterMetadata) {
+				mock.EXPECT().AddScooter(gomock.Any(), scooterLocation, metadata).
+					Return(false, redis.ErrClosed).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			metadata := model.NewScooterMetadata(testCity, tt.status, model.FullBattery)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository, metadata)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			if err := rs.CreateScooter(context.Background(), scooterLocation, metadata); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateScooter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChangeScooterStatus(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		status                     model.ScooterStatus
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		wantErr                    error
+	}{
+		"changing scooter's status successfully": {
+			status: model.StatusMaintenance,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusMaintenance, gomock.Any()).
+					Return(model.StatusAvailable, nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"changing status of reserved scooter drops its reservation": {
+			status: model.StatusMaintenance,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusMaintenance, gomock.Any()).
+					Return(model.StatusReserved, nil).Times(1)
+				mock.EXPECT().DeleteReservation(gomock.Any(), scooterUUID).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"changing scooter's status failed, because scooter is rented": {
+			status: model.StatusMaintenance,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusMaintenance, gomock.Any()).
+					Return(model.StatusRented, nil).Times(1)
+			},
+			wantErr: ErrScooterInUse,
+		},
+		"changing scooter's status failed, because scooter does not exist": {
+			status: model.StatusMaintenance,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusMaintenance, gomock.Any()).
+					Return(model.ScooterStatus(""), model.ErrScooterNotFound).Times(1)
+			},
+			wantErr: model.ErrScooterNotFound,
+		},
+		"changing scooter's status failed, because status is managed by rentals": {
+			status:                     model.StatusRented,
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {},
+			wantErr:                    ErrInvalidScooterStatus,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			if err := rs.ChangeScooterStatus(context.Background(), scooterUUID, tt.status); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChangeScooterStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSwapScooterStatus(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		want                       model.ScooterStatus
+		wantErr                    error
+	}{
+		"swapping scooter's status successfully": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusRented, model.StatusAvailable).
+					Return(model.StatusAvailable, nil).Times(1)
+			},
+			want: model.StatusAvailable,
+		},
+		"swapping scooter's status failed, because it was changed in the meantime": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusRented, model.StatusAvailable).
+					Return(model.StatusReserved, nil).Times(1)
+			},
+			want:    model.StatusReserved,
+			wantErr: ErrScooterStatusChanged,
+		},
+		"swapping scooter's status failed, because repository threw an error": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, model.StatusRented, model.StatusAvailable).
+					Return(model.ScooterStatus(""), redis.ErrClosed).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			got, err := rs.SwapScooterStatus(context.Background(), scooterUUID, model.StatusRented, model.StatusAvailable)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeleteScooter(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooterLocation := &redis.GeoPos{
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+	}
+
+	tests := map[string]struct {
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		wantErr                    error
+	}{
+		"deleting scooter successfully": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(model.NewScooterMetadata(testCity, model.StatusRetired, model.FullBattery), nil).Times(1)
+				mock.EXPECT().GetScooterLocation(gomock.Any(), scooterUUID, testCity).Return(scooterLocation, nil).Times(1)
+				mock.EXPECT().RemoveScooter(gomock.Any(), scooterUUID, testCity).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"deleting scooter failed, because scooter is rented": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(model.NewScooterMetadata(testCity, model.StatusRented, model.FullBattery), nil).Times(1)
+				mock.EXPECT().GetScooterLocation(gomock.Any(), scooterUUID, testCity).Return(scooterLocation, nil).Times(1)
+			},
+			wantErr: ErrScooterInUse,
+		},
+		"deleting scooter failed, because repository threw an error when removing scooter": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(model.NewScooterMetadata(testCity, model.StatusRetired, model.FullBattery), nil).Times(1)
+				mock.EXPECT().GetScooterLocation(gomock.Any(), scooterUUID, testCity).Return(scooterLocation, nil).Times(1)
+				mock.EXPECT().RemoveScooter(gomock.Any(), scooterUUID, testCity).Return(redis.ErrClosed).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			if err := rs.DeleteScooter(context.Background(), scooterUUID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteScooter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetScooterMetadata(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		want                       *model.ScooterMetadata
+		wantErr                    bool
+	}{
+		"getting scooter's metadata successfully": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(model.NewScooterMetadata(testCity, model.StatusAvailable, model.FullBattery), nil).Times(1)
+			},
+			want:    model.NewScooterMetadata(testCity, model.StatusAvailable, model.FullBattery),
+			wantErr: false,
+		},
+		"getting metadata of reserved scooter": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(model.NewScooterMetadata(testCity, model.StatusReserved, model.FullBattery), nil).Times(1)
 			},
+			want:    model.NewScooterMetadata(testCity, model.StatusReserved, model.FullBattery),
 			wantErr: false,
 		},
+		"getting scooter's metadata failed, because repository threw an error": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(nil, redis.ErrClosed).Times(1)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			got, err := rs.GetScooterMetadata(context.Background(), scooterUUID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetScooterMetadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetScooterMetadata() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReserveScooter(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		wantErr                    error
+	}{
+		"reserving scooter successfully": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(model.StatusAvailable, nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"reserving scooter failed, because scooter is already reserved": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(model.StatusReserved, nil).Times(1)
+			},
+			wantErr: ErrScooterReserved,
+		},
+		"reserving scooter failed, because scooter was rented in the meantime": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(model.StatusRented, nil).Times(1)
+			},
+			wantErr: ErrScooterNotAvailable,
+		},
+		"reserving scooter failed, because repository threw an error when reserving": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(model.ScooterStatus(""), redis.ErrClosed).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			err := rs.ReserveScooter(context.Background(), scooterUUID, clientUUID, testReservationTTL)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReserveScooter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReleaseReservation(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	anotherClientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		wantReleased               bool
+		wantErr                    error
+	}{
+		"releasing reservation of reserved scooter successfully": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(clientUUID, model.StatusReserved, nil).Times(1)
+			},
+			wantReleased: true,
+			wantErr:      nil,
+		},
+		"releasing reservation of rented scooter keeps its status": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(clientUUID, model.StatusRented, nil).Times(1)
+			},
+			wantReleased: false,
+			wantErr:      nil,
+		},
+		"releasing reservation failed, because it belongs to another client": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(anotherClientUUID, model.StatusReserved, nil).Times(1)
+			},
+			wantReleased: false,
+			wantErr:      ErrReservationNotOwned,
+		},
+		"releasing reservation failed, because it does not exist": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(uuid.Nil, model.ScooterStatus(""), model.ErrReservationNotFound).Times(1)
+			},
+			wantReleased: false,
+			wantErr:      model.ErrReservationNotFound,
+		},
+		"releasing reservation failed, because repository threw an error": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(uuid.Nil, model.ScooterStatus(""), redis.ErrClosed).Times(1)
+			},
+			wantReleased: false,
+			wantErr:      redis.ErrClosed,
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -353,9 +803,85 @@ func TestUpdateScooterAvailability(t *testing.T) {
 			tt.mockRedisRepositoryHandler(mockRedisRepository)
 
 			rs := NewRedisService(logger, mockRedisRepository)
-			if err := rs.UpdateScooterAvailability(firstScooterUUID, scooterAvailability); (err != nil) != tt.wantErr {
-				t.Errorf("UpdateScooterAvailability() error = %v, wantErr %v", err, tt.wantErr)
+
+			released, err := rs.ReleaseReservation(context.Background(), scooterUUID, clientUUID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReleaseReservation() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			require.Equal(t, tt.wantReleased, released)
+		})
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	logger := logging.Discard()
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	anotherScooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	before := time.Now()
+
+	tests := map[string]struct {
+		mockRedisRepositoryHandler func(mock *mock.MockRedisRepository)
+		want                       []uuid.UUID
+		wantErr                    error
+	}{
+		"releasing expired reservations successfully": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ExpiredReservations(gomock.Any(), before, int64(testExpiredReservationsCount)).
+					Return([]uuid.UUID{scooterUUID, anotherScooterUUID}, nil).Times(1)
+				mock.EXPECT().ReleaseExpiredReservation(gomock.Any(), scooterUUID).Return(true, nil).Times(1)
+				mock.EXPECT().ReleaseExpiredReservation(gomock.Any(), anotherScooterUUID).Return(false, nil).Times(1)
+			},
+			want:    []uuid.UUID{scooterUUID},
+			wantErr: nil,
+		},
+		"releasing expired reservations successfully, when none expired": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ExpiredReservations(gomock.Any(), before, int64(testExpiredReservationsCount)).
+					Return([]uuid.UUID{}, nil).Times(1)
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		"releasing expired reservations failed, because repository threw an error when listing them": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ExpiredReservations(gomock.Any(), before, int64(testExpiredReservationsCount)).
+					Return(nil, redis.ErrClosed).Times(1)
+			},
+			want:    nil,
+			wantErr: redis.ErrClosed,
+		},
+		"releasing expired reservations failed midway answers those released before": {
+			mockRedisRepositoryHandler: func(mock *mock.MockRedisRepository) {
+				mock.EXPECT().ExpiredReservations(gomock.Any(), before, int64(testExpiredReservationsCount)).
+					Return([]uuid.UUID{scooterUUID, anotherScooterUUID}, nil).Times(1)
+				mock.EXPECT().ReleaseExpiredReservation(gomock.Any(), scooterUUID).Return(true, nil).Times(1)
+				mock.EXPECT().ReleaseExpiredReservation(gomock.Any(), anotherScooterUUID).
+					Return(false, redis.ErrClosed).Times(1)
+			},
+			want:    []uuid.UUID{scooterUUID},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisRepository := mock.NewMockRedisRepository(controller)
+
+			tt.mockRedisRepositoryHandler(mockRedisRepository)
+
+			rs := NewRedisService(logger, mockRedisRepository)
+
+			released, err := rs.ReleaseExpiredReservations(context.Background(), before, testExpiredReservationsCount)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, released)
 		})
 	}
 }
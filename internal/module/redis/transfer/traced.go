@@ -0,0 +1,565 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/tracing"
+)
+
+// tracedRedisService spans every operation of the service with the scooter and city it concerns. SubscribeScooterEvents
+// and ReadStreamGroup are passed through, they wait for messages as long as their caller listens.
+type tracedRedisService struct {
+	RedisService
+}
+
+func NewTracedRedisService(service RedisService) *tracedRedisService {
+	return &tracedRedisService{RedisService: service}
+}
+
+func (ts *tracedRedisService) GetScooters(
+	ctx context.Context,
+	longitude, latitude, radius float64, city string,
+) ([]*model.RedisScooter, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetScooters", tracing.KeyCity.String(city))
+
+	scooters, err := ts.RedisService.GetScooters(ctx, longitude, latitude, radius, city)
+
+	tracing.End(span, err)
+
+	return scooters, err
+}
+
+func (ts *tracedRedisService) GetScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.FleetScooter, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetScooter", tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+	fleetScooter, err := ts.RedisService.GetScooter(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return fleetScooter, err
+}
+
+func (ts *tracedRedisService) GetScooterMetadata(
+	ctx context.Context,
+	scooterUUID uuid.UUID,
+) (*model.ScooterMetadata, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.GetScooterMetadata",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	metadata, err := ts.RedisService.GetScooterMetadata(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return metadata, err
+}
+
+func (ts *tracedRedisService) UpdateScooter(ctx context.Context, scooter *model.RedisScooter, city string) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.UpdateScooter",
+		tracing.KeyScooterUUID.String(scooter.Scooter.Name),
+		tracing.KeyCity.String(city),
+	)
+
+	err := ts.RedisService.UpdateScooter(ctx, scooter, city)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) UpdateScooterLocation(
+	ctx context.Context,
+	scooter *redis.GeoLocation, city string,
+) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.UpdateScooterLocation",
+		tracing.KeyScooterUUID.String(scooter.Name),
+		tracing.KeyCity.String(city),
+	)
+
+	err := ts.RedisService.UpdateScooterLocation(ctx, scooter, city)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) UpdateScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.UpdateScooterStatus",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	err := ts.RedisService.UpdateScooterStatus(ctx, scooterUUID, status)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) SwapScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus, expected ...model.ScooterStatus,
+) (model.ScooterStatus, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.SwapScooterStatus",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	previous, err := ts.RedisService.SwapScooterStatus(ctx, scooterUUID, status, expected...)
+
+	tracing.End(span, err)
+
+	return previous, err
+}
+
+func (ts *tracedRedisService) UpdateScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.UpdateScooterBattery",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	err := ts.RedisService.UpdateScooterBattery(ctx, scooterUUID, battery)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) CreateScooter(
+	ctx context.Context,
+	scooter *redis.GeoLocation, metadata *model.ScooterMetadata,
+) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.CreateScooter",
+		tracing.KeyScooterUUID.String(scooter.Name),
+		tracing.KeyCity.String(metadata.City),
+	)
+
+	err := ts.RedisService.CreateScooter(ctx, scooter, metadata)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) MoveScooter(ctx context.Context, scooter *redis.GeoLocation, city string) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.MoveScooter",
+		tracing.KeyScooterUUID.String(scooter.Name),
+		tracing.KeyCity.String(city),
+	)
+
+	err := ts.RedisService.MoveScooter(ctx, scooter, city)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) ChangeScooterStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, status model.ScooterStatus,
+) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.ChangeScooterStatus",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	err := ts.RedisService.ChangeScooterStatus(ctx, scooterUUID, status)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) DeleteScooter(ctx context.Context, scooterUUID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "RedisService.DeleteScooter", tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+	err := ts.RedisService.DeleteScooter(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) ReserveScooter(
+	ctx context.Context,
+	scooterUUID, clientUUID uuid.UUID, ttl time.Duration,
+) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.ReserveScooter",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+		tracing.KeyClientUUID.String(clientUUID.String()),
+	)
+
+	err := ts.RedisService.ReserveScooter(ctx, scooterUUID, clientUUID, ttl)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetReservation(ctx context.Context, scooterUUID uuid.UUID) (uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetReservation", tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+	holderUUID, err := ts.RedisService.GetReservation(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return holderUUID, err
+}
+
+func (ts *tracedRedisService) ReleaseReservation(ctx context.Context, scooterUUID, clientUUID uuid.UUID) (bool, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.ReleaseReservation",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	released, err := ts.RedisService.ReleaseReservation(ctx, scooterUUID, clientUUID)
+
+	tracing.End(span, err)
+
+	return released, err
+}
+
+func (ts *tracedRedisService) ReleaseExpiredReservations(
+	ctx context.Context,
+	before time.Time, count int64,
+) ([]uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.ReleaseExpiredReservations")
+
+	released, err := ts.RedisService.ReleaseExpiredReservations(ctx, before, count)
+
+	tracing.End(span, err)
+
+	return released, err
+}
+
+func (ts *tracedRedisService) StartRental(ctx context.Context, rental *model.Rental) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.StartRental",
+		tracing.KeyScooterUUID.String(rental.ScooterUUID.String()),
+		tracing.KeyCity.String(rental.City),
+	)
+
+	err := ts.RedisService.StartRental(ctx, rental)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetActiveRental(ctx context.Context, scooterUUID uuid.UUID) (*model.Rental, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetActiveRental", tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+	rental, err := ts.RedisService.GetActiveRental(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return rental, err
+}
+
+func (ts *tracedRedisService) GetRental(ctx context.Context, rentalID uuid.UUID) (*model.Rental, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetRental", tracing.KeyRentalID.String(rentalID.String()))
+
+	rental, err := ts.RedisService.GetRental(ctx, rentalID)
+
+	tracing.End(span, err)
+
+	return rental, err
+}
+
+func (ts *tracedRedisService) FinishRental(ctx context.Context, rental *model.Rental) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.FinishRental",
+		tracing.KeyScooterUUID.String(rental.ScooterUUID.String()),
+		tracing.KeyCity.String(rental.City),
+	)
+
+	err := ts.RedisService.FinishRental(ctx, rental)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetActiveRentals(ctx context.Context, city string) ([]*model.Rental, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetActiveRentals", tracing.KeyCity.String(city))
+
+	rentals, err := ts.RedisService.GetActiveRentals(ctx, city)
+
+	tracing.End(span, err)
+
+	return rentals, err
+}
+
+func (ts *tracedRedisService) GetClientRentals(
+	ctx context.Context,
+	clientUUID uuid.UUID, query *model.RentalsQuery,
+) (*model.RentalsPage, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetClientRentals", tracing.KeyClientUUID.String(clientUUID.String()))
+
+	page, err := ts.RedisService.GetClientRentals(ctx, clientUUID, query)
+
+	tracing.End(span, err)
+
+	return page, err
+}
+
+func (ts *tracedRedisService) GetScooterRentals(
+	ctx context.Context,
+	scooterUUID uuid.UUID, query *model.RentalsQuery,
+) (*model.RentalsPage, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RedisService.GetScooterRentals",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	page, err := ts.RedisService.GetScooterRentals(ctx, scooterUUID, query)
+
+	tracing.End(span, err)
+
+	return page, err
+}
+
+func (ts *tracedRedisService) ClaimIdempotencyKey(
+	ctx context.Context,
+	key, fingerprint string, ttl time.Duration,
+) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.ClaimIdempotencyKey")
+
+	claimed, err := ts.RedisService.ClaimIdempotencyKey(ctx, key, fingerprint, ttl)
+
+	tracing.End(span, err)
+
+	return claimed, err
+}
+
+func (ts *tracedRedisService) SaveIdempotentResponse(
+	ctx context.Context,
+	key string, response *model.IdempotentResponse, ttl time.Duration,
+) error {
+	ctx, span := tracing.Start(ctx, "RedisService.SaveIdempotentResponse")
+
+	err := ts.RedisService.SaveIdempotentResponse(ctx, key, response, ttl)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetIdempotentResponse(
+	ctx context.Context,
+	key string,
+) (*model.IdempotentResponse, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetIdempotentResponse")
+
+	response, err := ts.RedisService.GetIdempotentResponse(ctx, key)
+
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (ts *tracedRedisService) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "RedisService.ReleaseIdempotencyKey")
+
+	err := ts.RedisService.ReleaseIdempotencyKey(ctx, key)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) TakeRateLimitToken(
+	ctx context.Context,
+	key string, limit int, window time.Duration,
+) (*model.RateLimit, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.TakeRateLimitToken")
+
+	rateLimit, err := ts.RedisService.TakeRateLimitToken(ctx, key, limit, window)
+
+	tracing.End(span, err)
+
+	return rateLimit, err
+}
+
+func (ts *tracedRedisService) AppendToStream(
+	ctx context.Context,
+	stream string, maxLength int64, messageType string, data []byte,
+) (string, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.AppendToStream")
+
+	id, err := ts.RedisService.AppendToStream(ctx, stream, maxLength, messageType, data)
+
+	tracing.End(span, err)
+
+	return id, err
+}
+
+func (ts *tracedRedisService) CreateStreamGroup(ctx context.Context, stream, group string) error {
+	ctx, span := tracing.Start(ctx, "RedisService.CreateStreamGroup")
+
+	err := ts.RedisService.CreateStreamGroup(ctx, stream, group)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) ReadPendingStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	count int64,
+) ([]*model.StreamMessage, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.ReadPendingStreamGroup")
+
+	messages, err := ts.RedisService.ReadPendingStreamGroup(ctx, stream, group, consumer, from, count)
+
+	tracing.End(span, err)
+
+	return messages, err
+}
+
+func (ts *tracedRedisService) ClaimStreamGroup(
+	ctx context.Context,
+	stream, group, consumer, from string,
+	minIdle time.Duration,
+	count int64,
+) ([]*model.StreamMessage, string, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.ClaimStreamGroup")
+
+	messages, next, err := ts.RedisService.ClaimStreamGroup(ctx, stream, group, consumer, from, minIdle, count)
+
+	tracing.End(span, err)
+
+	return messages, next, err
+}
+
+func (ts *tracedRedisService) AckStreamMessage(ctx context.Context, stream, group, id string) error {
+	ctx, span := tracing.Start(ctx, "RedisService.AckStreamMessage")
+
+	err := ts.RedisService.AckStreamMessage(ctx, stream, group, id)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) SaveWebhookSubscription(
+	ctx context.Context,
+	subscription *model.WebhookSubscription,
+) error {
+	ctx, span := tracing.Start(ctx, "RedisService.SaveWebhookSubscription")
+
+	err := ts.RedisService.SaveWebhookSubscription(ctx, subscription)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetWebhookSubscriptions")
+
+	subscriptions, err := ts.RedisService.GetWebhookSubscriptions(ctx)
+
+	tracing.End(span, err)
+
+	return subscriptions, err
+}
+
+func (ts *tracedRedisService) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "RedisService.DeleteWebhookSubscription")
+
+	err := ts.RedisService.DeleteWebhookSubscription(ctx, id)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) AddWebhookDeadLetter(
+	ctx context.Context,
+	letter *model.WebhookDeadLetter, maxLength int64,
+) error {
+	ctx, span := tracing.Start(ctx, "RedisService.AddWebhookDeadLetter")
+
+	err := ts.RedisService.AddWebhookDeadLetter(ctx, letter, maxLength)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetWebhookDeadLetters(
+	ctx context.Context,
+	limit int64,
+) ([]*model.WebhookDeadLetter, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetWebhookDeadLetters")
+
+	letters, err := ts.RedisService.GetWebhookDeadLetters(ctx, limit)
+
+	tracing.End(span, err)
+
+	return letters, err
+}
+
+func (ts *tracedRedisService) MarkWebhookDelivered(
+	ctx context.Context,
+	deliveryID, subscriptionID uuid.UUID,
+	ttl time.Duration,
+) error {
+	ctx, span := tracing.Start(ctx, "RedisService.MarkWebhookDelivered")
+
+	err := ts.RedisService.MarkWebhookDelivered(ctx, deliveryID, subscriptionID, ttl)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRedisService) GetWebhookDelivered(ctx context.Context, deliveryID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "RedisService.GetWebhookDelivered")
+
+	subscriptionIDs, err := ts.RedisService.GetWebhookDelivered(ctx, deliveryID)
+
+	tracing.End(span, err)
+
+	return subscriptionIDs, err
+}
+
+func (ts *tracedRedisService) Ping(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "RedisService.Ping")
+
+	err := ts.RedisService.Ping(ctx)
+
+	tracing.End(span, err)
+
+	return err
+}
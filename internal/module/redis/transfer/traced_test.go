@@ -0,0 +1,77 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/redis/transfer/mock"
+	"scootinAboot/internal/tracing"
+)
+
+func TestTracedRedisService(t *testing.T) {
+	location := &redis.GeoLocation{Name: uuid.NewString()}
+	metadata := model.NewScooterMetadata(testCity, model.StatusAvailable, model.FullBattery)
+
+	tests := map[string]struct {
+		err        error
+		wantStatus codes.Code
+	}{
+		"creating scooter successfully": {
+			err:        nil,
+			wantStatus: codes.Unset,
+		},
+		"creating existing scooter is an expected answer": {
+			err:        ErrScooterAlreadyExists,
+			wantStatus: codes.Unset,
+		},
+		"creating scooter failed, because redis is unavailable": {
+			err:        fmt.Errorf("adding scooter: %w", domain.Unavailable(redis.ErrClosed)),
+			wantStatus: codes.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			recorder := tracetest.NewSpanRecorder()
+			tracing.Register(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			// The repository is called within the span, so the spans of its commands nest under it
+			var calledIn trace.SpanContext
+
+			mockRedisService := mock.NewMockRedisService(controller)
+			mockRedisService.EXPECT().CreateScooter(gomock.Any(), location, metadata).
+				DoAndReturn(func(ctx context.Context, _ *redis.GeoLocation, _ *model.ScooterMetadata) error {
+					calledIn = trace.SpanContextFromContext(ctx)
+
+					return tt.err
+				}).Times(1)
+
+			err := NewTracedRedisService(mockRedisService).CreateScooter(context.Background(), location, metadata)
+			require.Equal(t, tt.err, err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			require.Equal(t, "RedisService.CreateScooter", spans[0].Name())
+			require.Equal(t, spans[0].SpanContext().SpanID(), calledIn.SpanID())
+			require.Equal(t, tt.wantStatus, spans[0].Status().Code)
+			require.Contains(t, spans[0].Attributes(), tracing.KeyScooterUUID.String(location.Name))
+			require.Contains(t, spans[0].Attributes(), tracing.KeyCity.String(testCity))
+		})
+	}
+}
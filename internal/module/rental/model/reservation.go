@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation holds a scooter for the client until it expires.
+type Reservation struct {
+	ScooterUUID uuid.UUID
+	ClientUUID  uuid.UUID
+	ExpiresAt   time.Time
+}
+
+func NewReservation(scooterUUID, clientUUID uuid.UUID, expiresAt time.Time) *Reservation {
+	return &Reservation{
+		ScooterUUID: scooterUUID,
+		ClientUUID:  clientUUID,
+		ExpiresAt:   expiresAt,
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RideProgress is the state of the ride at the given moment, the fare is what the ride would cost if it ended then.
+// The last progress of the ride is marked as ended and carries the charged distance and fare.
+type RideProgress struct {
+	RentalID    uuid.UUID
+	ScooterUUID uuid.UUID
+	Longitude   float64
+	Latitude    float64
+	Elapsed     time.Duration
+	Distance    float64 // in meters
+	Fare        float64
+	Currency    string
+	Ended       bool
+	At          time.Time
+}
+
+func NewRideProgress(
+	rentalID, scooterUUID uuid.UUID,
+	longitude, latitude float64,
+	elapsed time.Duration,
+	distance float64,
+	at time.Time,
+) *RideProgress {
+	return &RideProgress{
+		RentalID:    rentalID,
+		ScooterUUID: scooterUUID,
+		Longitude:   longitude,
+		Latitude:    latitude,
+		Elapsed:     elapsed,
+		Distance:    distance,
+		At:          at,
+	}
+}
@@ -4,14 +4,12 @@ import "github.com/redis/go-redis/v9"
 
 type RentalScooter struct {
 	*redis.GeoLocation
-	City         string
-	Availability bool
+	City string
 }
 
-func NewRentalScooter(location *redis.GeoLocation, city string, availability bool) *RentalScooter {
+func NewRentalScooter(location *redis.GeoLocation, city string) *RentalScooter {
 	return &RentalScooter{
-		GeoLocation:  location,
-		City:         city,
-		Availability: availability,
+		GeoLocation: location,
+		City:        city,
 	}
 }
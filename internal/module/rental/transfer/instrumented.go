@@ -0,0 +1,42 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/metrics"
+	redismodel "scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/rental/model"
+)
+
+// instrumentedRentalService counts the outcomes of renting and freeing scooters.
+type instrumentedRentalService struct {
+	RentalService
+}
+
+func NewInstrumentedRentalService(service RentalService) *instrumentedRentalService {
+	return &instrumentedRentalService{RentalService: service}
+}
+
+func (is *instrumentedRentalService) Rent(
+	ctx context.Context,
+	clientUUID uuid.UUID, scooter *model.RentalScooter,
+) error {
+	err := is.RentalService.Rent(ctx, clientUUID, scooter)
+
+	metrics.RentalOutcomes.WithLabelValues(metrics.ActionRent, metrics.Outcome(err)).Inc()
+
+	return err
+}
+
+func (is *instrumentedRentalService) Free(
+	ctx context.Context,
+	clientUUID, scooterUUID uuid.UUID,
+) (*redismodel.Rental, error) {
+	rental, err := is.RentalService.Free(ctx, clientUUID, scooterUUID)
+
+	metrics.RentalOutcomes.WithLabelValues(metrics.ActionFree, metrics.Outcome(err)).Inc()
+
+	return rental, err
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/metrics"
+	redismodel "scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/rental/model"
+	"scootinAboot/internal/module/rental/transfer/mock"
+)
+
+func TestInstrumentedRentalService(t *testing.T) {
+	clientUUID, scooterUUID := uuid.New(), uuid.New()
+
+	scooter := &model.RentalScooter{}
+	rental := &redismodel.Rental{ID: uuid.New()}
+
+	tests := map[string]struct {
+		call        func(service RentalService) error
+		mock        func(mock *mock.MockRentalService)
+		wantAction  string
+		wantOutcome string
+	}{
+		"renting scooter successfully": {
+			call: func(service RentalService) error {
+				return service.Rent(context.Background(), clientUUID, scooter)
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, scooter).Return(nil).Times(1)
+			},
+			wantAction:  metrics.ActionRent,
+			wantOutcome: metrics.OutcomeSuccess,
+		},
+		"renting reserved scooter": {
+			call: func(service RentalService) error {
+				return service.Rent(context.Background(), clientUUID, scooter)
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, scooter).Return(ErrScooterReserved).Times(1)
+			},
+			wantAction:  metrics.ActionRent,
+			wantOutcome: ErrScooterReserved.Code,
+		},
+		"freeing scooter successfully": {
+			call: func(service RentalService) error {
+				got, err := service.Free(context.Background(), clientUUID, scooterUUID)
+				require.Equal(t, rental, got)
+
+				return err
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Free(gomock.Any(), clientUUID, scooterUUID).Return(rental, nil).Times(1)
+			},
+			wantAction:  metrics.ActionFree,
+			wantOutcome: metrics.OutcomeSuccess,
+		},
+		"freeing scooter while redis is unavailable": {
+			call: func(service RentalService) error {
+				_, err := service.Free(context.Background(), clientUUID, scooterUUID)
+
+				return err
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Free(gomock.Any(), clientUUID, scooterUUID).Return(nil, domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			wantAction:  metrics.ActionFree,
+			wantOutcome: "service_unavailable",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRentalService := mock.NewMockRentalService(controller)
+
+			tt.mock(mockRentalService)
+
+			outcomes := metrics.RentalOutcomes.WithLabelValues(tt.wantAction, tt.wantOutcome)
+			before := testutil.ToFloat64(outcomes)
+
+			_ = tt.call(NewInstrumentedRentalService(mockRentalService))
+
+			require.Equal(t, before+1, testutil.ToFloat64(outcomes))
+		})
+	}
+}
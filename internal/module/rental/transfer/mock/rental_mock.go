@@ -5,8 +5,10 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
-	model "scootinAboot/internal/module/rental/model"
+	model "scootinAboot/internal/module/redis/model"
+	model0 "scootinAboot/internal/module/rental/model"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -35,30 +37,89 @@ func (m *MockRentalService) EXPECT() *MockRentalServiceMockRecorder {
 	return m.recorder
 }
 
-// Free mocks base method.
-func (m *MockRentalService) Free(scooterUUID uuid.UUID) error {
+// CancelReservation mocks base method.
+func (m *MockRentalService) CancelReservation(ctx context.Context, clientUUID, scooterUUID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Free", scooterUUID)
+	ret := m.ctrl.Call(m, "CancelReservation", ctx, clientUUID, scooterUUID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CancelReservation indicates an expected call of CancelReservation.
+func (mr *MockRentalServiceMockRecorder) CancelReservation(ctx, clientUUID, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*MockRentalService)(nil).CancelReservation), ctx, clientUUID, scooterUUID)
+}
+
+// FollowRide mocks base method.
+func (m *MockRentalService) FollowRide(ctx context.Context, clientUUID, rentalID uuid.UUID) (<-chan *model0.RideProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowRide", ctx, clientUUID, rentalID)
+	ret0, _ := ret[0].(<-chan *model0.RideProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowRide indicates an expected call of FollowRide.
+func (mr *MockRentalServiceMockRecorder) FollowRide(ctx, clientUUID, rentalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowRide", reflect.TypeOf((*MockRentalService)(nil).FollowRide), ctx, clientUUID, rentalID)
+}
+
+// Free mocks base method.
+func (m *MockRentalService) Free(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (*model.Rental, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Free", ctx, clientUUID, scooterUUID)
+	ret0, _ := ret[0].(*model.Rental)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Free indicates an expected call of Free.
-func (mr *MockRentalServiceMockRecorder) Free(scooterUUID interface{}) *gomock.Call {
+func (mr *MockRentalServiceMockRecorder) Free(ctx, clientUUID, scooterUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Free", reflect.TypeOf((*MockRentalService)(nil).Free), scooterUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Free", reflect.TypeOf((*MockRentalService)(nil).Free), ctx, clientUUID, scooterUUID)
+}
+
+// ReleaseExpiredReservations mocks base method.
+func (m *MockRentalService) ReleaseExpiredReservations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockRentalServiceMockRecorder) ReleaseExpiredReservations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockRentalService)(nil).ReleaseExpiredReservations), ctx)
 }
 
 // Rent mocks base method.
-func (m *MockRentalService) Rent(scooter *model.RentalScooter) error {
+func (m *MockRentalService) Rent(ctx context.Context, clientUUID uuid.UUID, scooter *model0.RentalScooter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rent", scooter)
+	ret := m.ctrl.Call(m, "Rent", ctx, clientUUID, scooter)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Rent indicates an expected call of Rent.
-func (mr *MockRentalServiceMockRecorder) Rent(scooter interface{}) *gomock.Call {
+func (mr *MockRentalServiceMockRecorder) Rent(ctx, clientUUID, scooter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rent", reflect.TypeOf((*MockRentalService)(nil).Rent), ctx, clientUUID, scooter)
+}
+
+// Reserve mocks base method.
+func (m *MockRentalService) Reserve(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (*model0.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, clientUUID, scooterUUID)
+	ret0, _ := ret[0].(*model0.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockRentalServiceMockRecorder) Reserve(ctx, clientUUID, scooterUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rent", reflect.TypeOf((*MockRentalService)(nil).Rent), scooter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockRentalService)(nil).Reserve), ctx, clientUUID, scooterUUID)
 }
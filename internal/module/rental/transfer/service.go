@@ -1,74 +1,695 @@
 package transfer
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	eventsmodel "scootinAboot/internal/module/events/model"
+	events "scootinAboot/internal/module/events/transfer"
+	pricing "scootinAboot/internal/module/pricing/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
 	redis "scootinAboot/internal/module/redis/transfer"
 	"scootinAboot/internal/module/rental/model"
 	trackermodel "scootinAboot/internal/module/tracker/model"
 	tracker "scootinAboot/internal/module/tracker/transfer"
+	zone "scootinAboot/internal/module/zone/transfer"
+)
+
+const (
+	// finishAttempts bounds the attempts to store the end of the ride, the tracker is stopped by then
+	finishAttempts = 3
+	finishBackoff  = 100 * time.Millisecond
+
+	// expiredReservationsBatch bounds the reservations released by a single sweep, the next one takes the rest
+	expiredReservationsBatch = 100
+)
+
+var (
+	ErrScooterNotAvailable = domain.Conflict("scooter_not_available", "scooter is not available for rental")
+	ErrScooterLowBattery   = domain.Conflict("scooter_low_battery", "scooter's battery is too low for rental")
+	ErrScooterReserved     = domain.Conflict("scooter_reserved", "scooter is reserved by another client")
+	ErrReservationNotOwned = domain.Forbidden("reservation_not_owned", "reservation belongs to another client")
+	ErrRentalNotOwned      = domain.Forbidden("rental_not_owned", "rental belongs to another client")
+	ErrScooterCityMismatch = domain.Validation("scooter_city_mismatch", "scooter is registered in another city")
 )
 
 //go:generate mockgen -source=service.go -destination=mock/rental_mock.go -package=mock
 type RentalService interface {
-	Rent(scooter *model.RentalScooter) error
-	Free(scooterUUID uuid.UUID) error
+	Rent(ctx context.Context, clientUUID uuid.UUID, scooter *model.RentalScooter) error
+	Free(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (*redismodel.Rental, error)
+	Reserve(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (*model.Reservation, error)
+	CancelReservation(ctx context.Context, clientUUID, scooterUUID uuid.UUID) error
+	ReleaseExpiredReservations(ctx context.Context) error
+	FollowRide(ctx context.Context, clientUUID, rentalID uuid.UUID) (<-chan *model.RideProgress, error)
 }
 
 type rentalService struct {
-	logger          *log.Logger
-	redisService    redis.RedisService
-	trackingService tracker.TrackerService
+	logger              *slog.Logger
+	redisService        redis.RedisService
+	trackingService     tracker.TrackerService
+	pricingService      pricing.PricingService
+	zoneService         zone.ZoneService
+	bus                 events.EventBus
+	lowBatteryThreshold float64
+	reservationTTL      time.Duration
 }
 
-func NewRentalService(logger *log.Logger, rService redis.RedisService, tracker tracker.TrackerService) *rentalService {
+func NewRentalService(
+	logger *slog.Logger,
+	rService redis.RedisService,
+	tracker tracker.TrackerService,
+	pricing pricing.PricingService,
+	zones zone.ZoneService,
+	bus events.EventBus,
+	lowBatteryThreshold float64,
+	reservationTTL time.Duration,
+) *rentalService {
 	return &rentalService{
-		logger:          logger,
-		redisService:    rService,
-		trackingService: tracker,
+		logger:              logger,
+		redisService:        rService,
+		trackingService:     tracker,
+		pricingService:      pricing,
+		zoneService:         zones,
+		bus:                 bus,
+		lowBatteryThreshold: lowBatteryThreshold,
+		reservationTTL:      reservationTTL,
 	}
 }
 
-func (rs *rentalService) Rent(scooter *model.RentalScooter) error {
+func (rs *rentalService) Rent(ctx context.Context, clientUUID uuid.UUID, scooter *model.RentalScooter) error {
 	scooterUUID, err := uuid.Parse(scooter.GeoLocation.Name)
 	if err != nil {
 		return fmt.Errorf("parsing scooter's uuid: %w", err)
 	}
 
-	err = rs.redisService.UpdateScooterAvailability(scooterUUID, false)
+	reserved, err := rs.reservedBy(ctx, clientUUID, scooterUUID)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := rs.redisService.GetScooterMetadata(ctx, scooterUUID)
+	if err != nil {
+		return fmt.Errorf("getting scooter metadata: %w", err)
+	}
+
+	// The city is taken from the fleet, it prices the ride and scopes it to the city's operators
+	if scooter.City != metadata.City {
+		return fmt.Errorf("scooter is in %s, not in %s: %w", metadata.City, scooter.City, ErrScooterCityMismatch)
+	}
+
+	// Only available scooters can be rented, every other status means the scooter is taken or out of the fleet,
+	// except the one reserved by the renting client
+	if !metadata.Status.IsAvailable() && !(reserved && metadata.Status == redismodel.StatusReserved) {
+		return fmt.Errorf("scooter is %s: %w", metadata.Status, ErrScooterNotAvailable)
+	}
+
+	// The status is swapped only from the one just checked, the scooter could have been taken in the meantime
+	expected := []redismodel.ScooterStatus{redismodel.StatusAvailable}
+	if reserved {
+		expected = append(expected, redismodel.StatusReserved)
+	}
+
+	if metadata.Battery < rs.lowBatteryThreshold {
+		_, err = rs.redisService.SwapScooterStatus(ctx, scooterUUID, redismodel.StatusLowBattery, expected...)
+		if errors.Is(err, redis.ErrScooterStatusChanged) {
+			return fmt.Errorf("%w: %w", ErrScooterNotAvailable, err)
+		}
+
+		if err != nil {
+			return fmt.Errorf("updating scooter status: %w", err)
+		}
+
+		rs.publishStatus(ctx, scooterUUID, redismodel.StatusLowBattery)
+
+		if reserved {
+			if _, err = rs.redisService.ReleaseReservation(ctx, scooterUUID, clientUUID); err != nil {
+				return fmt.Errorf("releasing reservation: %w", err)
+			}
+		}
+
+		return fmt.Errorf("scooter has %.2f%% of battery: %w", metadata.Battery, ErrScooterLowBattery)
+	}
+
+	// Swapping the status takes the scooter, no one else can rent or reserve it from now on
+	previous, err := rs.redisService.SwapScooterStatus(ctx, scooterUUID, redismodel.StatusRented, expected...)
+	if errors.Is(err, redis.ErrScooterStatusChanged) {
+		return fmt.Errorf("%w: %w", ErrScooterNotAvailable, err)
+	}
+
 	if err != nil {
-		return fmt.Errorf("updating scooter availability: %w", err)
+		return fmt.Errorf("updating scooter status: %w", err)
 	}
 
-	rs.logger.Printf(
-		"Scooter with UUID: %s started his journey from %f, %f.",
+	rental := redismodel.NewRental(uuid.New(), clientUUID, scooterUUID, metadata.City, time.Now())
+
+	trackingScooter := trackermodel.NewTrackerScooter(
+		scooter.GeoLocation,
+		metadata.City,
+		metadata.Battery,
+		rental.ID,
+		clientUUID,
+	)
+
+	if err = rs.trackingService.TrackScooter(ctx, scooterUUID, trackingScooter); err != nil {
+		rs.restoreStatus(ctx, scooterUUID, previous)
+
+		return fmt.Errorf("tracking scooter: %w", err)
+	}
+
+	if err = rs.redisService.StartRental(ctx, rental); err != nil {
+		rs.untrack(ctx, scooterUUID)
+		rs.restoreStatus(ctx, scooterUUID, previous)
+
+		return fmt.Errorf("starting rental: %w", err)
+	}
+
+	rs.publishStatus(ctx, scooterUUID, redismodel.StatusRented)
+
+	// The scooter is rented already, a reservation left behind only expires later
+	if reserved {
+		if _, err = rs.redisService.ReleaseReservation(ctx, scooterUUID, clientUUID); err != nil {
+			rs.logger.Warn(
+				"releasing reservation of rented scooter failed",
+				logging.KeyScooterUUID, scooterUUID,
+				logging.KeyClientUUID, clientUUID,
+				logging.KeyError, err,
+			)
+		}
+	}
+
+	rs.logger.Info(
+		"ride started",
+		logging.KeyScooterUUID, scooterUUID,
+		logging.KeyClientUUID, clientUUID,
+		logging.KeyRentalID, rental.ID,
+		"longitude", scooter.Longitude,
+		"latitude", scooter.Latitude,
+	)
+
+	rs.publish(ctx, eventsmodel.NewScooterRented(
+		rental.ID,
+		clientUUID,
 		scooterUUID,
+		rental.City,
 		scooter.Longitude,
 		scooter.Latitude,
+		metadata.Battery,
+		rental.StartedAt,
+	))
+
+	return nil
+}
+
+// Free ends the ride and charges it, the returned rental holds the ride's distance, fare and events. Only the client
+// riding the scooter can end the ride, and not outside of the operating area or in a no-parking zone. Once the tracker
+// let the scooter go the ride is finished with retries, a rental that still can't be stored is tracked again.
+func (rs *rentalService) Free(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (*redismodel.Rental, error) {
+	rental, err := rs.redisService.GetActiveRental(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active rental: %w", err)
+	}
+
+	if rental.ClientUUID != clientUUID {
+		return nil, ErrRentalNotOwned
+	}
+
+	fleetScooter, err := rs.redisService.GetScooter(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter: %w", err)
+	}
+
+	err = rs.zoneService.CheckParking(fleetScooter.City, fleetScooter.Scooter.Longitude, fleetScooter.Scooter.Latitude)
+	if err != nil {
+		return nil, fmt.Errorf("checking parking spot: %w", err)
+	}
+
+	summary, err := rs.trackingService.FreeScooter(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("freeing scooter: %w", err)
+	}
+
+	// The tracker no longer follows the ride, from here on the ride is finished even when the client went away
+	ctx = context.WithoutCancel(ctx)
+
+	for _, warning := range summary.Warnings {
+		rs.logger.Warn(
+			"ride was tracked incompletely",
+			logging.KeyScooterUUID, scooterUUID,
+			logging.KeyRentalID, rental.ID,
+			"warning", warning,
+		)
+	}
+
+	rental.EndedAt = time.Now()
+	rental.Distance = summary.Distance
+	rental.Events = newRentalEvents(summary.Events)
+
+	fare, err := rs.pricingService.CalculateFare(rental.City, rental.Duration(), rental.Distance)
+	if err != nil {
+		// The ride is finished uncharged rather than lost, it can be charged from its history
+		rs.logger.Error(
+			"calculating fare failed, ride is finished uncharged",
+			logging.KeyScooterUUID, scooterUUID,
+			logging.KeyRentalID, rental.ID,
+			logging.KeyError, err,
+		)
+	} else {
+		rental.Fare = fare.Total
+		rental.Currency = fare.Currency
+	}
+
+	err = retry(ctx, func(ctx context.Context) error {
+		return rs.redisService.FinishRental(ctx, rental)
+	})
+	if err != nil {
+		// The ride goes on where the tracker left it, so the client can free the scooter again
+		rs.resumeRide(ctx, rental, fleetScooter, summary)
+
+		return nil, fmt.Errorf("finishing rental: %w", err)
+	}
+
+	status := rs.freedStatus(ctx, scooterUUID, fleetScooter.Battery)
+
+	err = retry(ctx, func(ctx context.Context) error {
+		_, err := rs.redisService.SwapScooterStatus(ctx, scooterUUID, status, redismodel.StatusRented)
+		if errors.Is(err, redis.ErrScooterStatusChanged) {
+			return nil
+		}
+
+		return err
+	})
+	if err != nil {
+		rs.logger.Error(
+			"resetting status of freed scooter failed, scooter stays rented",
+			logging.KeyScooterUUID, scooterUUID,
+			logging.KeyRentalID, rental.ID,
+			logging.KeyError, err,
+		)
+	} else {
+		rs.publishStatus(ctx, scooterUUID, status)
+	}
+
+	rs.logger.Info(
+		"ride ended",
+		logging.KeyScooterUUID, scooterUUID,
+		logging.KeyClientUUID, rental.ClientUUID,
+		logging.KeyRentalID, rental.ID,
+		"distance", rental.Distance,
+		"fare", rental.Fare,
+		"currency", rental.Currency,
 	)
 
-	trackingScooter := trackermodel.NewTrackerScooter(scooter.GeoLocation, scooter.City)
+	rs.publish(ctx, eventsmodel.NewScooterFreed(
+		rental.ID,
+		rental.ClientUUID,
+		scooterUUID,
+		rental.City,
+		rental.Duration(),
+		rental.Distance,
+		rental.Fare,
+		rental.Currency,
+		rental.EndedAt,
+	))
 
-	if err = rs.trackingService.TrackScooter(scooterUUID, trackingScooter); err != nil {
-		return fmt.Errorf("tracking scooter: %w", err)
+	return rental, nil
+}
+
+func (rs *rentalService) Reserve(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (*model.Reservation, error) {
+	metadata, err := rs.redisService.GetScooterMetadata(ctx, scooterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scooter metadata: %w", err)
+	}
+
+	if metadata.Status == redismodel.StatusReserved {
+		return nil, ErrScooterReserved
+	}
+
+	if !metadata.Status.IsAvailable() {
+		return nil, fmt.Errorf("scooter is %s: %w", metadata.Status, ErrScooterNotAvailable)
+	}
+
+	if metadata.Battery < rs.lowBatteryThreshold {
+		return nil, fmt.Errorf("scooter has %.2f%% of battery: %w", metadata.Battery, ErrScooterLowBattery)
+	}
+
+	expiresAt := time.Now().Add(rs.reservationTTL)
+
+	err = rs.redisService.ReserveScooter(ctx, scooterUUID, clientUUID, rs.reservationTTL)
+	if errors.Is(err, redis.ErrScooterReserved) {
+		return nil, ErrScooterReserved
+	}
+
+	if errors.Is(err, redis.ErrScooterNotAvailable) {
+		return nil, fmt.Errorf("%w: %w", ErrScooterNotAvailable, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reserving scooter: %w", err)
+	}
+
+	rs.publishStatus(ctx, scooterUUID, redismodel.StatusReserved)
+
+	rs.logger.Info(
+		"scooter reserved",
+		logging.KeyScooterUUID, scooterUUID,
+		logging.KeyClientUUID, clientUUID,
+		"expires_at", expiresAt.Format(time.RFC3339),
+	)
+
+	return model.NewReservation(scooterUUID, clientUUID, expiresAt), nil
+}
+
+func (rs *rentalService) CancelReservation(ctx context.Context, clientUUID, scooterUUID uuid.UUID) error {
+	// The holder is checked by the release itself, the reservation could expire and be taken by another client
+	// between a check and the release
+	released, err := rs.redisService.ReleaseReservation(ctx, scooterUUID, clientUUID)
+	if errors.Is(err, redis.ErrReservationNotOwned) {
+		return ErrReservationNotOwned
+	}
+
+	if err != nil {
+		return fmt.Errorf("releasing reservation: %w", err)
+	}
+
+	if released {
+		rs.publishStatus(ctx, scooterUUID, redismodel.StatusAvailable)
 	}
 
 	return nil
 }
 
-func (rs *rentalService) Free(scooterUUID uuid.UUID) error {
-	if err := rs.trackingService.FreeScooter(scooterUUID); err != nil {
-		return fmt.Errorf("freeing scooter: %w", err)
+// FollowRide streams the progress of the client's ride, starting with its current state. The channel is closed after
+// the progress marked as ended or once ctx is done, a ride that already ended gets its final progress only.
+func (rs *rentalService) FollowRide(
+	ctx context.Context,
+	clientUUID, rentalID uuid.UUID,
+) (<-chan *model.RideProgress, error) {
+	rental, err := rs.redisService.GetRental(ctx, rentalID)
+	if err != nil {
+		return nil, fmt.Errorf("getting rental: %w", err)
+	}
+
+	if rental.ClientUUID != clientUUID {
+		return nil, ErrRentalNotOwned
+	}
+
+	followCtx, cancel := context.WithCancel(ctx)
+
+	// Subscribing before reading the scooter, so the end of the ride can't slip in between
+	scooterEvents, err := rs.redisService.SubscribeScooterEvents(followCtx, rental.City)
+	if err != nil {
+		cancel()
+
+		return nil, fmt.Errorf("subscribing to scooter events: %w", err)
+	}
+
+	fleetScooter, err := rs.redisService.GetScooter(ctx, rental.ScooterUUID)
+	if err != nil {
+		cancel()
+
+		return nil, fmt.Errorf("getting scooter: %w", err)
 	}
 
-	rs.logger.Printf("Scooter with UUID: %s ended his journey.", scooterUUID)
+	progress := make(chan *model.RideProgress, 1)
 
-	if err := rs.redisService.UpdateScooterAvailability(scooterUUID, true); err != nil {
-		return fmt.Errorf("updating scooter availability: %w", err)
+	current := rs.rideProgress(ctx, rental, fleetScooter.Scooter.Longitude, fleetScooter.Scooter.Latitude, 0)
+	if ended := rs.endedRide(ctx, rental.ID, current); ended != nil {
+		current = ended
+	}
+
+	progress <- current
+
+	if current.Ended {
+		cancel()
+		close(progress)
+
+		return progress, nil
+	}
+
+	go func() {
+		defer close(progress)
+		defer cancel()
+
+		for event := range scooterEvents {
+			if event.ScooterUUID != rental.ScooterUUID {
+				continue
+			}
+
+			if event.Status == redismodel.StatusRented {
+				current = rs.rideProgress(ctx, rental, event.Longitude, event.Latitude, current.Distance)
+			} else {
+				ended := rs.endedRide(ctx, rental.ID, current)
+				if ended == nil {
+					// The scooter was freed, yet the ride is charged only after that, its last event follows
+					continue
+				}
+
+				current = ended
+			}
+
+			select {
+			case progress <- current:
+			case <-followCtx.Done():
+				return
+			}
+
+			if current.Ended {
+				return
+			}
+		}
+	}()
+
+	return progress, nil
+}
+
+// rideProgress describes the ride as it is now, the distance is taken from the tracker and falls back to the last
+// known one when the tracker no longer follows the scooter.
+func (rs *rentalService) rideProgress(
+	ctx context.Context,
+	rental *redismodel.Rental,
+	longitude, latitude float64,
+	lastDistance float64,
+) *model.RideProgress {
+	distance, err := rs.trackingService.RideDistance(ctx, rental.ScooterUUID)
+	if err != nil {
+		distance = lastDistance
+	}
+
+	now := time.Now()
+
+	progress := model.NewRideProgress(
+		rental.ID,
+		rental.ScooterUUID,
+		longitude,
+		latitude,
+		now.Sub(rental.StartedAt),
+		distance,
+		now,
+	)
+
+	fare, err := rs.pricingService.CalculateFare(rental.City, progress.Elapsed, distance)
+	if err != nil {
+		rs.logger.Warn(
+			"calculating running fare failed",
+			logging.KeyScooterUUID, rental.ScooterUUID,
+			logging.KeyClientUUID, rental.ClientUUID,
+			logging.KeyRentalID, rental.ID,
+			logging.KeyError, err,
+		)
+
+		return progress
+	}
+
+	progress.Fare = fare.Total
+	progress.Currency = fare.Currency
+
+	return progress
+}
+
+// endedRide returns the final progress of the ride once it was charged, nil while it goes on.
+func (rs *rentalService) endedRide(
+	ctx context.Context,
+	rentalID uuid.UUID, last *model.RideProgress,
+) *model.RideProgress {
+	rental, err := rs.redisService.GetRental(ctx, rentalID)
+	if err != nil {
+		rs.logger.Warn("getting rental failed", logging.KeyRentalID, rentalID, logging.KeyError, err)
+
+		return nil
+	}
+
+	if rental.EndedAt.IsZero() {
+		return nil
+	}
+
+	progress := model.NewRideProgress(
+		rental.ID,
+		rental.ScooterUUID,
+		last.Longitude,
+		last.Latitude,
+		rental.Duration(),
+		rental.Distance,
+		rental.EndedAt,
+	)
+	progress.Fare = rental.Fare
+	progress.Currency = rental.Currency
+	progress.Ended = true
+
+	return progress
+}
+
+// freedStatus tells the status of the freed scooter, scooter drained during the ride is taken out of the rentable pool
+// until it is charged. The battery stored by the tracker is preferred, the one read before freeing is the fallback.
+func (rs *rentalService) freedStatus(
+	ctx context.Context,
+	scooterUUID uuid.UUID, battery float64,
+) redismodel.ScooterStatus {
+	metadata, err := rs.redisService.GetScooterMetadata(ctx, scooterUUID)
+	if err == nil {
+		battery = metadata.Battery
+	}
+
+	if battery < rs.lowBatteryThreshold {
+		return redismodel.StatusLowBattery
+	}
+
+	return redismodel.StatusAvailable
+}
+
+// resumeRide tracks the ride again with what was observed until it was freed, for the rental that couldn't be
+// finished.
+func (rs *rentalService) resumeRide(
+	ctx context.Context,
+	rental *redismodel.Rental, fleetScooter *redismodel.FleetScooter, summary *trackermodel.RideSummary,
+) {
+	trackingScooter := trackermodel.NewTrackerScooter(
+		fleetScooter.Scooter,
+		rental.City,
+		fleetScooter.Battery,
+		rental.ID,
+		rental.ClientUUID,
+	)
+	trackingScooter.Summary = summary
+
+	if err := rs.trackingService.TrackScooter(ctx, rental.ScooterUUID, trackingScooter); err != nil {
+		rs.logger.Error(
+			"resuming ride that couldn't be finished failed",
+			logging.KeyScooterUUID, rental.ScooterUUID,
+			logging.KeyRentalID, rental.ID,
+			logging.KeyError, err,
+		)
+	}
+}
+
+// restoreStatus gives the scooter back the status it had before the failed rent, unless it was changed since. It runs
+// after the request could have been cancelled, so it doesn't inherit its cancellation.
+func (rs *rentalService) restoreStatus(ctx context.Context, scooterUUID uuid.UUID, previous redismodel.ScooterStatus) {
+	ctx = context.WithoutCancel(ctx)
+
+	_, err := rs.redisService.SwapScooterStatus(ctx, scooterUUID, previous, redismodel.StatusRented)
+	if err != nil {
+		rs.logger.Error(
+			"restoring status of scooter after failed rent failed",
+			logging.KeyScooterUUID, scooterUUID,
+			"status", previous,
+			logging.KeyError, err,
+		)
+	}
+}
+
+// untrack stops tracking the ride of the failed rent, nothing of the ride is kept.
+func (rs *rentalService) untrack(ctx context.Context, scooterUUID uuid.UUID) {
+	if _, err := rs.trackingService.FreeScooter(context.WithoutCancel(ctx), scooterUUID); err != nil {
+		rs.logger.Error(
+			"stopping tracking of scooter after failed rent failed",
+			logging.KeyScooterUUID, scooterUUID,
+			logging.KeyError, err,
+		)
+	}
+}
+
+// publish puts the event on the bus, the change it describes is already stored so a failure is only logged.
+func (rs *rentalService) publish(ctx context.Context, event eventsmodel.Event) {
+	if err := rs.bus.Publish(ctx, event); err != nil {
+		rs.logger.Error("publishing event failed", "event_type", event.Type(), logging.KeyError, err)
+	}
+}
+
+// ReleaseExpiredReservations puts the scooters whose reservation expired back to available, the server runs it
+// periodically, so the scooters don't wait for anyone reading them.
+func (rs *rentalService) ReleaseExpiredReservations(ctx context.Context) error {
+	released, err := rs.redisService.ReleaseExpiredReservations(ctx, time.Now(), expiredReservationsBatch)
+
+	for _, scooterUUID := range released {
+		rs.publishStatus(ctx, scooterUUID, redismodel.StatusAvailable)
+
+		rs.logger.Info("reservation expired", logging.KeyScooterUUID, scooterUUID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("releasing expired reservations: %w", err)
 	}
 
 	return nil
 }
+
+func (rs *rentalService) publishStatus(ctx context.Context, scooterUUID uuid.UUID, status redismodel.ScooterStatus) {
+	rs.publish(ctx, eventsmodel.NewScooterStatusChanged(scooterUUID, string(status), time.Now()))
+}
+
+// reservedBy reports whether the scooter is reserved by the client, reservation held by anyone else is an error.
+func (rs *rentalService) reservedBy(ctx context.Context, clientUUID, scooterUUID uuid.UUID) (bool, error) {
+	holderUUID, err := rs.redisService.GetReservation(ctx, scooterUUID)
+	if errors.Is(err, redismodel.ErrReservationNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("getting reservation: %w", err)
+	}
+
+	if holderUUID != clientUUID {
+		return false, ErrScooterReserved
+	}
+
+	return true, nil
+}
+
+// retry runs the step until it succeeds or runs out of attempts, the backoff doubles after every failure.
+func retry(ctx context.Context, step func(ctx context.Context) error) error {
+	backoff := finishBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := step(ctx)
+		if err == nil || attempt == finishAttempts {
+			return err
+		}
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+	}
+}
+
+func newRentalEvents(rideEvents []*trackermodel.RideEvent) []redismodel.RentalEvent {
+	rentalEvents := make([]redismodel.RentalEvent, 0, len(rideEvents))
+
+	for _, event := range rideEvents {
+		rentalEvents = append(rentalEvents, redismodel.RentalEvent{
+			Type:       string(event.Type),
+			Zone:       event.Zone,
+			Longitude:  event.Longitude,
+			Latitude:   event.Latitude,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+
+	return rentalEvents
+}
@@ -3,34 +3,57 @@
 package transfer
 
 import (
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 
+	eventsmodel "scootinAboot/internal/module/events/model"
+	events "scootinAboot/internal/module/events/transfer"
+	pricingmodel "scootinAboot/internal/module/pricing/model"
+	pricingmock "scootinAboot/internal/module/pricing/transfer/mock"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservice "scootinAboot/internal/module/redis/transfer"
 	redisservicemock "scootinAboot/internal/module/redis/transfer/mock"
 	"scootinAboot/internal/module/rental/model"
 	trackermodel "scootinAboot/internal/module/tracker/model"
+	tracker "scootinAboot/internal/module/tracker/transfer"
 	trackermock "scootinAboot/internal/module/tracker/transfer/mock"
+	zone "scootinAboot/internal/module/zone/transfer"
+	zonemock "scootinAboot/internal/module/zone/transfer/mock"
+	"scootinAboot/internal/testutil"
 )
 
 const (
 	testLongitude = 70
 	testLatitude  = 60
 	testCity      = "Montreal"
+
+	testLowBatteryThreshold = 15.0
+	testReservationTTL      = 10 * time.Minute
 )
 
 func TestRent(t *testing.T) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	anotherClientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
 	scooter := model.NewRentalScooter(
 		&redis.GeoLocation{
 			Name:      firstScooterUUID.String(),
@@ -38,21 +61,24 @@ func TestRent(t *testing.T) {
 			Latitude:  testLatitude,
 		},
 		testCity,
-		true)
+	)
+
+	availableMetadata := redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 90)
+	reservedMetadata := redismodel.NewScooterMetadata(testCity, redismodel.StatusReserved, 90)
 
 	wrongUUIDScooter := model.NewRentalScooter(
 		&redis.GeoLocation{
 			Name: "dd-dd-dd",
 		},
 		testCity,
-		false,
 	)
 
 	tests := map[string]struct {
-		logger                     *log.Logger
+		logger                     *slog.Logger
 		scooter                    *model.RentalScooter
 		mockRedisServiceHandler    func(mock *redisservicemock.MockRedisService)
 		mockTrackingServiceHandler func(mock *trackermock.MockTrackerService)
+		wantEvents                 []eventsmodel.EventType
 		wantErr                    bool
 	}{
 		"successfully rent scooter": {
@@ -62,20 +88,71 @@ func TestRent(t *testing.T) {
 				scooterUUID, innerErr := uuid.Parse(scooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().UpdateScooterAvailability(scooterUUID, false).Return(nil).Times(1)
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(availableMetadata, nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusRented, redismodel.StatusAvailable).
+					Return(redismodel.StatusAvailable, nil).Times(1)
+				mock.EXPECT().StartRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			},
 			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
 				trackerScooter := &trackermodel.TrackerScooter{
 					GeoLocation: scooter.GeoLocation,
 					City:        scooter.City,
+					Battery:     availableMetadata.Battery,
+					ClientUUID:  clientUUID,
 				}
 
 				scooterUUID, innerErr := uuid.Parse(trackerScooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().TrackScooter(scooterUUID, trackerScooter).Return(nil).Times(1)
+				mock.EXPECT().TrackScooter(gomock.Any(), scooterUUID, trackedRide{trackerScooter}).Return(nil).Times(1)
 			},
-			wantErr: false,
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged, eventsmodel.TypeScooterRented},
+			wantErr:    false,
+		},
+		"successfully rent scooter reserved by the client": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(clientUUID, nil).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(reservedMetadata, nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(
+					gomock.Any(),
+					scooterUUID, redismodel.StatusRented, redismodel.StatusAvailable, redismodel.StatusReserved,
+				).Return(redismodel.StatusReserved, nil).Times(1)
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).Return(false, nil).Times(1)
+				mock.EXPECT().StartRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				trackerScooter := &trackermodel.TrackerScooter{
+					GeoLocation: scooter.GeoLocation,
+					City:        scooter.City,
+					Battery:     reservedMetadata.Battery,
+					ClientUUID:  clientUUID,
+				}
+
+				scooterUUID, innerErr := uuid.Parse(trackerScooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().TrackScooter(gomock.Any(), scooterUUID, trackedRide{trackerScooter}).Return(nil).Times(1)
+			},
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged, eventsmodel.TypeScooterRented},
+			wantErr:    false,
+		},
+		"rent scooter failing because scooter is reserved by another client": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(anotherClientUUID, nil).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			wantErr:                    true,
 		},
 		"rent scooter failing because chosen scooter has incorrect UUID": {
 			logger:                     logger,
@@ -91,30 +168,132 @@ func TestRent(t *testing.T) {
 				scooterUUID, innerErr := uuid.Parse(scooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().UpdateScooterAvailability(scooterUUID, false).Return(redis.ErrClosed)
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(availableMetadata, nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusRented, redismodel.StatusAvailable).
+					Return(redismodel.ScooterStatus(""), redis.ErrClosed)
 			},
 			mockTrackingServiceHandler: nil,
 			wantErr:                    true,
 		},
-		"rent scooter failing because tracking service threw an error": {
+		"rent scooter failing because scooter is not available": {
 			logger:  logger,
 			scooter: scooter,
 			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
 				scooterUUID, innerErr := uuid.Parse(scooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().UpdateScooterAvailability(scooterUUID, false).Return(nil).Times(1)
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusMaintenance, 90), nil).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			wantErr:                    true,
+		},
+		"rent scooter failing because scooter is registered in another city": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata("Toronto", redismodel.StatusAvailable, 90), nil).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			wantErr:                    true,
+		},
+		"rent scooter failing because scooter's battery is too low": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 10), nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusLowBattery, redismodel.StatusAvailable).
+					Return(redismodel.StatusAvailable, nil).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			wantEvents:                 []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged},
+			wantErr:                    true,
+		},
+		"rent scooter failing because scooter was taken after it was checked": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(availableMetadata, nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusRented, redismodel.StatusAvailable).
+					Return(redismodel.StatusReserved, redisservice.ErrScooterStatusChanged).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			wantErr:                    true,
+		},
+		"rent scooter failing because tracking service threw an error restores scooter's status": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(uuid.Nil, redismodel.ErrReservationNotFound).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(availableMetadata, nil).Times(1)
+				gomock.InOrder(
+					mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusRented, redismodel.StatusAvailable).
+						Return(redismodel.StatusAvailable, nil),
+					mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusAvailable, redismodel.StatusRented).
+						Return(redismodel.StatusRented, nil),
+				)
 			},
 			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
 				trackerScooter := &trackermodel.TrackerScooter{
 					GeoLocation: scooter.GeoLocation,
 					City:        scooter.City,
+					Battery:     availableMetadata.Battery,
+					ClientUUID:  clientUUID,
 				}
 
 				scooterUUID, innerErr := uuid.Parse(trackerScooter.Name)
 				require.NoError(t, innerErr)
 
-				mock.EXPECT().TrackScooter(scooterUUID, trackerScooter).Return(errors.New("")).Times(1)
+				mock.EXPECT().TrackScooter(gomock.Any(), scooterUUID, trackedRide{trackerScooter}).Return(errors.New("")).Times(1)
+			},
+			wantErr: true,
+		},
+		"rent scooter failing because rental couldn't be started stops tracking and restores reservation": {
+			logger:  logger,
+			scooter: scooter,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				mock.EXPECT().GetReservation(gomock.Any(), scooterUUID).Return(clientUUID, nil).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).Return(reservedMetadata, nil).Times(1)
+				gomock.InOrder(
+					mock.EXPECT().SwapScooterStatus(
+						gomock.Any(),
+						scooterUUID, redismodel.StatusRented, redismodel.StatusAvailable, redismodel.StatusReserved,
+					).Return(redismodel.StatusReserved, nil),
+					mock.EXPECT().StartRental(gomock.Any(), gomock.Any()).Return(redis.ErrClosed),
+					mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusReserved, redismodel.StatusRented).
+						Return(redismodel.StatusRented, nil),
+				)
+			},
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				scooterUUID, innerErr := uuid.Parse(scooter.Name)
+				require.NoError(t, innerErr)
+
+				gomock.InOrder(
+					mock.EXPECT().TrackScooter(gomock.Any(), scooterUUID, gomock.Any()).Return(nil),
+					mock.EXPECT().FreeScooter(gomock.Any(), scooterUUID).Return(trackermodel.NewRideSummary(0, nil), nil),
+				)
 			},
 			wantErr: true,
 		},
@@ -126,6 +305,8 @@ func TestRent(t *testing.T) {
 
 			mockRedisService := redisservicemock.NewMockRedisService(controller)
 			mockTrackingService := trackermock.NewMockTrackerService(controller)
+			mockPricingService := pricingmock.NewMockPricingService(controller)
+			mockZoneService := zonemock.NewMockZoneService(controller)
 
 			if tt.mockRedisServiceHandler != nil {
 				tt.mockRedisServiceHandler(mockRedisService)
@@ -135,53 +316,257 @@ func TestRent(t *testing.T) {
 				tt.mockTrackingServiceHandler(mockTrackingService)
 			}
 
-			rs := NewRentalService(logger, mockRedisService, mockTrackingService)
-			if err = rs.Rent(tt.scooter); (err != nil) != tt.wantErr {
+			bus := events.NewMemoryBus(logger)
+
+			rs := NewRentalService(
+				logger,
+				mockRedisService,
+				mockTrackingService,
+				mockPricingService,
+				mockZoneService,
+				bus,
+				testLowBatteryThreshold,
+				testReservationTTL,
+			)
+			if err = rs.Rent(context.Background(), clientUUID, tt.scooter); (err != nil) != tt.wantErr {
 				t.Errorf("Rent() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			require.Equal(t, tt.wantEvents, publishedTypes(bus))
 		})
 	}
 }
 
 func TestFree(t *testing.T) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	rentalID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	startedAt := time.Now().Add(-5 * time.Minute)
+
+	summary := trackermodel.NewRideSummary(1200, []*trackermodel.RideEvent{
+		trackermodel.NewRideEvent(trackermodel.EventEnteredSlowZone, "Downtown", testLongitude, testLatitude, startedAt),
+	})
+
+	fleetScooter := redismodel.NewFleetScooter(
+		&redis.GeoLocation{Name: firstScooterUUID.String()},
+		&redis.GeoPos{Longitude: testLongitude, Latitude: testLatitude},
+		redismodel.NewScooterMetadata(testCity, redismodel.StatusRented, 80),
+	)
+
+	allowParking := func(mock *zonemock.MockZoneService) {
+		mock.EXPECT().CheckParking(testCity, float64(testLongitude), float64(testLatitude)).Return(nil).Times(1)
+	}
+
+	fare := &pricingmodel.Fare{
+		UnlockFee:    1,
+		TimeCost:     1.75,
+		DistanceCost: 0.06,
+		Total:        2.81,
+		Currency:     "CAD",
+	}
+
 	tests := map[string]struct {
-		logger                     *log.Logger
+		logger                     *slog.Logger
 		mockRedisServiceHandler    func(mock *redisservicemock.MockRedisService)
+		mockZoneServiceHandler     func(mock *zonemock.MockZoneService)
 		mockTrackingServiceHandler func(mock *trackermock.MockTrackerService)
+		mockPricingServiceHandler  func(mock *pricingmock.MockPricingService)
+		wantEvents                 []eventsmodel.EventType
+		wantUncharged              bool
 		wantErr                    bool
 	}{
 		"successfully freed scooter": {
 			logger: logger,
 			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
-				mock.EXPECT().UpdateScooterAvailability(firstScooterUUID, true).Return(nil).Times(1)
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+				mock.EXPECT().FinishRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusRented, 80), nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(
+					gomock.Any(),
+					firstScooterUUID, redismodel.StatusAvailable, redismodel.StatusRented,
+				).Return(redismodel.StatusRented, nil).Times(1)
+			},
+			mockZoneServiceHandler: allowParking,
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				mock.EXPECT().FreeScooter(gomock.Any(), firstScooterUUID).Return(summary, nil).Times(1)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), summary.Distance).Return(fare, nil).Times(1)
+			},
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged, eventsmodel.TypeScooterFreed},
+			wantErr:    false,
+		},
+		"successfully freed scooter drained below low battery threshold": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+				mock.EXPECT().FinishRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusRented, 5), nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(
+					gomock.Any(),
+					firstScooterUUID, redismodel.StatusLowBattery, redismodel.StatusRented,
+				).Return(redismodel.StatusRented, nil).Times(1)
+			},
+			mockZoneServiceHandler: allowParking,
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				mock.EXPECT().FreeScooter(gomock.Any(), firstScooterUUID).Return(summary, nil).Times(1)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), summary.Distance).Return(fare, nil).Times(1)
+			},
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged, eventsmodel.TypeScooterFreed},
+			wantErr:    false,
+		},
+		"successfully freed scooter although its status couldn't be reset": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+				mock.EXPECT().FinishRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), firstScooterUUID).Return(nil, redis.ErrClosed).Times(1)
+				mock.EXPECT().SwapScooterStatus(
+					gomock.Any(),
+					firstScooterUUID, redismodel.StatusAvailable, redismodel.StatusRented,
+				).Return(redismodel.ScooterStatus(""), redis.ErrClosed).Times(finishAttempts)
 			},
+			mockZoneServiceHandler: allowParking,
 			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
-				mock.EXPECT().FreeScooter(firstScooterUUID).Return(nil).Times(1)
+				mock.EXPECT().FreeScooter(gomock.Any(), firstScooterUUID).Return(summary, nil).Times(1)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), summary.Distance).Return(fare, nil).Times(1)
 			},
-			wantErr: false,
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterFreed},
+			wantErr:    false,
 		},
-		"freeing scooter failed because redis service threw an error when updating availability": {
+		"freeing scooter failed because rental couldn't be finished resumes the ride": {
 			logger: logger,
 			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
-				mock.EXPECT().UpdateScooterAvailability(firstScooterUUID, true).Return(redis.ErrClosed).Times(1)
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+				mock.EXPECT().FinishRental(gomock.Any(), gomock.Any()).Return(redis.ErrClosed).Times(finishAttempts)
 			},
+			mockZoneServiceHandler: allowParking,
 			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
-				mock.EXPECT().FreeScooter(firstScooterUUID).Return(nil).Times(1)
+				resumed := trackermodel.NewTrackerScooter(fleetScooter.Scooter, testCity, 80, rentalID, clientUUID)
+				resumed.Summary = summary
+
+				gomock.InOrder(
+					mock.EXPECT().FreeScooter(gomock.Any(), firstScooterUUID).Return(summary, nil),
+					mock.EXPECT().TrackScooter(gomock.Any(), firstScooterUUID, resumed).Return(nil),
+				)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), summary.Distance).Return(fare, nil).Times(1)
 			},
 			wantErr: true,
 		},
+		"freeing scooter failed because redis service threw an error when getting active rental": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).Return(nil, redismodel.ErrRentalNotFound).Times(1)
+			},
+			mockZoneServiceHandler:     nil,
+			mockTrackingServiceHandler: nil,
+			mockPricingServiceHandler:  nil,
+			wantErr:                    true,
+		},
+		"freeing scooter failed because the ride belongs to another client": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, uuid.New(), firstScooterUUID, testCity, startedAt), nil).Times(1)
+			},
+			mockZoneServiceHandler:     nil,
+			mockTrackingServiceHandler: nil,
+			mockPricingServiceHandler:  nil,
+			wantErr:                    true,
+		},
+		"successfully freed scooter uncharged, because pricing service threw an error": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+				mock.EXPECT().FinishRental(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusRented, 80), nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(
+					gomock.Any(),
+					firstScooterUUID, redismodel.StatusAvailable, redismodel.StatusRented,
+				).Return(redismodel.StatusRented, nil).Times(1)
+			},
+			mockZoneServiceHandler: allowParking,
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				mock.EXPECT().FreeScooter(gomock.Any(), firstScooterUUID).Return(summary, nil).Times(1)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), summary.Distance).
+					Return(nil, errors.New("")).Times(1)
+			},
+			wantEvents:    []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged, eventsmodel.TypeScooterFreed},
+			wantUncharged: true,
+			wantErr:       false,
+		},
+		"freeing scooter failed because scooter is parked in a no-parking zone": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+			},
+			mockZoneServiceHandler: func(mock *zonemock.MockZoneService) {
+				mock.EXPECT().CheckParking(testCity, float64(testLongitude), float64(testLatitude)).
+					Return(zone.ErrNoParkingZone).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			mockPricingServiceHandler:  nil,
+			wantErr:                    true,
+		},
+		"freeing scooter failed because scooter is outside of the operating area": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+			},
+			mockZoneServiceHandler: func(mock *zonemock.MockZoneService) {
+				mock.EXPECT().CheckParking(testCity, float64(testLongitude), float64(testLatitude)).
+					Return(zone.ErrOutsideOperatingArea).Times(1)
+			},
+			mockTrackingServiceHandler: nil,
+			mockPricingServiceHandler:  nil,
+			wantErr:                    true,
+		},
 		"freeing scooter failed because tracking service threw an error when freeing scooter": {
-			logger:                  logger,
-			mockRedisServiceHandler: nil,
+			logger: logger,
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetActiveRental(gomock.Any(), firstScooterUUID).
+					Return(redismodel.NewRental(rentalID, clientUUID, firstScooterUUID, testCity, startedAt), nil).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), firstScooterUUID).Return(fleetScooter, nil).Times(1)
+			},
+			mockZoneServiceHandler: allowParking,
 			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
-				mock.EXPECT().FreeScooter(firstScooterUUID).Return(errors.New("")).Times(1)
+				mock.EXPECT().FreeScooter(gomock.Any(), firstScooterUUID).Return(nil, errors.New("")).Times(1)
 			},
-			wantErr: true,
+			mockPricingServiceHandler: nil,
+			wantErr:                   true,
 		},
 	}
 	for name, tt := range tests {
@@ -191,19 +576,537 @@ func TestFree(t *testing.T) {
 
 			mockRedisService := redisservicemock.NewMockRedisService(controller)
 			mockTrackingService := trackermock.NewMockTrackerService(controller)
+			mockPricingService := pricingmock.NewMockPricingService(controller)
+			mockZoneService := zonemock.NewMockZoneService(controller)
 
 			if tt.mockRedisServiceHandler != nil {
 				tt.mockRedisServiceHandler(mockRedisService)
 			}
 
+			if tt.mockZoneServiceHandler != nil {
+				tt.mockZoneServiceHandler(mockZoneService)
+			}
+
 			if tt.mockTrackingServiceHandler != nil {
 				tt.mockTrackingServiceHandler(mockTrackingService)
 			}
 
-			rs := NewRentalService(logger, mockRedisService, mockTrackingService)
-			if err = rs.Free(firstScooterUUID); (err != nil) != tt.wantErr {
+			if tt.mockPricingServiceHandler != nil {
+				tt.mockPricingServiceHandler(mockPricingService)
+			}
+
+			bus := events.NewMemoryBus(logger)
+
+			rs := NewRentalService(
+				logger,
+				mockRedisService,
+				mockTrackingService,
+				mockPricingService,
+				mockZoneService,
+				bus,
+				testLowBatteryThreshold,
+				testReservationTTL,
+			)
+
+			rental, err := rs.Free(context.Background(), clientUUID, firstScooterUUID)
+
+			require.Equal(t, tt.wantEvents, publishedTypes(bus))
+
+			if (err != nil) != tt.wantErr {
 				t.Errorf("Free() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil {
+				wantFare := fare
+				if tt.wantUncharged {
+					wantFare = &pricingmodel.Fare{}
+				}
+
+				require.Equal(t, rentalID, rental.ID)
+				require.Equal(t, summary.Distance, rental.Distance)
+				require.Equal(t, wantFare.Total, rental.Fare)
+				require.Equal(t, wantFare.Currency, rental.Currency)
+				require.False(t, rental.EndedAt.Before(startedAt))
+				require.Equal(t, []redismodel.RentalEvent{{
+					Type:       string(trackermodel.EventEnteredSlowZone),
+					Zone:       "Downtown",
+					Longitude:  testLongitude,
+					Latitude:   testLatitude,
+					OccurredAt: startedAt,
+				}}, rental.Events)
+			}
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *redisservicemock.MockRedisService)
+		wantErr                 error
+	}{
+		"successfully reserved scooter": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 90), nil).Times(1)
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"reserving scooter failed because scooter is already reserved": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusReserved, 90), nil).Times(1)
+			},
+			wantErr: ErrScooterReserved,
+		},
+		"reserving scooter failed because another client reserved it in the meantime": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 90), nil).Times(1)
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(redisservice.ErrScooterReserved).Times(1)
+			},
+			wantErr: ErrScooterReserved,
+		},
+		"reserving scooter failed because scooter is not available": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusRented, 90), nil).Times(1)
+			},
+			wantErr: ErrScooterNotAvailable,
+		},
+		"reserving scooter failed because scooter was rented in the meantime": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 90), nil).Times(1)
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(redisservice.ErrScooterNotAvailable).Times(1)
+			},
+			wantErr: ErrScooterNotAvailable,
+		},
+		"reserving scooter failed because scooter's battery is too low": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 10), nil).Times(1)
+			},
+			wantErr: ErrScooterLowBattery,
+		},
+		"reserving scooter failed because redis service threw an error": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, 90), nil).Times(1)
+				mock.EXPECT().ReserveScooter(gomock.Any(), scooterUUID, clientUUID, testReservationTTL).
+					Return(redis.ErrClosed).Times(1)
+			},
+			wantErr: redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+			mockTrackingService := trackermock.NewMockTrackerService(controller)
+			mockPricingService := pricingmock.NewMockPricingService(controller)
+			mockZoneService := zonemock.NewMockZoneService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			rs := NewRentalService(
+				logger,
+				mockRedisService,
+				mockTrackingService,
+				mockPricingService,
+				mockZoneService,
+				events.NewMemoryBus(logger),
+				testLowBatteryThreshold,
+				testReservationTTL,
+			)
+
+			reservation, err := rs.Reserve(context.Background(), clientUUID, scooterUUID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Reserve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil {
+				require.Equal(t, scooterUUID, reservation.ScooterUUID)
+				require.Equal(t, clientUUID, reservation.ClientUUID)
+				require.WithinDuration(t, time.Now().Add(testReservationTTL), reservation.ExpiresAt, time.Second)
 			}
 		})
 	}
 }
+
+func TestCancelReservation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *redisservicemock.MockRedisService)
+		wantErr                 error
+	}{
+		"successfully cancelled reservation": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).Return(true, nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"successfully cancelled reservation of scooter rented in the meantime": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).Return(false, nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		"cancelling reservation failed because it belongs to another client": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(false, redisservice.ErrReservationNotOwned).Times(1)
+			},
+			wantErr: ErrReservationNotOwned,
+		},
+		"cancelling reservation failed because it does not exist": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseReservation(gomock.Any(), scooterUUID, clientUUID).
+					Return(false, redismodel.ErrReservationNotFound).Times(1)
+			},
+			wantErr: redismodel.ErrReservationNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+			mockTrackingService := trackermock.NewMockTrackerService(controller)
+			mockPricingService := pricingmock.NewMockPricingService(controller)
+			mockZoneService := zonemock.NewMockZoneService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			rs := NewRentalService(
+				logger,
+				mockRedisService,
+				mockTrackingService,
+				mockPricingService,
+				mockZoneService,
+				events.NewMemoryBus(logger),
+				testLowBatteryThreshold,
+				testReservationTTL,
+			)
+
+			if err := rs.CancelReservation(context.Background(), clientUUID, scooterUUID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CancelReservation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *redisservicemock.MockRedisService)
+		wantEvents              []eventsmodel.EventType
+		wantErr                 error
+	}{
+		"releasing expired reservations publishes released scooters": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseExpiredReservations(gomock.Any(), gomock.Any(), int64(expiredReservationsBatch)).
+					Return([]uuid.UUID{scooterUUID}, nil).Times(1)
+			},
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged},
+			wantErr:    nil,
+		},
+		"releasing expired reservations publishes nothing, when none expired": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseExpiredReservations(gomock.Any(), gomock.Any(), int64(expiredReservationsBatch)).
+					Return(nil, nil).Times(1)
+			},
+			wantEvents: nil,
+			wantErr:    nil,
+		},
+		"releasing expired reservations failed midway publishes those released before": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().ReleaseExpiredReservations(gomock.Any(), gomock.Any(), int64(expiredReservationsBatch)).
+					Return([]uuid.UUID{scooterUUID}, redis.ErrClosed).Times(1)
+			},
+			wantEvents: []eventsmodel.EventType{eventsmodel.TypeScooterStatusChanged},
+			wantErr:    redis.ErrClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			bus := events.NewMemoryBus(logger)
+
+			rs := NewRentalService(
+				logger,
+				mockRedisService,
+				trackermock.NewMockTrackerService(controller),
+				pricingmock.NewMockPricingService(controller),
+				zonemock.NewMockZoneService(controller),
+				bus,
+				testLowBatteryThreshold,
+				testReservationTTL,
+			)
+
+			err := rs.ReleaseExpiredReservations(context.Background())
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantEvents, publishedTypes(bus))
+		})
+	}
+}
+
+func TestFollowRide(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	anotherClientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	rentalID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	startedAt := time.Now().Add(-5 * time.Minute)
+
+	activeRental := redismodel.NewRental(rentalID, clientUUID, scooterUUID, testCity, startedAt)
+
+	endedRental := redismodel.NewRental(rentalID, clientUUID, scooterUUID, testCity, startedAt)
+	endedRental.EndedAt = startedAt.Add(5 * time.Minute)
+	endedRental.Distance = 1200
+	endedRental.Fare = 2.81
+	endedRental.Currency = "CAD"
+
+	fleetScooter := redismodel.NewFleetScooter(
+		&redis.GeoLocation{Name: scooterUUID.String()},
+		&redis.GeoPos{Longitude: testLongitude, Latitude: testLatitude},
+		redismodel.NewScooterMetadata(testCity, redismodel.StatusRented, 80),
+	)
+
+	scooterEvent := func(scooterUUID uuid.UUID, status redismodel.ScooterStatus) *redismodel.ScooterEvent {
+		return redismodel.NewScooterEvent(
+			redismodel.ScooterStatusChanged,
+			scooterUUID,
+			testCity,
+			testLongitude+0.01,
+			testLatitude,
+			status,
+			redismodel.FullBattery,
+		)
+	}
+
+	fare := &pricingmodel.Fare{Total: 1.85, Currency: "CAD"}
+
+	type progress struct {
+		distance float64
+		fare     float64
+		ended    bool
+	}
+
+	tests := map[string]struct {
+		mockRedisServiceHandler    func(mock *redisservicemock.MockRedisService)
+		mockTrackingServiceHandler func(mock *trackermock.MockTrackerService)
+		mockPricingServiceHandler  func(mock *pricingmock.MockPricingService)
+		want                       []progress
+		wantErr                    error
+	}{
+		"successfully followed ride until it was charged": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				calls := 0
+
+				// The scooter is freed before the ride is charged, the rental ends on the last event only
+				mock.EXPECT().GetRental(gomock.Any(), rentalID).
+					DoAndReturn(func(context.Context, uuid.UUID) (*redismodel.Rental, error) {
+						calls++
+						if calls < 4 {
+							return activeRental, nil
+						}
+
+						return endedRental, nil
+					}).Times(4)
+				mock.EXPECT().SubscribeScooterEvents(gomock.Any(), testCity).
+					DoAndReturn(func(ctx context.Context, city string) (<-chan *redismodel.ScooterEvent, error) {
+						return testutil.ScooterEventFeed(ctx, []*redismodel.ScooterEvent{
+							scooterEvent(uuid.New(), redismodel.StatusRented),
+							scooterEvent(scooterUUID, redismodel.StatusRented),
+							scooterEvent(scooterUUID, redismodel.StatusAvailable),
+							scooterEvent(scooterUUID, redismodel.StatusAvailable),
+						}), nil
+					}).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(fleetScooter, nil).Times(1)
+			},
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				mock.EXPECT().RideDistance(gomock.Any(), scooterUUID).Return(800.0, nil).Times(1)
+				mock.EXPECT().RideDistance(gomock.Any(), scooterUUID).Return(1000.0, nil).Times(1)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), 800.0).Return(fare, nil).Times(1)
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), 1000.0).Return(nil, errors.New("no tariff")).Times(1)
+			},
+			want: []progress{
+				{distance: 800, fare: 1.85},
+				// The running fare is left out when it can't be calculated
+				{distance: 1000},
+				{distance: 1200, fare: 2.81, ended: true},
+			},
+		},
+		"successfully followed ride that already ended": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetRental(gomock.Any(), rentalID).Return(endedRental, nil).Times(2)
+				mock.EXPECT().SubscribeScooterEvents(gomock.Any(), testCity).
+					DoAndReturn(func(ctx context.Context, city string) (<-chan *redismodel.ScooterEvent, error) {
+						return testutil.ScooterEventFeed(ctx, nil), nil
+					}).Times(1)
+				mock.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(fleetScooter, nil).Times(1)
+			},
+			mockTrackingServiceHandler: func(mock *trackermock.MockTrackerService) {
+				mock.EXPECT().RideDistance(gomock.Any(), scooterUUID).Return(0.0, tracker.ErrScooterNotTracked).Times(1)
+			},
+			mockPricingServiceHandler: func(mock *pricingmock.MockPricingService) {
+				mock.EXPECT().CalculateFare(testCity, gomock.Any(), 0.0).Return(fare, nil).Times(1)
+			},
+			want: []progress{
+				{distance: 1200, fare: 2.81, ended: true},
+			},
+		},
+		"following ride failed because it belongs to another client": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetRental(gomock.Any(), rentalID).
+					Return(redismodel.NewRental(rentalID, anotherClientUUID, scooterUUID, testCity, startedAt), nil).Times(1)
+			},
+			wantErr: ErrRentalNotOwned,
+		},
+		"following ride failed because it does not exist": {
+			mockRedisServiceHandler: func(mock *redisservicemock.MockRedisService) {
+				mock.EXPECT().GetRental(gomock.Any(), rentalID).Return(nil, redismodel.ErrRentalNotFound).Times(1)
+			},
+			wantErr: redismodel.ErrRentalNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := redisservicemock.NewMockRedisService(controller)
+			mockTrackingService := trackermock.NewMockTrackerService(controller)
+			mockPricingService := pricingmock.NewMockPricingService(controller)
+			mockZoneService := zonemock.NewMockZoneService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			if tt.mockTrackingServiceHandler != nil {
+				tt.mockTrackingServiceHandler(mockTrackingService)
+			}
+
+			if tt.mockPricingServiceHandler != nil {
+				tt.mockPricingServiceHandler(mockPricingService)
+			}
+
+			rs := NewRentalService(
+				logger,
+				mockRedisService,
+				mockTrackingService,
+				mockPricingService,
+				mockZoneService,
+				events.NewMemoryBus(logger),
+				testLowBatteryThreshold,
+				testReservationTTL,
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rideProgress, err := rs.FollowRide(ctx, clientUUID, rentalID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FollowRide() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			got := make([]progress, 0, len(tt.want))
+
+			timeout := time.After(time.Second)
+
+			for {
+				select {
+				case current, open := <-rideProgress:
+					if !open {
+						require.Equal(t, tt.want, got)
+
+						return
+					}
+
+					require.Equal(t, rentalID, current.RentalID)
+					got = append(got, progress{distance: current.Distance, fare: current.Fare, ended: current.Ended})
+				case <-timeout:
+					t.Fatalf("ride progress not closed, got %v", got)
+				}
+			}
+		})
+	}
+}
+
+// trackedRide matches the scooter handed to the tracker, the rental ID is generated by Rent, so it only has to be set.
+type trackedRide struct {
+	want *trackermodel.TrackerScooter
+}
+
+func (tr trackedRide) Matches(x interface{}) bool {
+	got, ok := x.(*trackermodel.TrackerScooter)
+	if !ok || got.RentalID == uuid.Nil {
+		return false
+	}
+
+	want := *tr.want
+	want.RentalID = got.RentalID
+
+	return reflect.DeepEqual(&want, got)
+}
+
+func (tr trackedRide) String() string {
+	return fmt.Sprintf("is tracker scooter %+v with a rental ID", tr.want)
+}
+
+// publishedTypes lists the types of the events on the bus, nil when nothing was published.
+func publishedTypes(bus interface{ Published() []eventsmodel.Event }) []eventsmodel.EventType {
+	var types []eventsmodel.EventType
+
+	for _, event := range bus.Published() {
+		types = append(types, event.Type())
+	}
+
+	return types
+}
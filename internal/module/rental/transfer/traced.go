@@ -0,0 +1,122 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	redismodel "scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/rental/model"
+	"scootinAboot/internal/tracing"
+)
+
+// tracedRentalService spans every operation of the service with the scooter, city and client it concerns. The span
+// of FollowRide covers setting up the ride's progress only, not following it.
+type tracedRentalService struct {
+	RentalService
+}
+
+func NewTracedRentalService(service RentalService) *tracedRentalService {
+	return &tracedRentalService{RentalService: service}
+}
+
+func (ts *tracedRentalService) Rent(ctx context.Context, clientUUID uuid.UUID, scooter *model.RentalScooter) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RentalService.Rent",
+		tracing.KeyScooterUUID.String(scooter.Name),
+		tracing.KeyCity.String(scooter.City),
+		tracing.KeyClientUUID.String(clientUUID.String()),
+	)
+
+	err := ts.RentalService.Rent(ctx, clientUUID, scooter)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRentalService) Free(
+	ctx context.Context,
+	clientUUID, scooterUUID uuid.UUID,
+) (*redismodel.Rental, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RentalService.Free",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+		tracing.KeyClientUUID.String(clientUUID.String()),
+	)
+
+	rental, err := ts.RentalService.Free(ctx, clientUUID, scooterUUID)
+	if err == nil {
+		span.SetAttributes(
+			tracing.KeyCity.String(rental.City),
+			tracing.KeyRentalID.String(rental.ID.String()),
+		)
+	}
+
+	tracing.End(span, err)
+
+	return rental, err
+}
+
+func (ts *tracedRentalService) Reserve(
+	ctx context.Context,
+	clientUUID, scooterUUID uuid.UUID,
+) (*model.Reservation, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RentalService.Reserve",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+		tracing.KeyClientUUID.String(clientUUID.String()),
+	)
+
+	reservation, err := ts.RentalService.Reserve(ctx, clientUUID, scooterUUID)
+
+	tracing.End(span, err)
+
+	return reservation, err
+}
+
+func (ts *tracedRentalService) CancelReservation(ctx context.Context, clientUUID, scooterUUID uuid.UUID) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"RentalService.CancelReservation",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+		tracing.KeyClientUUID.String(clientUUID.String()),
+	)
+
+	err := ts.RentalService.CancelReservation(ctx, clientUUID, scooterUUID)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRentalService) ReleaseExpiredReservations(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "RentalService.ReleaseExpiredReservations")
+
+	err := ts.RentalService.ReleaseExpiredReservations(ctx)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedRentalService) FollowRide(
+	ctx context.Context,
+	clientUUID, rentalID uuid.UUID,
+) (<-chan *model.RideProgress, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"RentalService.FollowRide",
+		tracing.KeyClientUUID.String(clientUUID.String()),
+		tracing.KeyRentalID.String(rentalID.String()),
+	)
+
+	progress, err := ts.RentalService.FollowRide(ctx, clientUUID, rentalID)
+
+	tracing.End(span, err)
+
+	return progress, err
+}
@@ -0,0 +1,120 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"scootinAboot/internal/domain"
+	redismodel "scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/module/rental/model"
+	"scootinAboot/internal/module/rental/transfer/mock"
+	"scootinAboot/internal/tracing"
+)
+
+func TestTracedRentalService(t *testing.T) {
+	clientUUID, scooterUUID := uuid.New(), uuid.New()
+
+	scooter := model.NewRentalScooter(&redis.GeoLocation{Name: scooterUUID.String()}, testCity)
+	rental := redismodel.NewRental(uuid.New(), clientUUID, scooterUUID, testCity, time.Now().UTC())
+
+	tests := map[string]struct {
+		call       func(service RentalService) error
+		mock       func(mock *mock.MockRentalService)
+		wantName   string
+		wantStatus codes.Code
+		wantAttrs  []attribute.KeyValue
+	}{
+		"renting scooter successfully": {
+			call: func(service RentalService) error {
+				return service.Rent(context.Background(), clientUUID, scooter)
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, scooter).Return(nil).Times(1)
+			},
+			wantName:   "RentalService.Rent",
+			wantStatus: codes.Unset,
+			wantAttrs: []attribute.KeyValue{
+				tracing.KeyScooterUUID.String(scooterUUID.String()),
+				tracing.KeyCity.String(testCity),
+				tracing.KeyClientUUID.String(clientUUID.String()),
+			},
+		},
+		"renting scooter failed, because redis is unavailable": {
+			call: func(service RentalService) error {
+				return service.Rent(context.Background(), clientUUID, scooter)
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, scooter).
+					Return(domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			wantName:   "RentalService.Rent",
+			wantStatus: codes.Error,
+			wantAttrs:  []attribute.KeyValue{tracing.KeyErrorCode.String("service_unavailable")},
+		},
+		"freeing scooter successfully": {
+			call: func(service RentalService) error {
+				_, err := service.Free(context.Background(), clientUUID, scooterUUID)
+
+				return err
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Free(gomock.Any(), clientUUID, scooterUUID).Return(rental, nil).Times(1)
+			},
+			wantName:   "RentalService.Free",
+			wantStatus: codes.Unset,
+			wantAttrs: []attribute.KeyValue{
+				tracing.KeyScooterUUID.String(scooterUUID.String()),
+				tracing.KeyCity.String(testCity),
+				tracing.KeyRentalID.String(rental.ID.String()),
+			},
+		},
+		"reserving reserved scooter": {
+			call: func(service RentalService) error {
+				_, err := service.Reserve(context.Background(), clientUUID, scooterUUID)
+
+				return err
+			},
+			mock: func(mock *mock.MockRentalService) {
+				mock.EXPECT().Reserve(gomock.Any(), clientUUID, scooterUUID).Return(nil, ErrScooterReserved).Times(1)
+			},
+			wantName:   "RentalService.Reserve",
+			wantStatus: codes.Unset,
+			wantAttrs:  []attribute.KeyValue{tracing.KeyErrorCode.String(ErrScooterReserved.Code)},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			recorder := tracetest.NewSpanRecorder()
+			tracing.Register(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			mockRentalService := mock.NewMockRentalService(controller)
+			tt.mock(mockRentalService)
+
+			_ = tt.call(NewTracedRentalService(mockRentalService))
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			require.Equal(t, tt.wantName, spans[0].Name())
+			require.Equal(t, tt.wantStatus, spans[0].Status().Code)
+
+			for _, attr := range tt.wantAttrs {
+				require.Contains(t, spans[0].Attributes(), attr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type RideEventType string
+
+const (
+	EventEnteredSlowZone   RideEventType = "entered_slow_zone"
+	EventLeftOperatingArea RideEventType = "left_operating_area"
+)
+
+// RideEvent is something noteworthy that happened during the ride, zone is empty for events not tied to one.
+type RideEvent struct {
+	Type       RideEventType
+	Zone       string
+	Longitude  float64
+	Latitude   float64
+	OccurredAt time.Time
+}
+
+func NewRideEvent(eventType RideEventType, zone string, longitude, latitude float64, occurredAt time.Time) *RideEvent {
+	return &RideEvent{
+		Type:       eventType,
+		Zone:       zone,
+		Longitude:  longitude,
+		Latitude:   latitude,
+		OccurredAt: occurredAt,
+	}
+}
+
+// RideSummary describes what the tracker observed during the ride, warnings tell what it failed to record.
+type RideSummary struct {
+	Distance float64 // in meters
+	Events   []*RideEvent
+	Warnings []string
+}
+
+func NewRideSummary(distance float64, events []*RideEvent) *RideSummary {
+	return &RideSummary{
+		Distance: distance,
+		Events:   events,
+	}
+}
@@ -1,15 +1,31 @@
 package model
 
-import "github.com/redis/go-redis/v9"
+import (
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
 
+// TrackerScooter is the scooter on a ride, the rental and client identify the ride in the tracker's logs.
 type TrackerScooter struct {
 	*redis.GeoLocation
-	City string
+	City       string
+	Battery    float64
+	RentalID   uuid.UUID
+	ClientUUID uuid.UUID
+	Summary    *RideSummary // observed before the ride was resumed, nil for a new ride
 }
 
-func NewTrackerScooter(location *redis.GeoLocation, city string) *TrackerScooter {
+func NewTrackerScooter(
+	location *redis.GeoLocation,
+	city string,
+	battery float64,
+	rentalID, clientUUID uuid.UUID,
+) *TrackerScooter {
 	return &TrackerScooter{
 		GeoLocation: location,
 		City:        city,
+		Battery:     battery,
+		RentalID:    rentalID,
+		ClientUUID:  clientUUID,
 	}
 }
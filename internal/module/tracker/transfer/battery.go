@@ -0,0 +1,18 @@
+package transfer
+
+const (
+	metersInKilometer = 1000.0
+
+	emptyBattery = 0.0
+)
+
+// drainBattery returns the battery level left after travelling given distance, the drain is linear with the distance
+// and the battery never goes below empty.
+func drainBattery(battery, distanceInMeters, drainPerKilometer float64) float64 {
+	drained := battery - distanceInMeters/metersInKilometer*drainPerKilometer
+	if drained < emptyBattery {
+		return emptyBattery
+	}
+
+	return drained
+}
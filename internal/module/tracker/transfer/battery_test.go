@@ -0,0 +1,56 @@
+//go:build unit
+
+package transfer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDrainBattery(t *testing.T) {
+	tests := map[string]struct {
+		battery           float64
+		distanceInMeters  float64
+		drainPerKilometer float64
+		want              float64
+	}{
+		"battery is not drained when scooter stands still": {
+			battery:           80,
+			distanceInMeters:  0,
+			drainPerKilometer: 1.5,
+			want:              80,
+		},
+		"battery is drained proportionally to the distance": {
+			battery:           80,
+			distanceInMeters:  2000,
+			drainPerKilometer: 1.5,
+			want:              77,
+		},
+		"battery is drained for distance shorter than a kilometer": {
+			battery:           50,
+			distanceInMeters:  500,
+			drainPerKilometer: 2,
+			want:              49,
+		},
+		"battery never goes below empty": {
+			battery:           1,
+			distanceInMeters:  10000,
+			drainPerKilometer: 1.5,
+			want:              0,
+		},
+		"empty battery stays empty": {
+			battery:           0,
+			distanceInMeters:  100,
+			drainPerKilometer: 1.5,
+			want:              0,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drainBattery(tt.battery, tt.distanceInMeters, tt.drainPerKilometer)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("drainBattery() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 	model "scootinAboot/internal/module/tracker/model"
 
@@ -35,30 +36,100 @@ func (m *MockTrackerService) EXPECT() *MockTrackerServiceMockRecorder {
 	return m.recorder
 }
 
+// ActiveRides mocks base method.
+func (m *MockTrackerService) ActiveRides() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveRides")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// ActiveRides indicates an expected call of ActiveRides.
+func (mr *MockTrackerServiceMockRecorder) ActiveRides() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveRides", reflect.TypeOf((*MockTrackerService)(nil).ActiveRides))
+}
+
 // FreeScooter mocks base method.
-func (m *MockTrackerService) FreeScooter(scooterUUID uuid.UUID) error {
+func (m *MockTrackerService) FreeScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.RideSummary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FreeScooter", scooterUUID)
+	ret := m.ctrl.Call(m, "FreeScooter", ctx, scooterUUID)
+	ret0, _ := ret[0].(*model.RideSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreeScooter indicates an expected call of FreeScooter.
+func (mr *MockTrackerServiceMockRecorder) FreeScooter(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeScooter", reflect.TypeOf((*MockTrackerService)(nil).FreeScooter), ctx, scooterUUID)
+}
+
+// ReportBattery mocks base method.
+func (m *MockTrackerService) ReportBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportBattery", ctx, scooterUUID, battery)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// FreeScooter indicates an expected call of FreeScooter.
-func (mr *MockTrackerServiceMockRecorder) FreeScooter(scooterUUID interface{}) *gomock.Call {
+// ReportBattery indicates an expected call of ReportBattery.
+func (mr *MockTrackerServiceMockRecorder) ReportBattery(ctx, scooterUUID, battery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportBattery", reflect.TypeOf((*MockTrackerService)(nil).ReportBattery), ctx, scooterUUID, battery)
+}
+
+// RideDistance mocks base method.
+func (m *MockTrackerService) RideDistance(ctx context.Context, scooterUUID uuid.UUID) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RideDistance", ctx, scooterUUID)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RideDistance indicates an expected call of RideDistance.
+func (mr *MockTrackerServiceMockRecorder) RideDistance(ctx, scooterUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RideDistance", reflect.TypeOf((*MockTrackerService)(nil).RideDistance), ctx, scooterUUID)
+}
+
+// Stop mocks base method.
+func (m *MockTrackerService) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockTrackerServiceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTrackerService)(nil).Stop))
+}
+
+// Stopping mocks base method.
+func (m *MockTrackerService) Stopping() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stopping")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Stopping indicates an expected call of Stopping.
+func (mr *MockTrackerServiceMockRecorder) Stopping() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeScooter", reflect.TypeOf((*MockTrackerService)(nil).FreeScooter), scooterUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stopping", reflect.TypeOf((*MockTrackerService)(nil).Stopping))
 }
 
 // TrackScooter mocks base method.
-func (m *MockTrackerService) TrackScooter(scooterUUID uuid.UUID, scooter *model.TrackerScooter) error {
+func (m *MockTrackerService) TrackScooter(ctx context.Context, scooterUUID uuid.UUID, scooter *model.TrackerScooter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TrackScooter", scooterUUID, scooter)
+	ret := m.ctrl.Call(m, "TrackScooter", ctx, scooterUUID, scooter)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TrackScooter indicates an expected call of TrackScooter.
-func (mr *MockTrackerServiceMockRecorder) TrackScooter(scooterUUID, scooter interface{}) *gomock.Call {
+func (mr *MockTrackerServiceMockRecorder) TrackScooter(ctx, scooterUUID, scooter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackScooter", reflect.TypeOf((*MockTrackerService)(nil).TrackScooter), scooterUUID, scooter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackScooter", reflect.TypeOf((*MockTrackerService)(nil).TrackScooter), ctx, scooterUUID, scooter)
 }
@@ -1,17 +1,32 @@
 package transfer
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/geo"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/metrics"
+	eventsmodel "scootinAboot/internal/module/events/model"
+	events "scootinAboot/internal/module/events/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
 	commonRedis "scootinAboot/internal/module/redis/transfer"
 	"scootinAboot/internal/module/tracker/model"
+	zonemodel "scootinAboot/internal/module/zone/model"
+	zone "scootinAboot/internal/module/zone/transfer"
+	"scootinAboot/internal/tracing"
 )
 
 const (
@@ -28,35 +43,77 @@ const (
 var (
 	myMux = sync.Mutex{}
 
-	ErrRentAlreadyRentedScooter = errors.New("this scooter is already rented, choose another one")
-	ErrNoScooterToFree          = errors.New("can't free scooter that have not been rented")
+	ErrRentAlreadyRentedScooter = domain.Conflict(
+		"scooter_already_rented",
+		"this scooter is already rented, choose another one",
+	)
+	ErrNoScooterToFree     = domain.Conflict("scooter_not_rented", "can't free scooter that have not been rented")
+	ErrScooterNotTracked   = domain.NotFound("scooter_not_tracked", "scooter is not on a ride tracked by the service")
+	ErrTrackerStopping     = domain.Unavailable(errors.New("tracker is shutting down"))
+	ErrInvalidBatteryLevel = domain.Validation(
+		"invalid_battery_level",
+		"battery level has to be between 0 and 100 percent",
+	)
 )
 
 //go:generate mockgen -source=service.go -destination=mock/tracker_mock.go -package=mock
 type TrackerService interface {
-	TrackScooter(scooterUUID uuid.UUID, scooter *model.TrackerScooter) error
-	FreeScooter(scooterUUID uuid.UUID) error
+	TrackScooter(ctx context.Context, scooterUUID uuid.UUID, scooter *model.TrackerScooter) error
+	FreeScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.RideSummary, error)
+	ReportBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error
+	RideDistance(ctx context.Context, scooterUUID uuid.UUID) (float64, error)
+	// Stop makes the tracker refuse new rides, the rides it follows go on until they are freed.
+	Stop()
+	Stopping() bool
+	ActiveRides() int
 }
 
 type trackingService struct {
-	logger         *log.Logger
-	service        commonRedis.RedisService
-	rentedScooters map[uuid.UUID]chan uuid.UUID
-	errorsChan     map[uuid.UUID]chan error
+	logger              *slog.Logger
+	service             commonRedis.RedisService
+	zoneService         zone.ZoneService
+	bus                 events.EventBus
+	batteryDrainPerKm   float64
+	lowBatteryThreshold float64
+	rentedScooters      map[uuid.UUID]chan uuid.UUID
+	warningsChan        map[uuid.UUID]chan []string
+	reportedBatteries   map[uuid.UUID]float64
+	rideSummaries       map[uuid.UUID]*model.RideSummary
+	stopping            atomic.Bool
 }
 
-func NewTrackingService(logger *log.Logger, service commonRedis.RedisService) *trackingService {
+func NewTrackingService(
+	logger *slog.Logger,
+	service commonRedis.RedisService,
+	zoneService zone.ZoneService,
+	bus events.EventBus,
+	batteryDrainPerKm float64,
+	lowBatteryThreshold float64,
+) *trackingService {
 	return &trackingService{
-		logger:         logger,
-		service:        service,
-		rentedScooters: make(map[uuid.UUID]chan uuid.UUID),
-		errorsChan:     make(map[uuid.UUID]chan error),
+		logger:              logger,
+		service:             service,
+		zoneService:         zoneService,
+		bus:                 bus,
+		batteryDrainPerKm:   batteryDrainPerKm,
+		lowBatteryThreshold: lowBatteryThreshold,
+		rentedScooters:      make(map[uuid.UUID]chan uuid.UUID),
+		warningsChan:        make(map[uuid.UUID]chan []string),
+		reportedBatteries:   make(map[uuid.UUID]float64),
+		rideSummaries:       make(map[uuid.UUID]*model.RideSummary),
 	}
 }
 
-func (ts *trackingService) TrackScooter(scooterUUID uuid.UUID, scooter *model.TrackerScooter) error {
+func (ts *trackingService) TrackScooter(
+	ctx context.Context,
+	scooterUUID uuid.UUID, scooter *model.TrackerScooter,
+) error {
+	if ts.stopping.Load() {
+		return ErrTrackerStopping
+	}
+
 	rentedScooterChan := make(chan uuid.UUID)
-	rentalErrorsChan := make(chan error)
+	rideWarningsChan := make(chan []string)
 
 	myMux.Lock()
 
@@ -68,52 +125,116 @@ func (ts *trackingService) TrackScooter(scooterUUID uuid.UUID, scooter *model.Tr
 		}
 	}
 
+	// A resumed ride goes on with what was observed before
+	summary := scooter.Summary
+	if summary == nil {
+		summary = model.NewRideSummary(0, nil)
+	}
+
 	ts.rentedScooters[scooterUUID] = rentedScooterChan
-	ts.errorsChan[scooterUUID] = rentalErrorsChan
+	ts.warningsChan[scooterUUID] = rideWarningsChan
+	// The summary is shared from the start, so the distance of the ride can be followed while it lasts
+	ts.rideSummaries[scooterUUID] = summary
 
 	myMux.Unlock()
 
+	metrics.ActiveRides.Inc()
+
+	// The ride outlives the request that started it, so its ticks are traced apart and only linked to the request
+	rideLink := trace.LinkFromContext(ctx)
+
 	go func() {
 		defer close(rentedScooterChan)
+		defer metrics.ActiveRides.Dec()
+
+		// Every record of the ride carries its identity, so it can be correlated with the requests of the client
+		logger := ts.logger.With(
+			logging.KeyScooterUUID, scooterUUID,
+			logging.KeyClientUUID, scooter.ClientUUID,
+			logging.KeyRentalID, scooter.RentalID,
+		)
 
 		rentalErrors := make(map[string]int)
+		location := ts.zoneService.Locate(scooter.City, scooter.Longitude, scooter.Latitude)
+
 		for {
 			select {
 			case <-time.After(MovingTimeInSeconds * time.Second):
+				tickCtx, span := tracing.Tracer().Start(
+					context.Background(),
+					"tracker.tick",
+					trace.WithLinks(rideLink),
+					trace.WithAttributes(
+						tracing.KeyScooterUUID.String(scooterUUID.String()),
+						tracing.KeyCity.String(scooter.City),
+						tracing.KeyRentalID.String(scooter.RentalID.String()),
+					),
+				)
+
+				previousLongitude, previousLatitude := scooter.Longitude, scooter.Latitude
+
 				simulateScooterMove(scooter.GeoLocation, MovingTimeInSeconds, north)
 
-				ts.logger.Printf(
-					"Scooter with UUID: %s continues his journey. Now it is at %f, %f.",
-					scooterUUID,
-					scooter.Longitude,
-					scooter.Latitude,
+				distance := geo.DistanceInMeters(previousLongitude, previousLatitude, scooter.Longitude, scooter.Latitude)
+				myMux.Lock()
+				summary.Distance += distance
+				myMux.Unlock()
+
+				scooter.Battery = ts.batteryAfterMove(scooterUUID, scooter.Battery, distance)
+
+				logger.Debug(
+					"ride continues",
+					"longitude", scooter.Longitude,
+					"latitude", scooter.Latitude,
+					"battery", scooter.Battery,
 				)
 
-				err := ts.service.UpdateScooterLocation(scooter.GeoLocation, scooter.City)
-				if err != nil {
-					if _, ok := rentalErrors[err.Error()]; ok {
-						rentalErrors[err.Error()] += 1
-					}
+				location = ts.recordZoneEvents(logger, summary, scooter, location)
+
+				if err := ts.service.UpdateScooterLocation(tickCtx, scooter.GeoLocation, scooter.City); err != nil {
+					rentalErrors[err.Error()]++
+					metrics.TrackerTickErrors.Inc()
 
-					rentalErrors[err.Error()] = 1
+					logger.Error("updating scooter's location failed", logging.KeyError, err)
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+
+					ts.publish(tickCtx, eventsmodel.NewRideErrored(scooterUUID, err, time.Now()))
 				}
-			case <-rentedScooterChan: // Signal to stop tracking
-				if len(rentalErrors) == 0 {
-					rentalErrorsChan <- nil
 
-					return
+				if err := ts.service.UpdateScooterBattery(tickCtx, scooterUUID, scooter.Battery); err != nil {
+					rentalErrors[err.Error()]++
+					metrics.TrackerTickErrors.Inc()
+
+					logger.Error("updating scooter's battery failed", logging.KeyError, err)
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+
+					ts.publish(tickCtx, eventsmodel.NewRideErrored(scooterUUID, err, time.Now()))
 				}
 
-				routineErrors := fmt.Errorf(
-					"go routine assigned to scooterUUID - %s met several errors",
+				ts.publish(tickCtx, eventsmodel.NewScooterMoved(
 					scooterUUID,
-				)
+					scooter.City,
+					scooter.Longitude,
+					scooter.Latitude,
+					distance,
+					scooter.Battery,
+					time.Now(),
+				))
+
+				span.End()
+			case <-rentedScooterChan: // Signal to stop tracking
+				// Failed ticks don't fail the ride, they only left its tracked data incomplete
+				warnings := make([]string, 0, len(rentalErrors))
 
 				for key, value := range rentalErrors {
-					routineErrors = fmt.Errorf("%w: %s, %d times", routineErrors, key, value)
+					warnings = append(warnings, fmt.Sprintf("%s, %d times", key, value))
 				}
 
-				rentalErrorsChan <- routineErrors
+				slices.Sort(warnings)
+
+				rideWarningsChan <- warnings
 
 				return
 			}
@@ -123,30 +244,194 @@ func (ts *trackingService) TrackScooter(scooterUUID uuid.UUID, scooter *model.Tr
 	return nil
 }
 
-func (ts *trackingService) FreeScooter(scooterUUID uuid.UUID) error {
+// FreeScooter stops tracking the scooter and returns the summary of its ride, ticks that failed during the ride are
+// left as its warnings.
+func (ts *trackingService) FreeScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.RideSummary, error) {
 	myMux.Lock()
 
 	scooterToFree, ok := ts.rentedScooters[scooterUUID]
-	if !ok {
-		return ErrNoScooterToFree
+	if !ok || scooterToFree == nil {
+		myMux.Unlock()
+
+		return nil, ErrNoScooterToFree
 	}
 
+	ts.rentedScooters[scooterUUID] = nil
+	delete(ts.reportedBatteries, scooterUUID)
+
+	rideWarningsChan := ts.warningsChan[scooterUUID]
+
+	myMux.Unlock()
+
+	// The lock is released before signalling, so the tracking routine can finish its current tick
 	scooterToFree <- scooterUUID
 
-	ts.rentedScooters[scooterUUID] = nil
+	warnings := <-rideWarningsChan
+
+	close(rideWarningsChan)
 
+	myMux.Lock()
+	summary := ts.rideSummaries[scooterUUID]
+	delete(ts.rideSummaries, scooterUUID)
+	summary.Warnings = append(summary.Warnings, warnings...)
 	myMux.Unlock()
 
-	potentialErrors := <-ts.errorsChan[scooterUUID]
-	if potentialErrors != nil {
-		return fmt.Errorf("freeing scooter: %w", potentialErrors)
+	return summary, nil
+}
+
+func (ts *trackingService) Stop() {
+	ts.stopping.Store(true)
+}
+
+func (ts *trackingService) Stopping() bool {
+	return ts.stopping.Load()
+}
+
+// ActiveRides counts the rides the tracker follows, freed scooters are kept in its map until they are rented again.
+func (ts *trackingService) ActiveRides() int {
+	myMux.Lock()
+	defer myMux.Unlock()
+
+	rides := 0
+
+	for _, rentedScooterChan := range ts.rentedScooters {
+		if rentedScooterChan != nil {
+			rides++
+		}
 	}
 
-	close(ts.errorsChan[scooterUUID])
+	return rides
+}
+
+// RideDistance returns how far the scooter got since the ride started.
+func (ts *trackingService) RideDistance(ctx context.Context, scooterUUID uuid.UUID) (float64, error) {
+	myMux.Lock()
+	defer myMux.Unlock()
+
+	summary, ok := ts.rideSummaries[scooterUUID]
+	if !ok || ts.rentedScooters[scooterUUID] == nil {
+		return 0, ErrScooterNotTracked
+	}
+
+	return summary.Distance, nil
+}
+
+// ReportBattery applies the battery level reported by scooter's telemetry. During a ride the reading replaces the
+// drain model on the next tracking tick, otherwise it is stored right away and idle scooters are moved in and out of
+// low battery state.
+func (ts *trackingService) ReportBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	if battery < emptyBattery || battery > redismodel.FullBattery {
+		return ErrInvalidBatteryLevel
+	}
+
+	myMux.Lock()
+
+	if ts.rentedScooters[scooterUUID] != nil {
+		ts.reportedBatteries[scooterUUID] = battery
+
+		myMux.Unlock()
+
+		return nil
+	}
+
+	myMux.Unlock()
+
+	// Reading the metadata first, so the battery of an unknown scooter doesn't create its hash
+	if _, err := ts.service.GetScooterMetadata(ctx, scooterUUID); err != nil {
+		return fmt.Errorf("getting scooter's metadata: %w", err)
+	}
+
+	if err := ts.service.UpdateScooterBattery(ctx, scooterUUID, battery); err != nil {
+		return fmt.Errorf("updating scooter's battery: %w", err)
+	}
+
+	status, from := redismodel.StatusAvailable, redismodel.StatusLowBattery
+	if battery < ts.lowBatteryThreshold {
+		status, from = redismodel.StatusLowBattery, redismodel.StatusAvailable
+	}
+
+	// The status is swapped only from the one the battery moves it out of, a scooter rented or reserved since the
+	// ride check keeps its status
+	_, err := ts.service.SwapScooterStatus(ctx, scooterUUID, status, from)
+	if errors.Is(err, commonRedis.ErrScooterStatusChanged) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("updating scooter's status: %w", err)
+	}
+
+	ts.publish(ctx, eventsmodel.NewScooterStatusChanged(scooterUUID, string(status), time.Now()))
+
+	ts.logger.Info(
+		"scooter reported battery and changed status",
+		logging.KeyScooterUUID, scooterUUID,
+		"battery", battery,
+		"status", status,
+	)
 
 	return nil
 }
 
+// publish puts the event on the bus, the change it describes is already stored so a failure is only logged.
+func (ts *trackingService) publish(ctx context.Context, event eventsmodel.Event) {
+	if err := ts.bus.Publish(ctx, event); err != nil {
+		ts.logger.Error("publishing event failed", "event_type", event.Type(), logging.KeyError, err)
+	}
+}
+
+// batteryAfterMove returns the battery reported by telemetry during the last tick if there was one, otherwise
+// the battery is drained proportionally to the distance travelled.
+func (ts *trackingService) batteryAfterMove(scooterUUID uuid.UUID, battery, distance float64) float64 {
+	myMux.Lock()
+	defer myMux.Unlock()
+
+	if reported, ok := ts.reportedBatteries[scooterUUID]; ok {
+		delete(ts.reportedBatteries, scooterUUID)
+
+		return reported
+	}
+
+	return drainBattery(battery, distance, ts.batteryDrainPerKm)
+}
+
+// recordZoneEvents adds an event to the ride when the scooter enters a slow zone or leaves the operating area,
+// it returns the current location to compare with on the next move.
+func (ts *trackingService) recordZoneEvents(
+	logger *slog.Logger,
+	summary *model.RideSummary,
+	scooter *model.TrackerScooter,
+	previous *zonemodel.Location,
+) *zonemodel.Location {
+	current := ts.zoneService.Locate(scooter.City, scooter.Longitude, scooter.Latitude)
+
+	if current.SlowZone != "" && current.SlowZone != previous.SlowZone {
+		summary.Events = append(summary.Events, model.NewRideEvent(
+			model.EventEnteredSlowZone,
+			current.SlowZone,
+			scooter.Longitude,
+			scooter.Latitude,
+			time.Now(),
+		))
+
+		logger.Info("scooter entered slow zone", "zone", current.SlowZone)
+	}
+
+	if previous.InsideOperatingArea && !current.InsideOperatingArea {
+		summary.Events = append(summary.Events, model.NewRideEvent(
+			model.EventLeftOperatingArea,
+			"",
+			scooter.Longitude,
+			scooter.Latitude,
+			time.Now(),
+		))
+
+		logger.Info("scooter left the operating area")
+	}
+
+	return current
+}
+
 // simulateScooterMove is simulating the move of the scooter, I assume that each scooter goes on average 36 km/h
 // which is around one second degree per second(approximately for both latitude and longitude). I pick
 // one of four sides(north, west, east, south) and move the scooter three second degrees in that direction.
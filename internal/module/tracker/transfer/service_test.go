@@ -3,28 +3,43 @@
 package transfer
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 
+	"scootinAboot/internal/geo"
+	"scootinAboot/internal/metrics"
+	eventsmodel "scootinAboot/internal/module/events/model"
+	events "scootinAboot/internal/module/events/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
+	commonRedis "scootinAboot/internal/module/redis/transfer"
 	"scootinAboot/internal/module/redis/transfer/mock"
 	"scootinAboot/internal/module/tracker/model"
+	zonemodel "scootinAboot/internal/module/zone/model"
+	zone "scootinAboot/internal/module/zone/transfer"
 )
 
 const (
 	amountOfScooterTrackingEvents = 2
 	firstTestCity                 = "Montreal"
 	secondTestCity                = "Ottawa"
+
+	testBatteryDrainPerKm   = 1.5
+	testLowBatteryThreshold = 15.0
+
+	testSlowZone = "Downtown"
 )
 
 func TestTrackScooter(t *testing.T) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -39,7 +54,8 @@ func TestTrackScooter(t *testing.T) {
 				Longitude: 70.01,
 				Latitude:  60.01,
 			},
-			City: firstTestCity,
+			City:    firstTestCity,
+			Battery: redismodel.FullBattery,
 		},
 		{
 			GeoLocation: &redis.GeoLocation{
@@ -47,38 +63,47 @@ func TestTrackScooter(t *testing.T) {
 				Longitude: 69.99,
 				Latitude:  59.99,
 			},
-			City: secondTestCity,
+			City:    secondTestCity,
+			Battery: redismodel.FullBattery,
 		},
 	}
 
 	tests := map[string]struct {
-		logger                  *log.Logger
+		logger                  *slog.Logger
 		mockRedisServiceHandler func(mock *mock.MockRedisService)
-		wantErr                 bool
+		wantWarnings            bool
+		wantTickErrors          float64
 	}{
 		"successfully tracking multiple scooters": {
 			logger: logger,
 			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
 				for i := range scooters {
-					mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooters[i].City).
+					mock.EXPECT().UpdateScooterLocation(gomock.Any(), gomock.Any(), scooters[i].City).
 						Return(nil).Times(amountOfScooterTrackingEvents)
 				}
+
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil).Times(amountOfScooterTrackingEvents * len(scooters))
 			},
-			wantErr: false,
+			wantWarnings: false,
 		},
-		"failed tracking multiple scooters, because of redis service threw error when updating scooter location ": {
+		"tracking multiple scooters leaves warnings, because redis service failed to update location": {
 			logger: logger,
 			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
-				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooters[0].City).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), gomock.Any(), scooters[0].City).
 					Return(nil).Times(amountOfScooterTrackingEvents - 1)
-				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooters[0].City).
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), gomock.Any(), scooters[0].City).
 					Return(redis.ErrClosed).Times(1)
 				for i := 1; i < len(scooters); i++ {
-					mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooters[i].City).
+					mock.EXPECT().UpdateScooterLocation(gomock.Any(), gomock.Any(), scooters[i].City).
 						Return(nil).Times(amountOfScooterTrackingEvents)
 				}
+
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil).Times(amountOfScooterTrackingEvents * len(scooters))
 			},
-			wantErr: true,
+			wantWarnings:   true,
+			wantTickErrors: 1,
 		},
 	}
 	for name, tt := range tests {
@@ -90,16 +115,28 @@ func TestTrackScooter(t *testing.T) {
 
 			tt.mockRedisServiceHandler(mockRedisService)
 
-			ts := NewTrackingService(tt.logger, mockRedisService)
+			ts := NewTrackingService(
+				tt.logger,
+				mockRedisService,
+				zone.NewZoneService(tt.logger, testZones()),
+				events.NewMemoryBus(tt.logger),
+				testBatteryDrainPerKm,
+				testLowBatteryThreshold,
+			)
+
+			activeRides := testutil.ToFloat64(metrics.ActiveRides)
+			tickErrors := testutil.ToFloat64(metrics.TrackerTickErrors)
 
 			for i := range scooters {
 				scooterUUID, innerErr := uuid.Parse(scooters[i].Name)
 				require.NoError(t, innerErr)
 
-				innerErr = ts.TrackScooter(scooterUUID, scooters[i])
+				innerErr = ts.TrackScooter(context.Background(), scooterUUID, scooters[i])
 				require.NoError(t, innerErr)
 			}
 
+			require.Equal(t, activeRides+float64(len(scooters)), testutil.ToFloat64(metrics.ActiveRides))
+
 			if len(ts.rentedScooters) != len(scooters) {
 				t.Errorf(
 					"TrackScooter() should rent all given scooters = %v, want %v",
@@ -112,6 +149,9 @@ func TestTrackScooter(t *testing.T) {
 				time.Sleep(MovingTimeInSeconds * time.Second)
 			}
 
+			// Half a tick more, so the routines finish their last tick before they are told to stop
+			time.Sleep(MovingTimeInSeconds * time.Second / 2)
+
 			for i := range scooters {
 				scooterUUID, err := uuid.Parse(scooters[i].Name)
 				require.NoError(t, err)
@@ -119,27 +159,29 @@ func TestTrackScooter(t *testing.T) {
 				ts.rentedScooters[scooterUUID] <- scooterUUID
 			}
 
-			var errorFound bool
+			var warningFound bool
 
-			for i := range ts.errorsChan {
-				if err = <-ts.errorsChan[i]; err != nil {
-					errorFound = true
+			for i := range ts.warningsChan {
+				if warnings := <-ts.warningsChan[i]; len(warnings) > 0 {
+					warningFound = true
 				}
 
-				close(ts.errorsChan[i])
+				close(ts.warningsChan[i])
 			}
 
-			if errorFound != tt.wantErr {
-				t.Errorf("TrackScooter() error = %v, wantErr %v", err, tt.wantErr)
+			require.Equal(t, tt.wantWarnings, warningFound)
 
-				return
-			}
+			require.Equal(t, tickErrors+tt.wantTickErrors, testutil.ToFloat64(metrics.TrackerTickErrors))
+			// The tracking routines leave the gauge once they reported their errors
+			require.Eventually(t, func() bool {
+				return testutil.ToFloat64(metrics.ActiveRides) == activeRides
+			}, time.Second, 10*time.Millisecond)
 		})
 	}
 }
 
 func TestFreeScooter(t *testing.T) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	firstScooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -150,40 +192,76 @@ func TestFreeScooter(t *testing.T) {
 			Longitude: 70.01,
 			Latitude:  60.01,
 		},
-		City: firstTestCity,
+		City:    firstTestCity,
+		Battery: redismodel.FullBattery,
+	}
+
+	movingScooter := &model.TrackerScooter{
+		GeoLocation: &redis.GeoLocation{
+			Name:      firstScooterUUID.String(),
+			Longitude: 70.01,
+			Latitude:  60.01,
+		},
+		City:    firstTestCity,
+		Battery: redismodel.FullBattery,
 	}
 
+	// Tracker moves the scooter north once before it is freed
+	movedDistance := geo.DistanceInMeters(70.01, 60.01, 70.01, 60.01+MovingTimeInSeconds*oneSecondDecimal)
+
 	tests := map[string]struct {
-		logger                  *log.Logger
+		logger                  *slog.Logger
 		mockRedisServiceHandler func(mock *mock.MockRedisService)
 		rentScooterHandler      func(tracker *trackingService)
+		wantDistance            float64
+		wantEvents              []model.RideEventType
+		wantWarnings            int
 		wantErr                 bool
 	}{
 		"successfully freeing scooter": {
 			logger:                  logger,
 			mockRedisServiceHandler: nil,
 			rentScooterHandler: func(ts *trackingService) {
-				ts.TrackScooter(firstScooterUUID, scooter)
+				ts.TrackScooter(context.Background(), firstScooterUUID, scooter)
 			},
 			wantErr: false,
 		},
+		"successfully freeing scooter with distance travelled during the ride": {
+			logger: logger,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), movingScooter.GeoLocation, movingScooter.City).Return(nil)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), firstScooterUUID, gomock.Any()).Return(nil)
+			},
+			rentScooterHandler: func(ts *trackingService) {
+				ts.TrackScooter(context.Background(), firstScooterUUID, movingScooter)
+
+				time.Sleep(MovingTimeInSeconds*time.Second + 500*time.Millisecond)
+			},
+			wantDistance: movedDistance,
+			wantEvents:   []model.RideEventType{model.EventEnteredSlowZone},
+			wantErr:      false,
+		},
 		"freeing scooter failed, because freed scooter have not been rented": {
 			logger:                  logger,
 			mockRedisServiceHandler: nil,
 			rentScooterHandler:      func(ts *trackingService) {},
 			wantErr:                 true,
 		},
-		"freeing scooter failed, because scooter's rental process threw error": {
+		"successfully freeing scooter with warnings, because tracking tick failed": {
 			logger: logger,
 			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
-				mock.EXPECT().UpdateScooterLocation(scooter.GeoLocation, scooter.City).Return(redis.ErrClosed)
+				mock.EXPECT().UpdateScooterLocation(gomock.Any(), scooter.GeoLocation, scooter.City).Return(redis.ErrClosed)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), firstScooterUUID, gomock.Any()).Return(nil)
 			},
 			rentScooterHandler: func(ts *trackingService) {
-				ts.TrackScooter(firstScooterUUID, scooter)
+				ts.TrackScooter(context.Background(), firstScooterUUID, scooter)
 
-				time.Sleep(MovingTimeInSeconds * time.Second)
+				time.Sleep(MovingTimeInSeconds*time.Second + 500*time.Millisecond)
 			},
-			wantErr: true,
+			wantDistance: movedDistance,
+			wantEvents:   []model.RideEventType{model.EventEnteredSlowZone},
+			wantWarnings: 1,
+			wantErr:      false,
 		},
 	}
 	for name, tt := range tests {
@@ -196,13 +274,376 @@ func TestFreeScooter(t *testing.T) {
 				tt.mockRedisServiceHandler(mockRedisService)
 			}
 
-			ts := NewTrackingService(tt.logger, mockRedisService)
+			ts := NewTrackingService(
+				tt.logger,
+				mockRedisService,
+				zone.NewZoneService(tt.logger, testZones()),
+				events.NewMemoryBus(tt.logger),
+				testBatteryDrainPerKm,
+				testLowBatteryThreshold,
+			)
 
 			tt.rentScooterHandler(ts)
 
-			if err = ts.FreeScooter(firstScooterUUID); (err != nil) != tt.wantErr {
+			summary, err := ts.FreeScooter(context.Background(), firstScooterUUID)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("FreeScooter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			require.Len(t, summary.Warnings, tt.wantWarnings)
+
+			if summary.Distance != tt.wantDistance {
+				t.Errorf("FreeScooter() distance = %v, want %v", summary.Distance, tt.wantDistance)
+			}
+
+			events := make([]model.RideEventType, 0, len(summary.Events))
+			for _, event := range summary.Events {
+				events = append(events, event.Type)
+			}
+
+			if len(events) != len(tt.wantEvents) {
+				t.Errorf("FreeScooter() events = %v, want %v", events, tt.wantEvents)
+				return
+			}
+
+			for i := range events {
+				if events[i] != tt.wantEvents[i] {
+					t.Errorf("FreeScooter() events = %v, want %v", events, tt.wantEvents)
+				}
 			}
 		})
 	}
 }
+
+func TestRideDistance(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooter := model.NewTrackerScooter(
+		&redis.GeoLocation{Name: scooterUUID.String(), Longitude: 70.01, Latitude: 60.01},
+		firstTestCity,
+		redismodel.FullBattery,
+		uuid.New(),
+		uuid.New(),
+	)
+
+	tests := map[string]struct {
+		rentScooterHandler func(ts *trackingService)
+		want               float64
+		wantErr            error
+	}{
+		"successfully getting distance of the ride": {
+			rentScooterHandler: func(ts *trackingService) {
+				require.NoError(t, ts.TrackScooter(context.Background(), scooterUUID, scooter))
+
+				myMux.Lock()
+				ts.rideSummaries[scooterUUID].Distance = 250
+				myMux.Unlock()
+			},
+			want:    250,
+			wantErr: nil,
+		},
+		"getting distance failed, because scooter is not rented": {
+			rentScooterHandler: func(ts *trackingService) {},
+			want:               0,
+			wantErr:            ErrScooterNotTracked,
+		},
+		"getting distance failed, because ride already ended": {
+			rentScooterHandler: func(ts *trackingService) {
+				require.NoError(t, ts.TrackScooter(context.Background(), scooterUUID, scooter))
+
+				_, freeErr := ts.FreeScooter(context.Background(), scooterUUID)
+				require.NoError(t, freeErr)
+			},
+			want:    0,
+			wantErr: ErrScooterNotTracked,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			ts := NewTrackingService(
+				logger,
+				mock.NewMockRedisService(controller),
+				zone.NewZoneService(logger, testZones()),
+				events.NewMemoryBus(logger),
+				testBatteryDrainPerKm,
+				testLowBatteryThreshold,
+			)
+
+			tt.rentScooterHandler(ts)
+
+			got, err := ts.RideDistance(context.Background(), scooterUUID)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+
+			if tt.wantErr == nil {
+				_, err = ts.FreeScooter(context.Background(), scooterUUID)
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReportBattery(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		battery                 float64
+		mockRedisServiceHandler func(mock *mock.MockRedisService)
+		wantStatus              redismodel.ScooterStatus
+		wantErr                 bool
+	}{
+		"successfully reporting battery of available scooter": {
+			battery: 80,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(firstTestCity, redismodel.StatusAvailable, 90), nil).Times(1)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), scooterUUID, 80.0).Return(nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusAvailable, redismodel.StatusLowBattery).
+					Return(redismodel.StatusAvailable, commonRedis.ErrScooterStatusChanged).Times(1)
+			},
+			wantErr: false,
+		},
+		"successfully switching scooter to low battery": {
+			battery: 10,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(firstTestCity, redismodel.StatusAvailable, 20), nil).Times(1)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), scooterUUID, 10.0).Return(nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusLowBattery, redismodel.StatusAvailable).
+					Return(redismodel.StatusAvailable, nil).Times(1)
+			},
+			wantStatus: redismodel.StatusLowBattery,
+			wantErr:    false,
+		},
+		"reporting low battery keeps status of scooter rented in the meantime": {
+			battery: 10,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(firstTestCity, redismodel.StatusAvailable, 20), nil).Times(1)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), scooterUUID, 10.0).Return(nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusLowBattery, redismodel.StatusAvailable).
+					Return(redismodel.StatusRented, commonRedis.ErrScooterStatusChanged).Times(1)
+			},
+			wantErr: false,
+		},
+		"successfully bringing charged scooter back to available": {
+			battery: 100,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(firstTestCity, redismodel.StatusLowBattery, 5), nil).Times(1)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), scooterUUID, 100.0).Return(nil).Times(1)
+				mock.EXPECT().SwapScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusAvailable, redismodel.StatusLowBattery).
+					Return(redismodel.StatusLowBattery, nil).Times(1)
+			},
+			wantStatus: redismodel.StatusAvailable,
+			wantErr:    false,
+		},
+		"reporting battery failed, because battery level is out of range": {
+			battery:                 120,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {},
+			wantErr:                 true,
+		},
+		"reporting battery failed, because redis service threw error when updating battery": {
+			battery: 50,
+			mockRedisServiceHandler: func(mock *mock.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(firstTestCity, redismodel.StatusAvailable, 60), nil).Times(1)
+				mock.EXPECT().UpdateScooterBattery(gomock.Any(), scooterUUID, 50.0).Return(redis.ErrClosed).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mockRedisService := mock.NewMockRedisService(controller)
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			bus := events.NewMemoryBus(logger)
+
+			ts := NewTrackingService(
+				logger,
+				mockRedisService,
+				zone.NewZoneService(logger, testZones()),
+				bus,
+				testBatteryDrainPerKm,
+				testLowBatteryThreshold,
+			)
+
+			if err = ts.ReportBattery(context.Background(), scooterUUID, tt.battery); (err != nil) != tt.wantErr {
+				t.Errorf("ReportBattery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// Only a change of the status is published
+			published := bus.Published()
+
+			if tt.wantStatus == "" {
+				require.Empty(t, published)
+
+				return
+			}
+
+			require.Len(t, published, 1)
+
+			statusChanged, ok := published[0].(*eventsmodel.ScooterStatusChanged)
+			require.True(t, ok)
+			require.Equal(t, scooterUUID, statusChanged.ScooterUUID)
+			require.Equal(t, string(tt.wantStatus), statusChanged.Status)
+		})
+	}
+}
+
+func TestRecordZoneEvents(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := map[string]struct {
+		previous   *zonemodel.Location
+		longitude  float64
+		latitude   float64
+		wantEvents []model.RideEventType
+	}{
+		"moving within operating area adds no event": {
+			previous:   &zonemodel.Location{InsideOperatingArea: true},
+			longitude:  70.005,
+			latitude:   60.005,
+			wantEvents: nil,
+		},
+		"entering slow zone adds event": {
+			previous:   &zonemodel.Location{InsideOperatingArea: true},
+			longitude:  70.01,
+			latitude:   60.015,
+			wantEvents: []model.RideEventType{model.EventEnteredSlowZone},
+		},
+		"moving within the same slow zone adds no event": {
+			previous:   &zonemodel.Location{InsideOperatingArea: true, SlowZone: testSlowZone},
+			longitude:  70.01,
+			latitude:   60.015,
+			wantEvents: nil,
+		},
+		"leaving operating area adds event": {
+			previous:   &zonemodel.Location{InsideOperatingArea: true},
+			longitude:  71,
+			latitude:   60,
+			wantEvents: []model.RideEventType{model.EventLeftOperatingArea},
+		},
+		"moving outside of operating area adds no event": {
+			previous:   &zonemodel.Location{},
+			longitude:  71,
+			latitude:   60,
+			wantEvents: nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := NewTrackingService(
+				logger,
+				nil,
+				zone.NewZoneService(logger, testZones()),
+				events.NewMemoryBus(logger),
+				testBatteryDrainPerKm,
+				testLowBatteryThreshold,
+			)
+
+			scooter := model.NewTrackerScooter(
+				&redis.GeoLocation{Name: uuid.NewString(), Longitude: tt.longitude, Latitude: tt.latitude},
+				firstTestCity,
+				redismodel.FullBattery,
+				uuid.New(),
+				uuid.New(),
+			)
+
+			summary := model.NewRideSummary(0, nil)
+
+			ts.recordZoneEvents(logger, summary, scooter, tt.previous)
+
+			require.Len(t, summary.Events, len(tt.wantEvents))
+
+			for i := range tt.wantEvents {
+				require.Equal(t, tt.wantEvents[i], summary.Events[i].Type)
+			}
+		})
+	}
+}
+
+// testZones gives the first test city an operating area around test scooters and a slow zone north of them.
+func testZones() map[string][]*zonemodel.Zone {
+	return map[string][]*zonemodel.Zone{
+		firstTestCity: {
+			zonemodel.NewZone("Montreal", zonemodel.ZoneOperatingArea, []zonemodel.Polygon{
+				{{{69, 59}, {70.5, 59}, {70.5, 61}, {69, 61}, {69, 59}}},
+			}),
+			zonemodel.NewZone(testSlowZone, zonemodel.ZoneSlow, []zonemodel.Polygon{
+				{{{70, 60.0105}, {70.02, 60.0105}, {70.02, 60.02}, {70, 60.02}, {70, 60.0105}}},
+			}),
+		},
+	}
+}
+
+func TestStop(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRedisService := mock.NewMockRedisService(controller)
+	mockRedisService.EXPECT().UpdateScooterLocation(gomock.Any(), gomock.Any(), firstTestCity).
+		Return(nil).AnyTimes()
+	mockRedisService.EXPECT().UpdateScooterBattery(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
+
+	ts := NewTrackingService(
+		logger,
+		mockRedisService,
+		zone.NewZoneService(logger, testZones()),
+		events.NewMemoryBus(logger),
+		testBatteryDrainPerKm,
+		testLowBatteryThreshold,
+	)
+
+	rentedScooterUUID, lateScooterUUID := uuid.New(), uuid.New()
+
+	err := ts.TrackScooter(context.Background(), rentedScooterUUID, testTrackerScooter(rentedScooterUUID))
+	require.NoError(t, err)
+	require.Equal(t, 1, ts.ActiveRides())
+
+	ts.Stop()
+	require.True(t, ts.Stopping())
+
+	// New rides are refused, the ride in progress goes on until it is freed
+	err = ts.TrackScooter(context.Background(), lateScooterUUID, testTrackerScooter(lateScooterUUID))
+	require.ErrorIs(t, err, ErrTrackerStopping)
+	require.Equal(t, 1, ts.ActiveRides())
+
+	_, err = ts.FreeScooter(context.Background(), rentedScooterUUID)
+	require.NoError(t, err)
+	require.Equal(t, 0, ts.ActiveRides())
+}
+
+func testTrackerScooter(scooterUUID uuid.UUID) *model.TrackerScooter {
+	return &model.TrackerScooter{
+		GeoLocation: &redis.GeoLocation{
+			Name:      scooterUUID.String(),
+			Longitude: 70.01,
+			Latitude:  60.01,
+		},
+		City:    firstTestCity,
+		Battery: redismodel.FullBattery,
+	}
+}
@@ -0,0 +1,74 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/module/tracker/model"
+	"scootinAboot/internal/tracing"
+)
+
+// tracedTrackerService spans every operation of the service, their time includes waiting for the lock shared by
+// the rides.
+type tracedTrackerService struct {
+	TrackerService
+}
+
+func NewTracedTrackerService(service TrackerService) *tracedTrackerService {
+	return &tracedTrackerService{TrackerService: service}
+}
+
+func (ts *tracedTrackerService) TrackScooter(
+	ctx context.Context,
+	scooterUUID uuid.UUID,
+	scooter *model.TrackerScooter,
+) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"TrackerService.TrackScooter",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+		tracing.KeyCity.String(scooter.City),
+		tracing.KeyRentalID.String(scooter.RentalID.String()),
+	)
+
+	err := ts.TrackerService.TrackScooter(ctx, scooterUUID, scooter)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedTrackerService) FreeScooter(ctx context.Context, scooterUUID uuid.UUID) (*model.RideSummary, error) {
+	ctx, span := tracing.Start(ctx, "TrackerService.FreeScooter", tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+	summary, err := ts.TrackerService.FreeScooter(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return summary, err
+}
+
+func (ts *tracedTrackerService) ReportBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	ctx, span := tracing.Start(
+		ctx,
+		"TrackerService.ReportBattery",
+		tracing.KeyScooterUUID.String(scooterUUID.String()),
+	)
+
+	err := ts.TrackerService.ReportBattery(ctx, scooterUUID, battery)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+func (ts *tracedTrackerService) RideDistance(ctx context.Context, scooterUUID uuid.UUID) (float64, error) {
+	ctx, span := tracing.Start(ctx, "TrackerService.RideDistance", tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+	distance, err := ts.TrackerService.RideDistance(ctx, scooterUUID)
+
+	tracing.End(span, err)
+
+	return distance, err
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/google/uuid"
+
+	eventsmodel "scootinAboot/internal/module/events/model"
+)
+
+// Delivery is the body POSTed to the webhook endpoints, the ID is the same for every endpoint and every retry of
+// the event, so receivers can drop the duplicates.
+type Delivery struct {
+	ID   uuid.UUID         `json:"id"`
+	Type string            `json:"type"`
+	Data eventsmodel.Event `json:"data"`
+}
+
+func NewDelivery(id uuid.UUID, eventType string, data eventsmodel.Event) *Delivery {
+	return &Delivery{
+		ID:   id,
+		Type: eventType,
+		Data: data,
+	}
+}
@@ -0,0 +1,378 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/logging"
+	eventsmodel "scootinAboot/internal/module/events/model"
+	events "scootinAboot/internal/module/events/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redis "scootinAboot/internal/module/redis/transfer"
+	"scootinAboot/internal/module/webhook/model"
+)
+
+const (
+	// webhooksGroup is the consumer group of the bus all instances deliver the webhooks from.
+	webhooksGroup = "webhooks"
+	// maxDeadLetters is how many of the latest failed deliveries are kept.
+	maxDeadLetters = 1000
+	// deliveredTTL is how long the subscriptions done with a delivery are remembered, longer than its event may
+	// stay pending.
+	deliveredTTL = 24 * time.Hour
+
+	HeaderEvent     = "X-Scootin-Event"
+	HeaderDelivery  = "X-Scootin-Delivery"
+	HeaderTimestamp = "X-Scootin-Timestamp"
+	HeaderSignature = "X-Scootin-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	// errPermanent marks a delivery the endpoint refused, sending it again would not change its mind.
+	errPermanent = errors.New("endpoint refused the delivery")
+	// errPending marks a delivery that was neither accepted nor dead lettered, its event is read again later.
+	errPending = errors.New("delivery left pending")
+
+	// deliveryNamespace derives the IDs of the deliveries from their events, so an event read again keeps its ID.
+	deliveryNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("urn:scootinaboot:webhook-delivery"))
+)
+
+//go:generate mockgen -source=service.go -destination=mock/webhook_mock.go -package=mock
+type WebhookService interface {
+	CreateSubscription(
+		ctx context.Context,
+		url, secret string, eventTypes []string,
+	) (*redismodel.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]*redismodel.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	GetDeadLetters(ctx context.Context, limit int64) ([]*redismodel.WebhookDeadLetter, error)
+	Stop()
+}
+
+type webhookService struct {
+	logger       *slog.Logger
+	redisService redis.RedisService
+	bus          events.EventBus
+	client       *http.Client
+	maxAttempts  int
+	backoff      time.Duration
+
+	mu         sync.Mutex
+	stop       context.CancelFunc // cancels the deliveries, nil until started
+	stopped    bool
+	deliveries sync.WaitGroup // events still being delivered
+}
+
+func NewWebhookService(
+	logger *slog.Logger,
+	service redis.RedisService,
+	bus events.EventBus,
+	client *http.Client,
+	maxAttempts int,
+	backoff time.Duration,
+) *webhookService {
+	return &webhookService{
+		logger:       logger,
+		redisService: service,
+		bus:          bus,
+		client:       client,
+		maxAttempts:  maxAttempts,
+		backoff:      backoff,
+	}
+}
+
+func (ws *webhookService) CreateSubscription(
+	ctx context.Context,
+	url, secret string,
+	eventTypes []string,
+) (*redismodel.WebhookSubscription, error) {
+	subscription := redismodel.NewWebhookSubscription(uuid.New(), url, secret, eventTypes, time.Now().UTC())
+
+	if err := ws.redisService.SaveWebhookSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("creating webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (ws *webhookService) GetSubscriptions(ctx context.Context) ([]*redismodel.WebhookSubscription, error) {
+	subscriptions, err := ws.redisService.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (ws *webhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := ws.redisService.DeleteWebhookSubscription(ctx, id); err != nil {
+		return fmt.Errorf("deleting webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (ws *webhookService) GetDeadLetters(ctx context.Context, limit int64) ([]*redismodel.WebhookDeadLetter, error) {
+	letters, err := ws.redisService.GetWebhookDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+// Start delivers the events of the bus to the subscriptions in the background until the context is done or the
+// service is stopped, the consumer names this instance within the webhooks group.
+func (ws *webhookService) Start(ctx context.Context, consumer string) error {
+	ws.mu.Lock()
+	ctx, ws.stop = context.WithCancel(ctx)
+	ws.mu.Unlock()
+
+	if err := ws.bus.Subscribe(ctx, webhooksGroup, consumer, ws.dispatch); err != nil {
+		ws.Stop()
+
+		return fmt.Errorf("starting webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// Stop cancels the deliveries and waits for them to give up. Their events aren't acknowledged, so they stay pending
+// in the webhooks group and are delivered again once this or another instance reads them.
+func (ws *webhookService) Stop() {
+	ws.mu.Lock()
+	ws.stopped = true
+
+	if ws.stop != nil {
+		ws.stop()
+	}
+
+	ws.mu.Unlock()
+
+	ws.deliveries.Wait()
+}
+
+// dispatch sends the event to every subscription that wants it, each delivery retries on its own so a slow
+// endpoint doesn't hold back the other endpoints. It returns once every delivery was accepted or dead lettered, so
+// the event is acknowledged only then, otherwise it is left pending to be read again. The subscriptions done with
+// the delivery are remembered, so the event read again goes only to the ones still pending.
+func (ws *webhookService) dispatch(ctx context.Context, event eventsmodel.Event) error {
+	// Stop waits for the events being delivered, none is taken after it was called
+	ws.mu.Lock()
+
+	if ws.stopped {
+		ws.mu.Unlock()
+
+		return fmt.Errorf("dispatching %s event: %w", event.Type(), errPending)
+	}
+
+	ws.deliveries.Add(1)
+	ws.mu.Unlock()
+
+	defer ws.deliveries.Done()
+
+	subscriptions, err := ws.redisService.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("getting webhook subscriptions: %w", err)
+	}
+
+	eventType := string(event.Type())
+
+	data, err := eventsmodel.Encode(event)
+	if err != nil {
+		return err
+	}
+
+	id := uuid.NewSHA1(deliveryNamespace, append([]byte(eventType+"."), data...))
+	delivery := model.NewDelivery(id, eventType, event)
+
+	payload, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("encoding %s delivery: %w", eventType, err)
+	}
+
+	delivered, err := ws.redisService.GetWebhookDelivered(ctx, id)
+	if err != nil {
+		return fmt.Errorf("getting delivered webhooks: %w", err)
+	}
+
+	done := make(map[uuid.UUID]bool, len(delivered))
+	for _, subscriptionID := range delivered {
+		done[subscriptionID] = true
+	}
+
+	var (
+		delivering sync.WaitGroup
+		pending    atomic.Int32
+	)
+
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(eventType) || done[subscription.ID] {
+			continue
+		}
+
+		delivering.Add(1)
+
+		go func(subscription *redismodel.WebhookSubscription) {
+			defer delivering.Done()
+
+			if err := ws.deliver(ctx, subscription, delivery, payload); err != nil {
+				pending.Add(1)
+
+				return
+			}
+
+			// The delivery is over already, failing to remember it risks sending it once more only
+			if err := ws.redisService.MarkWebhookDelivered(ctx, id, subscription.ID, deliveredTTL); err != nil {
+				ws.logger.Warn("marking webhook delivered failed", "webhook_id", subscription.ID, "delivery_id", id,
+					logging.KeyError, err)
+			}
+		}(subscription)
+	}
+
+	delivering.Wait()
+
+	if count := pending.Load(); count > 0 {
+		return fmt.Errorf("dispatching %s event to %d webhooks: %w", eventType, count, errPending)
+	}
+
+	return nil
+}
+
+// deliver sends the payload until the endpoint accepts it, waiting twice as long before every next attempt. A
+// delivery the endpoint refused or didn't accept within maxAttempts ends up among the dead letters. The one given up
+// on because the service stopped, or failed to be dead lettered, is returned as errPending.
+func (ws *webhookService) deliver(
+	ctx context.Context,
+	subscription *redismodel.WebhookSubscription,
+	delivery *model.Delivery,
+	payload []byte,
+) error {
+	var (
+		attempts int
+		err      error
+	)
+
+	logger := ws.logger.With("webhook_id", subscription.ID, "delivery_id", delivery.ID, "event_type", delivery.Type)
+
+	for attempts < ws.maxAttempts {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				err = fmt.Errorf("giving up on retries: %w", ctx.Err())
+			case <-time.After(ws.backoff << (attempts - 1)):
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		attempts++
+
+		err = ws.send(ctx, subscription, delivery, payload)
+		if err == nil || errors.Is(err, errPermanent) {
+			break
+		}
+
+		logger.Warn("webhook delivery attempt failed", "attempt", attempts, logging.KeyError, err)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		logger.Warn("webhook delivery left pending", "attempts", attempts, logging.KeyError, err)
+
+		return fmt.Errorf("%w: %w", errPending, err)
+	}
+
+	letter := redismodel.NewWebhookDeadLetter(
+		uuid.New(),
+		subscription,
+		delivery.Type,
+		payload,
+		attempts,
+		err.Error(),
+		time.Now().UTC(),
+	)
+
+	if err = ws.redisService.AddWebhookDeadLetter(ctx, letter, maxDeadLetters); err != nil {
+		logger.Error("dead lettering webhook delivery failed", logging.KeyError, err)
+
+		return fmt.Errorf("%w: %w", errPending, err)
+	}
+
+	logger.Error("webhook delivery dead lettered", "attempts", attempts, logging.KeyError, letter.LastError)
+
+	return nil
+}
+
+// send makes one attempt to deliver the payload, any 2xx response accepts it. Client errors other than a timeout
+// or throttling refuse it for good.
+func (ws *webhookService) send(
+	ctx context.Context,
+	subscription *redismodel.WebhookSubscription,
+	delivery *model.Delivery,
+	payload []byte,
+) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("building request: %w: %s", errPermanent, err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, delivery.Type)
+	request.Header.Set(HeaderDelivery, delivery.ID.String())
+	request.Header.Set(HeaderTimestamp, timestamp)
+	request.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, payload))
+
+	response, err := ws.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	// The body is drained, so the connection can be reused
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	switch code := response.StatusCode; {
+	case code >= 200 && code < 300:
+		return nil
+	case code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500:
+		return fmt.Errorf("endpoint responded with %s", response.Status)
+	default:
+		return fmt.Errorf("%w with %s", errPermanent, response.Status)
+	}
+}
+
+// Sign computes the signature of the delivery, the receiver recomputes it from the timestamp and body it got with
+// the secret it shares with the subscription and compares the two.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
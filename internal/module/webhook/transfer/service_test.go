@@ -0,0 +1,379 @@
+//go:build unit
+
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	eventsmodel "scootinAboot/internal/module/events/model"
+	events "scootinAboot/internal/module/events/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservicemock "scootinAboot/internal/module/redis/transfer/mock"
+)
+
+const (
+	testSecret      = "webhook_secret"
+	testMaxAttempts = 3
+	testBackoff     = time.Millisecond
+	testTimeout     = time.Second
+)
+
+// testReceiver is a webhook endpoint that answers with the statuses in turn, repeating the last one, and checks
+// the signature of every delivery it gets.
+type testReceiver struct {
+	t        *testing.T
+	server   *httptest.Server
+	statuses []int
+	mu       sync.Mutex
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func newTestReceiver(t *testing.T, statuses ...int) *testReceiver {
+	t.Helper()
+
+	receiver := &testReceiver{t: t, statuses: statuses}
+	receiver.server = httptest.NewServer(http.HandlerFunc(receiver.receive))
+
+	t.Cleanup(receiver.server.Close)
+
+	return receiver
+}
+
+func (tr *testReceiver) receive(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(tr.t, err)
+
+	require.Equal(tr.t, "application/json", r.Header.Get("Content-Type"))
+	require.Equal(tr.t, Sign(testSecret, r.Header.Get(HeaderTimestamp), body), r.Header.Get(HeaderSignature))
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.received = append(tr.received, r)
+	tr.bodies = append(tr.bodies, body)
+
+	status := tr.statuses[len(tr.statuses)-1]
+	if len(tr.received) <= len(tr.statuses) {
+		status = tr.statuses[len(tr.received)-1]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (tr *testReceiver) attempts() int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return len(tr.received)
+}
+
+func testSubscription(url string, eventTypes ...eventsmodel.EventType) *redismodel.WebhookSubscription {
+	types := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		types = append(types, string(eventType))
+	}
+
+	return redismodel.NewWebhookSubscription(uuid.New(), url, testSecret, types, time.Now().UTC())
+}
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"type":"scooter_freed"}`)
+
+	signature := Sign(testSecret, "1700000000", payload)
+
+	require.Equal(t, signature, Sign(testSecret, "1700000000", payload))
+	require.NotEqual(t, signature, Sign("other_secret", "1700000000", payload))
+	require.NotEqual(t, signature, Sign(testSecret, "1700000001", payload))
+	require.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+}
+
+func TestDispatch(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	event := eventsmodel.NewScooterFreed(uuid.New(), uuid.New(), uuid.New(), "Montreal", 10*time.Minute, 1200.0,
+		4.5, "CAD", time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := map[string]struct {
+		statuses       []int
+		eventTypes     []eventsmodel.EventType
+		delivered      bool // the subscription was done with the delivery before the event was read again
+		deadLetterErr  error
+		wantAttempts   int
+		wantDeadLetter bool
+		wantErr        error
+	}{
+		"delivering signed event on the first attempt": {
+			statuses:     []int{http.StatusNoContent},
+			eventTypes:   []eventsmodel.EventType{eventsmodel.TypeScooterRented, eventsmodel.TypeScooterFreed},
+			wantAttempts: 1,
+		},
+		"delivering event after the endpoint recovered": {
+			statuses:     []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			eventTypes:   []eventsmodel.EventType{eventsmodel.TypeScooterFreed},
+			wantAttempts: 3,
+		},
+		"skipping subscription that doesn't want the event": {
+			statuses:     []int{http.StatusOK},
+			eventTypes:   []eventsmodel.EventType{eventsmodel.TypeScooterRented},
+			wantAttempts: 0,
+		},
+		"skipping subscription done with the delivery before the event was read again": {
+			statuses:     []int{http.StatusOK},
+			eventTypes:   []eventsmodel.EventType{eventsmodel.TypeScooterFreed},
+			delivered:    true,
+			wantAttempts: 0,
+		},
+		"dead lettering event the endpoint kept failing": {
+			statuses:       []int{http.StatusBadGateway},
+			eventTypes:     []eventsmodel.EventType{eventsmodel.TypeScooterFreed},
+			wantAttempts:   testMaxAttempts,
+			wantDeadLetter: true,
+		},
+		"dead lettering event the endpoint refused without retries": {
+			statuses:       []int{http.StatusGone},
+			eventTypes:     []eventsmodel.EventType{eventsmodel.TypeScooterFreed},
+			wantAttempts:   1,
+			wantDeadLetter: true,
+		},
+		"leaving event pending because dead lettering failed": {
+			statuses:       []int{http.StatusGone},
+			eventTypes:     []eventsmodel.EventType{eventsmodel.TypeScooterFreed},
+			deadLetterErr:  redis.ErrClosed,
+			wantAttempts:   1,
+			wantDeadLetter: true,
+			wantErr:        errPending,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			receiver := newTestReceiver(t, tt.statuses...)
+			subscription := testSubscription(receiver.server.URL, tt.eventTypes...)
+
+			mockRedis := redisservicemock.NewMockRedisService(controller)
+			mockRedis.EXPECT().GetWebhookSubscriptions(gomock.Any()).Return([]*redismodel.WebhookSubscription{subscription}, nil)
+
+			var delivered []uuid.UUID
+			if tt.delivered {
+				delivered = []uuid.UUID{subscription.ID}
+			}
+
+			mockRedis.EXPECT().GetWebhookDelivered(gomock.Any(), gomock.Any()).Return(delivered, nil)
+
+			if tt.wantAttempts > 0 && tt.wantErr == nil {
+				mockRedis.EXPECT().MarkWebhookDelivered(gomock.Any(), gomock.Any(), subscription.ID, deliveredTTL).
+					Return(nil)
+			}
+
+			var letter *redismodel.WebhookDeadLetter
+
+			if tt.wantDeadLetter {
+				mockRedis.EXPECT().AddWebhookDeadLetter(gomock.Any(), gomock.Any(), int64(maxDeadLetters)).
+					DoAndReturn(func(_ context.Context, deadLetter *redismodel.WebhookDeadLetter, _ int64) error {
+						letter = deadLetter
+
+						return tt.deadLetterErr
+					})
+			}
+
+			ws := NewWebhookService(logger, mockRedis, events.NewMemoryBus(logger), http.DefaultClient,
+				testMaxAttempts, testBackoff)
+
+			err := ws.dispatch(context.Background(), event)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			require.Equal(t, tt.wantAttempts, receiver.attempts())
+
+			for i, request := range receiver.received {
+				require.Equal(t, string(eventsmodel.TypeScooterFreed), request.Header.Get(HeaderEvent))
+				require.Equal(t, receiver.received[0].Header.Get(HeaderDelivery), request.Header.Get(HeaderDelivery))
+
+				var delivery struct {
+					ID   uuid.UUID                 `json:"id"`
+					Type string                    `json:"type"`
+					Data *eventsmodel.ScooterFreed `json:"data"`
+				}
+
+				require.NoError(t, json.Unmarshal(receiver.bodies[i], &delivery))
+				require.Equal(t, request.Header.Get(HeaderDelivery), delivery.ID.String())
+				require.Equal(t, string(eventsmodel.TypeScooterFreed), delivery.Type)
+				require.Equal(t, event, delivery.Data)
+			}
+
+			if tt.wantDeadLetter {
+				require.NotNil(t, letter)
+				require.Equal(t, subscription.ID, letter.SubscriptionID)
+				require.Equal(t, tt.wantAttempts, letter.Attempts)
+				require.Equal(t, receiver.bodies[0], letter.Payload)
+			}
+		})
+	}
+}
+
+func TestDispatchSubscriptionsError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRedis := redisservicemock.NewMockRedisService(controller)
+	mockRedis.EXPECT().GetWebhookSubscriptions(gomock.Any()).Return(nil, redis.ErrClosed)
+
+	ws := NewWebhookService(logger, mockRedis, events.NewMemoryBus(logger), http.DefaultClient,
+		testMaxAttempts, testBackoff)
+
+	err := ws.dispatch(context.Background(), eventsmodel.NewRideErrored(uuid.New(), errors.New("lost"), time.Now()))
+	require.True(t, errors.Is(err, redis.ErrClosed))
+}
+
+func TestDispatchLeavesPendingDeliveryOnly(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	accepting := newTestReceiver(t, http.StatusOK)
+	failing := newTestReceiver(t, http.StatusGone, http.StatusOK)
+
+	accepted := testSubscription(accepting.server.URL, eventsmodel.TypeRideErrored)
+	pending := testSubscription(failing.server.URL, eventsmodel.TypeRideErrored)
+
+	// The subscriptions done with the delivery are remembered, the event read again goes to the pending one only
+	delivered := map[uuid.UUID]bool{}
+
+	mockRedis := redisservicemock.NewMockRedisService(controller)
+	mockRedis.EXPECT().GetWebhookSubscriptions(gomock.Any()).
+		Return([]*redismodel.WebhookSubscription{accepted, pending}, nil).Times(2)
+	mockRedis.EXPECT().GetWebhookDelivered(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID) ([]uuid.UUID, error) {
+			subscriptionIDs := make([]uuid.UUID, 0, len(delivered))
+			for subscriptionID := range delivered {
+				subscriptionIDs = append(subscriptionIDs, subscriptionID)
+			}
+
+			return subscriptionIDs, nil
+		}).Times(2)
+	mockRedis.EXPECT().MarkWebhookDelivered(gomock.Any(), gomock.Any(), gomock.Any(), deliveredTTL).
+		DoAndReturn(func(_ context.Context, _, subscriptionID uuid.UUID, _ time.Duration) error {
+			delivered[subscriptionID] = true
+
+			return nil
+		}).Times(2)
+
+	// The refused delivery fails to be dead lettered, so it is left pending
+	mockRedis.EXPECT().AddWebhookDeadLetter(gomock.Any(), gomock.Any(), int64(maxDeadLetters)).
+		Return(redis.ErrClosed).Times(1)
+
+	ws := NewWebhookService(logger, mockRedis, events.NewMemoryBus(logger), http.DefaultClient,
+		testMaxAttempts, testBackoff)
+
+	event := eventsmodel.NewRideErrored(uuid.New(), errors.New("lost"), time.Now())
+
+	require.ErrorIs(t, ws.dispatch(context.Background(), event), errPending)
+	require.NoError(t, ws.dispatch(context.Background(), event))
+
+	require.Equal(t, 1, accepting.attempts())
+	require.Equal(t, 2, failing.attempts())
+}
+
+func TestStart(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	receiver := newTestReceiver(t, http.StatusOK)
+
+	subscription := testSubscription(receiver.server.URL, eventsmodel.TypeRideErrored)
+
+	mockRedis := redisservicemock.NewMockRedisService(controller)
+	mockRedis.EXPECT().GetWebhookSubscriptions(gomock.Any()).
+		Return([]*redismodel.WebhookSubscription{subscription}, nil).AnyTimes()
+	mockRedis.EXPECT().GetWebhookDelivered(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRedis.EXPECT().MarkWebhookDelivered(gomock.Any(), gomock.Any(), subscription.ID, deliveredTTL).
+		Return(nil).AnyTimes()
+
+	bus := events.NewMemoryBus(logger)
+	ws := NewWebhookService(logger, mockRedis, bus, http.DefaultClient, testMaxAttempts, testBackoff)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, ws.Start(ctx, "instance-1"))
+	event := eventsmodel.NewRideErrored(uuid.New(), errors.New("lost"), time.Now())
+
+	require.NoError(t, bus.Publish(context.Background(), event))
+
+	require.Eventually(t, func() bool {
+		return receiver.attempts() == 1
+	}, testTimeout, 10*time.Millisecond)
+
+	ws.Stop()
+}
+
+func TestStop(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	receiver := newTestReceiver(t, http.StatusServiceUnavailable)
+
+	subscription := testSubscription(receiver.server.URL, eventsmodel.TypeRideErrored)
+
+	// The delivery is stopped while it waits for the next attempt, it is neither retried nor dead lettered
+	mockRedis := redisservicemock.NewMockRedisService(controller)
+	mockRedis.EXPECT().GetWebhookSubscriptions(gomock.Any()).
+		Return([]*redismodel.WebhookSubscription{subscription}, nil).Times(1)
+	mockRedis.EXPECT().GetWebhookDelivered(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+	bus := events.NewMemoryBus(logger)
+	ws := NewWebhookService(logger, mockRedis, bus, http.DefaultClient, testMaxAttempts, time.Hour)
+
+	require.NoError(t, ws.Start(context.Background(), "instance-1"))
+
+	event := eventsmodel.NewRideErrored(uuid.New(), errors.New("lost"), time.Now())
+
+	require.NoError(t, bus.Publish(context.Background(), event))
+
+	require.Eventually(t, func() bool {
+		return receiver.attempts() == 1
+	}, testTimeout, 10*time.Millisecond)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ws.Stop()
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(testTimeout):
+		t.Fatal("stopping waited for the backoff")
+	}
+
+	require.Equal(t, 1, receiver.attempts())
+
+	// Events read after the stop are left pending right away
+	require.ErrorIs(t, ws.dispatch(context.Background(), event), errPending)
+}
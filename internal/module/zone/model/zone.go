@@ -0,0 +1,89 @@
+package model
+
+// ZoneType tells what is allowed within the zone.
+type ZoneType string
+
+const (
+	ZoneOperatingArea ZoneType = "operating_area"
+	ZoneNoParking     ZoneType = "no_parking"
+	ZoneSlow          ZoneType = "slow"
+)
+
+func (t ZoneType) IsValid() bool {
+	switch t {
+	case ZoneOperatingArea, ZoneNoParking, ZoneSlow:
+		return true
+	default:
+		return false
+	}
+}
+
+// Point is a longitude, latitude pair as in GeoJSON.
+type Point [2]float64
+
+// Ring is a closed line, the first ring of a polygon is its boundary and the others are holes.
+type Ring []Point
+
+type Polygon []Ring
+
+type Zone struct {
+	Name     string
+	Type     ZoneType
+	Polygons []Polygon
+}
+
+func NewZone(name string, zoneType ZoneType, polygons []Polygon) *Zone {
+	return &Zone{
+		Name:     name,
+		Type:     zoneType,
+		Polygons: polygons,
+	}
+}
+
+// Contains reports whether the point lies within any of zone's polygons and outside of its holes.
+func (z *Zone) Contains(longitude, latitude float64) bool {
+	for _, polygon := range z.Polygons {
+		if polygon.contains(longitude, latitude) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Polygon) contains(longitude, latitude float64) bool {
+	if len(p) == 0 || !p[0].contains(longitude, latitude) {
+		return false
+	}
+
+	for _, hole := range p[1:] {
+		if hole.contains(longitude, latitude) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contains uses ray casting, zones are small enough to treat coordinates as planar.
+func (r Ring) contains(longitude, latitude float64) bool {
+	inside := false
+
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+
+		if (yi > latitude) != (yj > latitude) && longitude < (xj-xi)*(latitude-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Location describes zones the point lies in, empty zone name means the point is not in a zone of that type.
+type Location struct {
+	InsideOperatingArea bool
+	NoParkingZone       string
+	SlowZone            string
+}
@@ -0,0 +1,117 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"scootinAboot/internal/module/zone/model"
+)
+
+const (
+	zonesFileExtension = ".geojson"
+
+	geometryPolygon      = "Polygon"
+	geometryMultiPolygon = "MultiPolygon"
+)
+
+var (
+	ErrUnsupportedGeometry = errors.New("zone geometry has to be a Polygon or a MultiPolygon")
+	ErrUnknownZoneType     = errors.New("zone property has to be one of operating_area, no_parking or slow")
+)
+
+type featureCollection struct {
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Properties struct {
+		Name string `json:"name"`
+		Zone string `json:"zone"`
+	} `json:"properties"`
+	Geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// LoadZones reads zones of every city from GeoJSON files in the directory, the file name is the name of the city,
+// e.g. Montreal.geojson. Each feature needs a zone property with zone's type.
+func LoadZones(dir string) (map[string][]*model.Zone, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+zonesFileExtension))
+	if err != nil {
+		return nil, fmt.Errorf("listing zones files: %w", err)
+	}
+
+	zones := make(map[string][]*model.Zone, len(files))
+
+	for _, file := range files {
+		city := strings.TrimSuffix(filepath.Base(file), zonesFileExtension)
+
+		zones[city], err = loadCityZones(file)
+		if err != nil {
+			return nil, fmt.Errorf("loading zones of %s: %w", city, err)
+		}
+	}
+
+	return zones, nil
+}
+
+func loadCityZones(path string) ([]*model.Zone, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading zones file: %w", err)
+	}
+
+	var collection featureCollection
+
+	if err = json.Unmarshal(content, &collection); err != nil {
+		return nil, fmt.Errorf("decoding feature collection: %w", err)
+	}
+
+	zones := make([]*model.Zone, 0, len(collection.Features))
+
+	for i := range collection.Features {
+		zoneType := model.ZoneType(collection.Features[i].Properties.Zone)
+		if !zoneType.IsValid() {
+			return nil, fmt.Errorf("feature %d: %w", i, ErrUnknownZoneType)
+		}
+
+		var polygons []model.Polygon
+
+		polygons, err = decodePolygons(collection.Features[i].Geometry.Type, collection.Features[i].Geometry.Coordinates)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		zones = append(zones, model.NewZone(collection.Features[i].Properties.Name, zoneType, polygons))
+	}
+
+	return zones, nil
+}
+
+func decodePolygons(geometryType string, coordinates json.RawMessage) ([]model.Polygon, error) {
+	switch geometryType {
+	case geometryPolygon:
+		var polygon model.Polygon
+
+		if err := json.Unmarshal(coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("decoding polygon: %w", err)
+		}
+
+		return []model.Polygon{polygon}, nil
+	case geometryMultiPolygon:
+		var polygons []model.Polygon
+
+		if err := json.Unmarshal(coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("decoding multipolygon: %w", err)
+		}
+
+		return polygons, nil
+	default:
+		return nil, ErrUnsupportedGeometry
+	}
+}
@@ -0,0 +1,76 @@
+//go:build unit
+
+package transfer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"scootinAboot/internal/module/zone/model"
+)
+
+func TestLoadZones(t *testing.T) {
+	tests := map[string]struct {
+		dir     string
+		want    map[string][]*model.Zone
+		wantErr error
+	}{
+		"successfully loading zones": {
+			dir:     "test_zones/valid",
+			want:    map[string][]*model.Zone{testCity: testZones()},
+			wantErr: nil,
+		},
+		"loading zones from directory without files gives no zones": {
+			dir:     "test_zones/missing",
+			want:    map[string][]*model.Zone{},
+			wantErr: nil,
+		},
+		"loading zones failed, because of unknown zone type": {
+			dir:     "test_zones/unknown_type",
+			want:    nil,
+			wantErr: ErrUnknownZoneType,
+		},
+		"loading zones failed, because of unsupported geometry": {
+			dir:     "test_zones/unsupported_geometry",
+			want:    nil,
+			wantErr: ErrUnsupportedGeometry,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadZones(tt.dir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("LoadZones() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadZones() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadZones("test_zones/invalid_json"); err == nil {
+		t.Errorf("LoadZones() expected error for invalid GeoJSON")
+	}
+}
+
+// testZones mirrors test_zones/valid/Montreal.geojson.
+func testZones() []*model.Zone {
+	return []*model.Zone{
+		model.NewZone("Montreal", model.ZoneOperatingArea, []model.Polygon{
+			{
+				{{60, 50}, {80, 50}, {80, 70}, {60, 70}, {60, 50}},
+				{{75, 65}, {79, 65}, {79, 69}, {75, 69}, {75, 65}},
+			},
+		}),
+		model.NewZone("Docks", model.ZoneNoParking, []model.Polygon{
+			{{{61, 51}, {62, 51}, {62, 52}, {61, 52}, {61, 51}}},
+			{{{63, 53}, {64, 53}, {64, 54}, {63, 54}, {63, 53}}},
+		}),
+		model.NewZone("Downtown", model.ZoneSlow, []model.Polygon{
+			{{{69, 59}, {71, 59}, {71, 61}, {69, 61}, {69, 59}}},
+		}),
+	}
+}
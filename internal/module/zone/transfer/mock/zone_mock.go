@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	model "scootinAboot/internal/module/zone/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockZoneService is a mock of ZoneService interface.
+type MockZoneService struct {
+	ctrl     *gomock.Controller
+	recorder *MockZoneServiceMockRecorder
+}
+
+// MockZoneServiceMockRecorder is the mock recorder for MockZoneService.
+type MockZoneServiceMockRecorder struct {
+	mock *MockZoneService
+}
+
+// NewMockZoneService creates a new mock instance.
+func NewMockZoneService(ctrl *gomock.Controller) *MockZoneService {
+	mock := &MockZoneService{ctrl: ctrl}
+	mock.recorder = &MockZoneServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockZoneService) EXPECT() *MockZoneServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckParking mocks base method.
+func (m *MockZoneService) CheckParking(city string, longitude, latitude float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckParking", city, longitude, latitude)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckParking indicates an expected call of CheckParking.
+func (mr *MockZoneServiceMockRecorder) CheckParking(city, longitude, latitude interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckParking", reflect.TypeOf((*MockZoneService)(nil).CheckParking), city, longitude, latitude)
+}
+
+// Locate mocks base method.
+func (m *MockZoneService) Locate(city string, longitude, latitude float64) *model.Location {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Locate", city, longitude, latitude)
+	ret0, _ := ret[0].(*model.Location)
+	return ret0
+}
+
+// Locate indicates an expected call of Locate.
+func (mr *MockZoneServiceMockRecorder) Locate(city, longitude, latitude interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Locate", reflect.TypeOf((*MockZoneService)(nil).Locate), city, longitude, latitude)
+}
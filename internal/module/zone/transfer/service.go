@@ -0,0 +1,81 @@
+package transfer
+
+import (
+	"log/slog"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/module/zone/model"
+)
+
+var (
+	ErrOutsideOperatingArea = domain.Validation(
+		"outside_operating_area",
+		"scooter can't be parked outside of the operating area",
+	)
+	ErrNoParkingZone = domain.Validation("no_parking_zone", "scooter can't be parked in a no-parking zone")
+)
+
+//go:generate mockgen -source=service.go -destination=mock/zone_mock.go -package=mock
+type ZoneService interface {
+	Locate(city string, longitude, latitude float64) *model.Location
+	CheckParking(city string, longitude, latitude float64) error
+}
+
+type zoneService struct {
+	logger *slog.Logger
+	zones  map[string][]*model.Zone
+}
+
+func NewZoneService(logger *slog.Logger, zones map[string][]*model.Zone) *zoneService {
+	return &zoneService{
+		logger: logger,
+		zones:  zones,
+	}
+}
+
+// Locate finds zones of the city the point lies in. Cities without operating area have no limits, so every point
+// is inside of them.
+func (zs *zoneService) Locate(city string, longitude, latitude float64) *model.Location {
+	location := &model.Location{}
+	hasOperatingArea := false
+
+	for _, zone := range zs.zones[city] {
+		switch zone.Type {
+		case model.ZoneOperatingArea:
+			hasOperatingArea = true
+
+			if zone.Contains(longitude, latitude) {
+				location.InsideOperatingArea = true
+			}
+		case model.ZoneNoParking:
+			if location.NoParkingZone == "" && zone.Contains(longitude, latitude) {
+				location.NoParkingZone = zone.Name
+			}
+		case model.ZoneSlow:
+			if location.SlowZone == "" && zone.Contains(longitude, latitude) {
+				location.SlowZone = zone.Name
+			}
+		}
+	}
+
+	if !hasOperatingArea {
+		location.InsideOperatingArea = true
+	}
+
+	return location
+}
+
+// CheckParking tells whether a ride can end at the point.
+func (zs *zoneService) CheckParking(city string, longitude, latitude float64) error {
+	location := zs.Locate(city, longitude, latitude)
+
+	if !location.InsideOperatingArea {
+		return ErrOutsideOperatingArea
+	}
+
+	if location.NoParkingZone != "" {
+		return ErrNoParkingZone
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+//go:build unit
+
+package transfer
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+
+	"scootinAboot/internal/module/zone/model"
+)
+
+const (
+	testCity        = "Montreal"
+	testUnknownCity = "Toronto"
+)
+
+func TestLocate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	zones := map[string][]*model.Zone{testCity: testZones()}
+
+	tests := map[string]struct {
+		city      string
+		longitude float64
+		latitude  float64
+		want      *model.Location
+	}{
+		"point inside operating area only": {
+			city:      testCity,
+			longitude: 65,
+			latitude:  55,
+			want:      &model.Location{InsideOperatingArea: true},
+		},
+		"point inside slow zone": {
+			city:      testCity,
+			longitude: 70,
+			latitude:  60,
+			want:      &model.Location{InsideOperatingArea: true, SlowZone: "Downtown"},
+		},
+		"point inside second polygon of no-parking zone": {
+			city:      testCity,
+			longitude: 63.5,
+			latitude:  53.5,
+			want:      &model.Location{InsideOperatingArea: true, NoParkingZone: "Docks"},
+		},
+		"point outside operating area": {
+			city:      testCity,
+			longitude: 90,
+			latitude:  60,
+			want:      &model.Location{},
+		},
+		"point inside hole of operating area": {
+			city:      testCity,
+			longitude: 77,
+			latitude:  67,
+			want:      &model.Location{},
+		},
+		"point in city without zones has no limits": {
+			city:      testUnknownCity,
+			longitude: 0,
+			latitude:  0,
+			want:      &model.Location{InsideOperatingArea: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			zs := NewZoneService(logger, zones)
+
+			if got := zs.Locate(tt.city, tt.longitude, tt.latitude); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Locate() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckParking(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	zones := map[string][]*model.Zone{testCity: testZones()}
+
+	tests := map[string]struct {
+		city      string
+		longitude float64
+		latitude  float64
+		wantErr   error
+	}{
+		"parking inside operating area is allowed": {
+			city:      testCity,
+			longitude: 65,
+			latitude:  55,
+			wantErr:   nil,
+		},
+		"parking inside slow zone is allowed": {
+			city:      testCity,
+			longitude: 70,
+			latitude:  60,
+			wantErr:   nil,
+		},
+		"parking inside no-parking zone is refused": {
+			city:      testCity,
+			longitude: 61.5,
+			latitude:  51.5,
+			wantErr:   ErrNoParkingZone,
+		},
+		"parking outside operating area is refused": {
+			city:      testCity,
+			longitude: 59,
+			latitude:  60,
+			wantErr:   ErrOutsideOperatingArea,
+		},
+		"parking in city without zones is allowed": {
+			city:      testUnknownCity,
+			longitude: 59,
+			latitude:  60,
+			wantErr:   nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			zs := NewZoneService(logger, zones)
+
+			if err := zs.CheckParking(tt.city, tt.longitude, tt.latitude); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckParking() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
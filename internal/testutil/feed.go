@@ -0,0 +1,29 @@
+// Package testutil holds the fixtures shared by the tests of several modules.
+package testutil
+
+import (
+	"context"
+
+	redismodel "scootinAboot/internal/module/redis/model"
+)
+
+// ScooterEventFeed delivers the events like Redis does, until ctx is done.
+func ScooterEventFeed(ctx context.Context, events []*redismodel.ScooterEvent) <-chan *redismodel.ScooterEvent {
+	feed := make(chan *redismodel.ScooterEvent)
+
+	go func() {
+		defer close(feed)
+
+		for _, event := range events {
+			select {
+			case feed <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		<-ctx.Done()
+	}()
+
+	return feed
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/domain"
+)
+
+const instrumentationName = "scootinAboot"
+
+const (
+	// ExporterNone keeps propagating the trace context of the requests without recording any span.
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Keys of the attributes telling what the span was about.
+const (
+	KeyScooterUUID = attribute.Key("scooter.uuid")
+	KeyCity        = attribute.Key("scooter.city")
+	KeyClientUUID  = attribute.Key("client.uuid")
+	KeyRole        = attribute.Key("enduser.role")
+	KeyRentalID    = attribute.Key("rental.id")
+	KeyRequestID   = attribute.Key("request.id")
+	KeyErrorCode   = attribute.Key("error.code")
+)
+
+// NewTracerProvider builds the provider of the service sending the spans to the exporter: stdout writes them to w as
+// JSON, otlp sends them over HTTP to the collector at the endpoint, given as host:port.
+func NewTracerProvider(ctx context.Context, exporter, endpoint, serviceName string, w io.Writer) (
+	*sdktrace.TracerProvider,
+	error,
+) {
+	serviceResource := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))
+
+	var spanExporter sdktrace.SpanExporter
+
+	switch exporter {
+	case ExporterNone:
+		return sdktrace.NewTracerProvider(
+			sdktrace.WithResource(serviceResource),
+			sdktrace.WithSampler(sdktrace.NeverSample()),
+		), nil
+	case ExporterStdout:
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("creating stdout exporter: %w", err)
+		}
+
+		spanExporter = stdoutExporter
+	case ExporterOTLP:
+		otlpExporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+		if err != nil {
+			return nil, fmt.Errorf("creating otlp exporter: %w", err)
+		}
+
+		spanExporter = otlpExporter
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithResource(serviceResource),
+		sdktrace.WithBatcher(spanExporter),
+	), nil
+}
+
+// Register makes the provider the one of the whole service and propagates the trace context in W3C headers.
+func Register(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Tracer returns the tracer of the service, it follows the registered provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts the span of the operation as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span with the outcome of the operation. Domain errors are expected answers like a scooter not found,
+// they are recorded with their code but only unavailable dependencies and unknown errors fail the span.
+func End(span trace.Span, err error) {
+	defer span.End()
+
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+
+	if domainErr, ok := domain.As(err); ok {
+		span.SetAttributes(KeyErrorCode.String(domainErr.Code))
+
+		if domainErr.Kind != domain.KindUnavailable {
+			return
+		}
+	}
+
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,137 @@
+//go:build unit
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"scootinAboot/internal/domain"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	tests := map[string]struct {
+		exporter   string
+		wantErr    bool
+		wantOutput string
+	}{
+		"stdout exporter writes spans": {
+			exporter:   ExporterStdout,
+			wantOutput: `"Name":"RentalService.Rent"`,
+		},
+		"none exporter records nothing": {
+			exporter:   ExporterNone,
+			wantOutput: "",
+		},
+		"otlp exporter is created without reaching the collector": {
+			exporter:   ExporterOTLP,
+			wantOutput: "",
+		},
+		"unknown exporter": {
+			exporter: "zipkin",
+			wantErr:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			provider, err := NewTracerProvider(context.Background(), tt.exporter, "localhost:4318", "scootin_aboot", &output)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.exporter != ExporterOTLP {
+				_, span := provider.Tracer(instrumentationName).Start(context.Background(), "RentalService.Rent")
+				span.End()
+			}
+
+			require.NoError(t, provider.Shutdown(context.Background()))
+
+			if tt.wantOutput == "" {
+				require.Empty(t, output.String())
+
+				return
+			}
+
+			require.Contains(t, output.String(), tt.wantOutput)
+			require.Contains(t, output.String(), `"Value":"scootin_aboot"`)
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	tests := map[string]struct {
+		err           error
+		wantStatus    codes.Code
+		wantErrorCode string
+		wantEvents    int
+	}{
+		"successful operation": {
+			err:        nil,
+			wantStatus: codes.Unset,
+		},
+		"expected domain error doesn't fail the span": {
+			err:           fmt.Errorf("getting scooter: %w", domain.NotFound("scooter_not_found", "not found")),
+			wantStatus:    codes.Unset,
+			wantErrorCode: "scooter_not_found",
+			wantEvents:    1,
+		},
+		"unavailable dependency fails the span": {
+			err:           domain.Unavailable(errors.New("connection refused")),
+			wantStatus:    codes.Error,
+			wantErrorCode: "service_unavailable",
+			wantEvents:    1,
+		},
+		"unknown error fails the span": {
+			err:        errors.New("parsing battery"),
+			wantStatus: codes.Error,
+			wantEvents: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			Register(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			_, span := Start(context.Background(), "RedisService.GetScooter", KeyCity.String("Ottawa"))
+			End(span, tt.err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			require.Equal(t, "RedisService.GetScooter", spans[0].Name())
+			require.Equal(t, tt.wantStatus, spans[0].Status().Code)
+			require.Len(t, spans[0].Events(), tt.wantEvents)
+			require.Contains(t, spans[0].Attributes(), KeyCity.String("Ottawa"))
+
+			if tt.wantErrorCode != "" {
+				require.Contains(t, spans[0].Attributes(), KeyErrorCode.String(tt.wantErrorCode))
+			} else {
+				require.NotContains(t, attributeKeys(spans[0].Attributes()), KeyErrorCode)
+			}
+		})
+	}
+}
+
+func attributeKeys(attrs []attribute.KeyValue) []attribute.Key {
+	keys := make([]attribute.Key, 0, len(attrs))
+
+	for _, attr := range attrs {
+		keys = append(keys, attr.Key)
+	}
+
+	return keys
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/model"
+)
+
+const (
+	headerAccept    = "Accept"
+	headerRequestID = "X-Request-ID"
+
+	contentTypeProblemJSON = "application/problem+json"
+
+	// problemTypePrefix is followed by the error code, problems without a code are described by their status only
+	problemTypePrefix = "urn:scootinaboot:problem:"
+	problemTypeBlank  = "about:blank"
+)
+
+var statusByKind = map[domain.Kind]int{
+	domain.KindNotFound:        http.StatusNotFound,
+	domain.KindConflict:        http.StatusConflict,
+	domain.KindForbidden:       http.StatusForbidden,
+	domain.KindUnauthenticated: http.StatusUnauthorized,
+	domain.KindValidation:      http.StatusUnprocessableEntity,
+	domain.KindUnavailable:     http.StatusServiceUnavailable,
+	domain.KindRateLimited:     http.StatusTooManyRequests,
+	domain.KindTimeout:         http.StatusServiceUnavailable,
+}
+
+// problemField adds an extension member to the problem, e.g. the scooter the request was about.
+type problemField func(problem *model.Problem)
+
+func withScooter(scooterUUID uuid.UUID) problemField {
+	return func(problem *model.Problem) {
+		problem.ScooterUUID = scooterUUID.String()
+	}
+}
+
+// ServiceError writes the response for the error returned by a service. The status follows the kind of the domain
+// error, any other error is unexpected and answered with 500.
+func ServiceError(w http.ResponseWriter, r *http.Request, err error, message string, fields ...problemField) {
+	// Services fail by the deadline of the request's context with whatever they were waiting for, the client is told
+	// the request ran out of time instead
+	if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %w", errRequestTimeout, err)
+	}
+
+	Error(w, r, statusOf(err), err, message, fields...)
+}
+
+// RequestError writes the response for the request that could not be decoded or validated. Rejected fields are
+// answered with 422 and listed in the response, a body over the limit with 413 and a request that could not be read
+// at all with 400.
+func RequestError(w http.ResponseWriter, r *http.Request, err error, message string, fields ...problemField) {
+	statusCode := http.StatusBadRequest
+
+	var maxBytesErr *http.MaxBytesError
+
+	if _, ok := domain.As(err); ok {
+		statusCode = statusOf(err)
+	} else if errors.As(err, &maxBytesErr) {
+		statusCode = http.StatusRequestEntityTooLarge
+	}
+
+	Error(w, r, statusCode, err, message, fields...)
+}
+
+// Error writes an error response, problem details unless the client asks for plain JSON only. Only domain errors
+// are described to the client, any other error may carry internal details, so it is replaced with the status text.
+// The error is logged with the request's context either way, server errors at error level.
+func Error(
+	w http.ResponseWriter,
+	r *http.Request,
+	statusCode int,
+	err error,
+	message string,
+	fields ...problemField,
+) {
+	problem := model.Problem{
+		Type:      problemTypeBlank,
+		Title:     http.StatusText(statusCode),
+		Status:    statusCode,
+		Detail:    message + ": " + strings.ToLower(http.StatusText(statusCode)),
+		Instance:  r.URL.Path,
+		Code:      statusCodeName(statusCode),
+		RequestID: r.Header.Get(headerRequestID),
+	}
+
+	description := http.StatusText(statusCode)
+
+	if domainErr, ok := domain.As(err); ok {
+		problem.Type = problemTypePrefix + domainErr.Code
+		problem.Detail = message + ": " + domainErr.Message
+		problem.Code = domainErr.Code
+		description = domainErr.Message
+	}
+
+	if scooterUUID, parseErr := uuid.Parse(mux.Vars(r)[scooterUUIDVar]); parseErr == nil {
+		problem.ScooterUUID = scooterUUID.String()
+	}
+
+	var validationErr *validationError
+
+	if errors.As(err, &validationErr) {
+		problem.InvalidParams = validationErr.params
+	}
+
+	for _, field := range fields {
+		field(&problem)
+	}
+
+	logError(r, statusCode, problem.Code, problem.ScooterUUID, err, message)
+
+	if !acceptsProblem(r) {
+		JSON(w, statusCode, &model.LegacyError{
+			Code:    problem.Code,
+			Error:   description,
+			Message: message,
+
+			InvalidParams: problem.InvalidParams,
+		})
+
+		return
+	}
+
+	writeJSON(w, statusCode, contentTypeProblemJSON, &problem)
+}
+
+// logError logs the error the request failed with, the scooter is added unless the context already carries it.
+func logError(r *http.Request, statusCode int, code, scooterUUID string, err error, message string) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	args := []any{"status", statusCode, "code", code, logging.KeyError, err}
+
+	if _, ok := logging.Attr(r.Context(), logging.KeyScooterUUID); !ok && scooterUUID != "" {
+		args = append(args, logging.KeyScooterUUID, scooterUUID)
+	}
+
+	slog.Default().Log(r.Context(), level, message+" failed", args...)
+}
+
+// acceptsProblem tells whether the client understands problem details. Clients asking for plain JSON without
+// problem details get the legacy error body, everybody else gets problem details.
+func acceptsProblem(r *http.Request) bool {
+	accept := r.Header.Get(headerAccept)
+	if accept == "" {
+		return true
+	}
+
+	acceptsJSON := false
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case contentTypeProblemJSON:
+			return true
+		case contentTypeJSON:
+			acceptsJSON = true
+		}
+	}
+
+	return !acceptsJSON
+}
+
+func statusOf(err error) int {
+	domainErr, ok := domain.As(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	statusCode, ok := statusByKind[domainErr.Kind]
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	return statusCode
+}
+
+// statusCodeName turns the status into a code for errors unknown to the domain, e.g. 400 into bad_request.
+func statusCodeName(statusCode int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(statusCode)), " ", "_")
+}
@@ -0,0 +1,157 @@
+//go:build unit
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	rental "scootinAboot/internal/module/rental/transfer"
+	tracker "scootinAboot/internal/module/tracker/transfer"
+	zone "scootinAboot/internal/module/zone/transfer"
+)
+
+func TestServiceErrorLegacy(t *testing.T) {
+	tests := map[string]struct {
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		"not found error": {
+			err:          fmt.Errorf("getting scooter: %w", redismodel.ErrScooterNotFound),
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"code":"scooter_not_found","Error":"scooter with given UUID was not found","Message":"testing"}`,
+		},
+		"conflict error": {
+			err:          fmt.Errorf("tracking scooter: %w", tracker.ErrRentAlreadyRentedScooter),
+			expectedCode: http.StatusConflict,
+			expectedBody: `{"code":"scooter_already_rented","Error":"this scooter is already rented, choose another one",` +
+				`"Message":"testing"}`,
+		},
+		"forbidden error": {
+			err:          rental.ErrReservationNotOwned,
+			expectedCode: http.StatusForbidden,
+			expectedBody: `{"code":"reservation_not_owned","Error":"reservation belongs to another client","Message":"testing"}`,
+		},
+		"validation error": {
+			err:          fmt.Errorf("checking parking spot: %w", zone.ErrNoParkingZone),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"code":"no_parking_zone","Error":"scooter can't be parked in a no-parking zone","Message":"testing"}`,
+		},
+		"unavailable error hides its cause": {
+			err:          fmt.Errorf("getting scooters from redis: %w", domain.Unavailable(redis.ErrClosed)),
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"code":"service_unavailable","Error":"service is temporarily unavailable, try again later",` +
+				`"Message":"testing"}`,
+		},
+		"unexpected error hides its details": {
+			err:          errors.New("parsing scooter's battery: invalid syntax"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"code":"internal_server_error","Error":"Internal Server Error","Message":"testing"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, version+rentPath, nil)
+			request.Header.Set(headerAccept, contentTypeJSON)
+
+			responseRecorder := httptest.NewRecorder()
+
+			ServiceError(responseRecorder, request, tt.err, "testing")
+
+			require.Equal(t, tt.expectedCode, responseRecorder.Code)
+			require.JSONEq(t, tt.expectedBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestServiceErrorProblem(t *testing.T) {
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		err             error
+		fields          []problemField
+		expectedProblem model.Problem
+	}{
+		"domain error with scooter": {
+			err:    fmt.Errorf("tracking scooter: %w", tracker.ErrRentAlreadyRentedScooter),
+			fields: []problemField{withScooter(scooterUUID)},
+			expectedProblem: model.Problem{
+				Type:        problemTypePrefix + "scooter_already_rented",
+				Title:       "Conflict",
+				Status:      http.StatusConflict,
+				Detail:      "renting scooter: this scooter is already rented, choose another one",
+				Instance:    version + rentPath,
+				Code:        "scooter_already_rented",
+				ScooterUUID: scooterUUID.String(),
+				RequestID:   "request-1",
+			},
+		},
+		"unexpected error hides its details": {
+			err:    errors.New("parsing scooter's battery: invalid syntax"),
+			fields: nil,
+			expectedProblem: model.Problem{
+				Type:      problemTypeBlank,
+				Title:     "Internal Server Error",
+				Status:    http.StatusInternalServerError,
+				Detail:    "renting scooter: internal server error",
+				Instance:  version + rentPath,
+				Code:      "internal_server_error",
+				RequestID: "request-1",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, version+rentPath+"?city=Montreal", nil)
+			request.Header.Set(headerRequestID, "request-1")
+
+			responseRecorder := httptest.NewRecorder()
+
+			ServiceError(responseRecorder, request, tt.err, "renting scooter", tt.fields...)
+
+			require.Equal(t, tt.expectedProblem.Status, responseRecorder.Code)
+			require.Equal(t, contentTypeProblemJSON, responseRecorder.Header().Get(headerContentType))
+
+			var problem model.Problem
+
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+			require.Equal(t, tt.expectedProblem, problem)
+		})
+	}
+}
+
+func TestAcceptsProblem(t *testing.T) {
+	tests := map[string]struct {
+		accept string
+		want   bool
+	}{
+		"no preference":                  {accept: "", want: true},
+		"any media type":                 {accept: "*/*", want: true},
+		"problem details":                {accept: "application/problem+json", want: true},
+		"problem details next to json":   {accept: "application/json, application/problem+json;q=0.9", want: true},
+		"plain json only":                {accept: "application/json", want: false},
+		"plain json with parameters":     {accept: "text/html, application/json; charset=utf-8", want: false},
+		"malformed media range and json": {accept: "%%%, application/json", want: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, version+scootersPath, nil)
+			request.Header.Set(headerAccept, tt.accept)
+
+			require.Equal(t, tt.want, acceptsProblem(request))
+		})
+	}
+}
@@ -2,15 +2,15 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"net/http"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/schema"
 
+	"scootinAboot/internal/auth"
 	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
 	modelrental "scootinAboot/internal/module/rental/model"
 )
 
@@ -19,83 +19,92 @@ const (
 	contentTypeJSON   = "application/json"
 )
 
-var (
-	errExpectedHeaderParamNotFound = errors.New("expected header parameter was not found")
-)
-
 func (s *Server) GetScooters(w http.ResponseWriter, r *http.Request) {
-	if _, err := clientUUIDFromHeader(r); err != nil {
-		fmt.Println(err.Error())
+	var queryParams model.ScooterQueryParams
 
-		Error(w, http.StatusBadRequest, err, "getting clientUUID from header")
+	if err := decodeQuery(r, &queryParams); err != nil {
+		RequestError(w, r, err, "decoding query params")
 
 		return
 	}
 
-	var queryParams model.ScooterQueryParams
-
-	decoder := schema.NewDecoder()
+	if err := s.validateScooterQuery(&queryParams); err != nil {
+		RequestError(w, r, err, "validating query params")
 
-	if err := decoder.Decode(&queryParams, r.URL.Query()); err != nil {
-		fmt.Println(err.Error())
+		return
+	}
 
-		Error(w, http.StatusBadRequest, err, "decoding query params")
+	if err := authorizeCity(r, queryParams.City); err != nil {
+		ServiceError(w, r, err, "authorizing city")
 
 		return
 	}
 
+	statusFilter := redismodel.ScooterStatus(queryParams.Status)
+
 	redisScooters, err := s.redisService.GetScooters(
-		queryParams.Longitude,
-		queryParams.Latitude,
+		r.Context(),
+		*queryParams.Longitude,
+		*queryParams.Latitude,
 		queryParams.Radius,
 		queryParams.City,
 	)
 	if err != nil {
-		fmt.Println(err.Error())
-
-		Error(w, http.StatusBadRequest, err, "getting scooters")
+		ServiceError(w, r, err, "getting scooters")
 
 		return
 	}
 
-	scooters := make([]model.ScooterGet, len(redisScooters))
+	scooters := make([]model.ScooterGet, 0, len(redisScooters))
 
 	for i := range redisScooters {
+		// Scooters with low battery are kept out of riders' sight until they are charged
+		if redisScooters[i].Status == redismodel.StatusLowBattery {
+			continue
+		}
+
+		if statusFilter != "" && redisScooters[i].Status != statusFilter {
+			continue
+		}
+
 		scooterUUID, innerErr := uuid.Parse(redisScooters[i].Scooter.Name)
 		if innerErr != nil {
-			fmt.Println(innerErr.Error())
-
-			Error(w, http.StatusBadRequest, innerErr, "parsing scooter's uuid")
+			ServiceError(w, r, innerErr, "parsing scooter's uuid")
 
 			return
 		}
 
-		scooters[i] = model.ScooterGet{
+		scooters = append(scooters, model.ScooterGet{
 			UUID:         scooterUUID,
 			Latitude:     redisScooters[i].Scooter.Latitude,
 			Longitude:    redisScooters[i].Scooter.Longitude,
-			Availability: redisScooters[i].Availability,
-		}
+			Status:       string(redisScooters[i].Status),
+			Battery:      redisScooters[i].Battery,
+			Availability: redisScooters[i].Status.IsAvailable(),
+		})
 	}
 
 	JSON(w, http.StatusOK, scooters)
 }
 
 func (s *Server) RentScooter(w http.ResponseWriter, r *http.Request) {
-	if _, err := clientUUIDFromHeader(r); err != nil {
-		fmt.Println(err.Error())
-
-		Error(w, http.StatusBadRequest, err, "getting clientUUID from header")
+	clientUUID, err := authenticatedClient(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating client")
 
 		return
 	}
 
 	var scooter model.ScooterPost
 
-	if err := json.NewDecoder(r.Body).Decode(&scooter); err != nil {
-		fmt.Println(err.Error())
+	if err = decodeJSON(r, &scooter); err != nil {
+		RequestError(w, r, err, "decoding request body to scooter")
+
+		return
+	}
 
-		Error(w, http.StatusBadRequest, err, "decoding request body to scooter")
+	if err = validateScooterPost(&scooter); err != nil {
+		RequestError(w, r, err, "validating scooter")
 
 		return
 	}
@@ -106,14 +115,11 @@ func (s *Server) RentScooter(w http.ResponseWriter, r *http.Request) {
 			Longitude: scooter.Longitude,
 			Latitude:  scooter.Latitude,
 		},
-		City:         scooter.City,
-		Availability: scooter.Availability,
+		City: scooter.City,
 	}
 
-	if err := s.rentalService.Rent(&rentalScooter); err != nil {
-		fmt.Println(err.Error())
-
-		Error(w, http.StatusBadRequest, err, "renting scooter")
+	if err = s.rentalService.Rent(r.Context(), clientUUID, &rentalScooter); err != nil {
+		ServiceError(w, r, err, "renting scooter", withScooter(scooter.UUID))
 
 		return
 	}
@@ -122,52 +128,125 @@ func (s *Server) RentScooter(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) FreeScooter(w http.ResponseWriter, r *http.Request) {
-	if _, err := clientUUIDFromHeader(r); err != nil {
-		fmt.Println(err.Error())
-
-		Error(w, http.StatusBadRequest, err, "getting clientUUID from header")
+	clientUUID, err := authenticatedClient(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating client")
 
 		return
 	}
 
 	var scooterUUID uuid.UUID
 
-	if err := json.NewDecoder(r.Body).Decode(&scooterUUID); err != nil {
-		fmt.Println(err.Error())
-
-		Error(w, http.StatusBadRequest, err, "decoding request body to scooter")
+	if err = decodeJSON(r, &scooterUUID); err != nil {
+		RequestError(w, r, err, "decoding request body to scooter")
 
 		return
 	}
 
-	if err := s.rentalService.Free(scooterUUID); err != nil {
-		fmt.Println(err.Error())
+	if err = validateScooterUUID(scooterUUID); err != nil {
+		RequestError(w, r, err, "validating scooter")
 
-		Error(w, http.StatusBadRequest, err, "freeing scooter")
+		return
+	}
+
+	rental, err := s.rentalService.Free(r.Context(), clientUUID, scooterUUID)
+	if err != nil {
+		ServiceError(w, r, err, "freeing scooter", withScooter(scooterUUID))
 
 		return
 	}
 
-	JSON(w, http.StatusNoContent, nil)
+	JSON(w, http.StatusOK, newRentalGet(rental))
 }
 
-func clientUUIDFromHeader(r *http.Request) (uuid.UUID, error) {
-	clientUUIDAsString := r.Header.Get("clientUUID")
-	if len(clientUUIDAsString) == 0 {
-		return uuid.Nil, errExpectedHeaderParamNotFound
+func newRentalGet(rental *redismodel.Rental) model.RentalGet {
+	events := make([]model.RentalEventGet, 0, len(rental.Events))
+
+	for _, event := range rental.Events {
+		events = append(events, model.RentalEventGet{
+			Type:       event.Type,
+			Zone:       event.Zone,
+			Longitude:  event.Longitude,
+			Latitude:   event.Latitude,
+			OccurredAt: event.OccurredAt,
+		})
 	}
 
-	clientUUID, err := uuid.Parse(clientUUIDAsString)
+	return model.RentalGet{
+		ID:              rental.ID,
+		ClientUUID:      rental.ClientUUID,
+		ScooterUUID:     rental.ScooterUUID,
+		City:            rental.City,
+		StartedAt:       rental.StartedAt,
+		EndedAt:         rental.EndedAt,
+		DurationSeconds: int64(rental.Duration().Seconds()),
+		Distance:        rental.Distance,
+		Fare:            rental.Fare,
+		Currency:        rental.Currency,
+		Events:          events,
+	}
+}
+
+// authenticatedClient returns the client whose token authentication verified, only riders act as clients.
+func authenticatedClient(r *http.Request) (uuid.UUID, error) {
+	principal, err := authenticatedPrincipal(r)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("parsing clientUUID: %w", err)
+		return uuid.Nil, err
+	}
+
+	if principal.Role != auth.RoleRider {
+		return uuid.Nil, fmt.Errorf("%w: %s isn't a client", auth.ErrRoleNotPermitted, principal.Role)
+	}
+
+	return principal.Subject, nil
+}
+
+func authenticatedPrincipal(r *http.Request) (*auth.Principal, error) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return nil, auth.ErrMissingToken
 	}
 
-	return clientUUID, nil
+	return principal, nil
+}
+
+// authorizeCity checks the principal may act within the city, operators are kept within their own.
+func authorizeCity(r *http.Request, city string) error {
+	principal, err := authenticatedPrincipal(r)
+	if err != nil {
+		return err
+	}
+
+	return principal.AuthorizeCity(city)
+}
+
+// authorizeScooter checks the principal may act on the scooter, the city of the scooter is looked up for the
+// principals scoped by city only.
+func (s *Server) authorizeScooter(r *http.Request, scooterUUID uuid.UUID) error {
+	principal, err := authenticatedPrincipal(r)
+	if err != nil {
+		return err
+	}
+
+	if !principal.CityScoped() {
+		return nil
+	}
+
+	metadata, err := s.redisService.GetScooterMetadata(r.Context(), scooterUUID)
+	if err != nil {
+		return fmt.Errorf("getting scooter's city: %w", err)
+	}
+
+	return principal.AuthorizeCity(metadata.City)
 }
 
 // JSON writes a JSON response.
 func JSON(w http.ResponseWriter, statusCode int, payload interface{}) {
-	w.Header().Set(headerContentType, contentTypeJSON)
+	writeJSON(w, statusCode, contentTypeJSON, payload)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, contentType string, payload interface{}) {
+	w.Header().Set(headerContentType, contentType)
 	body, err := json.Marshal(payload)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -179,16 +258,3 @@ func JSON(w http.ResponseWriter, statusCode int, payload interface{}) {
 	w.WriteHeader(statusCode)
 	_, _ = w.Write(body)
 }
-
-// Error writes an error response.
-func Error(w http.ResponseWriter, statusCode int, err error, message string) {
-	apiError := struct {
-		Error   string `json:"Error"`
-		Message string `json:"Message"`
-	}{
-		Error:   err.Error(),
-		Message: message,
-	}
-
-	JSON(w, statusCode, &apiError)
-}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+)
+
+func (s *Server) CreateScooter(w http.ResponseWriter, r *http.Request) {
+	var scooter model.ScooterAdminPost
+
+	if err := decodeJSON(r, &scooter); err != nil {
+		RequestError(w, r, err, "decoding request body to scooter")
+
+		return
+	}
+
+	if err := validateScooterAdminPost(&scooter); err != nil {
+		RequestError(w, r, err, "validating scooter")
+
+		return
+	}
+
+	if err := authorizeCity(r, scooter.City); err != nil {
+		ServiceError(w, r, err, "authorizing city")
+
+		return
+	}
+
+	if scooter.UUID == uuid.Nil {
+		scooter.UUID = uuid.New()
+	}
+
+	metadata := redismodel.NewScooterMetadata(
+		scooter.City,
+		redismodel.ScooterStatus(scooter.Status),
+		redismodel.FullBattery,
+	)
+
+	if metadata.Status == "" {
+		metadata.Status = redismodel.StatusAvailable
+	}
+
+	if scooter.Battery != nil {
+		metadata.Battery = *scooter.Battery
+	}
+
+	location := &redis.GeoLocation{
+		Name:      scooter.UUID.String(),
+		Longitude: scooter.Longitude,
+		Latitude:  scooter.Latitude,
+	}
+
+	if err := s.redisService.CreateScooter(r.Context(), location, metadata); err != nil {
+		ServiceError(w, r, err, "creating scooter")
+
+		return
+	}
+
+	JSON(w, http.StatusCreated, model.ScooterAdminGet{
+		UUID:         scooter.UUID,
+		Longitude:    scooter.Longitude,
+		Latitude:     scooter.Latitude,
+		City:         metadata.City,
+		Status:       string(metadata.Status),
+		Battery:      metadata.Battery,
+		Availability: metadata.Status.IsAvailable(),
+	})
+}
+
+func (s *Server) GetScooter(w http.ResponseWriter, r *http.Request) {
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	fleetScooter, err := s.redisService.GetScooter(r.Context(), scooterUUID)
+	if err != nil {
+		ServiceError(w, r, err, "getting scooter")
+
+		return
+	}
+
+	if err = authorizeCity(r, fleetScooter.City); err != nil {
+		ServiceError(w, r, err, "authorizing city")
+
+		return
+	}
+
+	JSON(w, http.StatusOK, model.ScooterAdminGet{
+		UUID:         scooterUUID,
+		Longitude:    fleetScooter.Scooter.Longitude,
+		Latitude:     fleetScooter.Scooter.Latitude,
+		City:         fleetScooter.City,
+		Status:       string(fleetScooter.Status),
+		Battery:      fleetScooter.Battery,
+		Availability: fleetScooter.Status.IsAvailable(),
+	})
+}
+
+func (s *Server) MoveScooter(w http.ResponseWriter, r *http.Request) {
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	var location model.ScooterLocationPut
+
+	if err = decodeJSON(r, &location); err != nil {
+		RequestError(w, r, err, "decoding request body to location")
+
+		return
+	}
+
+	if err = validateScooterLocationPut(&location); err != nil {
+		RequestError(w, r, err, "validating location")
+
+		return
+	}
+
+	// Operators can't take the scooter out of their city, nor bring one from elsewhere
+	if err = s.authorizeScooter(r, scooterUUID); err != nil {
+		ServiceError(w, r, err, "authorizing scooter")
+
+		return
+	}
+
+	if err = authorizeCity(r, location.City); err != nil {
+		ServiceError(w, r, err, "authorizing city")
+
+		return
+	}
+
+	geoLocation := &redis.GeoLocation{
+		Name:      scooterUUID.String(),
+		Longitude: location.Longitude,
+		Latitude:  location.Latitude,
+	}
+
+	if err = s.redisService.MoveScooter(r.Context(), geoLocation, location.City); err != nil {
+		ServiceError(w, r, err, "moving scooter")
+
+		return
+	}
+
+	JSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) ChangeScooterStatus(w http.ResponseWriter, r *http.Request) {
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	var status model.ScooterStatusPut
+
+	if err = decodeJSON(r, &status); err != nil {
+		RequestError(w, r, err, "decoding request body to status")
+
+		return
+	}
+
+	if err = validateScooterStatusPut(&status); err != nil {
+		RequestError(w, r, err, "validating status")
+
+		return
+	}
+
+	if err = s.authorizeScooter(r, scooterUUID); err != nil {
+		ServiceError(w, r, err, "authorizing scooter")
+
+		return
+	}
+
+	err = s.redisService.ChangeScooterStatus(r.Context(), scooterUUID, redismodel.ScooterStatus(status.Status))
+	if err != nil {
+		ServiceError(w, r, err, "changing scooter's status")
+
+		return
+	}
+
+	JSON(w, http.StatusNoContent, nil)
+}
+
+// ReportScooterBattery takes battery level reported by scooter's telemetry.
+func (s *Server) ReportScooterBattery(w http.ResponseWriter, r *http.Request) {
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	var battery model.ScooterBatteryPut
+
+	if err = decodeJSON(r, &battery); err != nil {
+		RequestError(w, r, err, "decoding request body to battery")
+
+		return
+	}
+
+	if err = validateScooterBatteryPut(&battery); err != nil {
+		RequestError(w, r, err, "validating battery")
+
+		return
+	}
+
+	if err = s.authorizeScooter(r, scooterUUID); err != nil {
+		ServiceError(w, r, err, "authorizing scooter")
+
+		return
+	}
+
+	if err = s.trackService.ReportBattery(r.Context(), scooterUUID, battery.Battery); err != nil {
+		ServiceError(w, r, err, "reporting scooter's battery")
+
+		return
+	}
+
+	JSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) DeleteScooter(w http.ResponseWriter, r *http.Request) {
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	if err = s.authorizeScooter(r, scooterUUID); err != nil {
+		ServiceError(w, r, err, "authorizing scooter")
+
+		return
+	}
+
+	if err = s.redisService.DeleteScooter(r.Context(), scooterUUID); err != nil {
+		ServiceError(w, r, err, "deleting scooter")
+
+		return
+	}
+
+	JSON(w, http.StatusNoContent, nil)
+}
+
+func scooterUUIDFromPath(r *http.Request) (uuid.UUID, error) {
+	scooterUUID, err := uuid.Parse(mux.Vars(r)[scooterUUIDVar])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parsing scooterUUID: %w", err)
+	}
+
+	return scooterUUID, nil
+}
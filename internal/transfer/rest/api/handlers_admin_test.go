@@ -0,0 +1,346 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	redisservice "scootinAboot/internal/module/redis/transfer"
+	mockredis "scootinAboot/internal/module/redis/transfer/mock"
+	mocktracker "scootinAboot/internal/module/tracker/transfer/mock"
+)
+
+func TestCreateScooter(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooter := model.ScooterAdminPost{
+		UUID:      scooterUUID,
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+		City:      testCity,
+		Status:    string(redismodel.StatusMaintenance),
+	}
+
+	scooterJSON, err := json.Marshal(scooter)
+	require.NoError(t, err)
+
+	expectedScooterJSON, err := json.Marshal(model.ScooterAdminGet{
+		UUID:         scooterUUID,
+		Longitude:    testLongitude,
+		Latitude:     testLatitude,
+		City:         testCity,
+		Status:       string(redismodel.StatusMaintenance),
+		Battery:      redismodel.FullBattery,
+		Availability: false,
+	})
+	require.NoError(t, err)
+
+	location := &redis.GeoLocation{
+		Name:      scooterUUID.String(),
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+	}
+
+	metadata := redismodel.NewScooterMetadata(testCity, redismodel.StatusMaintenance, redismodel.FullBattery)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
+		body                    []byte
+		token                   string
+		expectedCode            int
+		expectedBody            string
+	}{
+		"successfully creating scooter": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().CreateScooter(gomock.Any(), location, metadata).
+					Return(nil).Times(1)
+			},
+			body:         scooterJSON,
+			token:        testAdminToken(t),
+			expectedCode: http.StatusCreated,
+			expectedBody: string(expectedScooterJSON),
+		},
+		"failed creating scooter because request has no admin credential": {
+			mockRedisServiceHandler: nil,
+			body:                    scooterJSON,
+			token:                   "",
+			expectedCode:            http.StatusUnauthorized,
+			expectedBody: `{"type":"urn:scootinaboot:problem:missing_token","title":"Unauthorized","status":401,` +
+				`"detail":"authenticating request: bearer token is missing",` +
+				`"instance":"/v1/admin/scooters","code":"missing_token"}`,
+		},
+		"failed creating scooter because request has wrong admin credential": {
+			mockRedisServiceHandler: nil,
+			body:                    scooterJSON,
+			token:                   "wrong",
+			expectedCode:            http.StatusUnauthorized,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_token","title":"Unauthorized","status":401,` +
+				`"detail":"authenticating request: token is malformed or its signature is invalid",` +
+				`"instance":"/v1/admin/scooters","code":"invalid_token"}`,
+		},
+		"failed creating scooter because operators don't manage the fleet": {
+			mockRedisServiceHandler: nil,
+			body:                    scooterJSON,
+			token:                   testToken(t, auth.NewOperator(uuid.New(), testCity)),
+			expectedCode:            http.StatusForbidden,
+			expectedBody: `{"type":"urn:scootinaboot:problem:role_not_permitted","title":"Forbidden","status":403,` +
+				`"detail":"authorizing request: role of the token doesn't permit the operation",` +
+				`"instance":"/v1/admin/scooters","code":"role_not_permitted"}`,
+		},
+		"failed creating scooter because redis service threw error": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().CreateScooter(gomock.Any(), location, metadata).
+					Return(errors.New("")).Times(1)
+			},
+			body:         scooterJSON,
+			token:        testAdminToken(t),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"creating scooter: internal server error",` +
+				`"instance":"/v1/admin/scooters","code":"internal_server_error"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+scootersPath, http.MethodPost, tt.body, tt.token)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRedisServiceHandler != nil {
+				tt.mockRedisServiceHandler(mockRedisService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestChangeScooterStatus(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	statusJSON, err := json.Marshal(model.ScooterStatusPut{Status: string(redismodel.StatusLost)})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
+		path                    string
+		token                   string
+		expectedCode            int
+	}{
+		"successfully changing scooter's status": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().ChangeScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusLost).Return(nil).Times(1)
+			},
+			path:         "/scooters/" + scooterUUID.String() + statusPath,
+			token:        testAdminToken(t),
+			expectedCode: http.StatusNoContent,
+		},
+		"successfully changing status of scooter within operator's city": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata(testCity, redismodel.StatusAvailable, redismodel.FullBattery), nil).
+					Times(1)
+				mock.EXPECT().ChangeScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusLost).Return(nil).Times(1)
+			},
+			path:         "/scooters/" + scooterUUID.String() + statusPath,
+			token:        testToken(t, auth.NewOperator(uuid.New(), testCity)),
+			expectedCode: http.StatusNoContent,
+		},
+		"failed changing status of scooter because it is in another city than the operator's": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetScooterMetadata(gomock.Any(), scooterUUID).
+					Return(redismodel.NewScooterMetadata("Ottawa", redismodel.StatusAvailable, redismodel.FullBattery), nil).
+					Times(1)
+			},
+			path:         "/scooters/" + scooterUUID.String() + statusPath,
+			token:        testToken(t, auth.NewOperator(uuid.New(), testCity)),
+			expectedCode: http.StatusForbidden,
+		},
+		"failed changing scooter's status because riders don't operate scooters": {
+			mockRedisServiceHandler: nil,
+			path:                    "/scooters/" + scooterUUID.String() + statusPath,
+			token:                   testClientToken(t, uuid.New()),
+			expectedCode:            http.StatusForbidden,
+		},
+		"failed changing scooter's status because scooter is rented": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().ChangeScooterStatus(gomock.Any(), scooterUUID, redismodel.StatusLost).
+					Return(redisservice.ErrScooterInUse).Times(1)
+			},
+			path:         "/scooters/" + scooterUUID.String() + statusPath,
+			token:        testAdminToken(t),
+			expectedCode: http.StatusConflict,
+		},
+		"failed changing scooter's status because of invalid scooterUUID": {
+			mockRedisServiceHandler: nil,
+			path:                    "/scooters/invalid" + statusPath,
+			token:                   testAdminToken(t),
+			expectedCode:            http.StatusBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+tt.path, http.MethodPut, statusJSON, tt.token)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRedisServiceHandler != nil {
+				tt.mockRedisServiceHandler(mockRedisService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestReportScooterBattery(t *testing.T) {
+	s, _, _, mockTrackerService, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	batteryJSON, err := json.Marshal(model.ScooterBatteryPut{Battery: 42})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockTrackerServiceHandler func(mock *mocktracker.MockTrackerService)
+		path                      string
+		expectedCode              int
+	}{
+		"successfully reporting scooter's battery": {
+			mockTrackerServiceHandler: func(mock *mocktracker.MockTrackerService) {
+				mock.EXPECT().ReportBattery(gomock.Any(), scooterUUID, 42.0).Return(nil).Times(1)
+			},
+			path:         "/scooters/" + scooterUUID.String() + batteryPath,
+			expectedCode: http.StatusNoContent,
+		},
+		"failed reporting scooter's battery because tracker service threw error": {
+			mockTrackerServiceHandler: func(mock *mocktracker.MockTrackerService) {
+				mock.EXPECT().ReportBattery(gomock.Any(), scooterUUID, 42.0).Return(errors.New("")).Times(1)
+			},
+			path:         "/scooters/" + scooterUUID.String() + batteryPath,
+			expectedCode: http.StatusInternalServerError,
+		},
+		"failed reporting scooter's battery because of invalid scooterUUID": {
+			mockTrackerServiceHandler: nil,
+			path:                      "/scooters/invalid" + batteryPath,
+			expectedCode:              http.StatusBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+tt.path, http.MethodPut, batteryJSON, testAdminToken(t))
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockTrackerServiceHandler != nil {
+				tt.mockTrackerServiceHandler(mockTrackerService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestDeleteScooter(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
+		expectedCode            int
+	}{
+		"successfully deleting scooter": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().DeleteScooter(gomock.Any(), scooterUUID).Return(nil).Times(1)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		"failed deleting scooter because it does not exist": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().DeleteScooter(gomock.Any(), scooterUUID).Return(redismodel.ErrScooterNotFound).Times(1)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		"failed deleting scooter because redis service threw error": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().DeleteScooter(gomock.Any(), scooterUUID).Return(errors.New("")).Times(1)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(
+				t,
+				adminPath+"/scooters/"+scooterUUID.String(),
+				http.MethodDelete,
+				nil,
+				testAdminToken(t),
+			)
+
+			responseRecorder := httptest.NewRecorder()
+
+			tt.mockRedisServiceHandler(mockRedisService)
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func buildAdminRequest(t *testing.T, path string, method string, body []byte, token string) *http.Request {
+	t.Helper()
+
+	request := buildRequest(t, path, method, bytes.NewBuffer(body), false)
+
+	if token != "" {
+		request.Header.Set(headerAuthorization, bearerPrefix+token)
+	}
+
+	return request
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/model"
+)
+
+const (
+	checkRedis       = "redis"
+	checkTracker     = "tracker"
+	checkServer      = "server"
+	checkOK          = "ok"
+	checkUnavailable = "unavailable" // the cause is logged, the public probes don't get it
+)
+
+// GetHealth tells the process is alive, it doesn't look at its dependencies.
+func (s *Server) GetHealth(w http.ResponseWriter, _ *http.Request) {
+	JSON(w, http.StatusOK, model.HealthGet{Status: model.HealthStatusOK})
+}
+
+// GetReadiness tells whether the instance may be sent traffic, it isn't once Redis doesn't answer in time or the
+// instance shuts down.
+func (s *Server) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	checks, ready, _ := s.readiness(r.Context())
+
+	readiness := model.ReadinessGet{Status: model.HealthStatusReady, Checks: checks}
+	if !ready {
+		readiness.Status = model.HealthStatusNotReady
+		JSON(w, http.StatusServiceUnavailable, readiness)
+
+		return
+	}
+
+	JSON(w, http.StatusOK, readiness)
+}
+
+// GetStatus reports the instance to operators, it succeeds even when the instance isn't ready.
+func (s *Server) GetStatus(w http.ResponseWriter, r *http.Request) {
+	checks, ready, redisLatency := s.readiness(r.Context())
+
+	status := model.StatusGet{
+		Status:       model.HealthStatusReady,
+		Checks:       checks,
+		RedisLatency: float64(redisLatency.Microseconds()) / 1000,
+		ActiveRides:  s.trackService.ActiveRides(),
+		StartedAt:    s.startedAt,
+		Build:        buildGet(),
+	}
+	if !ready {
+		status.Status = model.HealthStatusNotReady
+	}
+
+	JSON(w, http.StatusOK, status)
+}
+
+// readiness runs the checks of the instance, Redis has to answer the ping within the readiness timeout.
+func (s *Server) readiness(ctx context.Context) (map[string]string, bool, time.Duration) {
+	checks := map[string]string{checkRedis: checkOK, checkTracker: checkOK, checkServer: checkOK}
+	ready := true
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.ReadinessTimeoutMilliseconds)*time.Millisecond)
+	defer cancel()
+
+	pingedAt := time.Now()
+	err := s.redisService.Ping(ctx)
+	redisLatency := time.Since(pingedAt)
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "redis is not ready", logging.KeyError, err)
+
+		checks[checkRedis], ready = checkUnavailable, false
+	}
+
+	if s.trackService.Stopping() {
+		checks[checkTracker], ready = "shutting down", false
+	}
+
+	if s.shuttingDown.Load() {
+		checks[checkServer], ready = "shutting down", false
+	}
+
+	return checks, ready, redisLatency
+}
+
+// buildGet describes the binary from the information the Go toolchain embeds in it.
+func buildGet() model.BuildGet {
+	build := model.BuildGet{Version: "unknown", GoVersion: runtime.Version()}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+
+	build.Version = info.Main.Version
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			build.Revision = setting.Value
+		}
+	}
+
+	return build
+}
@@ -0,0 +1,143 @@
+//go:build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+)
+
+func TestGetHealth(t *testing.T) {
+	s, _, _, _, _, _, _ := beforeTest(t)
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, healthPath, nil)
+	require.NoError(t, err)
+
+	responseRecorder := httptest.NewRecorder()
+
+	s.router.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, `{"status":"ok"}`, responseRecorder.Body.String())
+}
+
+func TestGetReadiness(t *testing.T) {
+	redisDown := fmt.Errorf("checking redis: %w", domain.Unavailable(redis.ErrClosed))
+
+	tests := map[string]struct {
+		pingErr        error
+		trackerStopped bool
+		shuttingDown   bool
+		expectedCode   int
+		expectedBody   string
+	}{
+		"instance is ready": {
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status":"ready","checks":{"redis":"ok","server":"ok","tracker":"ok"}}`,
+		},
+		"instance isn't ready, because redis is unavailable": {
+			pingErr:      redisDown,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"not_ready","checks":{"redis":"unavailable","server":"ok","tracker":"ok"}}`,
+		},
+		"instance isn't ready, because it shuts down": {
+			trackerStopped: true,
+			shuttingDown:   true,
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedBody: `{"status":"not_ready","checks":{"redis":"ok","server":"shutting down",` +
+				`"tracker":"shutting down"}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, mockRedisService, _, mockTrackerService, _, _, _ := beforeTest(t)
+
+			s.shuttingDown.Store(tt.shuttingDown)
+
+			mockRedisService.EXPECT().Ping(gomock.Any()).
+				DoAndReturn(func(ctx context.Context) error {
+					// The ping may not take longer than the readiness timeout
+					deadline, ok := ctx.Deadline()
+					require.True(t, ok)
+					require.WithinDuration(t, time.Now(), deadline, testReadinessTimeoutMilliseconds*time.Millisecond)
+
+					return tt.pingErr
+				}).Times(1)
+			mockTrackerService.EXPECT().Stopping().Return(tt.trackerStopped).Times(1)
+
+			request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, readyPath, nil)
+			require.NoError(t, err)
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.expectedCode, responseRecorder.Code)
+			require.Equal(t, tt.expectedBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	tests := map[string]struct {
+		pingErr        error
+		expectedStatus model.HealthStatus
+	}{
+		"instance is ready": {
+			pingErr:        nil,
+			expectedStatus: model.HealthStatusReady,
+		},
+		"status is reported, even though redis is unavailable": {
+			pingErr:        domain.Unavailable(redis.ErrClosed),
+			expectedStatus: model.HealthStatusNotReady,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, mockRedisService, _, mockTrackerService, _, _, _ := beforeTest(t)
+
+			mockRedisService.EXPECT().Ping(gomock.Any()).Return(tt.pingErr).Times(1)
+			mockTrackerService.EXPECT().Stopping().Return(false).Times(1)
+			mockTrackerService.EXPECT().ActiveRides().Return(3).Times(1)
+
+			request := buildAdminRequest(t, adminPath+adminStatusPath, http.MethodGet, nil, testAdminToken(t))
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+			var status model.StatusGet
+
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &status))
+			require.Equal(t, tt.expectedStatus, status.Status)
+			require.Equal(t, 3, status.ActiveRides)
+			require.Equal(t, s.startedAt, status.StartedAt)
+			require.NotEmpty(t, status.Build.GoVersion)
+			require.GreaterOrEqual(t, status.RedisLatency, 0.0)
+		})
+	}
+}
+
+func TestGetStatusRequiresAdminToken(t *testing.T) {
+	s, _, _, _, _, _, _ := beforeTest(t)
+
+	request := buildAdminRequest(t, adminPath+adminStatusPath, http.MethodGet, nil, "wrong_token")
+	responseRecorder := httptest.NewRecorder()
+
+	s.router.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+)
+
+const (
+	defaultRentalsLimit = 20
+	maxRentalsLimit     = 100
+)
+
+const reasonTimestamp = "has to be an RFC 3339 timestamp"
+
+var errForeignRentals = domain.Forbidden("foreign_rentals", "rentals of other clients can't be viewed")
+
+// GetClientRentals returns the ride history of the client, clients can only see their own rides.
+func (s *Server) GetClientRentals(w http.ResponseWriter, r *http.Request) {
+	clientUUID, err := authenticatedClient(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating client")
+
+		return
+	}
+
+	pathClientUUID, err := uuid.Parse(mux.Vars(r)[clientUUIDVar])
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting clientUUID from path")
+
+		return
+	}
+
+	if pathClientUUID != clientUUID {
+		Error(w, r, http.StatusForbidden, errForeignRentals, "getting client's rentals")
+
+		return
+	}
+
+	query, err := rentalsQueryFromRequest(r)
+	if err != nil {
+		RequestError(w, r, err, "decoding query params")
+
+		return
+	}
+
+	page, err := s.redisService.GetClientRentals(r.Context(), clientUUID, query)
+	if err != nil {
+		ServiceError(w, r, err, "getting client's rentals")
+
+		return
+	}
+
+	JSON(w, http.StatusOK, newRentalsGet(page))
+}
+
+// GetScooterRentals returns the ride history of the scooter, it is meant for the fleet operators only.
+func (s *Server) GetScooterRentals(w http.ResponseWriter, r *http.Request) {
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	if err = s.authorizeScooter(r, scooterUUID); err != nil {
+		ServiceError(w, r, err, "authorizing scooter")
+
+		return
+	}
+
+	query, err := rentalsQueryFromRequest(r)
+	if err != nil {
+		RequestError(w, r, err, "decoding query params")
+
+		return
+	}
+
+	page, err := s.redisService.GetScooterRentals(r.Context(), scooterUUID, query)
+	if err != nil {
+		ServiceError(w, r, err, "getting scooter's rentals")
+
+		return
+	}
+
+	JSON(w, http.StatusOK, newRentalsGet(page))
+}
+
+// GetActiveRentals returns the rides going on in the city, operators see the ones of their own city when they leave
+// the city out.
+func (s *Server) GetActiveRentals(w http.ResponseWriter, r *http.Request) {
+	principal, err := authenticatedPrincipal(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating request")
+
+		return
+	}
+
+	var queryParams model.ActiveRentalsQueryParams
+
+	if err = decodeQuery(r, &queryParams); err != nil {
+		RequestError(w, r, err, "decoding query params")
+
+		return
+	}
+
+	if queryParams.City == "" && principal.CityScoped() {
+		queryParams.City = principal.City
+	}
+
+	if err = validateActiveRentalsQuery(&queryParams); err != nil {
+		RequestError(w, r, err, "validating query params")
+
+		return
+	}
+
+	if err = principal.AuthorizeCity(queryParams.City); err != nil {
+		ServiceError(w, r, err, "authorizing city")
+
+		return
+	}
+
+	rentals, err := s.redisService.GetActiveRentals(r.Context(), queryParams.City)
+	if err != nil {
+		ServiceError(w, r, err, "getting active rentals")
+
+		return
+	}
+
+	result := model.ActiveRentalsGet{Rentals: make([]model.RentalGet, 0, len(rentals))}
+
+	for _, rental := range rentals {
+		result.Rentals = append(result.Rentals, newRentalGet(rental))
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+func rentalsQueryFromRequest(r *http.Request) (*redismodel.RentalsQuery, error) {
+	var queryParams model.RentalsQueryParams
+
+	if err := decodeQuery(r, &queryParams); err != nil {
+		return nil, err
+	}
+
+	if queryParams.Limit == 0 {
+		queryParams.Limit = defaultRentalsLimit
+	}
+
+	v := &validator{}
+
+	v.between("limit", float64(queryParams.Limit), 1, maxRentalsLimit)
+
+	from, fromErr := parseTimestamp(queryParams.From)
+	v.check(fromErr == nil, "from", reasonTimestamp)
+
+	to, toErr := parseTimestamp(queryParams.To)
+	v.check(toErr == nil, "to", reasonTimestamp)
+
+	v.check(from.IsZero() || to.IsZero() || !from.After(to), "from", "has to be before to")
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return redismodel.NewRentalsQuery(from, to, queryParams.Cursor, queryParams.Limit), nil
+}
+
+// parseTimestamp parses RFC 3339 timestamp, an empty one leaves the time range open.
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing timestamp: %w", err)
+	}
+
+	return timestamp, nil
+}
+
+func newRentalsGet(page *redismodel.RentalsPage) model.RentalsGet {
+	rentals := make([]model.RentalGet, 0, len(page.Rentals))
+
+	for _, rental := range page.Rentals {
+		rentals = append(rentals, newRentalGet(rental))
+	}
+
+	return model.RentalsGet{
+		Rentals:    rentals,
+		NextCursor: page.NextCursor,
+	}
+}
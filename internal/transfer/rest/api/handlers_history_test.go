@@ -0,0 +1,285 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	mockredis "scootinAboot/internal/module/redis/transfer/mock"
+)
+
+func TestGetClientRentals(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	startedAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	rental := redismodel.NewRental(uuid.New(), clientUUID, uuid.New(), testCity, startedAt)
+	rental.EndedAt = startedAt.Add(10 * time.Minute)
+	rental.Distance = 1500
+	rental.Fare = 4.5
+	rental.Currency = "CAD"
+
+	page := &redismodel.RentalsPage{Rentals: []*redismodel.Rental{rental}, NextCursor: "next"}
+
+	expectedRentalsJSON, err := json.Marshal(model.RentalsGet{
+		Rentals:    []model.RentalGet{newRentalGet(rental)},
+		NextCursor: "next",
+	})
+	require.NoError(t, err)
+
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
+		pathClientUUID          uuid.UUID
+		query                   string
+		expectedCode            int
+		expectedBody            string
+	}{
+		"successfully getting client's rentals with default limit": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().
+					GetClientRentals(
+						gomock.Any(),
+						clientUUID,
+						redismodel.NewRentalsQuery(time.Time{}, time.Time{}, "", defaultRentalsLimit),
+					).
+					Return(page, nil).Times(1)
+			},
+			pathClientUUID: clientUUID,
+			query:          "",
+			expectedCode:   http.StatusOK,
+			expectedBody:   string(expectedRentalsJSON),
+		},
+		"successfully getting client's rentals within time range": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().
+					GetClientRentals(gomock.Any(), clientUUID, redismodel.NewRentalsQuery(from, to, "next", 5)).
+					Return(page, nil).Times(1)
+			},
+			pathClientUUID: clientUUID,
+			query:          "?from=2024-01-01T00:00:00Z&to=2024-01-31T00:00:00Z&cursor=next&limit=5",
+			expectedCode:   http.StatusOK,
+			expectedBody:   string(expectedRentalsJSON),
+		},
+		"failed getting rentals because they belong to other client": {
+			mockRedisServiceHandler: nil,
+			pathClientUUID:          uuid.New(),
+			query:                   "",
+			expectedCode:            http.StatusForbidden,
+			expectedBody:            "",
+		},
+		"failed getting rentals because limit is too high": {
+			mockRedisServiceHandler: nil,
+			pathClientUUID:          clientUUID,
+			query:                   "?limit=1000",
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody:            "",
+		},
+		"failed getting rentals because time range is reversed": {
+			mockRedisServiceHandler: nil,
+			pathClientUUID:          clientUUID,
+			query:                   "?from=2024-01-31T00:00:00Z&to=2024-01-01T00:00:00Z",
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody:            "",
+		},
+		"failed getting rentals because from is not a timestamp": {
+			mockRedisServiceHandler: nil,
+			pathClientUUID:          clientUUID,
+			query:                   "?from=yesterday&limit=0.5",
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity","status":422,` +
+				`"detail":"decoding query params: request has invalid fields","instance":"/v1/clients/` + clientUUID.String() +
+				`/rentals","code":"invalid_request","invalidParams":[{"name":"limit","reason":"has to be a number"}]}`,
+		},
+		"failed getting rentals because redis service threw error": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetClientRentals(gomock.Any(), clientUUID, gomock.Any()).
+					Return(nil, redismodel.ErrInvalidCursor).Times(1)
+			},
+			pathClientUUID: clientUUID,
+			query:          "?cursor=broken",
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedBody:   "",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildClientRequest(t, "/clients/"+tt.pathClientUUID.String()+rentalsPath+tt.query,
+				http.MethodGet, &bytes.Buffer{}, clientUUID)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRedisServiceHandler != nil {
+				tt.mockRedisServiceHandler(mockRedisService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, responseRecorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetScooterRentals(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
+		token                   string
+		expectedCode            int
+	}{
+		"successfully getting scooter's rentals": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().
+					GetScooterRentals(
+						gomock.Any(),
+						scooterUUID,
+						redismodel.NewRentalsQuery(time.Time{}, time.Time{}, "", defaultRentalsLimit),
+					).
+					Return(&redismodel.RentalsPage{}, nil).Times(1)
+			},
+			token:        testAdminToken(t),
+			expectedCode: http.StatusOK,
+		},
+		"failed getting scooter's rentals because admin token is missing": {
+			mockRedisServiceHandler: nil,
+			token:                   "",
+			expectedCode:            http.StatusUnauthorized,
+		},
+		"failed getting scooter's rentals because redis service threw error": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetScooterRentals(gomock.Any(), scooterUUID, gomock.Any()).Return(nil, errors.New("")).Times(1)
+			},
+			token:        testAdminToken(t),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, scootersPath+"/"+scooterUUID.String()+rentalsPath,
+				http.MethodGet, nil, tt.token)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRedisServiceHandler != nil {
+				tt.mockRedisServiceHandler(mockRedisService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestGetActiveRentals(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	rental := redismodel.NewRental(uuid.New(), uuid.New(), uuid.New(), testCity, time.Now().UTC())
+
+	expectedRentalsJSON, err := json.Marshal(model.ActiveRentalsGet{Rentals: []model.RentalGet{newRentalGet(rental)}})
+	require.NoError(t, err)
+
+	operatorToken := testToken(t, auth.NewOperator(uuid.New(), testCity))
+
+	tests := map[string]struct {
+		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
+		query                   string
+		token                   string
+		expectedCode            int
+		expectedBody            string
+	}{
+		"successfully getting active rentals of the city": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetActiveRentals(gomock.Any(), testCity).Return([]*redismodel.Rental{rental}, nil).Times(1)
+			},
+			query:        "?city=" + testCity,
+			token:        testAdminToken(t),
+			expectedCode: http.StatusOK,
+			expectedBody: string(expectedRentalsJSON),
+		},
+		"successfully getting active rentals of operator's city by default": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetActiveRentals(gomock.Any(), testCity).Return([]*redismodel.Rental{}, nil).Times(1)
+			},
+			token:        operatorToken,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"rentals":[]}`,
+		},
+		"failed getting active rentals because the city is missing": {
+			mockRedisServiceHandler: nil,
+			token:                   testAdminToken(t),
+			expectedCode:            http.StatusUnprocessableEntity,
+		},
+		"failed getting active rentals because the city isn't operator's": {
+			mockRedisServiceHandler: nil,
+			query:                   "?city=Ottawa",
+			token:                   operatorToken,
+			expectedCode:            http.StatusForbidden,
+		},
+		"failed getting active rentals because riders don't operate scooters": {
+			mockRedisServiceHandler: nil,
+			query:                   "?city=" + testCity,
+			token:                   testClientToken(t, uuid.New()),
+			expectedCode:            http.StatusForbidden,
+		},
+		"failed getting active rentals because redis service threw error": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetActiveRentals(gomock.Any(), testCity).Return(nil, errors.New("")).Times(1)
+			},
+			query:        "?city=" + testCity,
+			token:        testAdminToken(t),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+rentalsPath+tt.query, http.MethodGet, nil, tt.token)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRedisServiceHandler != nil {
+				tt.mockRedisServiceHandler(mockRedisService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.expectedCode, responseRecorder.Code)
+
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, responseRecorder.Body.String())
+			}
+		})
+	}
+}
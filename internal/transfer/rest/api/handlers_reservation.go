@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"scootinAboot/internal/model"
+)
+
+// ReserveScooter holds the scooter for the client while they walk to it.
+func (s *Server) ReserveScooter(w http.ResponseWriter, r *http.Request) {
+	clientUUID, err := authenticatedClient(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating client")
+
+		return
+	}
+
+	var reservation model.ReservationPost
+
+	if err = decodeJSON(r, &reservation); err != nil {
+		RequestError(w, r, err, "decoding request body to reservation")
+
+		return
+	}
+
+	if err = validateReservationPost(&reservation); err != nil {
+		RequestError(w, r, err, "validating reservation")
+
+		return
+	}
+
+	rentalReservation, err := s.rentalService.Reserve(r.Context(), clientUUID, reservation.ScooterUUID)
+	if err != nil {
+		ServiceError(w, r, err, "reserving scooter", withScooter(reservation.ScooterUUID))
+
+		return
+	}
+
+	JSON(w, http.StatusCreated, model.ReservationGet{
+		ScooterUUID: rentalReservation.ScooterUUID,
+		ExpiresAt:   rentalReservation.ExpiresAt,
+	})
+}
+
+func (s *Server) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	clientUUID, err := authenticatedClient(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating client")
+
+		return
+	}
+
+	scooterUUID, err := scooterUUIDFromPath(r)
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting scooterUUID from path")
+
+		return
+	}
+
+	if err = s.rentalService.CancelReservation(r.Context(), clientUUID, scooterUUID); err != nil {
+		ServiceError(w, r, err, "cancelling reservation")
+
+		return
+	}
+
+	JSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,160 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/model"
+	rentalmodel "scootinAboot/internal/module/rental/model"
+	rental "scootinAboot/internal/module/rental/transfer"
+	mockrental "scootinAboot/internal/module/rental/transfer/mock"
+)
+
+func TestReserveScooter(t *testing.T) {
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	expiresAt := time.Date(2024, time.January, 1, 12, 10, 0, 0, time.UTC)
+
+	reservationJSON, err := json.Marshal(model.ReservationPost{ScooterUUID: scooterUUID})
+	require.NoError(t, err)
+
+	expectedReservationJSON, err := json.Marshal(model.ReservationGet{
+		ScooterUUID: scooterUUID,
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRentalServiceHandler func(mock *mockrental.MockRentalService)
+		body                     []byte
+		withToken                bool
+		expectedCode             int
+		expectedBody             string
+	}{
+		"successfully reserving scooter": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Reserve(gomock.Any(), gomock.Any(), scooterUUID).
+					Return(rentalmodel.NewReservation(scooterUUID, uuid.New(), expiresAt), nil).Times(1)
+			},
+			body:         reservationJSON,
+			withToken:    true,
+			expectedCode: http.StatusCreated,
+			expectedBody: string(expectedReservationJSON),
+		},
+		"failed reserving scooter because request has no token": {
+			mockRentalServiceHandler: nil,
+			body:                     reservationJSON,
+			withToken:                false,
+			expectedCode:             http.StatusUnauthorized,
+			expectedBody: `{"type":"urn:scootinaboot:problem:missing_token","title":"Unauthorized","status":401,` +
+				`"detail":"authenticating request: bearer token is missing",` +
+				`"instance":"/v1/reservations","code":"missing_token"}`,
+		},
+		"failed reserving scooter because rental service threw error": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Reserve(gomock.Any(), gomock.Any(), scooterUUID).Return(nil, errors.New("")).Times(1)
+			},
+			body:         reservationJSON,
+			withToken:    true,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"reserving scooter: internal server error",` +
+				`"instance":"/v1/reservations","code":"internal_server_error","scooterUUID":"` + scooterUUID.String() + `"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildRequest(t, reservationsPath, http.MethodPost, bytes.NewBuffer(tt.body), tt.withToken)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRentalServiceHandler != nil {
+				tt.mockRentalServiceHandler(mockRentalService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestCancelReservation(t *testing.T) {
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRentalServiceHandler func(mock *mockrental.MockRentalService)
+		path                     string
+		expectedCode             int
+	}{
+		"successfully cancelling reservation": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), scooterUUID).Return(nil).Times(1)
+			},
+			path:         reservationsPath + "/" + scooterUUID.String(),
+			expectedCode: http.StatusNoContent,
+		},
+		"failed cancelling reservation because it belongs to another client": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), scooterUUID).
+					Return(rental.ErrReservationNotOwned).Times(1)
+			},
+			path:         reservationsPath + "/" + scooterUUID.String(),
+			expectedCode: http.StatusForbidden,
+		},
+		"failed cancelling reservation because rental service threw error": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), scooterUUID).Return(errors.New("")).Times(1)
+			},
+			path:         reservationsPath + "/" + scooterUUID.String(),
+			expectedCode: http.StatusInternalServerError,
+		},
+		"failed cancelling reservation because of invalid scooterUUID": {
+			mockRentalServiceHandler: nil,
+			path:                     reservationsPath + "/invalid",
+			expectedCode:             http.StatusBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildRequest(t, tt.path, http.MethodDelete, &bytes.Buffer{}, true)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRentalServiceHandler != nil {
+				tt.mockRentalServiceHandler(mockRentalService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/model"
+	availabilitymodel "scootinAboot/internal/module/availability/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	rentalmodel "scootinAboot/internal/module/rental/model"
+)
+
+const (
+	scootersStreamPath = scootersPath + "/stream"
+	rentalLivePath     = rentalsPath + "/{" + rentalIDVar + "}/live"
+
+	contentTypeEventStream = "text/event-stream"
+	headerCacheControl     = "Cache-Control"
+	headerAccelBuffering   = "X-Accel-Buffering"
+
+	eventSnapshot = "snapshot"
+	eventResync   = "resync"
+	eventProgress = "progress"
+	eventEnded    = "ended"
+)
+
+var errStreamingUnsupported = errors.New("response writer can't flush")
+
+// StreamScooters follows the scooters within the area as server-sent events. The stream starts with the snapshot
+// of the area followed by an event per change. When the client can't keep up, it gets the resync event and the
+// stream is closed, so it has to connect again and start over with a new snapshot.
+func (s *Server) StreamScooters(w http.ResponseWriter, r *http.Request) {
+	var queryParams model.ScooterStreamQueryParams
+
+	if err := decodeQuery(r, &queryParams); err != nil {
+		RequestError(w, r, err, "decoding query params")
+
+		return
+	}
+
+	if err := s.validateScooterStreamQuery(&queryParams); err != nil {
+		RequestError(w, r, err, "validating query params")
+
+		return
+	}
+
+	if err := authorizeCity(r, queryParams.City); err != nil {
+		ServiceError(w, r, err, "authorizing city")
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		Error(w, r, http.StatusInternalServerError, errStreamingUnsupported, "streaming scooters")
+
+		return
+	}
+
+	subscription, err := s.availabilityService.Subscribe(r.Context(), streamArea(&queryParams))
+	if err != nil {
+		ServiceError(w, r, err, "subscribing to scooters")
+
+		return
+	}
+
+	startEventStream(w)
+
+	snapshot := make([]model.ScooterEventGet, 0, len(subscription.Snapshot))
+
+	for _, event := range subscription.Snapshot {
+		if event.Status != redismodel.StatusLowBattery {
+			snapshot = append(snapshot, scooterEventGet(event))
+		}
+	}
+
+	writeEvent(w, eventSnapshot, snapshot)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(time.Duration(s.config.StreamHeartbeatSeconds) * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streams.Done():
+			return
+		case <-heartbeat.C:
+			writeHeartbeat(w)
+		case event, open := <-subscription.Updates:
+			if !open {
+				if subscription.Err != nil {
+					s.logger.WarnContext(r.Context(), "closing scooters stream", logging.KeyError, subscription.Err)
+
+					writeEvent(w, eventResync, streamProblem(r, subscription.Err))
+					flusher.Flush()
+				}
+
+				return
+			}
+
+			update := scooterEventGet(event)
+
+			writeEvent(w, update.Type, update)
+		}
+
+		flusher.Flush()
+	}
+}
+
+// StreamRide follows the client's ride as server-sent events. The stream starts with the current progress of the
+// ride, a progress event follows every move of the scooter and the ended event with the charged fare closes it.
+func (s *Server) StreamRide(w http.ResponseWriter, r *http.Request) {
+	clientUUID, err := authenticatedClient(r)
+	if err != nil {
+		ServiceError(w, r, err, "authenticating client")
+
+		return
+	}
+
+	rentalID, err := uuid.Parse(mux.Vars(r)[rentalIDVar])
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, err, "getting rentalID from path")
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		Error(w, r, http.StatusInternalServerError, errStreamingUnsupported, "streaming ride")
+
+		return
+	}
+
+	progress, err := s.rentalService.FollowRide(r.Context(), clientUUID, rentalID)
+	if err != nil {
+		ServiceError(w, r, err, "following ride")
+
+		return
+	}
+
+	startEventStream(w)
+
+	heartbeat := time.NewTicker(time.Duration(s.config.StreamHeartbeatSeconds) * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streams.Done():
+			return
+		case <-heartbeat.C:
+			writeHeartbeat(w)
+		case current, open := <-progress:
+			if !open {
+				return
+			}
+
+			name := eventProgress
+			if current.Ended {
+				name = eventEnded
+			}
+
+			writeEvent(w, name, rideProgressGet(current))
+		}
+
+		flusher.Flush()
+	}
+}
+
+// streamArea turns the validated query into the area, a query without radius is a bounding box.
+func streamArea(query *model.ScooterStreamQueryParams) *availabilitymodel.Area {
+	if query.Radius != nil {
+		return availabilitymodel.NewCircleArea(query.City, *query.Longitude, *query.Latitude, *query.Radius)
+	}
+
+	return availabilitymodel.NewBoxArea(
+		query.City,
+		*query.MinLongitude,
+		*query.MinLatitude,
+		*query.MaxLongitude,
+		*query.MaxLatitude,
+	)
+}
+
+// scooterEventGet hides scooters with low battery from riders, for them the scooter is gone until it is charged.
+func scooterEventGet(event *redismodel.ScooterEvent) model.ScooterEventGet {
+	eventType := event.Type
+	if event.Status == redismodel.StatusLowBattery {
+		eventType = redismodel.ScooterRemoved
+	}
+
+	return model.ScooterEventGet{
+		Type:         string(eventType),
+		UUID:         event.ScooterUUID,
+		Longitude:    event.Longitude,
+		Latitude:     event.Latitude,
+		Status:       string(event.Status),
+		Battery:      event.Battery,
+		Availability: event.Status.IsAvailable(),
+		OccurredAt:   event.OccurredAt,
+	}
+}
+
+func rideProgressGet(progress *rentalmodel.RideProgress) model.RideProgressGet {
+	return model.RideProgressGet{
+		ID:             progress.RentalID,
+		ScooterUUID:    progress.ScooterUUID,
+		Longitude:      progress.Longitude,
+		Latitude:       progress.Latitude,
+		ElapsedSeconds: int64(progress.Elapsed.Seconds()),
+		Distance:       progress.Distance,
+		Fare:           progress.Fare,
+		Currency:       progress.Currency,
+		Ended:          progress.Ended,
+		At:             progress.At,
+	}
+}
+
+// streamProblem describes why the stream ended, in the shape of the problem details of the error responses.
+func streamProblem(r *http.Request, err error) *model.Problem {
+	problem := &model.Problem{
+		Type:      problemTypeBlank,
+		Title:     http.StatusText(http.StatusInternalServerError),
+		Status:    http.StatusInternalServerError,
+		Detail:    "streaming scooters: " + strings.ToLower(http.StatusText(http.StatusInternalServerError)),
+		Instance:  r.URL.Path,
+		Code:      statusCodeName(http.StatusInternalServerError),
+		RequestID: r.Header.Get(headerRequestID),
+	}
+
+	if domainErr, ok := domain.As(err); ok {
+		problem.Type = problemTypePrefix + domainErr.Code
+		problem.Title = http.StatusText(statusOf(err))
+		problem.Status = statusOf(err)
+		problem.Detail = "streaming scooters: " + domainErr.Message
+		problem.Code = domainErr.Code
+	}
+
+	return problem
+}
+
+// startEventStream sends the headers of the server-sent events, the events follow as the body. The stream outlives
+// the write timeout of the server, so its deadline is lifted.
+func startEventStream(w http.ResponseWriter) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set(headerContentType, contentTypeEventStream)
+	w.Header().Set(headerCacheControl, "no-cache")
+	// Proxies buffering the response would hold the events back
+	w.Header().Set(headerAccelBuffering, "no")
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeHeartbeat writes a comment, it keeps idle connections from being closed by proxies.
+func writeHeartbeat(w http.ResponseWriter) {
+	_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+}
+
+// writeEvent writes the server-sent event, data is a single line of JSON.
+func writeEvent(w http.ResponseWriter, name string, data interface{}) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("encoding server-sent event failed", "event", name, logging.KeyError, err)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, encoded)
+}
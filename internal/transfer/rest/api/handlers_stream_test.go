@@ -0,0 +1,361 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+	availabilitymodel "scootinAboot/internal/module/availability/model"
+	availability "scootinAboot/internal/module/availability/transfer"
+	mockavailability "scootinAboot/internal/module/availability/transfer/mock"
+	redismodel "scootinAboot/internal/module/redis/model"
+	rentalmodel "scootinAboot/internal/module/rental/model"
+	rental "scootinAboot/internal/module/rental/transfer"
+	mockrental "scootinAboot/internal/module/rental/transfer/mock"
+)
+
+func TestStreamScooters(t *testing.T) {
+	s, _, _, _, _, mockAvailabilityService, _ := beforeTest(t)
+
+	availableUUID, lowBatteryUUID := uuid.New(), uuid.New()
+	occurredAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	scooterEvent := func(
+		eventType redismodel.ScooterEventType,
+		scooterUUID uuid.UUID,
+		status redismodel.ScooterStatus,
+	) *redismodel.ScooterEvent {
+		return &redismodel.ScooterEvent{
+			Type:        eventType,
+			ScooterUUID: scooterUUID,
+			City:        testCity,
+			Longitude:   testLongitude,
+			Latitude:    testLatitude,
+			Status:      status,
+			Battery:     redismodel.FullBattery,
+			OccurredAt:  occurredAt,
+		}
+	}
+
+	// Updates are buffered and closed up front, so the handler streams them all and returns
+	subscription := func(err error, updates ...*redismodel.ScooterEvent) *availability.Subscription {
+		updatesChan := make(chan *redismodel.ScooterEvent, len(updates))
+
+		for _, update := range updates {
+			updatesChan <- update
+		}
+
+		close(updatesChan)
+
+		return &availability.Subscription{
+			Snapshot: []*redismodel.ScooterEvent{
+				scooterEvent(redismodel.ScooterAdded, availableUUID, redismodel.StatusAvailable),
+				scooterEvent(redismodel.ScooterAdded, lowBatteryUUID, redismodel.StatusLowBattery),
+			},
+			Updates: updatesChan,
+			Err:     err,
+		}
+	}
+
+	eventJSON := func(eventType redismodel.ScooterEventType, scooterUUID uuid.UUID, status string) string {
+		encoded, err := json.Marshal(model.ScooterEventGet{
+			Type:         string(eventType),
+			UUID:         scooterUUID,
+			Longitude:    testLongitude,
+			Latitude:     testLatitude,
+			Status:       status,
+			Battery:      redismodel.FullBattery,
+			Availability: status == string(redismodel.StatusAvailable),
+			OccurredAt:   occurredAt,
+		})
+		require.NoError(t, err)
+
+		return string(encoded)
+	}
+
+	snapshotEvent := "event: snapshot\ndata: [" + eventJSON(redismodel.ScooterAdded, availableUUID, "available") + "]\n\n"
+
+	tests := map[string]struct {
+		mockAvailabilityServiceHandler func(mock *mockavailability.MockAvailabilityService)
+		query                          string
+		withToken                      bool
+		expectedCode                   int
+		expectedBody                   string
+	}{
+		"successfully streaming scooters around the location": {
+			mockAvailabilityServiceHandler: func(mock *mockavailability.MockAvailabilityService) {
+				mock.EXPECT().
+					Subscribe(gomock.Any(), availabilitymodel.NewCircleArea(testCity, testLongitude, testLatitude, testRadius)).
+					Return(subscription(nil,
+						scooterEvent(redismodel.ScooterStatusChanged, availableUUID, redismodel.StatusReserved),
+						// Riders don't see scooters with low battery, the scooter is gone for them
+						scooterEvent(redismodel.ScooterMoved, availableUUID, redismodel.StatusLowBattery),
+					), nil).Times(1)
+			},
+			query:        "?longitude=70&latitude=60&radius=10000&city=Montreal",
+			withToken:    true,
+			expectedCode: http.StatusOK,
+			expectedBody: snapshotEvent +
+				"event: status_changed\ndata: " + eventJSON(redismodel.ScooterStatusChanged, availableUUID, "reserved") +
+				"\n\n" +
+				"event: removed\ndata: " + eventJSON(redismodel.ScooterRemoved, availableUUID, "low_battery") + "\n\n",
+		},
+		"successfully streaming scooters within the bounding box until the client lags behind": {
+			mockAvailabilityServiceHandler: func(mock *mockavailability.MockAvailabilityService) {
+				mock.EXPECT().Subscribe(gomock.Any(), availabilitymodel.NewBoxArea(testCity, 69.5, 59.5, 70.5, 60.5)).
+					Return(subscription(availability.ErrSubscriberLagged), nil).Times(1)
+			},
+			query:        "?minLongitude=69.5&minLatitude=59.5&maxLongitude=70.5&maxLatitude=60.5&city=Montreal",
+			withToken:    true,
+			expectedCode: http.StatusOK,
+			expectedBody: snapshotEvent +
+				`event: resync` + "\n" + `data: {"type":"urn:scootinaboot:problem:subscriber_lagged",` +
+				`"title":"Conflict","status":409,` +
+				`"detail":"streaming scooters: subscriber fell too far behind the changes, it has to subscribe again",` +
+				`"instance":"/v1/scooters/stream","code":"subscriber_lagged"}` + "\n\n",
+		},
+		"failed streaming scooters because request has no token": {
+			query:        "?longitude=70&latitude=60&radius=10000&city=Montreal",
+			withToken:    false,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"type":"urn:scootinaboot:problem:missing_token","title":"Unauthorized","status":401,` +
+				`"detail":"authenticating request: bearer token is missing",` +
+				`"instance":"/v1/scooters/stream","code":"missing_token"}`,
+		},
+		"failed streaming scooters because circle is combined with bounding box": {
+			query:        "?longitude=70&latitude=60&radius=10000&minLongitude=69&city=Montreal",
+			withToken:    true,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity",` +
+				`"status":422,"detail":"validating query params: request has invalid fields",` +
+				`"instance":"/v1/scooters/stream","code":"invalid_request",` +
+				`"invalidParams":[{"name":"radius","reason":"can't be combined with a bounding box"}]}`,
+		},
+		"failed streaming scooters because bounding box is incomplete and inverted": {
+			query:        "?minLongitude=71&minLatitude=59.5&maxLongitude=70.5",
+			withToken:    true,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity",` +
+				`"status":422,"detail":"validating query params: request has invalid fields",` +
+				`"instance":"/v1/scooters/stream","code":"invalid_request","invalidParams":[` +
+				`{"name":"city","reason":"is required"},{"name":"maxLatitude","reason":"is required"},` +
+				`{"name":"maxLongitude","reason":"has to be greater than minLongitude"}]}`,
+		},
+		"failed streaming scooters because redis is unavailable": {
+			mockAvailabilityServiceHandler: func(mock *mockavailability.MockAvailabilityService) {
+				mock.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil, domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			query:        "?longitude=70&latitude=60&radius=10000&city=Montreal",
+			withToken:    true,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"type":"urn:scootinaboot:problem:service_unavailable","title":"Service Unavailable",` +
+				`"status":503,"detail":"subscribing to scooters: service is temporarily unavailable, try again later",` +
+				`"instance":"/v1/scooters/stream","code":"service_unavailable"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildRequest(t, scootersStreamPath+tt.query, http.MethodGet, bytes.NewBuffer(nil), tt.withToken)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockAvailabilityServiceHandler != nil {
+				tt.mockAvailabilityServiceHandler(mockAvailabilityService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+
+			if tt.expectedCode == http.StatusOK {
+				require.Equal(t, contentTypeEventStream, responseRecorder.Header().Get(headerContentType))
+			}
+		})
+	}
+}
+
+func TestStreamRide(t *testing.T) {
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
+
+	rentalID, scooterUUID := uuid.New(), uuid.New()
+	at := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	rideProgress := func(elapsed time.Duration, distance, fare float64, ended bool) *rentalmodel.RideProgress {
+		progress := rentalmodel.NewRideProgress(rentalID, scooterUUID, testLongitude, testLatitude, elapsed, distance, at)
+		progress.Fare = fare
+		progress.Currency = "CAD"
+		progress.Ended = ended
+
+		return progress
+	}
+
+	// Progress is buffered and closed up front, so the handler streams it all and returns
+	follow := func(progress ...*rentalmodel.RideProgress) <-chan *rentalmodel.RideProgress {
+		progressChan := make(chan *rentalmodel.RideProgress, len(progress))
+
+		for _, current := range progress {
+			progressChan <- current
+		}
+
+		close(progressChan)
+
+		return progressChan
+	}
+
+	eventJSON := func(name string, progress *rentalmodel.RideProgress) string {
+		encoded, err := json.Marshal(rideProgressGet(progress))
+		require.NoError(t, err)
+
+		return "event: " + name + "\ndata: " + string(encoded) + "\n\n"
+	}
+
+	running := rideProgress(2*time.Minute, 300, 1.6, false)
+	moved := rideProgress(3*time.Minute, 450, 1.95, false)
+	ended := rideProgress(4*time.Minute, 600, 2.3, true)
+
+	tests := map[string]struct {
+		mockRentalServiceHandler func(mock *mockrental.MockRentalService)
+		rentalID                 string
+		withToken                bool
+		expectedCode             int
+		expectedBody             string
+	}{
+		"successfully streaming ride until it ends": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().FollowRide(gomock.Any(), gomock.Any(), rentalID).
+					Return(follow(running, moved, ended), nil).Times(1)
+			},
+			rentalID:     rentalID.String(),
+			withToken:    true,
+			expectedCode: http.StatusOK,
+			expectedBody: eventJSON("progress", running) + eventJSON("progress", moved) + eventJSON("ended", ended),
+		},
+		"failed streaming ride because request has no token": {
+			rentalID:     rentalID.String(),
+			withToken:    false,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"type":"urn:scootinaboot:problem:missing_token","title":"Unauthorized","status":401,` +
+				`"detail":"authenticating request: bearer token is missing",` +
+				`"instance":"/v1/rentals/` + rentalID.String() + `/live","code":"missing_token"}`,
+		},
+		"failed streaming ride because rentalID is not valid": {
+			rentalID:     "not-a-uuid",
+			withToken:    true,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,` +
+				`"detail":"getting rentalID from path: bad request","instance":"/v1/rentals/not-a-uuid/live",` +
+				`"code":"bad_request"}`,
+		},
+		"failed streaming ride because it belongs to another client": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().FollowRide(gomock.Any(), gomock.Any(), rentalID).
+					Return(nil, rental.ErrRentalNotOwned).Times(1)
+			},
+			rentalID:     rentalID.String(),
+			withToken:    true,
+			expectedCode: http.StatusForbidden,
+			expectedBody: `{"type":"urn:scootinaboot:problem:rental_not_owned","title":"Forbidden","status":403,` +
+				`"detail":"following ride: rental belongs to another client",` +
+				`"instance":"/v1/rentals/` + rentalID.String() + `/live","code":"rental_not_owned"}`,
+		},
+		"failed streaming ride because it does not exist": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().FollowRide(gomock.Any(), gomock.Any(), rentalID).
+					Return(nil, redismodel.ErrRentalNotFound).Times(1)
+			},
+			rentalID:     rentalID.String(),
+			withToken:    true,
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"type":"urn:scootinaboot:problem:rental_not_found","title":"Not Found","status":404,` +
+				`"detail":"following ride: rental was not found",` +
+				`"instance":"/v1/rentals/` + rentalID.String() + `/live","code":"rental_not_found"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildRequest(t, rentalsPath+"/"+tt.rentalID+"/live", http.MethodGet, bytes.NewBuffer(nil),
+				tt.withToken)
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRentalServiceHandler != nil {
+				tt.mockRentalServiceHandler(mockRentalService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+
+			if tt.expectedCode == http.StatusOK {
+				require.Equal(t, contentTypeEventStream, responseRecorder.Header().Get(headerContentType))
+			}
+		})
+	}
+}
+
+func TestStreamRideOnShutdown(t *testing.T) {
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
+
+	rentalID, scooterUUID := uuid.New(), uuid.New()
+	at := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	running := rentalmodel.NewRideProgress(rentalID, scooterUUID, testLongitude, testLatitude, time.Minute, 150, at)
+
+	// The ride goes on, only the shutdown ends the stream
+	progressChan := make(chan *rentalmodel.RideProgress, 1)
+	progressChan <- running
+
+	mockRentalService.EXPECT().FollowRide(gomock.Any(), gomock.Any(), rentalID).
+		Return((<-chan *rentalmodel.RideProgress)(progressChan), nil).Times(1)
+
+	require.NoError(t, s.httpServer.Shutdown(context.Background()))
+
+	request := buildRequest(t, rentalsPath+"/"+rentalID.String()+"/live", http.MethodGet, bytes.NewBuffer(nil), true)
+	responseRecorder := httptest.NewRecorder()
+
+	served := make(chan struct{})
+
+	go func() {
+		defer close(served)
+
+		s.router.ServeHTTP(responseRecorder, request)
+	}()
+
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("stream outlived the shutdown")
+	}
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, contentTypeEventStream, responseRecorder.Header().Get(headerContentType))
+}
@@ -8,7 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -16,6 +16,7 @@ import (
 	rentalmodel "scootinAboot/internal/module/rental/model"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -23,10 +24,19 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/config"
+	"scootinAboot/internal/domain"
 	"scootinAboot/internal/model"
+	mockavailability "scootinAboot/internal/module/availability/transfer/mock"
+	mockidempotency "scootinAboot/internal/module/idempotency/transfer/mock"
+	ratelimit "scootinAboot/internal/module/ratelimit/transfer"
 	redismodel "scootinAboot/internal/module/redis/model"
 	mockredis "scootinAboot/internal/module/redis/transfer/mock"
 	mockrental "scootinAboot/internal/module/rental/transfer/mock"
+	tracker "scootinAboot/internal/module/tracker/transfer"
+	mocktracker "scootinAboot/internal/module/tracker/transfer/mock"
+	mockwebhook "scootinAboot/internal/module/webhook/transfer/mock"
 )
 
 const (
@@ -34,14 +44,31 @@ const (
 	testLongitude = 70.0
 	testLatitude  = 60.0
 	testRadius    = 10000.0
+
+	testMinSearchRadius = 1.0
+	testMaxSearchRadius = 50000.0
+
+	testStreamHeartbeatSeconds       = 1
+	testReadinessTimeoutMilliseconds = 100
+
+	testBootstrapToken = "test_admin_token"
+	testTokenKey       = "test_token_key_of_at_least_32_bytes"
+	testTokenTTL       = time.Hour
+	testName           = "scootin_aboot"
 )
 
 func TestGetScooters(t *testing.T) {
-	s, mockRedisService, _ := beforeTest(t)
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
 
 	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
 
+	secScooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	lowBatteryScooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
 	redisScooters := []*redismodel.RedisScooter{
 		redismodel.NewRedisScooter(
 			&redis.GeoLocation{
@@ -51,7 +78,30 @@ func TestGetScooters(t *testing.T) {
 				Longitude: testLongitude,
 				Latitude:  testLatitude,
 			},
-			true,
+			redismodel.StatusAvailable,
+			redismodel.FullBattery,
+		),
+		redismodel.NewRedisScooter(
+			&redis.GeoLocation{
+				Name: secScooterUUID.String(),
+			},
+			&redis.GeoPos{
+				Longitude: testLongitude,
+				Latitude:  testLatitude,
+			},
+			redismodel.StatusMaintenance,
+			50,
+		),
+		redismodel.NewRedisScooter(
+			&redis.GeoLocation{
+				Name: lowBatteryScooterUUID.String(),
+			},
+			&redis.GeoPos{
+				Longitude: testLongitude,
+				Latitude:  testLatitude,
+			},
+			redismodel.StatusLowBattery,
+			5,
 		),
 	}
 
@@ -60,74 +110,154 @@ func TestGetScooters(t *testing.T) {
 			UUID:         scooterUUID,
 			Longitude:    testLongitude,
 			Latitude:     testLatitude,
+			Status:       string(redismodel.StatusAvailable),
+			Battery:      redismodel.FullBattery,
 			Availability: true,
 		},
+		{
+			UUID:         secScooterUUID,
+			Longitude:    testLongitude,
+			Latitude:     testLatitude,
+			Status:       string(redismodel.StatusMaintenance),
+			Battery:      50,
+			Availability: false,
+		},
 	}
 
+	longitude, latitude := float64(testLongitude), float64(testLatitude)
+
 	params := &model.ScooterQueryParams{
-		Longitude: testLongitude,
-		Latitude:  testLatitude,
+		Longitude: &longitude,
+		Latitude:  &latitude,
 		Radius:    testRadius,
 		City:      testCity,
 	}
 
 	validURLQuery := &url.Values{}
-	validURLQuery.Add("longitude", strconv.FormatFloat(params.Longitude, 'f', -1, 64))
-	validURLQuery.Add("latitude", strconv.FormatFloat(params.Latitude, 'f', -1, 64))
+	validURLQuery.Add("longitude", strconv.FormatFloat(*params.Longitude, 'f', -1, 64))
+	validURLQuery.Add("latitude", strconv.FormatFloat(*params.Latitude, 'f', -1, 64))
 	validURLQuery.Add("radius", strconv.FormatFloat(params.Radius, 'f', -1, 64))
 	validURLQuery.Add("city", params.City)
 
+	statusURLQuery := &url.Values{}
+	statusURLQuery.Add("longitude", strconv.FormatFloat(*params.Longitude, 'f', -1, 64))
+	statusURLQuery.Add("latitude", strconv.FormatFloat(*params.Latitude, 'f', -1, 64))
+	statusURLQuery.Add("radius", strconv.FormatFloat(params.Radius, 'f', -1, 64))
+	statusURLQuery.Add("city", params.City)
+	statusURLQuery.Add("status", string(redismodel.StatusAvailable))
+
+	unknownStatusURLQuery := &url.Values{}
+	unknownStatusURLQuery.Add("longitude", strconv.FormatFloat(*params.Longitude, 'f', -1, 64))
+	unknownStatusURLQuery.Add("latitude", strconv.FormatFloat(*params.Latitude, 'f', -1, 64))
+	unknownStatusURLQuery.Add("radius", strconv.FormatFloat(params.Radius, 'f', -1, 64))
+	unknownStatusURLQuery.Add("city", params.City)
+	unknownStatusURLQuery.Add("status", "broken")
+
+	outOfRangeURLQuery := &url.Values{}
+	outOfRangeURLQuery.Add("longitude", "-181")
+	outOfRangeURLQuery.Add("latitude", "500")
+	outOfRangeURLQuery.Add("radius", "-5")
+
+	missingLocationURLQuery := &url.Values{}
+	missingLocationURLQuery.Add("radius", strconv.FormatFloat(params.Radius, 'f', -1, 64))
+	missingLocationURLQuery.Add("city", params.City)
+
 	invalidURLQuery := &url.Values{}
 	invalidURLQuery.Add("wrong", "wrong")
+	invalidURLQuery.Add("radius", "far")
 
 	expectedScootersJSON, err := json.Marshal(expectedScooters)
 	require.NoError(t, err)
 
+	expectedAvailableScootersJSON, err := json.Marshal(expectedScooters[:1])
+	require.NoError(t, err)
+
 	tests := map[string]struct {
 		mockRedisServiceHandler func(mock *mockredis.MockRedisService)
 		urlQuery                *url.Values
-		withHeader              bool
+		withToken               bool
 		expectedCode            int
 		expectedBody            string
 	}{
 		"successfully getting scooters": {
 			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
-				mock.EXPECT().GetScooters(testLongitude, testLatitude, testRadius, testCity).
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
 					Return(redisScooters, nil).Times(1)
 			},
 			urlQuery:     validURLQuery,
-			withHeader:   true,
+			withToken:    true,
 			expectedCode: http.StatusOK,
 			expectedBody: string(expectedScootersJSON),
 		},
-		"failed getting scooter because request has no clientUUID in header": {
+		"successfully getting scooters filtered by status": {
+			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
+					Return(redisScooters, nil).Times(1)
+			},
+			urlQuery:     statusURLQuery,
+			withToken:    true,
+			expectedCode: http.StatusOK,
+			expectedBody: string(expectedAvailableScootersJSON),
+		},
+		"failed getting scooter because status filter is unknown": {
+			mockRedisServiceHandler: nil,
+			urlQuery:                unknownStatusURLQuery,
+			withToken:               true,
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity","status":422,"detail":"validating query params: request has invalid fields",` +
+				`"instance":"/v1/scooters","code":"invalid_request","invalidParams":[{"name":"status","reason":"is not a known scooter status"}]}`,
+		},
+		"failed getting scooter because query params are out of range": {
+			mockRedisServiceHandler: nil,
+			urlQuery:                outOfRangeURLQuery,
+			withToken:               true,
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity","status":422,"detail":"validating query params: request has invalid fields",` +
+				`"instance":"/v1/scooters","code":"invalid_request","invalidParams":[{"name":"longitude","reason":"has to be between -180 and 180"},` +
+				`{"name":"latitude","reason":"has to be between -90 and 90"},{"name":"radius","reason":"has to be between 1 and 50000"},` +
+				`{"name":"city","reason":"is required"}]}`,
+		},
+		"failed getting scooter because location is missing": {
+			mockRedisServiceHandler: nil,
+			urlQuery:                missingLocationURLQuery,
+			withToken:               true,
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity","status":422,"detail":"validating query params: request has invalid fields",` +
+				`"instance":"/v1/scooters","code":"invalid_request","invalidParams":[{"name":"longitude","reason":"is required"},` +
+				`{"name":"latitude","reason":"is required"}]}`,
+		},
+		"failed getting scooter because request has no token": {
 			mockRedisServiceHandler: nil,
 			urlQuery:                validURLQuery,
-			withHeader:              false,
-			expectedCode:            http.StatusBadRequest,
-			expectedBody:            "{\"Error\":\"expected header parameter was not found\",\"Message\":\"getting clientUUID from header\"}",
+			withToken:               false,
+			expectedCode:            http.StatusUnauthorized,
+			expectedBody: `{"type":"urn:scootinaboot:problem:missing_token","title":"Unauthorized","status":401,` +
+				`"detail":"authenticating request: bearer token is missing",` +
+				`"instance":"/v1/scooters","code":"missing_token"}`,
 		},
 		"failed getting scooter because request has wrong query params": {
 			mockRedisServiceHandler: nil,
 			urlQuery:                invalidURLQuery,
-			withHeader:              true,
-			expectedCode:            http.StatusBadRequest,
-			expectedBody:            "{\"Error\":\"schema: invalid path \\\"wrong\\\"\",\"Message\":\"decoding query params\"}",
+			withToken:               true,
+			expectedCode:            http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity","status":422,"detail":"decoding query params: request has invalid fields",` +
+				`"instance":"/v1/scooters","code":"invalid_request","invalidParams":[{"name":"radius","reason":"has to be a number"},{"name":"wrong","reason":"is unknown"}]}`,
 		},
 		"failed getting scooter because redis service threw error while getting scooters": {
 			mockRedisServiceHandler: func(mock *mockredis.MockRedisService) {
-				mock.EXPECT().GetScooters(testLongitude, testLatitude, testRadius, testCity).
+				mock.EXPECT().GetScooters(gomock.Any(), testLongitude, testLatitude, testRadius, testCity).
 					Return(nil, errors.New("")).Times(1)
 			},
 			urlQuery:     validURLQuery,
-			withHeader:   true,
-			expectedCode: http.StatusBadRequest,
-			expectedBody: "{\"Error\":\"\",\"Message\":\"getting scooters\"}",
+			withToken:    true,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"getting scooters: internal server error",` +
+				`"instance":"/v1/scooters","code":"internal_server_error"}`,
 		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			request := buildRequest(t, scootersPath, http.MethodGet, &bytes.Buffer{}, tt.withHeader)
+			request := buildRequest(t, scootersPath, http.MethodGet, &bytes.Buffer{}, tt.withToken)
 
 			request.URL.RawQuery = tt.urlQuery.Encode()
 
@@ -137,7 +267,7 @@ func TestGetScooters(t *testing.T) {
 				tt.mockRedisServiceHandler(mockRedisService)
 			}
 
-			s.GetScooters(responseRecorder, request)
+			s.router.ServeHTTP(responseRecorder, request)
 
 			if status := responseRecorder.Code; status != tt.expectedCode {
 				t.Errorf("handler returned wrong status code: got = %v want = %v",
@@ -153,7 +283,7 @@ func TestGetScooters(t *testing.T) {
 }
 
 func TestRentScooter(t *testing.T) {
-	s, _, mockRentalService := beforeTest(t)
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
 
 	scooterUUID, err := uuid.NewRandom()
 	require.NoError(t, err)
@@ -178,55 +308,84 @@ func TestRentScooter(t *testing.T) {
 			Latitude:  scooter.Latitude,
 			Longitude: scooter.Longitude,
 		},
-		City:         scooter.City,
-		Availability: scooter.Availability,
+		City: scooter.City,
 	}
 
 	tests := map[string]struct {
 		mockRentalServiceHandler func(mock *mockrental.MockRentalService)
 		body                     *bytes.Buffer
-		withHeader               bool
+		withToken                bool
 		expectedCode             int
 	}{
 		"successfully renting scooter": {
 			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
-				mock.EXPECT().Rent(rentalScooter).Return(nil).Times(1)
+				mock.EXPECT().Rent(gomock.Any(), gomock.Any(), rentalScooter).Return(nil).Times(1)
 			},
 			body:         bytes.NewBuffer(scooterJSON),
-			withHeader:   true,
+			withToken:    true,
 			expectedCode: http.StatusNoContent,
 		},
-		"failed renting scooter because request has no clientUUID in header": {
+		"failed renting scooter because request has no token": {
 			mockRentalServiceHandler: nil,
 			body:                     bytes.NewBuffer(scooterJSON),
-			withHeader:               false,
-			expectedCode:             http.StatusBadRequest,
+			withToken:                false,
+			expectedCode:             http.StatusUnauthorized,
 		},
 		"failed renting scooter because request has invalid body": {
 			mockRentalServiceHandler: nil,
 			body:                     bytes.NewBuffer(invalidScooterJSON),
-			withHeader:               true,
+			withToken:                true,
 			expectedCode:             http.StatusBadRequest,
 		},
+		"failed renting scooter because request has unknown fields": {
+			mockRentalServiceHandler: nil,
+			body: bytes.NewBufferString(
+				`{"UUID":"` + scooterUUID.String() + `","city":"Montreal","speed":1,"color":"red"}`,
+			),
+			withToken:    true,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		"failed renting scooter because request has invalid fields": {
+			mockRentalServiceHandler: nil,
+			body:                     bytes.NewBufferString(`{"longitude":200,"latitude":-95}`),
+			withToken:                true,
+			expectedCode:             http.StatusUnprocessableEntity,
+		},
+		"failed renting scooter because it is already rented": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), gomock.Any(), rentalScooter).Return(tracker.ErrRentAlreadyRentedScooter).Times(1)
+			},
+			body:         bytes.NewBuffer(scooterJSON),
+			withToken:    true,
+			expectedCode: http.StatusConflict,
+		},
+		"failed renting scooter because redis is unavailable": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), gomock.Any(), rentalScooter).Return(domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			body:         bytes.NewBuffer(scooterJSON),
+			withToken:    true,
+			expectedCode: http.StatusServiceUnavailable,
+		},
 		"failed renting scooter because rental service threw error while renting scooter": {
 			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
-				mock.EXPECT().Rent(rentalScooter).Return(errors.New("")).Times(1)
+				mock.EXPECT().Rent(gomock.Any(), gomock.Any(), rentalScooter).Return(errors.New("")).Times(1)
 			},
 			body:         bytes.NewBuffer(scooterJSON),
-			withHeader:   true,
-			expectedCode: http.StatusBadRequest,
+			withToken:    true,
+			expectedCode: http.StatusInternalServerError,
 		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			request := buildRequest(t, rentPath, http.MethodPost, tt.body, tt.withHeader)
+			request := buildRequest(t, rentPath, http.MethodPost, tt.body, tt.withToken)
 
 			responseRecorder := httptest.NewRecorder()
 
 			if tt.mockRentalServiceHandler != nil {
 				tt.mockRentalServiceHandler(mockRentalService)
 			}
-			s.RentScooter(responseRecorder, request)
+			s.router.ServeHTTP(responseRecorder, request)
 
 			if status := responseRecorder.Code; status != tt.expectedCode {
 				t.Errorf("handler returned wrong status code: got = %v want = %v",
@@ -236,17 +395,133 @@ func TestRentScooter(t *testing.T) {
 	}
 }
 
-func beforeTest(t *testing.T) (*Server, *mockredis.MockRedisService, *mockrental.MockRentalService) {
-	logger := log.New(os.Stdout, "TEST ", log.LstdFlags)
+func TestFreeScooter(t *testing.T) {
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
+
+	scooterUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooterUUIDJSON, err := json.Marshal(scooterUUID)
+	require.NoError(t, err)
+
+	startedAt := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	rental := redismodel.NewRental(uuid.New(), uuid.New(), scooterUUID, testCity, startedAt)
+	rental.EndedAt = startedAt.Add(10 * time.Minute)
+	rental.Distance = 2500
+	rental.Fare = 4.75
+	rental.Currency = "CAD"
+	rental.Events = []redismodel.RentalEvent{
+		{Type: "entered_slow_zone", Zone: "Downtown", Longitude: testLongitude, Latitude: testLatitude, OccurredAt: startedAt},
+	}
+
+	expectedRentalJSON, err := json.Marshal(model.RentalGet{
+		ID:              rental.ID,
+		ClientUUID:      rental.ClientUUID,
+		ScooterUUID:     scooterUUID,
+		City:            testCity,
+		StartedAt:       rental.StartedAt,
+		EndedAt:         rental.EndedAt,
+		DurationSeconds: 600,
+		Distance:        2500,
+		Fare:            4.75,
+		Currency:        "CAD",
+		Events: []model.RentalEventGet{
+			{Type: "entered_slow_zone", Zone: "Downtown", Longitude: testLongitude, Latitude: testLatitude, OccurredAt: startedAt},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockRentalServiceHandler func(mock *mockrental.MockRentalService)
+		withToken                bool
+		expectedCode             int
+		expectedBody             string
+	}{
+		"successfully freeing scooter": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Free(gomock.Any(), rental.ClientUUID, scooterUUID).Return(rental, nil).Times(1)
+			},
+			withToken:    true,
+			expectedCode: http.StatusOK,
+			expectedBody: string(expectedRentalJSON),
+		},
+		"failed freeing scooter because rental service threw error while freeing scooter": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Free(gomock.Any(), rental.ClientUUID, scooterUUID).Return(nil, errors.New("")).Times(1)
+			},
+			withToken:    true,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"freeing scooter: internal server error",` +
+				`"instance":"/v1/free","code":"internal_server_error","scooterUUID":"` + scooterUUID.String() + `"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildRequest(t, freePath, http.MethodPost, bytes.NewBuffer(scooterUUIDJSON), false)
+			if tt.withToken {
+				request = buildClientRequest(t, freePath, http.MethodPost, bytes.NewBuffer(scooterUUIDJSON), rental.ClientUUID)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRentalServiceHandler != nil {
+				tt.mockRentalServiceHandler(mockRentalService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func beforeTest(t *testing.T) (
+	*Server,
+	*mockredis.MockRedisService,
+	*mockrental.MockRentalService,
+	*mocktracker.MockTrackerService,
+	*mockidempotency.MockIdempotencyService,
+	*mockavailability.MockAvailabilityService,
+	*mockwebhook.MockWebhookService,
+) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	controller := gomock.NewController(t)
 	httpRouter := mux.NewRouter()
 
 	mockRedisService := mockredis.NewMockRedisService(controller)
 	mockRentalService := mockrental.NewMockRentalService(controller)
+	mockTrackerService := mocktracker.NewMockTrackerService(controller)
+	mockIdempotencyService := mockidempotency.NewMockIdempotencyService(controller)
+	mockAvailabilityService := mockavailability.NewMockAvailabilityService(controller)
+	mockWebhookService := mockwebhook.NewMockWebhookService(controller)
 
 	s := NewServer(
 		logger,
+		&config.Config{
+			HTTP:       8081,
+			Name:       testName,
+			AdminToken: testBootstrapToken,
+
+			TokenIssuing: true,
+
+			MinSearchRadius: testMinSearchRadius,
+			MaxSearchRadius: testMaxSearchRadius,
+
+			StreamHeartbeatSeconds: testStreamHeartbeatSeconds,
+
+			ReadinessTimeoutMilliseconds: testReadinessTimeoutMilliseconds,
+		},
+		testSigner(t),
 		&http.Server{
 			Addr:    fmt.Sprintf(":%d", 8081),
 			Handler: httpRouter,
@@ -254,12 +529,19 @@ func beforeTest(t *testing.T) (*Server, *mockredis.MockRedisService, *mockrental
 		httpRouter,
 		mockRedisService,
 		mockRentalService,
+		mockTrackerService,
+		mockIdempotencyService,
+		mockAvailabilityService,
+		mockWebhookService,
+		// Without limits no request is limited, the tests of the rate limiting replace the service
+		ratelimit.NewRateLimitService(logger, mockRedisService, nil),
 	)
 
-	return s, mockRedisService, mockRentalService
+	return s, mockRedisService, mockRentalService, mockTrackerService, mockIdempotencyService, mockAvailabilityService,
+		mockWebhookService
 }
 
-func buildRequest(t *testing.T, path string, method string, body *bytes.Buffer, withHeader bool) *http.Request {
+func buildRequest(t *testing.T, path string, method string, body *bytes.Buffer, withToken bool) *http.Request {
 	t.Helper()
 
 	request, err := http.NewRequestWithContext(
@@ -270,12 +552,51 @@ func buildRequest(t *testing.T, path string, method string, body *bytes.Buffer,
 	)
 	require.NoErrorf(t, err, "Building new request")
 
-	if withHeader {
-		clientUUID, innerErr := uuid.NewRandom()
-		require.NoError(t, innerErr)
-
-		request.Header.Set("clientUUID", clientUUID.String())
+	if withToken {
+		request.Header.Set(headerAuthorization, bearerPrefix+testClientToken(t, uuid.New()))
 	}
 
 	return request
 }
+
+// buildClientRequest builds the request the client sends with its token.
+func buildClientRequest(t *testing.T, path, method string, body *bytes.Buffer, clientUUID uuid.UUID) *http.Request {
+	t.Helper()
+
+	request := buildRequest(t, path, method, body, false)
+	request.Header.Set(headerAuthorization, bearerPrefix+testClientToken(t, clientUUID))
+
+	return request
+}
+
+// testSigner signs the tokens the same way the server of beforeTest verifies them.
+func testSigner(t *testing.T) *auth.Signer {
+	t.Helper()
+
+	signer, err := auth.NewSigner([]byte(testTokenKey), testName, testTokenTTL)
+	require.NoError(t, err)
+
+	return signer
+}
+
+func testClientToken(t *testing.T, clientUUID uuid.UUID) string {
+	t.Helper()
+
+	return testToken(t, auth.NewRider(clientUUID))
+}
+
+// testAdminToken is signed for an admin, the admin token of the instance only bootstraps such tokens.
+func testAdminToken(t *testing.T) string {
+	t.Helper()
+
+	return testToken(t, auth.NewAdmin(uuid.New()))
+}
+
+func testToken(t *testing.T, principal *auth.Principal) string {
+	t.Helper()
+
+	token, _, err := testSigner(t).Issue(principal)
+	require.NoError(t, err)
+
+	return token
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+)
+
+var errTokenIssuingDisabled = domain.NotFound(
+	"token_issuing_disabled", "tokens are issued by the API in development only",
+)
+
+// IssueToken signs the token of the client, so the endpoints can be tried out without an identity provider. Anybody
+// may get the token of any client or role, so it is served only when TOKEN_ISSUING is turned on. Otherwise only the
+// holder of the instance's ADMIN_TOKEN may get the tokens of admins, so the first admin doesn't need a signed token.
+func (s *Server) IssueToken(w http.ResponseWriter, r *http.Request) {
+	bootstrap := !s.config.TokenIssuing && s.bootstrapping(r)
+	if !s.config.TokenIssuing && !bootstrap {
+		ServiceError(w, r, errTokenIssuingDisabled, "issuing token")
+
+		return
+	}
+
+	var token model.TokenPost
+
+	if err := decodeJSON(r, &token); err != nil {
+		RequestError(w, r, err, "decoding request body to token")
+
+		return
+	}
+
+	if err := validateTokenPost(&token); err != nil {
+		RequestError(w, r, err, "validating token")
+
+		return
+	}
+
+	principal := &auth.Principal{Subject: uuid.New(), Role: auth.Role(token.Role), City: token.City}
+	if token.ClientUUID != nil {
+		principal.Subject = *token.ClientUUID
+	}
+
+	if principal.Role == "" {
+		principal.Role = auth.RoleRider
+	}
+
+	if bootstrap && principal.Role != auth.RoleAdmin {
+		ServiceError(w, r, auth.ErrRoleNotPermitted, "issuing token")
+
+		return
+	}
+
+	signed, expiresAt, err := s.signer.Issue(principal)
+	if err != nil {
+		Error(w, r, http.StatusInternalServerError, err, "issuing token")
+
+		return
+	}
+
+	JSON(w, http.StatusCreated, model.TokenGet{
+		Token:      signed,
+		TokenType:  model.TokenTypeBearer,
+		ClientUUID: principal.Subject,
+		Role:       string(principal.Role),
+		City:       principal.City,
+		ExpiresAt:  expiresAt,
+	})
+}
+
+// bootstrapping reports whether the request carries the admin token of the instance, an empty one is never matched.
+func (s *Server) bootstrapping(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get(headerAuthorization), bearerPrefix)
+
+	return ok && s.config.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) == 1
+}
@@ -0,0 +1,154 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/model"
+)
+
+func TestIssueToken(t *testing.T) {
+	clientUUID := uuid.New()
+
+	tests := map[string]struct {
+		issuing        bool
+		token          string
+		body           string
+		wantCode       int
+		wantClientUUID uuid.UUID
+		wantRole       auth.Role
+		wantCity       string
+		wantErrorCode  string
+	}{
+		"issuing token of given client": {
+			issuing:        true,
+			body:           `{"clientUUID":"` + clientUUID.String() + `"}`,
+			wantCode:       http.StatusCreated,
+			wantClientUUID: clientUUID,
+			wantRole:       auth.RoleRider,
+		},
+		"issuing token of new client": {
+			issuing:  true,
+			body:     `{}`,
+			wantCode: http.StatusCreated,
+			wantRole: auth.RoleRider,
+		},
+		"issuing token of operator": {
+			issuing:  true,
+			body:     `{"role":"operator","city":"` + testCity + `"}`,
+			wantCode: http.StatusCreated,
+			wantRole: auth.RoleOperator,
+			wantCity: testCity,
+		},
+		"issuing token of admin": {
+			issuing:  true,
+			body:     `{"role":"admin"}`,
+			wantCode: http.StatusCreated,
+			wantRole: auth.RoleAdmin,
+		},
+		"failed issuing token, because the role is unknown": {
+			issuing:       true,
+			body:          `{"role":"superuser"}`,
+			wantCode:      http.StatusUnprocessableEntity,
+			wantErrorCode: "invalid_request",
+		},
+		"failed issuing token, because the operator has no city": {
+			issuing:       true,
+			body:          `{"role":"operator"}`,
+			wantCode:      http.StatusUnprocessableEntity,
+			wantErrorCode: "invalid_request",
+		},
+		"failed issuing token, because the rider is given a city": {
+			issuing:       true,
+			body:          `{"city":"` + testCity + `"}`,
+			wantCode:      http.StatusUnprocessableEntity,
+			wantErrorCode: "invalid_request",
+		},
+		"failed issuing token, because the client is the nil UUID": {
+			issuing:       true,
+			body:          `{"clientUUID":"` + uuid.Nil.String() + `"}`,
+			wantCode:      http.StatusUnprocessableEntity,
+			wantErrorCode: "invalid_request",
+		},
+		"issuing token of admin for the holder of the admin token": {
+			issuing:  false,
+			token:    testBootstrapToken,
+			body:     `{"role":"admin"}`,
+			wantCode: http.StatusCreated,
+			wantRole: auth.RoleAdmin,
+		},
+		"failed issuing token, because the admin token gets the tokens of admins only": {
+			issuing:       false,
+			token:         testBootstrapToken,
+			body:          `{"role":"operator","city":"` + testCity + `"}`,
+			wantCode:      http.StatusForbidden,
+			wantErrorCode: "role_not_permitted",
+		},
+		"failed issuing token, because the admin token is wrong": {
+			issuing:       false,
+			token:         "wrong_admin_token",
+			body:          `{"role":"admin"}`,
+			wantCode:      http.StatusNotFound,
+			wantErrorCode: "token_issuing_disabled",
+		},
+		"failed issuing token, because the instance doesn't issue tokens": {
+			issuing:       false,
+			body:          `{"clientUUID":"` + clientUUID.String() + `"}`,
+			wantCode:      http.StatusNotFound,
+			wantErrorCode: "token_issuing_disabled",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, _ := beforeTest(t)
+			s.config.TokenIssuing = tt.issuing
+
+			request := buildRequest(t, tokensPath, http.MethodPost, bytes.NewBufferString(tt.body), false)
+			if tt.token != "" {
+				request.Header.Set(headerAuthorization, bearerPrefix+tt.token)
+			}
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			if tt.wantErrorCode != "" {
+				var problem model.Problem
+
+				require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+				require.Equal(t, tt.wantErrorCode, problem.Code)
+
+				return
+			}
+
+			var token model.TokenGet
+
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &token))
+			require.Equal(t, model.TokenTypeBearer, token.TokenType)
+			require.NotEqual(t, uuid.Nil, token.ClientUUID)
+			require.Equal(t, string(tt.wantRole), token.Role)
+			require.Equal(t, tt.wantCity, token.City)
+			require.WithinDuration(t, time.Now().Add(testTokenTTL), token.ExpiresAt, time.Minute)
+
+			if tt.wantClientUUID != uuid.Nil {
+				require.Equal(t, tt.wantClientUUID, token.ClientUUID)
+			}
+
+			// The issued token authenticates the principal it was issued to
+			verified, err := s.signer.Verify(token.Token)
+			require.NoError(t, err)
+			require.Equal(t, &auth.Principal{Subject: token.ClientUUID, Role: tt.wantRole, City: tt.wantCity}, verified)
+		})
+	}
+}
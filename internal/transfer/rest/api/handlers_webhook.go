@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"scootinAboot/internal/model"
+	eventsmodel "scootinAboot/internal/module/events/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+)
+
+const (
+	defaultDeadLettersLimit = 20
+	maxDeadLettersLimit     = 100
+)
+
+// CreateWebhook subscribes the URL to the events of given types, the deliveries are signed with the secret.
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhook model.WebhookPost
+
+	if err := decodeJSON(r, &webhook); err != nil {
+		RequestError(w, r, err, "decoding request body to webhook")
+
+		return
+	}
+
+	if err := validateWebhookPost(&webhook); err != nil {
+		RequestError(w, r, err, "validating webhook")
+
+		return
+	}
+
+	subscription, err := s.webhookService.CreateSubscription(r.Context(), webhook.URL, webhook.Secret, webhook.EventTypes)
+	if err != nil {
+		ServiceError(w, r, err, "creating webhook")
+
+		return
+	}
+
+	JSON(w, http.StatusCreated, webhookGet(subscription))
+}
+
+func (s *Server) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := s.webhookService.GetSubscriptions(r.Context())
+	if err != nil {
+		ServiceError(w, r, err, "getting webhooks")
+
+		return
+	}
+
+	webhooks := make([]model.WebhookGet, 0, len(subscriptions))
+
+	for _, subscription := range subscriptions {
+		webhooks = append(webhooks, webhookGet(subscription))
+	}
+
+	JSON(w, http.StatusOK, model.WebhooksGet{Webhooks: webhooks})
+}
+
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := uuid.Parse(mux.Vars(r)[webhookIDVar])
+	if err != nil {
+		Error(w, r, http.StatusBadRequest, fmt.Errorf("parsing webhookID: %w", err), "getting webhookID from path")
+
+		return
+	}
+
+	if err = s.webhookService.DeleteSubscription(r.Context(), webhookID); err != nil {
+		ServiceError(w, r, err, "deleting webhook")
+
+		return
+	}
+
+	JSON(w, http.StatusNoContent, nil)
+}
+
+// GetWebhookDeadLetters returns the deliveries given up on, the latest first.
+func (s *Server) GetWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var queryParams model.WebhookDeadLettersQueryParams
+
+	if err := decodeQuery(r, &queryParams); err != nil {
+		RequestError(w, r, err, "decoding query params")
+
+		return
+	}
+
+	if queryParams.Limit == 0 {
+		queryParams.Limit = defaultDeadLettersLimit
+	}
+
+	v := &validator{}
+	v.between("limit", float64(queryParams.Limit), 1, maxDeadLettersLimit)
+
+	if err := v.err(); err != nil {
+		RequestError(w, r, err, "validating query params")
+
+		return
+	}
+
+	letters, err := s.webhookService.GetDeadLetters(r.Context(), int64(queryParams.Limit))
+	if err != nil {
+		ServiceError(w, r, err, "getting webhook dead letters")
+
+		return
+	}
+
+	deadLetters := make([]model.WebhookDeadLetterGet, 0, len(letters))
+
+	for _, letter := range letters {
+		deadLetters = append(deadLetters, model.WebhookDeadLetterGet{
+			ID:        letter.ID,
+			WebhookID: letter.SubscriptionID,
+			URL:       letter.URL,
+			EventType: letter.EventType,
+			Payload:   json.RawMessage(letter.Payload),
+			Attempts:  letter.Attempts,
+			LastError: letter.LastError,
+			FailedAt:  letter.FailedAt,
+		})
+	}
+
+	JSON(w, http.StatusOK, model.WebhookDeadLettersGet{DeadLetters: deadLetters})
+}
+
+func webhookGet(subscription *redismodel.WebhookSubscription) model.WebhookGet {
+	return model.WebhookGet{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func validateWebhookPost(webhook *model.WebhookPost) error {
+	v := &validator{}
+
+	target, err := url.Parse(webhook.URL)
+	v.check(err == nil && (target.Scheme == "http" || target.Scheme == "https") && target.Host != "",
+		"url", "has to be an absolute http or https URL")
+
+	v.required("secret", webhook.Secret)
+	v.check(len(webhook.EventTypes) > 0, "eventTypes", reasonRequired)
+
+	for _, eventType := range webhook.EventTypes {
+		v.check(eventsmodel.EventType(eventType).IsValid(), "eventTypes", "has unknown event type "+eventType)
+	}
+
+	return v.err()
+}
@@ -0,0 +1,270 @@
+//go:build unit
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+	mockwebhook "scootinAboot/internal/module/webhook/transfer/mock"
+)
+
+const (
+	testWebhookURL    = "https://billing.example.com/hooks"
+	testWebhookSecret = "webhook_secret"
+)
+
+func testWebhook() *redismodel.WebhookSubscription {
+	return redismodel.NewWebhookSubscription(
+		uuid.New(),
+		testWebhookURL,
+		testWebhookSecret,
+		[]string{"scooter_rented", "scooter_freed"},
+		time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+	subscription := testWebhook()
+
+	expectedWebhookJSON, err := json.Marshal(webhookGet(subscription))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		mockWebhookServiceHandler func(mock *mockwebhook.MockWebhookService)
+		body                      string
+		expectedCode              int
+		expectedBody              string
+	}{
+		"successfully creating webhook": {
+			mockWebhookServiceHandler: func(mock *mockwebhook.MockWebhookService) {
+				mock.EXPECT().CreateSubscription(gomock.Any(), testWebhookURL, testWebhookSecret, subscription.EventTypes).
+					Return(subscription, nil).Times(1)
+			},
+			body: `{"url":"` + testWebhookURL + `","secret":"` + testWebhookSecret + `",` +
+				`"eventTypes":["scooter_rented","scooter_freed"]}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: string(expectedWebhookJSON),
+		},
+		"failed creating webhook because it is invalid": {
+			mockWebhookServiceHandler: nil,
+			body:                      `{"url":"ftp://billing.example.com","eventTypes":["scooter_rented","scooter_stolen"]}`,
+			expectedCode:              http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity",` +
+				`"status":422,"detail":"validating webhook: request has invalid fields",` +
+				`"instance":"/v1/admin/webhooks","code":"invalid_request","invalidParams":[` +
+				`{"name":"url","reason":"has to be an absolute http or https URL"},` +
+				`{"name":"secret","reason":"is required"},` +
+				`{"name":"eventTypes","reason":"has unknown event type scooter_stolen"}]}`,
+		},
+		"failed creating webhook because redis is unavailable": {
+			mockWebhookServiceHandler: func(mock *mockwebhook.MockWebhookService) {
+				mock.EXPECT().CreateSubscription(gomock.Any(), testWebhookURL, testWebhookSecret, []string{"ride_errored"}).
+					Return(nil, domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			body:         `{"url":"` + testWebhookURL + `","secret":"` + testWebhookSecret + `","eventTypes":["ride_errored"]}`,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"type":"urn:scootinaboot:problem:service_unavailable","title":"Service Unavailable",` +
+				`"status":503,"detail":"creating webhook: service is temporarily unavailable, try again later",` +
+				`"instance":"/v1/admin/webhooks","code":"service_unavailable"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+webhooksPath, http.MethodPost, []byte(tt.body), testAdminToken(t))
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockWebhookServiceHandler != nil {
+				tt.mockWebhookServiceHandler(mockWebhookService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestGetWebhooks(t *testing.T) {
+	s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+	subscription := testWebhook()
+
+	mockWebhookService.EXPECT().GetSubscriptions(gomock.Any()).
+		Return([]*redismodel.WebhookSubscription{subscription}, nil).Times(1)
+
+	request := buildAdminRequest(t, adminPath+webhooksPath, http.MethodGet, nil, testAdminToken(t))
+
+	responseRecorder := httptest.NewRecorder()
+
+	s.router.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.NotContains(t, responseRecorder.Body.String(), testWebhookSecret)
+
+	var webhooks model.WebhooksGet
+
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &webhooks))
+	require.Equal(t, model.WebhooksGet{Webhooks: []model.WebhookGet{webhookGet(subscription)}}, webhooks)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+	webhookID := uuid.New()
+
+	tests := map[string]struct {
+		mockWebhookServiceHandler func(mock *mockwebhook.MockWebhookService)
+		webhookID                 string
+		expectedCode              int
+		expectedBody              string
+	}{
+		"successfully deleting webhook": {
+			mockWebhookServiceHandler: func(mock *mockwebhook.MockWebhookService) {
+				mock.EXPECT().DeleteSubscription(gomock.Any(), webhookID).Return(nil).Times(1)
+			},
+			webhookID:    webhookID.String(),
+			expectedCode: http.StatusNoContent,
+			expectedBody: "null",
+		},
+		"failed deleting webhook because it doesn't exist": {
+			mockWebhookServiceHandler: func(mock *mockwebhook.MockWebhookService) {
+				mock.EXPECT().DeleteSubscription(gomock.Any(), webhookID).
+					Return(redismodel.ErrWebhookSubscriptionNotFound).Times(1)
+			},
+			webhookID:    webhookID.String(),
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"type":"urn:scootinaboot:problem:webhook_subscription_not_found","title":"Not Found",` +
+				`"status":404,"detail":"deleting webhook: webhook subscription with given ID was not found",` +
+				`"instance":"/v1/admin/webhooks/` + webhookID.String() + `","code":"webhook_subscription_not_found"}`,
+		},
+		"failed deleting webhook because its ID is not UUID": {
+			mockWebhookServiceHandler: nil,
+			webhookID:                 "first",
+			expectedCode:              http.StatusBadRequest,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,` +
+				`"detail":"getting webhookID from path: bad request","instance":"/v1/admin/webhooks/first",` +
+				`"code":"bad_request"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+webhooksPath+"/"+tt.webhookID, http.MethodDelete, nil,
+				testAdminToken(t))
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockWebhookServiceHandler != nil {
+				tt.mockWebhookServiceHandler(mockWebhookService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestGetWebhookDeadLetters(t *testing.T) {
+	s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+	letter := redismodel.NewWebhookDeadLetter(
+		uuid.New(),
+		testWebhook(),
+		"scooter_freed",
+		[]byte(`{"type":"scooter_freed"}`),
+		5,
+		"endpoint responded with 502 Bad Gateway",
+		time.Date(2024, time.January, 1, 12, 5, 0, 0, time.UTC),
+	)
+
+	tests := map[string]struct {
+		mockWebhookServiceHandler func(mock *mockwebhook.MockWebhookService)
+		query                     string
+		expectedCode              int
+		expectedBody              string
+	}{
+		"successfully getting dead letters": {
+			mockWebhookServiceHandler: func(mock *mockwebhook.MockWebhookService) {
+				mock.EXPECT().GetDeadLetters(gomock.Any(), int64(defaultDeadLettersLimit)).
+					Return([]*redismodel.WebhookDeadLetter{letter}, nil).Times(1)
+			},
+			query:        "",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"deadLetters":[{"id":"` + letter.ID.String() + `","webhookID":"` +
+				letter.SubscriptionID.String() + `","url":"` + testWebhookURL + `","eventType":"scooter_freed",` +
+				`"payload":{"type":"scooter_freed"},"attempts":5,"lastError":"endpoint responded with 502 Bad Gateway",` +
+				`"failedAt":"2024-01-01T12:05:00Z"}]}`,
+		},
+		"successfully getting no dead letters": {
+			mockWebhookServiceHandler: func(mock *mockwebhook.MockWebhookService) {
+				mock.EXPECT().GetDeadLetters(gomock.Any(), int64(5)).Return(nil, nil).Times(1)
+			},
+			query:        "?limit=5",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"deadLetters":[]}`,
+		},
+		"failed getting dead letters because limit is too big": {
+			mockWebhookServiceHandler: nil,
+			query:                     "?limit=1000",
+			expectedCode:              http.StatusUnprocessableEntity,
+			expectedBody: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity",` +
+				`"status":422,"detail":"validating query params: request has invalid fields",` +
+				`"instance":"/v1/admin/webhooks/dead-letters","code":"invalid_request",` +
+				`"invalidParams":[{"name":"limit","reason":"has to be between 1 and 100"}]}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildAdminRequest(t, adminPath+deadLettersPath+tt.query, http.MethodGet, nil, testAdminToken(t))
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockWebhookServiceHandler != nil {
+				tt.mockWebhookServiceHandler(mockWebhookService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			if body := responseRecorder.Body.String(); body != tt.expectedBody {
+				t.Errorf("handler returned unexpected body: got = %v want = %v",
+					body, tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,738 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/metrics"
+	ratelimit "scootinAboot/internal/module/ratelimit/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
+	"scootinAboot/internal/tracing"
+)
+
+const (
+	headerAuthorization      = "Authorization"
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	headerRetryAfter         = "Retry-After"
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+	headerRateLimitPolicy    = "RateLimit-Policy"
+	headerAcceptEncoding     = "Accept-Encoding"
+	headerContentEncoding    = "Content-Encoding"
+	headerContentLength      = "Content-Length"
+	headerVary               = "Vary"
+	headerOrigin             = "Origin"
+	bearerPrefix             = "Bearer "
+	encodingGzip             = "gzip"
+
+	headerCORSAllowOrigin   = "Access-Control-Allow-Origin"
+	headerCORSAllowMethods  = "Access-Control-Allow-Methods"
+	headerCORSAllowHeaders  = "Access-Control-Allow-Headers"
+	headerCORSExposeHeaders = "Access-Control-Expose-Headers"
+	headerCORSMaxAge        = "Access-Control-Max-Age"
+	headerCORSRequestMethod = "Access-Control-Request-Method"
+	corsAnyOrigin           = "*"
+
+	maxIdempotencyKeyLength = 255
+	maxRequestIDLength      = 128
+
+	contentTypeMetrics = "text/plain"
+)
+
+var (
+	// The methods and headers the web app may send, and the headers of the responses its scripts may read
+	corsAllowedMethods = strings.Join(
+		[]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		", ",
+	)
+	corsAllowedHeaders = strings.Join(
+		[]string{headerAuthorization, headerContentType, headerIdempotencyKey, headerRequestID},
+		", ",
+	)
+	corsExposedHeaders = strings.Join(
+		[]string{
+			headerRequestID,
+			headerIdempotentReplayed,
+			headerRetryAfter,
+			headerRateLimitLimit,
+			headerRateLimitRemaining,
+			headerRateLimitReset,
+			headerRateLimitPolicy,
+		},
+		", ",
+	)
+
+	errRequestTimeout = domain.Timeout("request_timeout", "request wasn't handled in time, try again later")
+	errPanic          = errors.New("handler panicked")
+
+	errIdempotencyKeyTooLong = domain.Validation(
+		"idempotency_key_too_long",
+		fmt.Sprintf("idempotency key can't be longer than %d characters", maxIdempotencyKeyLength),
+	)
+)
+
+// requestID makes sure every request has an ID, the one sent by the client in X-Request-ID or a generated one. The ID
+// is answered in the same header and, together with the scooter the request is about, it is attached to every record
+// logged with the request's context, so the records can be correlated with the ones of the ride. The client is
+// attached once its token is verified.
+func (s *Server) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(headerRequestID)
+		if !validRequestID(id) {
+			// Only IDs chosen by the client are echoed in problem details
+			r.Header.Del(headerRequestID)
+
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(headerRequestID, id)
+
+		ctx := logging.With(r.Context(), logging.KeyRequestID, id)
+
+		if scooterUUID, err := uuid.Parse(mux.Vars(r)[scooterUUIDVar]); err == nil {
+			ctx = logging.With(ctx, logging.KeyScooterUUID, scooterUUID.String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID accepts IDs of printable ASCII characters without spaces, so they can't forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// trace spans the request by its route template, as a child of the span the client propagated in the W3C
+// traceparent header if it sent one. The spans of the services the handler calls are nested under it.
+func (s *Server) trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, err := mux.CurrentRoute(r).GetPathTemplate()
+		if err != nil {
+			route = r.URL.Path
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Tracer().Start(
+			ctx,
+			r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(r.Method), semconv.HTTPRoute(route)),
+		)
+		defer span.End()
+
+		if id, ok := logging.Attr(ctx, logging.KeyRequestID); ok {
+			span.SetAttributes(tracing.KeyRequestID.String(id.String()))
+		}
+
+		if scooterUUID, ok := logging.Attr(ctx, logging.KeyScooterUUID); ok {
+			span.SetAttributes(tracing.KeyScooterUUID.String(scooterUUID.String()))
+		}
+
+		// The records of a sampled request point to its trace
+		if span.SpanContext().IsSampled() {
+			ctx = logging.With(ctx, logging.KeyTraceID, span.SpanContext().TraceID().String())
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCodeKey.Int(recorder.status))
+
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// instrument counts the requests and measures how long they take by the route template, so the IDs in the paths
+// don't multiply the series. Requests no route matched don't reach it.
+func (s *Server) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		route, err := mux.CurrentRoute(r).GetPathTemplate()
+		if err != nil {
+			route = r.URL.Path
+		}
+
+		metrics.HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(recorder.status)).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder remembers the status the handler responded with, flushing is passed through for the event streams.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// authentication identifies the caller by the bearer token signed by the signer. The principal is put into the
+// request's context and its role into the request's records and span, riders are attached by their client as well.
+func (s *Server) authentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get(headerAuthorization), bearerPrefix)
+
+		principal, err := s.principal(token)
+		if err != nil {
+			ServiceError(w, r, err, "authenticating request")
+
+			return
+		}
+
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		ctx = logging.With(ctx, logging.KeyRole, string(principal.Role))
+
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(tracing.KeyRole.String(string(principal.Role)))
+
+		if principal.Role == auth.RoleRider {
+			ctx = logging.With(ctx, logging.KeyClientUUID, principal.Subject.String())
+			span.SetAttributes(tracing.KeyClientUUID.String(principal.Subject.String()))
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// principal verifies the token, the admin token of the instance isn't one, it only bootstraps the tokens of admins.
+func (s *Server) principal(token string) (*auth.Principal, error) {
+	principal, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("verifying token: %w", err)
+	}
+
+	return principal, nil
+}
+
+// authorize lets through only the principals whose role has the permission, it has to run after authentication.
+// Checks of the resources, e.g. the city of an operator, are left to the handlers.
+func (s *Server) authorize(permission auth.Permission) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				ServiceError(w, r, auth.ErrMissingToken, "authorizing request")
+
+				return
+			}
+
+			if err := principal.Authorize(permission); err != nil {
+				ServiceError(w, r, err, "authorizing request")
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimit takes a token from the bucket the caller has for the route, requests finding it empty are answered with
+// 429 until it refills. Callers are told about their limit in the RateLimit headers. The buckets are kept in Redis,
+// so the limits hold across the instances, but Redis being down doesn't take the API down with it: the requests go
+// through unlimited then. Callers are identified by the principal, so it has to run after authentication on the
+// routes that need a token, and by their address on the public ones.
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rateLimit, err := s.rateLimitService.Take(r.Context(), operationID(r), rateLimitCaller(r))
+		if err != nil {
+			s.logger.WarnContext(r.Context(), "rate limiting failed, request is let through", logging.KeyError, err)
+		}
+
+		if rateLimit == nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Set(headerRateLimitLimit, strconv.Itoa(rateLimit.Limit))
+		w.Header().Set(headerRateLimitRemaining, strconv.Itoa(rateLimit.Remaining))
+		w.Header().Set(headerRateLimitReset, strconv.Itoa(seconds(rateLimit.Reset)))
+		w.Header().Set(headerRateLimitPolicy, fmt.Sprintf("%d;w=%d", rateLimit.Limit, seconds(rateLimit.Window)))
+
+		if !rateLimit.Allowed {
+			w.Header().Set(headerRetryAfter, strconv.Itoa(max(seconds(rateLimit.RetryAfter), 1)))
+			ServiceError(w, r, ratelimit.ErrRateLimited, "limiting request rate")
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitCaller identifies whose bucket the request takes its token from. Addresses are taken from the connection,
+// the headers of proxies could be forged by the callers to get a new bucket for every request.
+func rateLimitCaller(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return principal.Subject.String()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// seconds rounds the duration up, so the caller waiting that long doesn't come too early.
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+// idempotency replays the stored response for requests repeating the Idempotency-Key of an already handled request,
+// so a client retrying on a flaky network does not rent or free the scooter twice. Keys are scoped by the principal,
+// so it has to run after authentication.
+func (s *Server) idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(headerIdempotencyKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			Error(w, r, http.StatusBadRequest, errIdempotencyKeyTooLong, "reading idempotency key")
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			RequestError(w, r, err, "reading request body")
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var scope uuid.UUID
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			scope = principal.Subject
+		}
+
+		scopedKey := scope.String() + ":" + key
+		fingerprint := requestFingerprint(r, body)
+
+		stored, err := s.idempotencyService.Begin(r.Context(), scopedKey, fingerprint)
+		if err != nil {
+			ServiceError(w, r, err, "checking idempotency key")
+
+			return
+		}
+
+		if stored != nil {
+			replay(w, stored)
+
+			return
+		}
+
+		recorder := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		completed := false
+
+		// The key is freed or stored even when the request timed out or the client went away
+		keyCtx := context.WithoutCancel(r.Context())
+
+		// Failures on the server side and panics are not final, the key is freed so the request can be retried
+		defer func() {
+			if completed {
+				return
+			}
+
+			if abandonErr := s.idempotencyService.Abandon(keyCtx, scopedKey); abandonErr != nil {
+				s.logger.ErrorContext(r.Context(), "abandoning idempotency key failed", logging.KeyError, abandonErr)
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		response := redismodel.NewIdempotentResponse(
+			fingerprint,
+			recorder.statusCode,
+			recorder.Header().Get(headerContentType),
+			recorder.body.Bytes(),
+		)
+
+		if err = s.idempotencyService.Complete(keyCtx, scopedKey, response); err != nil {
+			s.logger.ErrorContext(r.Context(), "completing idempotency key failed", logging.KeyError, err)
+
+			return
+		}
+
+		completed = true
+	})
+}
+
+// requestFingerprint identifies the request, so the key reused for a different one is recognized.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, response *redismodel.IdempotentResponse) {
+	if response.ContentType != "" {
+		w.Header().Set(headerContentType, response.ContentType)
+	}
+
+	w.Header().Set(headerIdempotentReplayed, "true")
+	w.WriteHeader(response.StatusCode)
+	_, _ = w.Write(response.Body)
+}
+
+// recordingResponseWriter keeps a copy of the response written by the handler.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(statusCode int) {
+	rw.statusCode = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *recordingResponseWriter) Write(body []byte) (int, error) {
+	rw.body.Write(body)
+
+	return rw.ResponseWriter.Write(body)
+}
+
+// chain wraps the handler in the middlewares, the first of them runs first.
+func chain(handler http.Handler, middlewares ...mux.MiddlewareFunc) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// recovery answers the request whose handler panicked with 500 instead of dropping the connection, the panic is logged
+// with its stack. Handlers aborting on purpose with http.ErrAbortHandler are left to the HTTP server, so are the ones
+// that already started the response, their connection is dropped as the client can't be told about the failure.
+func (s *Server) recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &startRecorder{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			err := fmt.Errorf("%w: %v\n%s", errPanic, recovered, debug.Stack())
+
+			if recorder.started {
+				s.logger.ErrorContext(r.Context(), "handler panicked after response started", logging.KeyError, err)
+
+				panic(http.ErrAbortHandler)
+			}
+
+			Error(w, r, http.StatusInternalServerError, err, "handling request")
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// startRecorder remembers whether the handler started the response, flushing is passed through for the event streams.
+type startRecorder struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (sr *startRecorder) WriteHeader(status int) {
+	sr.started = true
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *startRecorder) Write(body []byte) (int, error) {
+	sr.started = true
+
+	return sr.ResponseWriter.Write(body)
+}
+
+func (sr *startRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		sr.started = true
+		flusher.Flush()
+	}
+}
+
+func (sr *startRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// timeout gives the request the time of its operation, from ROUTE_TIMEOUTS_MILLISECONDS or the default one, by the
+// deadline of its context. The handler gives up at its next call to Redis once the deadline passes and the request is
+// answered with 503, see ServiceError. The event streams have no deadline, they last as long as the client wants.
+func (s *Server) timeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := s.requestTimeout(r)
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s *Server) requestTimeout(r *http.Request) time.Duration {
+	milliseconds := s.config.RequestTimeoutMilliseconds
+
+	if operation, ok := routeOperation(r); ok {
+		if operation.streamed() {
+			return 0
+		}
+
+		if routeMilliseconds, found := s.config.RouteTimeoutsMilliseconds[operation.id]; found {
+			milliseconds = routeMilliseconds
+		}
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond
+}
+
+// limitBody answers the requests whose body is longer than MAX_BODY_BYTES with 413, the ones declaring the length
+// right away, the others once the handler reads past the limit.
+func (s *Server) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := s.config.MaxBodyBytes
+		if limit <= 0 || r.Body == nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if r.ContentLength > limit {
+			RequestError(w, r, &http.MaxBytesError{Limit: limit}, "limiting request body")
+
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// compress gzips the responses of the operations answering lists, e.g. of scooters or rentals, for the clients
+// accepting it. The writers are pooled, every one of them holds a few hundred kilobytes of compression state.
+func (s *Server) compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if operation, ok := routeOperation(r); !ok || !operation.compressed {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Add(headerVary, headerAcceptEncoding)
+
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		writer, _ := s.gzipWriters.Get().(*gzip.Writer)
+		defer s.gzipWriters.Put(writer)
+
+		writer.Reset(w)
+
+		compressed := &gzipResponseWriter{ResponseWriter: w, writer: writer}
+
+		next.ServeHTTP(compressed, r)
+
+		// A handler writing nothing leaves the response as it is, the gzip header would be its only content
+		if !compressed.wroteHeader {
+			return
+		}
+
+		if err := writer.Close(); err != nil {
+			s.logger.WarnContext(r.Context(), "finishing compressed response failed", logging.KeyError, err)
+		}
+	})
+}
+
+// newGzipWriters pools the gzip writers of the level, a level compress/gzip doesn't know is replaced by its default.
+func newGzipWriters(logger *slog.Logger, level int) *sync.Pool {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		logger.Warn("unknown gzip level, the default one is used", "level", level)
+
+		level = gzip.DefaultCompression
+	}
+
+	return &sync.Pool{
+		New: func() any {
+			writer, _ := gzip.NewWriterLevel(io.Discard, level)
+
+			return writer
+		},
+	}
+}
+
+// acceptsGzip tells whether the client listed gzip in Accept-Encoding, unless with the weight of 0.
+func acceptsGzip(r *http.Request) bool {
+	for _, coding := range strings.Split(r.Header.Get(headerAcceptEncoding), ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		if !strings.EqualFold(strings.TrimSpace(name), encodingGzip) {
+			continue
+		}
+
+		weight, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+
+		if q, err := strconv.ParseFloat(weight, 64); err != nil || q > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gzipResponseWriter compresses the body written by the handler, the length set by the handler is the one of the
+// uncompressed body, so it is dropped.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	wroteHeader bool
+}
+
+func (gw *gzipResponseWriter) WriteHeader(statusCode int) {
+	if !gw.wroteHeader {
+		gw.wroteHeader = true
+
+		gw.Header().Set(headerContentEncoding, encodingGzip)
+		gw.Header().Del(headerContentLength)
+	}
+
+	gw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (gw *gzipResponseWriter) Write(body []byte) (int, error) {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+
+	return gw.writer.Write(body)
+}
+
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// cors lets the web app call the API from the origins in CORS_ALLOWED_ORIGINS. It runs before the routing, the
+// preflight requests the browsers send ahead of the calls match no route. Requests from the other origins are
+// answered without the CORS headers, so the browsers keep the responses from the scripts of those origins.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get(headerOrigin)
+		if origin == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Add(headerVary, headerOrigin)
+
+		if !slices.Contains(s.config.CORSAllowedOrigins, origin) &&
+			!slices.Contains(s.config.CORSAllowedOrigins, corsAnyOrigin) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Set(headerCORSAllowOrigin, origin)
+
+		if r.Method == http.MethodOptions && r.Header.Get(headerCORSRequestMethod) != "" {
+			w.Header().Set(headerCORSAllowMethods, corsAllowedMethods)
+			w.Header().Set(headerCORSAllowHeaders, corsAllowedHeaders)
+			w.Header().Set(headerCORSMaxAge, strconv.Itoa(s.config.CORSMaxAgeSeconds))
+			w.WriteHeader(http.StatusNoContent)
+
+			return
+		}
+
+		w.Header().Set(headerCORSExposeHeaders, corsExposedHeaders)
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,1231 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/metrics"
+	"scootinAboot/internal/model"
+	availabilitymodel "scootinAboot/internal/module/availability/model"
+	availability "scootinAboot/internal/module/availability/transfer"
+	idempotency "scootinAboot/internal/module/idempotency/transfer"
+	mockidempotency "scootinAboot/internal/module/idempotency/transfer/mock"
+	ratelimit "scootinAboot/internal/module/ratelimit/transfer"
+	mockratelimit "scootinAboot/internal/module/ratelimit/transfer/mock"
+	redismodel "scootinAboot/internal/module/redis/model"
+	rentalmodel "scootinAboot/internal/module/rental/model"
+	mockrental "scootinAboot/internal/module/rental/transfer/mock"
+	"scootinAboot/internal/tracing"
+)
+
+const testIdempotencyKey = "retry-1"
+
+func TestIdempotency(t *testing.T) {
+	s, _, mockRentalService, _, mockIdempotencyService, _, _ := beforeTest(t)
+
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooterJSON, err := json.Marshal(model.ScooterPost{
+		UUID:      uuid.New(),
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+		City:      testCity,
+	})
+	require.NoError(t, err)
+
+	scopedKey := clientUUID.String() + ":" + testIdempotencyKey
+
+	tests := map[string]struct {
+		mockRentalServiceHandler      func(mock *mockrental.MockRentalService)
+		mockIdempotencyServiceHandler func(mock *mockidempotency.MockIdempotencyService)
+		key                           string
+		expectedCode                  int
+		expectedReplay                bool
+	}{
+		"successfully renting scooter without idempotency key": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, gomock.Any()).Return(nil).Times(1)
+			},
+			mockIdempotencyServiceHandler: nil,
+			key:                           "",
+			expectedCode:                  http.StatusNoContent,
+			expectedReplay:                false,
+		},
+		"successfully renting scooter and storing the response": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, gomock.Any()).Return(nil).Times(1)
+			},
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, nil).Times(1)
+				mock.EXPECT().Complete(gomock.Any(), scopedKey, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, response *redismodel.IdempotentResponse) error {
+						require.True(t, response.Completed)
+						require.Equal(t, http.StatusNoContent, response.StatusCode)
+
+						return nil
+					}).Times(1)
+			},
+			key:            testIdempotencyKey,
+			expectedCode:   http.StatusNoContent,
+			expectedReplay: false,
+		},
+		"successfully replaying the response of already handled request": {
+			mockRentalServiceHandler: nil,
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).
+					Return(redismodel.NewIdempotentResponse("", http.StatusNoContent, "", nil), nil).Times(1)
+			},
+			key:            testIdempotencyKey,
+			expectedCode:   http.StatusNoContent,
+			expectedReplay: true,
+		},
+		"failed renting scooter because idempotency key was used for different request": {
+			mockRentalServiceHandler: nil,
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, idempotency.ErrKeyReused).Times(1)
+			},
+			key:            testIdempotencyKey,
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedReplay: false,
+		},
+		"failed renting scooter because the first request is still being handled": {
+			mockRentalServiceHandler: nil,
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, idempotency.ErrRequestInProgress).Times(1)
+			},
+			key:            testIdempotencyKey,
+			expectedCode:   http.StatusConflict,
+			expectedReplay: false,
+		},
+		"failed renting scooter and abandoning the key because redis is unavailable": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, gomock.Any()).Return(domain.Unavailable(redis.ErrClosed)).Times(1)
+			},
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, nil).Times(1)
+				mock.EXPECT().Abandon(gomock.Any(), scopedKey).Return(nil).Times(1)
+			},
+			key:            testIdempotencyKey,
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedReplay: false,
+		},
+		"failed renting scooter and abandoning the key because handler panicked": {
+			mockRentalServiceHandler: func(mock *mockrental.MockRentalService) {
+				mock.EXPECT().Rent(gomock.Any(), clientUUID, gomock.Any()).
+					DoAndReturn(func(context.Context, uuid.UUID, *rentalmodel.RentalScooter) error {
+						panic("renting went wrong")
+					}).Times(1)
+			},
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, nil).Times(1)
+				mock.EXPECT().Abandon(gomock.Any(), scopedKey).Return(nil).Times(1)
+			},
+			key:            testIdempotencyKey,
+			expectedCode:   http.StatusInternalServerError,
+			expectedReplay: false,
+		},
+		"failed renting scooter because idempotency key is too long": {
+			mockRentalServiceHandler:      nil,
+			mockIdempotencyServiceHandler: nil,
+			key:                           strings.Repeat("k", maxIdempotencyKeyLength+1),
+			expectedCode:                  http.StatusBadRequest,
+			expectedReplay:                false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := buildClientRequest(t, rentPath, http.MethodPost, bytes.NewBuffer(scooterJSON), clientUUID)
+
+			if tt.key != "" {
+				request.Header.Set(headerIdempotencyKey, tt.key)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.mockRentalServiceHandler != nil {
+				tt.mockRentalServiceHandler(mockRentalService)
+			}
+
+			if tt.mockIdempotencyServiceHandler != nil {
+				tt.mockIdempotencyServiceHandler(mockIdempotencyService)
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			if status := responseRecorder.Code; status != tt.expectedCode {
+				t.Errorf("handler returned wrong status code: got = %v want = %v",
+					status, tt.expectedCode)
+			}
+
+			require.Equal(t, tt.expectedReplay, responseRecorder.Header().Get(headerIdempotentReplayed) == "true")
+		})
+	}
+}
+
+func TestIdempotencyTimeout(t *testing.T) {
+	clientUUID, err := uuid.NewRandom()
+	require.NoError(t, err)
+
+	scooterJSON, err := json.Marshal(model.ScooterPost{
+		UUID:      uuid.New(),
+		Longitude: testLongitude,
+		Latitude:  testLatitude,
+		City:      testCity,
+	})
+	require.NoError(t, err)
+
+	scopedKey := clientUUID.String() + ":" + testIdempotencyKey
+
+	// The key is handled after the deadline of the request, so its context must not be cancelled
+	requireLive := func(ctx context.Context) {
+		require.NoError(t, ctx.Err())
+	}
+
+	tests := map[string]struct {
+		rentErr                       error
+		mockIdempotencyServiceHandler func(mock *mockidempotency.MockIdempotencyService)
+		expectedCode                  int
+	}{
+		"successfully abandoning the key of timed out request": {
+			rentErr: context.DeadlineExceeded,
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, nil).Times(1)
+				mock.EXPECT().Abandon(gomock.Any(), scopedKey).
+					DoAndReturn(func(ctx context.Context, _ string) error {
+						requireLive(ctx)
+
+						return nil
+					}).Times(1)
+			},
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		"successfully storing the response of request finished past its deadline": {
+			rentErr: nil,
+			mockIdempotencyServiceHandler: func(mock *mockidempotency.MockIdempotencyService) {
+				mock.EXPECT().Begin(gomock.Any(), scopedKey, gomock.Any()).Return(nil, nil).Times(1)
+				mock.EXPECT().Complete(gomock.Any(), scopedKey, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ string, _ *redismodel.IdempotentResponse) error {
+						requireLive(ctx)
+
+						return nil
+					}).Times(1)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, mockRentalService, _, mockIdempotencyService, _, _ := beforeTest(t)
+
+			s.config.RequestTimeoutMilliseconds = 1
+
+			mockRentalService.EXPECT().Rent(gomock.Any(), clientUUID, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ uuid.UUID, _ *rentalmodel.RentalScooter) error {
+					<-ctx.Done()
+
+					if tt.rentErr != nil {
+						return domain.Unavailable(tt.rentErr)
+					}
+
+					return nil
+				}).Times(1)
+
+			tt.mockIdempotencyServiceHandler(mockIdempotencyService)
+
+			request := buildClientRequest(t, rentPath, http.MethodPost, bytes.NewBuffer(scooterJSON), clientUUID)
+			request.Header.Set(headerIdempotencyKey, testIdempotencyKey)
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.expectedCode, responseRecorder.Code)
+		})
+	}
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	rentRequest := httptest.NewRequest(http.MethodPost, version+rentPath, nil)
+	freeRequest := httptest.NewRequest(http.MethodPost, version+freePath, nil)
+
+	body := []byte(`{"uuid":"0dae4f8c-dbbf-4bac-90f2-b80f07255ba5"}`)
+
+	require.Equal(t, requestFingerprint(rentRequest, body), requestFingerprint(rentRequest, body))
+	require.NotEqual(t, requestFingerprint(rentRequest, body), requestFingerprint(freeRequest, body))
+	require.NotEqual(t, requestFingerprint(rentRequest, body), requestFingerprint(rentRequest, []byte(`{}`)))
+}
+
+func TestInstrument(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	scooterUUID := uuid.New()
+	route := version + adminPath + adminScooterPath
+
+	mockRedisService.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(nil, redismodel.ErrScooterNotFound).Times(1)
+
+	requests := metrics.HTTPRequests.WithLabelValues(route, http.MethodGet, "404")
+	before := testutil.ToFloat64(requests)
+
+	request := buildAdminRequest(
+		t,
+		adminPath+scootersPath+"/"+scooterUUID.String(),
+		http.MethodGet,
+		nil,
+		testAdminToken(t),
+	)
+
+	s.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	require.Equal(t, before+1, testutil.ToFloat64(requests), "requests are counted by their route template")
+
+	// Served from the default registry, where main registers the collectors
+	if err := metrics.Register(prometheus.DefaultRegisterer); err != nil {
+		require.ErrorAs(t, err, &prometheus.AlreadyRegisteredError{})
+	}
+
+	responseRecorder := httptest.NewRecorder()
+
+	s.router.ServeHTTP(responseRecorder, buildRequest(t, metricsPath, http.MethodGet, &bytes.Buffer{}, false))
+
+	require.Equal(t, http.StatusNotFound, responseRecorder.Code, "metrics are served outside the version")
+
+	metricsRequest, err := http.NewRequest(http.MethodGet, metricsPath, nil)
+	require.NoError(t, err)
+
+	responseRecorder = httptest.NewRecorder()
+
+	s.router.ServeHTTP(responseRecorder, metricsRequest)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Contains(t, responseRecorder.Body.String(),
+		`scootin_http_requests_total{method="GET",route="`+route+`",status="404"}`)
+}
+
+func TestRequestID(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	var output bytes.Buffer
+
+	logger, err := logging.NewLogger(&output, "info", logging.FormatJSON)
+	require.NoError(t, err)
+
+	defaultLogger := slog.Default()
+	slog.SetDefault(logger)
+
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+	})
+
+	scooterUUID := uuid.New()
+
+	tests := map[string]struct {
+		requestID            string
+		wantRequestID        string
+		wantGeneratedID      bool
+		wantProblemRequestID string
+	}{
+		"propagating request ID of the client": {
+			requestID:            "request-1",
+			wantRequestID:        "request-1",
+			wantProblemRequestID: "request-1",
+		},
+		"generating request ID when the client sent none": {
+			requestID:       "",
+			wantGeneratedID: true,
+		},
+		"generating request ID when the client sent an invalid one": {
+			requestID:       "request 1\nlevel=ERROR",
+			wantGeneratedID: true,
+		},
+		"generating request ID when the client sent a too long one": {
+			requestID:       strings.Repeat("r", maxRequestIDLength+1),
+			wantGeneratedID: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			output.Reset()
+
+			mockRedisService.EXPECT().GetScooter(gomock.Any(), scooterUUID).Return(nil, redismodel.ErrScooterNotFound).Times(1)
+
+			request := buildAdminRequest(t, adminPath+scootersPath+"/"+scooterUUID.String(), http.MethodGet, nil,
+				testAdminToken(t))
+
+			if tt.requestID != "" {
+				request.Header.Set(headerRequestID, tt.requestID)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			requestID := responseRecorder.Header().Get(headerRequestID)
+
+			if tt.wantGeneratedID {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err, "generated request ID is a UUID")
+			} else {
+				require.Equal(t, tt.wantRequestID, requestID)
+			}
+
+			var problem model.Problem
+
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+			require.Equal(t, tt.wantProblemRequestID, problem.RequestID, "only IDs of the client are echoed")
+
+			var record map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(output.Bytes(), &record), "the failed request is logged once")
+			require.Equal(t, "getting scooter failed", record["msg"])
+			require.Equal(t, requestID, record[logging.KeyRequestID])
+			require.Equal(t, scooterUUID.String(), record[logging.KeyScooterUUID])
+			require.Equal(t, "scooter_not_found", record["code"])
+		})
+	}
+}
+
+func TestTrace(t *testing.T) {
+	s, mockRedisService, _, _, _, _, _ := beforeTest(t)
+
+	scooterUUID := uuid.New()
+	clientTraceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := map[string]struct {
+		traceparent  string
+		err          error
+		wantCode     int
+		wantStatus   codes.Code
+		wantParentID string
+	}{
+		"tracing request without trace of the client": {
+			traceparent: "",
+			err:         redismodel.ErrScooterNotFound,
+			wantCode:    http.StatusNotFound,
+			wantStatus:  codes.Unset,
+		},
+		"tracing request continuing trace of the client": {
+			traceparent:  "00-" + clientTraceID + "-00f067aa0ba902b7-01",
+			err:          redismodel.ErrScooterNotFound,
+			wantCode:     http.StatusNotFound,
+			wantStatus:   codes.Unset,
+			wantParentID: "00f067aa0ba902b7",
+		},
+		"tracing request failed on the server side": {
+			traceparent: "",
+			err:         errors.New("parsing scooter's battery"),
+			wantCode:    http.StatusInternalServerError,
+			wantStatus:  codes.Error,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tracing.Register(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			// The handler is called within the span of the request
+			var calledIn trace.SpanContext
+
+			mockRedisService.EXPECT().GetScooter(gomock.Any(), scooterUUID).
+				DoAndReturn(func(ctx context.Context, _ uuid.UUID) (*redismodel.FleetScooter, error) {
+					calledIn = trace.SpanContextFromContext(ctx)
+
+					return nil, tt.err
+				}).Times(1)
+
+			request := buildAdminRequest(t, adminPath+scootersPath+"/"+scooterUUID.String(), http.MethodGet, nil,
+				testAdminToken(t))
+			request.Header.Set(headerRequestID, "request-1")
+
+			if tt.traceparent != "" {
+				request.Header.Set("traceparent", tt.traceparent)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+			require.Equal(t, "GET /v1/admin/scooters/{scooterUUID}", span.Name())
+			require.Equal(t, trace.SpanKindServer, span.SpanKind())
+			require.Equal(t, span.SpanContext().SpanID(), calledIn.SpanID())
+			require.Equal(t, tt.wantStatus, span.Status().Code)
+			require.Contains(t, span.Attributes(), semconv.HTTPRoute("/v1/admin/scooters/{scooterUUID}"))
+			require.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCodeKey.Int(tt.wantCode))
+			require.Contains(t, span.Attributes(), tracing.KeyRequestID.String("request-1"))
+			require.Contains(t, span.Attributes(), tracing.KeyScooterUUID.String(scooterUUID.String()))
+
+			if tt.wantParentID == "" {
+				require.False(t, span.Parent().IsValid())
+
+				return
+			}
+
+			require.Equal(t, clientTraceID, span.SpanContext().TraceID().String())
+			require.Equal(t, tt.wantParentID, span.Parent().SpanID().String())
+			require.True(t, span.Parent().IsRemote())
+		})
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	s, _, mockRentalService, _, _, _, _ := beforeTest(t)
+
+	clientUUID, scooterUUID := uuid.New(), uuid.New()
+	token := testClientToken(t, clientUUID)
+
+	expiringSigner, err := auth.NewSigner([]byte(testTokenKey), testName, -time.Minute)
+	require.NoError(t, err)
+
+	expiredToken, _, err := expiringSigner.Issue(auth.NewRider(clientUUID))
+	require.NoError(t, err)
+
+	foreignSigner, err := auth.NewSigner([]byte(strings.Repeat("k", auth.MinKeyLength)), testName, testTokenTTL)
+	require.NoError(t, err)
+
+	foreignToken, _, err := foreignSigner.Issue(auth.NewRider(clientUUID))
+	require.NoError(t, err)
+
+	// The claims of another client are put into the token, so its signature doesn't match them anymore
+	parts := strings.Split(token, ".")
+	parts[1] = strings.Split(testClientToken(t, uuid.New()), ".")[1]
+	tamperedToken := strings.Join(parts, ".")
+
+	tests := map[string]struct {
+		authorization string
+		wantCode      int
+		wantErrorCode string
+	}{
+		"authenticating client by its token": {
+			authorization: bearerPrefix + token,
+			wantCode:      http.StatusCreated,
+		},
+		"failed authenticating client, because the token is missing": {
+			authorization: "",
+			wantCode:      http.StatusUnauthorized,
+			wantErrorCode: "missing_token",
+		},
+		"failed authenticating client, because the token isn't a bearer credential": {
+			authorization: "Basic " + token,
+			wantCode:      http.StatusUnauthorized,
+			wantErrorCode: "invalid_token",
+		},
+		"failed authenticating client, because the token has expired": {
+			authorization: bearerPrefix + expiredToken,
+			wantCode:      http.StatusUnauthorized,
+			wantErrorCode: "expired_token",
+		},
+		"failed authenticating client, because the token was tampered with": {
+			authorization: bearerPrefix + tamperedToken,
+			wantCode:      http.StatusUnauthorized,
+			wantErrorCode: "invalid_token",
+		},
+		"failed authenticating client, because the token was signed by another key": {
+			authorization: bearerPrefix + foreignToken,
+			wantCode:      http.StatusUnauthorized,
+			wantErrorCode: "invalid_token",
+		},
+		"authenticating admin, who can't act as a client": {
+			authorization: bearerPrefix + testAdminToken(t),
+			wantCode:      http.StatusForbidden,
+			wantErrorCode: "role_not_permitted",
+		},
+		"failed authenticating client, because the admin token of the instance isn't a bearer token": {
+			authorization: bearerPrefix + testBootstrapToken,
+			wantCode:      http.StatusUnauthorized,
+			wantErrorCode: "invalid_token",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tracing.Register(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			if tt.wantErrorCode == "" {
+				// The service acts on behalf of the client the token was issued to
+				mockRentalService.EXPECT().Reserve(gomock.Any(), clientUUID, scooterUUID).
+					DoAndReturn(func(ctx context.Context, _, _ uuid.UUID) (*rentalmodel.Reservation, error) {
+						loggedClient, ok := logging.Attr(ctx, logging.KeyClientUUID)
+						require.True(t, ok)
+						require.Equal(t, clientUUID.String(), loggedClient.String())
+
+						return rentalmodel.NewReservation(scooterUUID, clientUUID, time.Now().UTC()), nil
+					}).Times(1)
+			}
+
+			body, err := json.Marshal(model.ReservationPost{ScooterUUID: scooterUUID})
+			require.NoError(t, err)
+
+			request := buildRequest(t, reservationsPath, http.MethodPost, bytes.NewBuffer(body), false)
+			if tt.authorization != "" {
+				request.Header.Set(headerAuthorization, tt.authorization)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			if tt.wantErrorCode != "" {
+				var problem model.Problem
+
+				require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+				require.Equal(t, tt.wantErrorCode, problem.Code)
+
+				return
+			}
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			require.Contains(t, spans[0].Attributes(), tracing.KeyClientUUID.String(clientUUID.String()))
+			require.Contains(t, spans[0].Attributes(), tracing.KeyRole.String(string(auth.RoleRider)))
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	operatorToken := testToken(t, auth.NewOperator(uuid.New(), testCity))
+	signedAdminToken := testToken(t, auth.NewAdmin(uuid.New()))
+
+	tokens := map[string]struct {
+		token string
+		role  auth.Role
+	}{
+		"rider":    {token: testClientToken(t, uuid.New()), role: auth.RoleRider},
+		"operator": {token: operatorToken, role: auth.RoleOperator},
+		"admin":    {token: signedAdminToken, role: auth.RoleAdmin},
+	}
+
+	rider, operator, admin := auth.RoleRider, auth.RoleOperator, auth.RoleAdmin
+
+	// The roles every endpoint is meant for, written out rather than derived so a changed permission shows up here
+	permitted := map[string][]auth.Role{
+		"getScooters":           {rider, operator, admin},
+		"streamScooters":        {rider, operator, admin},
+		"rentScooter":           {rider},
+		"freeScooter":           {rider},
+		"getClientRentals":      {rider},
+		"streamRide":            {rider},
+		"reserveScooter":        {rider},
+		"cancelReservation":     {rider},
+		"getScooterRentals":     {operator, admin},
+		"getScooter":            {operator, admin},
+		"moveScooter":           {operator, admin},
+		"changeScooterStatus":   {operator, admin},
+		"reportScooterBattery":  {operator, admin},
+		"getActiveRentals":      {operator, admin},
+		"createScooter":         {admin},
+		"deleteScooter":         {admin},
+		"getStatus":             {admin},
+		"createWebhook":         {admin},
+		"getWebhooks":           {admin},
+		"getWebhookDeadLetters": {admin},
+		"deleteWebhook":         {admin},
+	}
+
+	pathVar := regexp.MustCompile(`{[^}]+}`)
+
+	for _, operation := range apiOperations {
+		if operation.permission == "" {
+			continue
+		}
+
+		roles, ok := permitted[operation.id]
+		require.Truef(t, ok, "roles permitted to %s are missing", operation.id)
+		require.ElementsMatch(t, roles, auth.RolesWith(operation.permission), operation.id)
+
+		path := pathVar.ReplaceAllStringFunc(operation.path, func(string) string { return uuid.NewString() })
+
+		for name, principal := range tokens {
+			t.Run(operation.id+" as "+name, func(t *testing.T) {
+				s, mockRedisService, mockRentalService, mockTrackerService, _, mockAvailabilityService, mockWebhookService :=
+					beforeTest(t)
+
+				// The handlers fail right after they are reached, only the authorization is of interest here
+				unavailable := domain.Unavailable(redis.ErrClosed)
+
+				mockRedisService.EXPECT().GetScooters(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, unavailable).AnyTimes()
+				mockRedisService.EXPECT().GetScooter(gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockRedisService.EXPECT().GetScooterMetadata(gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockRedisService.EXPECT().GetClientRentals(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, unavailable).AnyTimes()
+				mockRedisService.EXPECT().GetScooterRentals(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, unavailable).AnyTimes()
+				mockRedisService.EXPECT().GetActiveRentals(gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockRedisService.EXPECT().DeleteScooter(gomock.Any(), gomock.Any()).Return(unavailable).AnyTimes()
+				mockRedisService.EXPECT().Ping(gomock.Any()).Return(unavailable).AnyTimes()
+				mockRentalService.EXPECT().Free(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockRentalService.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockRentalService.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(unavailable).AnyTimes()
+				mockRentalService.EXPECT().FollowRide(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, unavailable).AnyTimes()
+				mockTrackerService.EXPECT().ReportBattery(gomock.Any(), gomock.Any(), gomock.Any()).Return(unavailable).AnyTimes()
+				mockTrackerService.EXPECT().Stopping().Return(false).AnyTimes()
+				mockTrackerService.EXPECT().ActiveRides().Return(0).AnyTimes()
+				mockAvailabilityService.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockWebhookService.EXPECT().GetSubscriptions(gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockWebhookService.EXPECT().GetDeadLetters(gomock.Any(), gomock.Any()).Return(nil, unavailable).AnyTimes()
+				mockWebhookService.EXPECT().DeleteSubscription(gomock.Any(), gomock.Any()).Return(unavailable).AnyTimes()
+
+				request := buildAdminRequest(t, path, operation.method, []byte(`{}`), principal.token)
+				responseRecorder := httptest.NewRecorder()
+
+				s.router.ServeHTTP(responseRecorder, request)
+
+				var problem model.Problem
+
+				_ = json.Unmarshal(responseRecorder.Body.Bytes(), &problem)
+
+				if slices.Contains(roles, principal.role) {
+					require.NotEqual(t, http.StatusUnauthorized, responseRecorder.Code)
+					require.NotEqual(t, auth.ErrRoleNotPermitted.Code, problem.Code)
+				} else {
+					require.Equal(t, http.StatusForbidden, responseRecorder.Code)
+					require.Equal(t, auth.ErrRoleNotPermitted.Code, problem.Code)
+				}
+			})
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	clientUUID := uuid.New()
+
+	allowed := &redismodel.RateLimit{
+		Allowed:   true,
+		Limit:     10,
+		Remaining: 9,
+		Window:    time.Minute,
+		Reset:     5500 * time.Millisecond,
+	}
+	refused := &redismodel.RateLimit{
+		Limit:      10,
+		Window:     time.Minute,
+		Reset:      time.Minute,
+		RetryAfter: 5500 * time.Millisecond,
+	}
+
+	tests := map[string]struct {
+		path        string
+		method      string
+		token       string
+		rateLimit   *redismodel.RateLimit
+		err         error
+		wantRoute   string
+		wantCaller  string
+		wantCode    int
+		wantHeaders map[string]string
+	}{
+		"letting request through within the limit": {
+			path:      openAPIPath,
+			method:    http.MethodGet,
+			rateLimit: allowed,
+			wantRoute: "getOpenAPI",
+			// Public routes are limited by the address of the caller
+			wantCaller: "192.0.2.1",
+			wantCode:   http.StatusOK,
+			wantHeaders: map[string]string{
+				headerRateLimitLimit:     "10",
+				headerRateLimitRemaining: "9",
+				headerRateLimitReset:     "6",
+				headerRateLimitPolicy:    "10;w=60",
+				headerRetryAfter:         "",
+			},
+		},
+		"refusing request beyond the limit": {
+			path:       reservationsPath,
+			method:     http.MethodPost,
+			token:      testClientToken(t, clientUUID),
+			rateLimit:  refused,
+			wantRoute:  "reserveScooter",
+			wantCaller: clientUUID.String(),
+			wantCode:   http.StatusTooManyRequests,
+			wantHeaders: map[string]string{
+				headerRateLimitLimit:     "10",
+				headerRateLimitRemaining: "0",
+				headerRateLimitReset:     "60",
+				headerRetryAfter:         "6",
+			},
+		},
+		"refusing request beyond the limit for less than a second": {
+			path:   reservationsPath,
+			method: http.MethodPost,
+			token:  testClientToken(t, clientUUID),
+			rateLimit: &redismodel.RateLimit{
+				Limit:      10,
+				Window:     time.Minute,
+				Reset:      time.Minute,
+				RetryAfter: 0,
+			},
+			wantRoute:   "reserveScooter",
+			wantCaller:  clientUUID.String(),
+			wantCode:    http.StatusTooManyRequests,
+			wantHeaders: map[string]string{headerRetryAfter: "1"},
+		},
+		"letting request through, because the route isn't limited": {
+			path:       openAPIPath,
+			method:     http.MethodGet,
+			rateLimit:  nil,
+			wantRoute:  "getOpenAPI",
+			wantCaller: "192.0.2.1",
+			wantCode:   http.StatusOK,
+			wantHeaders: map[string]string{
+				headerRateLimitLimit: "",
+				headerRetryAfter:     "",
+			},
+		},
+		"letting request through, because redis is unavailable": {
+			path:       openAPIPath,
+			method:     http.MethodGet,
+			err:        domain.Unavailable(redis.ErrClosed),
+			wantRoute:  "getOpenAPI",
+			wantCaller: "192.0.2.1",
+			wantCode:   http.StatusOK,
+			wantHeaders: map[string]string{
+				headerRateLimitLimit: "",
+				headerRetryAfter:     "",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, _ := beforeTest(t)
+
+			mockRateLimitService := mockratelimit.NewMockRateLimitService(gomock.NewController(t))
+			mockRateLimitService.EXPECT().Take(gomock.Any(), tt.wantRoute, tt.wantCaller).
+				Return(tt.rateLimit, tt.err).Times(1)
+
+			s.rateLimitService = mockRateLimitService
+
+			request := buildAdminRequest(t, tt.path, tt.method, nil, tt.token)
+			request.RemoteAddr = "192.0.2.1:1234"
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			for header, want := range tt.wantHeaders {
+				require.Equal(t, want, responseRecorder.Header().Get(header), header)
+			}
+
+			if tt.wantCode == http.StatusTooManyRequests {
+				var problem model.Problem
+
+				require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+				require.Equal(t, ratelimit.ErrRateLimited.Code, problem.Code)
+			}
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	tests := map[string]struct {
+		recovered interface{}
+		wantPanic bool
+	}{
+		"recovering handler panicking with value": {
+			recovered: "assignment to entry in nil map",
+		},
+		"recovering handler panicking with error": {
+			recovered: errors.New("runtime error: index out of range"),
+		},
+		"leaving aborted handler to the server": {
+			recovered: http.ErrAbortHandler,
+			wantPanic: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+			mockWebhookService.EXPECT().GetSubscriptions(gomock.Any()).
+				DoAndReturn(func(context.Context) ([]*redismodel.WebhookSubscription, error) {
+					panic(tt.recovered)
+				}).Times(1)
+
+			request := buildAdminRequest(t, adminPath+webhooksPath, http.MethodGet, nil, testAdminToken(t))
+			responseRecorder := httptest.NewRecorder()
+
+			if tt.wantPanic {
+				require.PanicsWithValue(t, tt.recovered, func() { s.router.ServeHTTP(responseRecorder, request) })
+
+				return
+			}
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+			require.NotEmpty(t, responseRecorder.Header().Get(headerRequestID))
+
+			var problem model.Problem
+
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+			require.Equal(t, "internal_server_error", problem.Code)
+			require.Equal(t, "handling request: internal server error", problem.Detail)
+		})
+	}
+}
+
+func TestRecoveryAfterResponseStarted(t *testing.T) {
+	s, _, _, _, _, _, _ := beforeTest(t)
+
+	handler := s.recovery(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"partial":`))
+
+		panic("encoding went wrong")
+	}))
+
+	request := buildRequest(t, scootersPath, http.MethodGet, bytes.NewBuffer(nil), false)
+	responseRecorder := httptest.NewRecorder()
+
+	// The status was already sent, the server drops the connection instead of appending the problem to the body
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() { handler.ServeHTTP(responseRecorder, request) })
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+	require.Equal(t, `{"partial":`, responseRecorder.Body.String())
+}
+
+func TestTimeout(t *testing.T) {
+	// The service waits for the deadline of the request, if it has any, the way Redis calls do
+	waitForDeadline := func(ctx context.Context) ([]*redismodel.WebhookSubscription, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return nil, nil
+		}
+
+		<-ctx.Done()
+
+		return nil, domain.Unavailable(ctx.Err())
+	}
+
+	tests := map[string]struct {
+		requestTimeout int
+		routeTimeouts  map[string]int
+		wantCode       int
+		wantProblem    string
+	}{
+		"giving up request after default timeout": {
+			requestTimeout: 1,
+			wantCode:       http.StatusServiceUnavailable,
+			wantProblem:    errRequestTimeout.Code,
+		},
+		"giving up request after timeout of its route": {
+			routeTimeouts: map[string]int{"getWebhooks": 1},
+			wantCode:      http.StatusServiceUnavailable,
+			wantProblem:   errRequestTimeout.Code,
+		},
+		"letting request run, because timeout is turned off": {
+			wantCode: http.StatusOK,
+		},
+		"letting request run, because timeout of its route is turned off": {
+			requestTimeout: 1,
+			routeTimeouts:  map[string]int{"getWebhooks": 0},
+			wantCode:       http.StatusOK,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+			s.config.RequestTimeoutMilliseconds = tt.requestTimeout
+			s.config.RouteTimeoutsMilliseconds = tt.routeTimeouts
+
+			mockWebhookService.EXPECT().GetSubscriptions(gomock.Any()).DoAndReturn(waitForDeadline).Times(1)
+
+			request := buildAdminRequest(t, adminPath+webhooksPath, http.MethodGet, nil, testAdminToken(t))
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			if tt.wantProblem != "" {
+				var problem model.Problem
+
+				require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+				require.Equal(t, tt.wantProblem, problem.Code)
+			}
+		})
+	}
+
+	t.Run("streaming scooters without deadline", func(t *testing.T) {
+		s, _, _, _, _, mockAvailabilityService, _ := beforeTest(t)
+
+		s.config.RequestTimeoutMilliseconds = 1
+
+		mockAvailabilityService.EXPECT().Subscribe(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ availabilitymodel.Area) (*availability.Subscription, error) {
+				_, ok := ctx.Deadline()
+				require.False(t, ok, "event streams last as long as the client wants")
+
+				return nil, domain.Unavailable(redis.ErrClosed)
+			}).Times(1)
+
+		request := buildRequest(t, scootersStreamPath+"?longitude=70&latitude=60&radius=10000&city="+testCity,
+			http.MethodGet, bytes.NewBuffer(nil), true)
+
+		s.router.ServeHTTP(httptest.NewRecorder(), request)
+	})
+}
+
+func TestLimitBody(t *testing.T) {
+	clientUUID := uuid.New()
+	overLimit := `{"scooterUUID":"` + uuid.NewString() + `"}`
+
+	tests := map[string]struct {
+		path           string
+		body           string
+		undeclared     bool // the length isn't known until the body is read, e.g. a chunked body
+		idempotencyKey string
+		wantCode       int
+	}{
+		"refusing body declared longer than limit": {
+			path:     reservationsPath,
+			body:     overLimit,
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+		"refusing body read past limit": {
+			path:       reservationsPath,
+			body:       overLimit,
+			undeclared: true,
+			wantCode:   http.StatusRequestEntityTooLarge,
+		},
+		"refusing body read past limit by idempotency": {
+			path:           rentPath,
+			body:           overLimit,
+			undeclared:     true,
+			idempotencyKey: uuid.NewString(),
+			wantCode:       http.StatusRequestEntityTooLarge,
+		},
+		"reading body within limit": {
+			path:     reservationsPath,
+			body:     `{"scooter":1}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, _ := beforeTest(t)
+
+			s.config.MaxBodyBytes = 16
+
+			request := buildClientRequest(t, tt.path, http.MethodPost, bytes.NewBufferString(tt.body), clientUUID)
+			request.Header.Set(headerIdempotencyKey, tt.idempotencyKey)
+
+			if tt.undeclared {
+				request.ContentLength = -1
+			}
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			if tt.wantCode == http.StatusRequestEntityTooLarge {
+				var problem model.Problem
+
+				require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &problem))
+				require.Equal(t, "request_entity_too_large", problem.Code)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		acceptEncoding string
+		wantEncoding   string
+		wantVary       string
+	}{
+		"compressing list for client accepting gzip": {
+			path:           adminPath + webhooksPath,
+			acceptEncoding: "deflate, gzip;q=0.8",
+			wantEncoding:   encodingGzip,
+			wantVary:       headerAcceptEncoding,
+		},
+		"not compressing list for client refusing gzip": {
+			path:           adminPath + webhooksPath,
+			acceptEncoding: "gzip;q=0, identity",
+			wantVary:       headerAcceptEncoding,
+		},
+		"not compressing list for client not accepting any encoding": {
+			path:     adminPath + webhooksPath,
+			wantVary: headerAcceptEncoding,
+		},
+		"not compressing single resource": {
+			path:           openAPIPath,
+			acceptEncoding: encodingGzip,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, mockWebhookService := beforeTest(t)
+
+			subscription := testWebhook()
+
+			mockWebhookService.EXPECT().GetSubscriptions(gomock.Any()).
+				Return([]*redismodel.WebhookSubscription{subscription}, nil).AnyTimes()
+
+			request := buildAdminRequest(t, tt.path, http.MethodGet, nil, testAdminToken(t))
+			request.Header.Set(headerAcceptEncoding, tt.acceptEncoding)
+
+			responseRecorder := httptest.NewRecorder()
+
+			s.router.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, http.StatusOK, responseRecorder.Code)
+			require.Equal(t, tt.wantEncoding, responseRecorder.Header().Get(headerContentEncoding))
+			require.Equal(t, tt.wantVary, responseRecorder.Header().Get(headerVary))
+
+			body := responseRecorder.Body.Bytes()
+
+			if tt.wantEncoding == encodingGzip {
+				reader, err := gzip.NewReader(responseRecorder.Body)
+				require.NoError(t, err)
+
+				body, err = io.ReadAll(reader)
+				require.NoError(t, err)
+			}
+
+			require.True(t, json.Valid(body))
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	const webApp = "https://app.scootinaboot.test"
+
+	tests := map[string]struct {
+		allowedOrigins []string
+		method         string
+		path           string
+		origin         string
+		requestMethod  string
+		wantCode       int
+		wantHeaders    map[string]string
+	}{
+		"answering preflight of allowed origin": {
+			allowedOrigins: []string{webApp},
+			method:         http.MethodOptions,
+			path:           rentPath,
+			origin:         webApp,
+			requestMethod:  http.MethodPost,
+			wantCode:       http.StatusNoContent,
+			wantHeaders: map[string]string{
+				headerCORSAllowOrigin:  webApp,
+				headerCORSAllowMethods: corsAllowedMethods,
+				headerCORSAllowHeaders: corsAllowedHeaders,
+				headerCORSMaxAge:       "600",
+				headerVary:             headerOrigin,
+			},
+		},
+		"answering preflight of any origin": {
+			allowedOrigins: []string{corsAnyOrigin},
+			method:         http.MethodOptions,
+			path:           rentPath,
+			origin:         webApp,
+			requestMethod:  http.MethodPost,
+			wantCode:       http.StatusNoContent,
+			wantHeaders:    map[string]string{headerCORSAllowOrigin: webApp},
+		},
+		"leaving preflight of foreign origin to router": {
+			allowedOrigins: []string{webApp},
+			method:         http.MethodOptions,
+			path:           rentPath,
+			origin:         "https://evil.example",
+			requestMethod:  http.MethodPost,
+			wantCode:       http.StatusMethodNotAllowed,
+			wantHeaders:    map[string]string{headerCORSAllowOrigin: "", headerVary: headerOrigin},
+		},
+		"exposing headers to allowed origin": {
+			allowedOrigins: []string{webApp},
+			method:         http.MethodGet,
+			path:           openAPIPath,
+			origin:         webApp,
+			wantCode:       http.StatusOK,
+			wantHeaders: map[string]string{
+				headerCORSAllowOrigin:   webApp,
+				headerCORSExposeHeaders: corsExposedHeaders,
+				headerCORSAllowMethods:  "",
+			},
+		},
+		"not exposing response to foreign origin": {
+			allowedOrigins: []string{webApp},
+			method:         http.MethodGet,
+			path:           openAPIPath,
+			origin:         "https://evil.example",
+			wantCode:       http.StatusOK,
+			wantHeaders:    map[string]string{headerCORSAllowOrigin: "", headerCORSExposeHeaders: ""},
+		},
+		"not varying response of request without origin": {
+			allowedOrigins: []string{webApp},
+			method:         http.MethodGet,
+			path:           openAPIPath,
+			wantCode:       http.StatusOK,
+			wantHeaders:    map[string]string{headerCORSAllowOrigin: "", headerVary: ""},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, _, _, _, _, _, _ := beforeTest(t)
+
+			s.config.CORSAllowedOrigins = tt.allowedOrigins
+			s.config.CORSMaxAgeSeconds = 600
+
+			request := buildRequest(t, tt.path, tt.method, bytes.NewBuffer(nil), false)
+			request.Header.Set(headerOrigin, tt.origin)
+			request.Header.Set(headerCORSRequestMethod, tt.requestMethod)
+
+			responseRecorder := httptest.NewRecorder()
+
+			// The preflight requests match no route, CORS wraps the router
+			s.httpServer.Handler.ServeHTTP(responseRecorder, request)
+
+			require.Equal(t, tt.wantCode, responseRecorder.Code)
+
+			for header, want := range tt.wantHeaders {
+				require.Equal(t, want, responseRecorder.Header().Get(header), header)
+			}
+		})
+	}
+}
@@ -0,0 +1,748 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+)
+
+const (
+	openAPIPath = "/openapi.json"
+
+	openAPIVersion = "3.0.3"
+	apiVersion     = "1.0.0"
+
+	tagRiders        = "riders"
+	tagTokens        = "tokens"
+	tagFleet         = "fleet"
+	tagWebhooks      = "webhooks"
+	tagSpecification = "specification"
+	tagMonitoring    = "monitoring"
+
+	securityAdminToken = "adminToken"
+	securityToken      = "token"
+	schemaRefPrefix    = "#/components/schemas/"
+)
+
+var pathVarPattern = regexp.MustCompile(`{([^}]+)}`)
+
+// apiOperation describes a registered route in the specification, every route of registerRoutes needs one.
+type apiOperation struct {
+	method      string
+	path        string // as registered in the router, without the version
+	id          string
+	summary     string
+	tag         string
+	permission  auth.Permission // the role of the token has to have, public when empty
+	idempotent  bool            // honours the Idempotency-Key header
+	unversioned bool            // registered at the root of the router, outside the version
+	bootstrap   bool            // takes the admin token of the instance, public otherwise
+
+	query         interface{}
+	requiredQuery []string
+	body          interface{}
+
+	status      int
+	result      interface{} // nil when the successful response has no body
+	contentType string      // of the successful response, JSON unless set
+	compressed  bool        // gzipped for the clients accepting it, the lists may grow long
+	errors      []int
+}
+
+var apiOperations = []apiOperation{
+	{
+		method:        http.MethodGet,
+		path:          scootersPath,
+		id:            "getScooters",
+		summary:       "Find scooters around the given location, scooters with low battery are left out",
+		tag:           tagRiders,
+		permission:    auth.PermissionSearchScooters,
+		query:         model.ScooterQueryParams{},
+		requiredQuery: []string{"longitude", "latitude", "radius", "city"},
+		status:        http.StatusOK,
+		result:        []model.ScooterGet{},
+		compressed:    true,
+		errors:        []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	},
+	{
+		method: http.MethodGet,
+		path:   scootersStreamPath,
+		id:     "streamScooters",
+		summary: "Follow scooters within the circle or the bounding box as server-sent events, the stream starts " +
+			"with the snapshot event listing the scooters and ends with the resync event when the client lags behind",
+		tag:         tagRiders,
+		permission:  auth.PermissionSearchScooters,
+		query:       model.ScooterStreamQueryParams{},
+		status:      http.StatusOK,
+		result:      model.ScooterEventGet{},
+		contentType: contentTypeEventStream,
+		errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodPost,
+		path:       rentPath,
+		id:         "rentScooter",
+		summary:    "Start a ride on the scooter",
+		tag:        tagRiders,
+		permission: auth.PermissionRide,
+		idempotent: true,
+		body:       model.ScooterPost{},
+		status:     http.StatusNoContent,
+		errors: []int{
+			http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       freePath,
+		id:         "freeScooter",
+		summary:    "Finish the ride on the scooter and get its fare",
+		tag:        tagRiders,
+		permission: auth.PermissionRide,
+		idempotent: true,
+		body:       uuid.UUID{},
+		status:     http.StatusOK,
+		result:     model.RentalGet{},
+		errors: []int{
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodGet,
+		path:       clientRentalsPath,
+		id:         "getClientRentals",
+		summary:    "Get the ride history of the client, newest ride first",
+		tag:        tagRiders,
+		permission: auth.PermissionRide,
+		query:      model.RentalsQueryParams{},
+		status:     http.StatusOK,
+		result:     model.RentalsGet{},
+		compressed: true,
+		errors: []int{
+			http.StatusBadRequest, http.StatusForbidden, http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method: http.MethodGet,
+		path:   rentalLivePath,
+		id:     "streamRide",
+		summary: "Follow the client's ride as server-sent events, a progress event comes with every move of the " +
+			"scooter and the ended event with the charged fare closes the stream",
+		tag:         tagRiders,
+		permission:  auth.PermissionRide,
+		status:      http.StatusOK,
+		result:      model.RideProgressGet{},
+		contentType: contentTypeEventStream,
+		errors: []int{
+			http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodGet,
+		path:       scooterRentalsPath,
+		id:         "getScooterRentals",
+		summary:    "Get the ride history of the scooter, newest ride first",
+		tag:        tagFleet,
+		permission: auth.PermissionOperateScooters,
+		query:      model.RentalsQueryParams{},
+		status:     http.StatusOK,
+		result:     model.RentalsGet{},
+		compressed: true,
+		errors:     []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodPost,
+		path:       reservationsPath,
+		id:         "reserveScooter",
+		summary:    "Hold the scooter for the client while they walk to it",
+		tag:        tagRiders,
+		permission: auth.PermissionRide,
+		body:       model.ReservationPost{},
+		status:     http.StatusCreated,
+		result:     model.ReservationGet{},
+		errors: []int{
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodDelete,
+		path:       reservationPath,
+		id:         "cancelReservation",
+		summary:    "Cancel the client's reservation of the scooter",
+		tag:        tagRiders,
+		permission: auth.PermissionRide,
+		status:     http.StatusNoContent,
+		errors: []int{
+			http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodPost,
+		path:       adminPath + scootersPath,
+		id:         "createScooter",
+		summary:    "Add the scooter to the fleet",
+		tag:        tagFleet,
+		permission: auth.PermissionManageFleet,
+		body:       model.ScooterAdminPost{},
+		status:     http.StatusCreated,
+		result:     model.ScooterAdminGet{},
+		errors: []int{
+			http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodGet,
+		path:       adminPath + adminScooterPath,
+		id:         "getScooter",
+		summary:    "Get the scooter of the fleet",
+		tag:        tagFleet,
+		permission: auth.PermissionOperateScooters,
+		status:     http.StatusOK,
+		result:     model.ScooterAdminGet{},
+		errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodDelete,
+		path:       adminPath + adminScooterPath,
+		id:         "deleteScooter",
+		summary:    "Remove the scooter from the fleet",
+		tag:        tagFleet,
+		permission: auth.PermissionManageFleet,
+		status:     http.StatusNoContent,
+		errors: []int{
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodPut,
+		path:       adminPath + adminScooterPath + locationPath,
+		id:         "moveScooter",
+		summary:    "Move the scooter, e.g. after it was taken to another city",
+		tag:        tagFleet,
+		permission: auth.PermissionOperateScooters,
+		body:       model.ScooterLocationPut{},
+		status:     http.StatusNoContent,
+		errors: []int{
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodPut,
+		path:       adminPath + adminScooterPath + statusPath,
+		id:         "changeScooterStatus",
+		summary:    "Change the status of the scooter",
+		tag:        tagFleet,
+		permission: auth.PermissionOperateScooters,
+		body:       model.ScooterStatusPut{},
+		status:     http.StatusNoContent,
+		errors: []int{
+			http.StatusBadRequest, http.StatusNotFound, http.StatusConflict,
+			http.StatusUnprocessableEntity, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodPut,
+		path:       adminPath + adminScooterPath + batteryPath,
+		id:         "reportScooterBattery",
+		summary:    "Report the battery level read by the scooter's telemetry",
+		tag:        tagFleet,
+		permission: auth.PermissionOperateScooters,
+		body:       model.ScooterBatteryPut{},
+		status:     http.StatusNoContent,
+		errors: []int{
+			http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	},
+	{
+		method:     http.MethodGet,
+		path:       adminPath + rentalsPath,
+		id:         "getActiveRentals",
+		summary:    "Get the rides going on in the city, operators get the ones of their own city without the city",
+		tag:        tagFleet,
+		permission: auth.PermissionOperateScooters,
+		query:      model.ActiveRentalsQueryParams{},
+		status:     http.StatusOK,
+		result:     model.ActiveRentalsGet{},
+		compressed: true,
+		errors:     []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodPost,
+		path:       adminPath + webhooksPath,
+		id:         "createWebhook",
+		summary:    "Subscribe the URL to the events of given types, deliveries are signed with the secret",
+		tag:        tagWebhooks,
+		permission: auth.PermissionManageService,
+		body:       model.WebhookPost{},
+		status:     http.StatusCreated,
+		result:     model.WebhookGet{},
+		errors:     []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodGet,
+		path:       adminPath + webhooksPath,
+		id:         "getWebhooks",
+		summary:    "Get the webhooks, their secrets are left out",
+		tag:        tagWebhooks,
+		permission: auth.PermissionManageService,
+		status:     http.StatusOK,
+		result:     model.WebhooksGet{},
+		compressed: true,
+		errors:     []int{http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodGet,
+		path:       adminPath + deadLettersPath,
+		id:         "getWebhookDeadLetters",
+		summary:    "Get the deliveries that were given up on, latest first",
+		tag:        tagWebhooks,
+		permission: auth.PermissionManageService,
+		query:      model.WebhookDeadLettersQueryParams{},
+		status:     http.StatusOK,
+		result:     model.WebhookDeadLettersGet{},
+		compressed: true,
+		errors:     []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodDelete,
+		path:       adminPath + webhookPath,
+		id:         "deleteWebhook",
+		summary:    "Stop delivering events to the webhook",
+		tag:        tagWebhooks,
+		permission: auth.PermissionManageService,
+		status:     http.StatusNoContent,
+		errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+	{
+		method: http.MethodPost,
+		path:   tokensPath,
+		id:     "issueToken",
+		summary: "Issue the token of the client or of the role, a new client is made up unless one is given, " +
+			"answered with 404 unless the instance issues tokens for development, the admin token of the " +
+			"instance gets the tokens of admins only",
+		tag:       tagTokens,
+		bootstrap: true,
+		body:      model.TokenPost{},
+		status:    http.StatusCreated,
+		result:    model.TokenGet{},
+		errors: []int{
+			http.StatusBadRequest,
+			http.StatusForbidden,
+			http.StatusNotFound,
+			http.StatusUnprocessableEntity,
+		},
+	},
+	{
+		method:  http.MethodGet,
+		path:    openAPIPath,
+		id:      "getOpenAPI",
+		summary: "Get this specification",
+		tag:     tagSpecification,
+		status:  http.StatusOK,
+		result:  map[string]interface{}{},
+	},
+	{
+		method:      http.MethodGet,
+		path:        metricsPath,
+		id:          "getMetrics",
+		summary:     "Get the metrics of the instance in the Prometheus text format",
+		tag:         tagMonitoring,
+		unversioned: true,
+		status:      http.StatusOK,
+		result:      "",
+		contentType: contentTypeMetrics,
+	},
+	{
+		method:      http.MethodGet,
+		path:        healthPath,
+		id:          "getHealth",
+		summary:     "Tell the process is alive",
+		tag:         tagMonitoring,
+		unversioned: true,
+		status:      http.StatusOK,
+		result:      model.HealthGet{},
+	},
+	{
+		method: http.MethodGet,
+		path:   readyPath,
+		id:     "getReadiness",
+		summary: "Tell whether the instance may get traffic, it isn't ready when Redis doesn't answer in time or " +
+			"the instance shuts down, the checks come with the 503 as well",
+		tag:         tagMonitoring,
+		unversioned: true,
+		status:      http.StatusOK,
+		result:      model.ReadinessGet{},
+		errors:      []int{http.StatusServiceUnavailable},
+	},
+	{
+		method:     http.MethodGet,
+		path:       adminPath + adminStatusPath,
+		id:         "getStatus",
+		summary:    "Get the readiness checks, the Redis latency, the count of active rides and the build of the instance",
+		tag:        tagMonitoring,
+		permission: auth.PermissionManageService,
+		status:     http.StatusOK,
+		result:     model.StatusGet{},
+	},
+}
+
+// GetOpenAPI serves the OpenAPI specification of the API.
+func (s *Server) GetOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	JSON(w, http.StatusOK, s.openAPI())
+}
+
+// routeOperations maps the routes, by their method and path template, to their operations.
+var routeOperations = func() map[string]*apiOperation {
+	operations := make(map[string]*apiOperation, len(apiOperations))
+
+	for i := range apiOperations {
+		path := apiOperations[i].path
+		if !apiOperations[i].unversioned {
+			path = version + path
+		}
+
+		operations[apiOperations[i].method+" "+path] = &apiOperations[i]
+	}
+
+	return operations
+}()
+
+// routeOperation returns the operation the request was routed to, e.g. to look up its timeout.
+func routeOperation(r *http.Request) (*apiOperation, bool) {
+	route, err := mux.CurrentRoute(r).GetPathTemplate()
+	if err != nil {
+		return nil, false
+	}
+
+	operation, ok := routeOperations[r.Method+" "+route]
+
+	return operation, ok
+}
+
+// operationID returns the ID of the operation the request was routed to, e.g. to look up its rate limit.
+func operationID(r *http.Request) string {
+	if operation, ok := routeOperation(r); ok {
+		return operation.id
+	}
+
+	route, err := mux.CurrentRoute(r).GetPathTemplate()
+	if err != nil {
+		return r.Method + " " + r.URL.Path
+	}
+
+	return r.Method + " " + route
+}
+
+// streamed tells whether the operation answers with an event stream, the stream lasts as long as the client wants.
+func (o *apiOperation) streamed() bool {
+	return o.contentType == contentTypeEventStream
+}
+
+// openAPI generates the specification from apiOperations, schemas are reflected from the REST models.
+func (s *Server) openAPI() *model.OpenAPI {
+	generator := &schemaGenerator{
+		schemas: make(map[string]*model.OpenAPISchema),
+		bounds: map[string][2]float64{
+			"latitude":     {minLatitude, maxLatitude},
+			"longitude":    {minLongitude, maxLongitude},
+			"radius":       {s.config.MinSearchRadius, s.config.MaxSearchRadius},
+			"minLatitude":  {minLatitude, maxLatitude},
+			"maxLatitude":  {minLatitude, maxLatitude},
+			"minLongitude": {minLongitude, maxLongitude},
+			"maxLongitude": {minLongitude, maxLongitude},
+			"battery":      {minBattery, maxBattery},
+			"limit":        {1, maxRentalsLimit},
+		},
+	}
+
+	document := &model.OpenAPI{
+		OpenAPI: openAPIVersion,
+		Info: model.OpenAPIInfo{
+			Title:       "Scootin' Aboot",
+			Description: "Rent electric scooters and manage the fleet",
+			Version:     apiVersion,
+		},
+		Paths: make(map[string]model.OpenAPIPathItem),
+		Components: model.OpenAPIComponents{
+			SecuritySchemes: map[string]*model.OpenAPISecurityScheme{
+				securityAdminToken: {Type: "http", Scheme: "bearer"},
+				securityToken:      {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Tags: []model.OpenAPITag{
+			{Name: tagRiders, Description: "Finding, reserving and riding scooters, requires the token of a rider"},
+			{Name: tagFleet, Description: "Fleet management, operators are kept within the city of their token"},
+			{Name: tagWebhooks, Description: "Signed deliveries of the domain events, requires an admin"},
+			{Name: tagTokens, Description: "Tokens of the clients and roles, issued by the API in development only"},
+			{Name: tagSpecification},
+			{Name: tagMonitoring, Description: "Operational endpoints, served outside the version"},
+		},
+	}
+
+	for i := range apiOperations {
+		path := apiOperations[i].path
+		if !apiOperations[i].unversioned {
+			path = version + path
+		}
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(model.OpenAPIPathItem)
+		}
+
+		document.Paths[path][strings.ToLower(apiOperations[i].method)] = generator.operation(&apiOperations[i])
+	}
+
+	document.Components.Schemas = generator.schemas
+
+	return document
+}
+
+// schemaGenerator reflects Go types into schemas, structs are kept in the components and referenced.
+type schemaGenerator struct {
+	schemas map[string]*model.OpenAPISchema
+	bounds  map[string][2]float64 // minimum and maximum of numeric fields by their JSON name
+}
+
+func (g *schemaGenerator) operation(operation *apiOperation) *model.OpenAPIOperation {
+	result := &model.OpenAPIOperation{
+		OperationID: operation.id,
+		Summary:     operation.summary,
+		Tags:        []string{operation.tag},
+		Responses:   make(map[string]*model.OpenAPIResponse),
+	}
+
+	for _, match := range pathVarPattern.FindAllStringSubmatch(operation.path, -1) {
+		result.Parameters = append(result.Parameters, model.OpenAPIParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   g.schemaOf(reflect.TypeOf(uuid.UUID{})),
+		})
+	}
+
+	if operation.idempotent {
+		result.Parameters = append(result.Parameters, model.OpenAPIParameter{
+			Name:        headerIdempotencyKey,
+			In:          "header",
+			Description: "Up to " + strconv.Itoa(maxIdempotencyKeyLength) + " characters, retries reusing it are replayed",
+			Schema:      &model.OpenAPISchema{Type: "string"},
+		})
+	}
+
+	if operation.query != nil {
+		result.Parameters = append(result.Parameters, g.queryParameters(operation.query, operation.requiredQuery)...)
+	}
+
+	if operation.body != nil {
+		result.RequestBody = &model.OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]*model.OpenAPIMediaType{
+				contentTypeJSON: {Schema: g.schemaOf(reflect.TypeOf(operation.body))},
+			},
+		}
+	}
+
+	success := &model.OpenAPIResponse{Description: http.StatusText(operation.status)}
+
+	if operation.result != nil {
+		contentType := operation.contentType
+		if contentType == "" {
+			contentType = contentTypeJSON
+		}
+
+		success.Content = map[string]*model.OpenAPIMediaType{
+			contentType: {Schema: g.schemaOf(reflect.TypeOf(operation.result))},
+		}
+	}
+
+	result.Responses[strconv.Itoa(operation.status)] = success
+
+	errorStatuses := operation.errors
+
+	if operation.permission != "" {
+		roles := auth.RolesWith(operation.permission)
+		names := make([]string, 0, len(roles))
+
+		result.Security = []map[string][]string{{securityToken: {}}}
+
+		for _, role := range roles {
+			names = append(names, string(role))
+		}
+
+		result.Description = "Permitted to " + strings.Join(names, ", ")
+		errorStatuses = append([]int{http.StatusUnauthorized, http.StatusForbidden}, errorStatuses...)
+	}
+
+	// The admin token is optional, the route is public while the instance issues tokens for development
+	if operation.bootstrap {
+		result.Security = []map[string][]string{{}, {securityAdminToken: {}}}
+	}
+
+	// Bodies are limited to MAX_BODY_BYTES
+	if operation.body != nil {
+		errorStatuses = append(errorStatuses, http.StatusRequestEntityTooLarge)
+	}
+
+	// Every route of the version is rate limited, unless its limit is turned off
+	if !operation.unversioned {
+		errorStatuses = append(errorStatuses, http.StatusTooManyRequests)
+	}
+
+	// Requests running out of their time are given up, the event streams have no deadline
+	if !operation.streamed() && !slices.Contains(errorStatuses, http.StatusServiceUnavailable) {
+		errorStatuses = append(errorStatuses, http.StatusServiceUnavailable)
+	}
+
+	errorStatuses = append(errorStatuses, http.StatusInternalServerError)
+
+	for _, status := range errorStatuses {
+		result.Responses[strconv.Itoa(status)] = &model.OpenAPIResponse{
+			Description: http.StatusText(status),
+			Content: map[string]*model.OpenAPIMediaType{
+				contentTypeProblemJSON: {Schema: g.schemaOf(reflect.TypeOf(model.Problem{}))},
+				contentTypeJSON:        {Schema: g.schemaOf(reflect.TypeOf(model.LegacyError{}))},
+			},
+		}
+	}
+
+	return result
+}
+
+func (g *schemaGenerator) queryParameters(query interface{}, required []string) []model.OpenAPIParameter {
+	queryType := reflect.TypeOf(query)
+	parameters := make([]model.OpenAPIParameter, 0, queryType.NumField())
+
+	for i := 0; i < queryType.NumField(); i++ {
+		name, ok := jsonName(queryType.Field(i))
+		if !ok {
+			continue
+		}
+
+		parameter := model.OpenAPIParameter{
+			Name:   name,
+			In:     "query",
+			Schema: g.fieldSchema(name, queryType.Field(i).Type),
+		}
+
+		for _, requiredName := range required {
+			parameter.Required = parameter.Required || requiredName == name
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters
+}
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) *model.OpenAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(uuid.UUID{}):
+		return &model.OpenAPISchema{Type: "string", Format: "uuid"}
+	case reflect.TypeOf(time.Time{}):
+		return &model.OpenAPISchema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(json.RawMessage{}):
+		return &model.OpenAPISchema{Type: "object"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, ok := g.schemas[t.Name()]; !ok {
+			schema := &model.OpenAPISchema{Type: "object", Properties: make(map[string]*model.OpenAPISchema)}
+
+			// Registered before the fields, so types referring to themselves don't recurse forever
+			g.schemas[t.Name()] = schema
+
+			for i := 0; i < t.NumField(); i++ {
+				if name, ok := jsonName(t.Field(i)); ok {
+					schema.Properties[name] = g.fieldSchema(name, t.Field(i).Type)
+				}
+			}
+		}
+
+		return &model.OpenAPISchema{Ref: schemaRefPrefix + t.Name()}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &model.OpenAPISchema{Type: "string", Format: "byte"}
+		}
+
+		return &model.OpenAPISchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &model.OpenAPISchema{Type: "object"}
+	case reflect.Float32, reflect.Float64:
+		return &model.OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &model.OpenAPISchema{Type: "integer"}
+	case reflect.Bool:
+		return &model.OpenAPISchema{Type: "boolean"}
+	default:
+		return &model.OpenAPISchema{Type: "string"}
+	}
+}
+
+// fieldSchema adds the constraints the validation puts on the field with given JSON name.
+func (g *schemaGenerator) fieldSchema(name string, t reflect.Type) *model.OpenAPISchema {
+	schema := g.schemaOf(t)
+
+	if bounds, ok := g.bounds[name]; ok && (schema.Type == "number" || schema.Type == "integer") {
+		schema.Minimum, schema.Maximum = &bounds[0], &bounds[1]
+	}
+
+	switch {
+	case t == reflect.TypeOf(model.HealthStatus("")):
+		for _, status := range model.HealthStatuses {
+			schema.Enum = append(schema.Enum, string(status))
+		}
+	case name == "role" && schema.Type == "string":
+		for _, role := range auth.Roles {
+			schema.Enum = append(schema.Enum, string(role))
+		}
+	case name == "status" && schema.Type == "string":
+		for _, status := range redismodel.ScooterStatuses {
+			schema.Enum = append(schema.Enum, string(status))
+		}
+	}
+
+	return schema
+}
+
+// jsonName returns the name the field has in JSON, fields left out of JSON are reported as not ok.
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
@@ -0,0 +1,150 @@
+//go:build unit
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/model"
+)
+
+// TestOpenAPIMatchesRoutes fails when a route is registered without being described in the specification or the
+// specification describes a route that is not registered.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	s, _, _, _, _, _, _ := beforeTest(t)
+
+	registered := make([]string, 0)
+
+	err := s.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, innerErr := route.GetMethods()
+		if innerErr != nil {
+			// Path prefixes of subrouters don't handle requests themselves
+			return nil
+		}
+
+		path, innerErr := route.GetPathTemplate()
+		if innerErr != nil {
+			return innerErr
+		}
+
+		for _, method := range methods {
+			registered = append(registered, method+" "+path)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	specified := make([]string, 0)
+
+	for path, item := range s.openAPI().Paths {
+		for method := range item {
+			specified = append(specified, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	require.ElementsMatch(t, registered, specified)
+}
+
+func TestGetOpenAPI(t *testing.T) {
+	s, _, _, _, _, _, _ := beforeTest(t)
+
+	request := buildRequest(t, openAPIPath, http.MethodGet, &bytes.Buffer{}, false)
+	responseRecorder := httptest.NewRecorder()
+
+	s.router.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var document model.OpenAPI
+
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &document))
+	require.Equal(t, openAPIVersion, document.OpenAPI)
+
+	rent := document.Paths[version+rentPath][strings.ToLower(http.MethodPost)]
+	require.NotNil(t, rent)
+	require.Contains(t, rent.Responses, "409")
+	require.Equal(t, schemaRefPrefix+"Problem", rent.Responses["409"].Content[contentTypeProblemJSON].Schema.Ref)
+	require.Equal(t, []map[string][]string{{securityToken: {}}}, rent.Security)
+	require.Equal(t, "Permitted to rider", rent.Description)
+	require.Contains(t, rent.Responses, "401")
+	require.Contains(t, rent.Responses, "403")
+	require.Contains(t, rent.Responses, "429")
+	require.Contains(t, rent.Responses, "413", "bodies are limited")
+
+	// Every operation but the event streams may run out of time, only the ones taking a body may exceed its limit
+	health := document.Paths[healthPath][strings.ToLower(http.MethodGet)]
+	require.Contains(t, health.Responses, "503")
+	require.NotContains(t, health.Responses, "413")
+
+	// Routes permitted to admins take signed tokens only, the admin token of the instance bootstraps them
+	changeStatus := document.Paths[version+adminPath+adminScooterPath+statusPath][strings.ToLower(http.MethodPut)]
+	require.NotNil(t, changeStatus)
+	require.Equal(t, []map[string][]string{{securityToken: {}}}, changeStatus.Security)
+
+	issueToken := document.Paths[version+tokensPath][strings.ToLower(http.MethodPost)]
+	require.NotNil(t, issueToken)
+	require.Equal(t, []map[string][]string{{}, {securityAdminToken: {}}}, issueToken.Security)
+	require.Equal(t, "Permitted to operator, admin", changeStatus.Description)
+
+	// Probes are served outside the version, they aren't rate limited
+	require.NotContains(t, document.Paths[readyPath][strings.ToLower(http.MethodGet)].Responses, "429")
+
+	radius := document.Components.Schemas["ScooterQueryParams"]
+	require.Nil(t, radius, "query params are described as parameters, not as a schema")
+
+	for _, parameter := range document.Paths[version+scootersPath][strings.ToLower(http.MethodGet)].Parameters {
+		if parameter.Name == "radius" {
+			require.True(t, parameter.Required)
+			require.Equal(t, testMinSearchRadius, *parameter.Schema.Minimum)
+			require.Equal(t, testMaxSearchRadius, *parameter.Schema.Maximum)
+		}
+	}
+
+	// Every reference has to point to a schema of the components
+	for _, ref := range collectRefs(t, responseRecorder.Body.Bytes()) {
+		name, found := strings.CutPrefix(ref, schemaRefPrefix)
+		require.True(t, found, ref)
+		require.Contains(t, document.Components.Schemas, name)
+	}
+}
+
+func collectRefs(t *testing.T, body []byte) []string {
+	t.Helper()
+
+	var raw interface{}
+
+	require.NoError(t, json.Unmarshal(body, &raw))
+
+	refs := make([]string, 0)
+
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			for key, inner := range typed {
+				if ref, ok := inner.(string); ok && key == "$ref" {
+					refs = append(refs, ref)
+				}
+
+				walk(inner)
+			}
+		case []interface{}:
+			for _, inner := range typed {
+				walk(inner)
+			}
+		}
+	}
+
+	walk(raw)
+
+	return refs
+}
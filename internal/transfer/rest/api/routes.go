@@ -1,20 +1,118 @@
 package api
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"scootinAboot/internal/auth"
+)
 
 const (
 	version      = "/v1"
+	metricsPath  = "/metrics"
+	healthPath   = "/healthz"
+	readyPath    = "/readyz"
 	scootersPath = "/scooters"
 	rentPath     = "/rent"
 	freePath     = "/free"
+	tokensPath   = "/tokens"
+
+	rentalsPath        = "/rentals"
+	clientRentalsPath  = "/clients/{" + clientUUIDVar + "}" + rentalsPath
+	scooterRentalsPath = scootersPath + "/{" + scooterUUIDVar + "}" + rentalsPath
+
+	reservationsPath = "/reservations"
+	reservationPath  = reservationsPath + "/{" + scooterUUIDVar + "}"
+
+	adminPath        = "/admin"
+	adminScooterPath = "/scooters/{" + scooterUUIDVar + "}"
+	locationPath     = "/location"
+	statusPath       = "/status"
+	batteryPath      = "/battery"
+	adminStatusPath  = "/status"
+
+	webhooksPath    = "/webhooks"
+	webhookPath     = webhooksPath + "/{" + webhookIDVar + "}"
+	deadLettersPath = webhooksPath + "/dead-letters"
+
+	scooterUUIDVar = "scooterUUID"
+	clientUUIDVar  = "clientUUID"
+	rentalIDVar    = "rentalID"
+	webhookIDVar   = "webhookID"
 )
 
-// registerRoutes sets service routes.
+// registerRoutes sets service routes and the middlewares of the server.
 func (s *Server) registerRoutes() {
+	// CORS runs before the routing, the preflight requests match no route
+	s.httpServer.Handler = chain(s.router, s.cors)
+
+	// The rest run once the route is matched, in this order, so the timeout and the compression know the operation.
+	// Panics are recovered inside the tracing and the metrics, so the 500 they are answered with is recorded
+	s.router.Use(s.requestID, s.trace, s.instrument, s.recovery, s.timeout, s.limitBody, s.compress)
+
+	// Scraped by Prometheus from the default registry, main registers the collectors of the service there
+	s.router.Path(metricsPath).Methods(http.MethodGet).Handler(promhttp.Handler())
+	// Probed by the orchestrator, the instance is restarted when it isn't healthy and gets no traffic when not ready
+	s.router.Path(healthPath).Methods(http.MethodGet).HandlerFunc(s.GetHealth)
+	s.router.Path(readyPath).Methods(http.MethodGet).HandlerFunc(s.GetReadiness)
+
 	versionRoute := s.router.PathPrefix(version).Subrouter()
 
-	versionRoute.Path(scootersPath).Methods(http.MethodGet).HandlerFunc(s.GetScooters)
+	// Every route has to be described in apiOperations as well, the specification is generated from them. The
+	// routes of the version are rate limited by operation, see the limits in RATE_LIMITS_PATH
+	publicRoute := versionRoute.NewRoute().Subrouter()
+	publicRoute.Use(s.rateLimit)
+	publicRoute.Path(openAPIPath).Methods(http.MethodGet).HandlerFunc(s.GetOpenAPI)
+	publicRoute.Path(tokensPath).Methods(http.MethodPost).HandlerFunc(s.IssueToken)
+
+	// Every other route needs a token whose role permits the route, the handlers check the resources on top of that:
+	// riders act on behalf of the client the token was issued to and operators within the city of the token
+	authRoute := versionRoute.NewRoute().Subrouter()
+	authRoute.Use(s.authentication, s.rateLimit)
+
+	searchRoute := s.permitted(authRoute, auth.PermissionSearchScooters)
+	searchRoute.Path(scootersPath).Methods(http.MethodGet).HandlerFunc(s.GetScooters)
+	searchRoute.Path(scootersStreamPath).Methods(http.MethodGet).HandlerFunc(s.StreamScooters)
+
+	rideRoute := s.permitted(authRoute, auth.PermissionRide)
+	rideRoute.Path(rentPath).Methods(http.MethodPost).Handler(s.idempotency(http.HandlerFunc(s.RentScooter)))
+	rideRoute.Path(freePath).Methods(http.MethodPost).Handler(s.idempotency(http.HandlerFunc(s.FreeScooter)))
+	rideRoute.Path(clientRentalsPath).Methods(http.MethodGet).HandlerFunc(s.GetClientRentals)
+	rideRoute.Path(rentalLivePath).Methods(http.MethodGet).HandlerFunc(s.StreamRide)
+	rideRoute.Path(reservationsPath).Methods(http.MethodPost).HandlerFunc(s.ReserveScooter)
+	rideRoute.Path(reservationPath).Methods(http.MethodDelete).HandlerFunc(s.CancelReservation)
+
+	// Rides of a scooter belong to many clients, only the fleet may see them
+	operateRoute := s.permitted(authRoute, auth.PermissionOperateScooters)
+	operateRoute.Path(scooterRentalsPath).Methods(http.MethodGet).HandlerFunc(s.GetScooterRentals)
+
+	adminRoute := authRoute.PathPrefix(adminPath).Subrouter()
+
+	fleetOperateRoute := s.permitted(adminRoute, auth.PermissionOperateScooters)
+	fleetOperateRoute.Path(adminScooterPath).Methods(http.MethodGet).HandlerFunc(s.GetScooter)
+	fleetOperateRoute.Path(adminScooterPath + locationPath).Methods(http.MethodPut).HandlerFunc(s.MoveScooter)
+	fleetOperateRoute.Path(adminScooterPath + statusPath).Methods(http.MethodPut).HandlerFunc(s.ChangeScooterStatus)
+	fleetOperateRoute.Path(adminScooterPath + batteryPath).Methods(http.MethodPut).HandlerFunc(s.ReportScooterBattery)
+	fleetOperateRoute.Path(rentalsPath).Methods(http.MethodGet).HandlerFunc(s.GetActiveRentals)
+
+	fleetRoute := s.permitted(adminRoute, auth.PermissionManageFleet)
+	fleetRoute.Path(scootersPath).Methods(http.MethodPost).HandlerFunc(s.CreateScooter)
+	fleetRoute.Path(adminScooterPath).Methods(http.MethodDelete).HandlerFunc(s.DeleteScooter)
+
+	serviceRoute := s.permitted(adminRoute, auth.PermissionManageService)
+	serviceRoute.Path(adminStatusPath).Methods(http.MethodGet).HandlerFunc(s.GetStatus)
+	serviceRoute.Path(webhooksPath).Methods(http.MethodPost).HandlerFunc(s.CreateWebhook)
+	serviceRoute.Path(webhooksPath).Methods(http.MethodGet).HandlerFunc(s.GetWebhooks)
+	serviceRoute.Path(deadLettersPath).Methods(http.MethodGet).HandlerFunc(s.GetWebhookDeadLetters)
+	serviceRoute.Path(webhookPath).Methods(http.MethodDelete).HandlerFunc(s.DeleteWebhook)
+}
+
+// permitted returns the subrouter of the routes whose principals need the permission.
+func (s *Server) permitted(router *mux.Router, permission auth.Permission) *mux.Router {
+	route := router.NewRoute().Subrouter()
+	route.Use(s.authorize(permission))
 
-	versionRoute.Path(rentPath).Methods(http.MethodPost).HandlerFunc(s.RentScooter)
-	versionRoute.Path(freePath).Methods(http.MethodPost).HandlerFunc(s.FreeScooter)
+	return route
 }
@@ -3,43 +3,91 @@ package api
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/config"
+	"scootinAboot/internal/logging"
+	availability "scootinAboot/internal/module/availability/transfer"
+	idempotency "scootinAboot/internal/module/idempotency/transfer"
+	ratelimit "scootinAboot/internal/module/ratelimit/transfer"
 	redis "scootinAboot/internal/module/redis/transfer"
 	"scootinAboot/internal/module/rental/transfer"
+	tracker "scootinAboot/internal/module/tracker/transfer"
+	webhook "scootinAboot/internal/module/webhook/transfer"
 )
 
 type Server struct {
-	logger        *log.Logger
+	logger        *slog.Logger
+	config        *config.Config
+	signer        *auth.Signer
 	httpServer    *http.Server
 	router        *mux.Router
 	redisService  redis.RedisService
 	rentalService transfer.RentalService
+	trackService  tracker.TrackerService
+
+	idempotencyService  idempotency.IdempotencyService
+	availabilityService availability.AvailabilityService
+	webhookService      webhook.WebhookService
+	rateLimitService    ratelimit.RateLimitService
+
+	gzipWriters *sync.Pool
+
+	startedAt    time.Time
+	shuttingDown atomic.Bool // fails the readiness while the requests in flight are finished
+
+	streams     context.Context // the event streams end when it is done, Shutdown doesn't wait for them
+	stopStreams context.CancelFunc
 }
 
 func NewServer(
-	logger *log.Logger,
+	logger *slog.Logger,
+	cfg *config.Config,
+	signer *auth.Signer,
 	server *http.Server,
 	router *mux.Router,
 	redis redis.RedisService,
 	rental transfer.RentalService,
+	track tracker.TrackerService,
+	idempotencyService idempotency.IdempotencyService,
+	availabilityService availability.AvailabilityService,
+	webhookService webhook.WebhookService,
+	rateLimitService ratelimit.RateLimitService,
 ) *Server {
 
 	s := &Server{
 		logger:        logger,
+		config:        cfg,
+		signer:        signer,
 		httpServer:    server,
 		router:        router,
 		redisService:  redis,
 		rentalService: rental,
+		trackService:  track,
+
+		idempotencyService:  idempotencyService,
+		availabilityService: availabilityService,
+		webhookService:      webhookService,
+		rateLimitService:    rateLimitService,
+
+		gzipWriters: newGzipWriters(logger, cfg.GzipLevel),
+
+		startedAt: time.Now().UTC(),
 	}
 
+	s.streams, s.stopStreams = context.WithCancel(context.Background())
+	server.RegisterOnShutdown(s.stopStreams)
+
 	s.registerRoutes()
 
 	return s
@@ -55,7 +103,7 @@ func (s *Server) Run() {
 		signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
 
 		sig := <-signalChan
-		s.logger.Println(sig.String())
+		s.logger.Info("received signal", "signal", sig.String())
 
 		cancel()
 	}()
@@ -64,22 +112,65 @@ func (s *Server) Run() {
 
 	go func(running *sync.WaitGroup) {
 		defer running.Done()
-		s.logger.Printf("Starting HTTP server: address - %s\n", s.httpServer.Addr)
+		s.logger.Info("starting HTTP server", "address", s.httpServer.Addr)
 
 		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			cancel()
 
-			s.logger.Fatalf("can't close http server: %v\n", err)
+			s.logger.Error("can't close http server", logging.KeyError, err)
+			os.Exit(1)
 		}
 
-		s.logger.Println("Stopping HTTP server")
+		s.logger.Info("stopping HTTP server")
 	}(&waitGroup)
 
+	go s.sweepReservations(ctx)
+
 	<-ctx.Done()
 
-	if err := s.httpServer.Shutdown(context.Background()); err != nil {
-		s.logger.Fatalf("can't shutdown gracefully: %v\n", err)
+	s.drain()
+
+	timeout := time.Duration(s.config.ShutdownTimeoutSeconds) * time.Second
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), timeout)
+	defer cancelShutdown()
+
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		s.logger.Error("can't shutdown gracefully", "timeout", timeout.String(), logging.KeyError, err)
+		_ = s.httpServer.Close()
+		os.Exit(1)
 	}
 
 	waitGroup.Wait()
 }
+
+// sweepReservations releases the expired reservations every RESERVATION_SWEEP_SECONDS until the signal stops the
+// service, a failed sweep is logged and retried by the next one.
+func (s *Server) sweepReservations(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.config.ReservationSweepSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.rentalService.ReleaseExpiredReservations(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("sweeping expired reservations failed", logging.KeyError, err)
+			}
+		}
+	}
+}
+
+// drain fails the readiness, stops the tracker taking new rides and the webhook deliveries, then waits the grace
+// period for the orchestrator to notice it and send the traffic elsewhere.
+func (s *Server) drain() {
+	s.shuttingDown.Store(true)
+	s.trackService.Stop()
+	s.webhookService.Stop()
+
+	grace := time.Duration(s.config.ShutdownGraceSeconds) * time.Second
+	s.logger.Info("draining before shutdown", "grace", grace.String(), "active_rides", s.trackService.ActiveRides())
+
+	time.Sleep(grace)
+}
@@ -0,0 +1,393 @@
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/schema"
+
+	"scootinAboot/internal/auth"
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+	redismodel "scootinAboot/internal/module/redis/model"
+)
+
+const (
+	minLatitude  = -90.0
+	maxLatitude  = 90.0
+	minLongitude = -180.0
+	maxLongitude = 180.0
+
+	minBattery = 0.0
+	maxBattery = 100.0
+
+	reasonRequired = "is required"
+	reasonUnknown  = "is unknown"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+var errInvalidRequest = domain.Validation("invalid_request", "request has invalid fields")
+
+// validationError lists every field of the request that was rejected, so the client can fix them all at once.
+type validationError struct {
+	params []model.InvalidParam
+}
+
+func (ve *validationError) Error() string {
+	reasons := make([]string, 0, len(ve.params))
+
+	for _, param := range ve.params {
+		reasons = append(reasons, param.Name+" "+param.Reason)
+	}
+
+	return errInvalidRequest.Message + ": " + strings.Join(reasons, ", ")
+}
+
+func (ve *validationError) Unwrap() error {
+	return errInvalidRequest
+}
+
+// validator collects the failing fields of the request instead of stopping at the first one.
+type validator struct {
+	params []model.InvalidParam
+}
+
+func (v *validator) check(ok bool, name, reason string) {
+	if !ok {
+		v.params = append(v.params, model.InvalidParam{Name: name, Reason: reason})
+	}
+}
+
+func (v *validator) required(name, value string) {
+	v.check(value != "", name, reasonRequired)
+}
+
+func (v *validator) requiredUUID(name string, value uuid.UUID) {
+	v.check(value != uuid.Nil, name, reasonRequired)
+}
+
+func (v *validator) between(name string, value, min, max float64) {
+	v.check(value >= min && value <= max, name, fmt.Sprintf("has to be between %v and %v", min, max))
+}
+
+func (v *validator) requiredBetween(name string, value *float64, min, max float64) {
+	if value == nil {
+		v.check(false, name, reasonRequired)
+
+		return
+	}
+
+	v.between(name, *value, min, max)
+}
+
+func (v *validator) location(longitude, latitude float64) {
+	v.between("longitude", longitude, minLongitude, maxLongitude)
+	v.between("latitude", latitude, minLatitude, maxLatitude)
+}
+
+// status accepts an empty status, callers that need one check it with required first.
+func (v *validator) status(name, value string) {
+	v.check(value == "" || redismodel.ScooterStatus(value).IsValid(), name, "is not a known scooter status")
+}
+
+func (v *validator) err() error {
+	if len(v.params) == 0 {
+		return nil
+	}
+
+	return &validationError{params: v.params}
+}
+
+func (s *Server) validateScooterQuery(query *model.ScooterQueryParams) error {
+	v := &validator{}
+
+	v.requiredBetween("longitude", query.Longitude, minLongitude, maxLongitude)
+	v.requiredBetween("latitude", query.Latitude, minLatitude, maxLatitude)
+	v.between("radius", query.Radius, s.config.MinSearchRadius, s.config.MaxSearchRadius)
+	v.required("city", query.City)
+	v.status("status", query.Status)
+
+	return v.err()
+}
+
+func validateActiveRentalsQuery(query *model.ActiveRentalsQueryParams) error {
+	v := &validator{}
+
+	v.required("city", query.City)
+
+	return v.err()
+}
+
+// validateScooterStreamQuery accepts either a circle or a bounding box, never both.
+func (s *Server) validateScooterStreamQuery(query *model.ScooterStreamQueryParams) error {
+	v := &validator{}
+
+	v.required("city", query.City)
+
+	circle := query.Longitude != nil || query.Latitude != nil || query.Radius != nil
+	box := query.MinLongitude != nil || query.MinLatitude != nil || query.MaxLongitude != nil ||
+		query.MaxLatitude != nil
+
+	switch {
+	case circle && box:
+		v.check(false, "radius", "can't be combined with a bounding box")
+	case circle:
+		v.requiredBetween("longitude", query.Longitude, minLongitude, maxLongitude)
+		v.requiredBetween("latitude", query.Latitude, minLatitude, maxLatitude)
+		v.requiredBetween("radius", query.Radius, s.config.MinSearchRadius, s.config.MaxSearchRadius)
+	case box:
+		v.requiredBetween("minLongitude", query.MinLongitude, minLongitude, maxLongitude)
+		v.requiredBetween("minLatitude", query.MinLatitude, minLatitude, maxLatitude)
+		v.requiredBetween("maxLongitude", query.MaxLongitude, minLongitude, maxLongitude)
+		v.requiredBetween("maxLatitude", query.MaxLatitude, minLatitude, maxLatitude)
+
+		if query.MinLongitude != nil && query.MaxLongitude != nil {
+			v.check(*query.MinLongitude < *query.MaxLongitude, "maxLongitude", "has to be greater than minLongitude")
+		}
+
+		if query.MinLatitude != nil && query.MaxLatitude != nil {
+			v.check(*query.MinLatitude < *query.MaxLatitude, "maxLatitude", "has to be greater than minLatitude")
+		}
+	default:
+		v.check(false, "radius", "or a bounding box is required")
+	}
+
+	return v.err()
+}
+
+func validateScooterPost(scooter *model.ScooterPost) error {
+	v := &validator{}
+
+	v.requiredUUID("UUID", scooter.UUID)
+	v.location(scooter.Longitude, scooter.Latitude)
+	v.required("city", scooter.City)
+
+	return v.err()
+}
+
+func validateScooterAdminPost(scooter *model.ScooterAdminPost) error {
+	v := &validator{}
+
+	v.location(scooter.Longitude, scooter.Latitude)
+	v.required("city", scooter.City)
+	v.status("status", scooter.Status)
+
+	if scooter.Battery != nil {
+		v.between("battery", *scooter.Battery, minBattery, maxBattery)
+	}
+
+	return v.err()
+}
+
+func validateScooterLocationPut(location *model.ScooterLocationPut) error {
+	v := &validator{}
+
+	v.location(location.Longitude, location.Latitude)
+	v.required("city", location.City)
+
+	return v.err()
+}
+
+func validateScooterStatusPut(status *model.ScooterStatusPut) error {
+	v := &validator{}
+
+	v.required("status", status.Status)
+	v.status("status", status.Status)
+
+	return v.err()
+}
+
+func validateScooterBatteryPut(battery *model.ScooterBatteryPut) error {
+	v := &validator{}
+
+	v.between("battery", battery.Battery, minBattery, maxBattery)
+
+	return v.err()
+}
+
+func validateReservationPost(reservation *model.ReservationPost) error {
+	v := &validator{}
+
+	v.requiredUUID("scooterUUID", reservation.ScooterUUID)
+
+	return v.err()
+}
+
+func validateTokenPost(token *model.TokenPost) error {
+	v := &validator{}
+
+	if token.ClientUUID != nil {
+		v.check(*token.ClientUUID != uuid.Nil, "clientUUID", "can't be the nil UUID")
+	}
+
+	role := auth.Role(token.Role)
+
+	v.check(token.Role == "" || role.IsValid(), "role", "is not a known role")
+
+	if role == auth.RoleOperator {
+		v.required("city", token.City)
+	} else {
+		v.check(token.City == "", "city", "can be given for operators only")
+	}
+
+	return v.err()
+}
+
+func validateScooterUUID(scooterUUID uuid.UUID) error {
+	v := &validator{}
+
+	v.requiredUUID("scooterUUID", scooterUUID)
+
+	return v.err()
+}
+
+// decodeJSON decodes the request body into v rejecting fields v doesn't have. Unknown fields and values of wrong
+// type are reported as invalid params, a body that is not JSON at all is returned as a plain error.
+func decodeJSON(r *http.Request, v interface{}) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &validationError{params: []model.InvalidParam{
+			{Name: typeErr.Field, Reason: "has to be " + typeName(typeErr.Type)},
+		}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// The decoder stops at the first unknown field, the rest of them are found by comparing the keys
+		validator := &validator{}
+
+		for _, name := range unknownFields(body, reflect.TypeOf(v)) {
+			validator.check(false, name, reasonUnknown)
+		}
+
+		if validationErr := validator.err(); validationErr != nil {
+			return validationErr
+		}
+	}
+
+	return fmt.Errorf("decoding request body: %w", err)
+}
+
+// decodeQuery decodes the query params into v, every param that is unknown or can't be converted is reported.
+func decodeQuery(r *http.Request, v interface{}) error {
+	err := schema.NewDecoder().Decode(v, r.URL.Query())
+	if err == nil {
+		return nil
+	}
+
+	var multiErr schema.MultiError
+
+	if !errors.As(err, &multiErr) {
+		return fmt.Errorf("decoding query params: %w", err)
+	}
+
+	names := make([]string, 0, len(multiErr))
+
+	for name := range multiErr {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	validator := &validator{}
+
+	for _, name := range names {
+		var (
+			conversionErr schema.ConversionError
+			unknownKeyErr schema.UnknownKeyError
+		)
+
+		switch {
+		case errors.As(multiErr[name], &conversionErr):
+			validator.check(false, name, "has to be "+typeName(conversionErr.Type))
+		case errors.As(multiErr[name], &unknownKeyErr):
+			validator.check(false, name, reasonUnknown)
+		default:
+			validator.check(false, name, multiErr[name].Error())
+		}
+	}
+
+	return validator.err()
+}
+
+// unknownFields returns the sorted top level keys of the JSON object that the struct has no field for. Keys are
+// matched case-insensitively, the same way encoding/json does.
+func unknownFields(body []byte, structType reflect.Type) []string {
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil
+	}
+
+	known := make(map[string]bool, structType.NumField())
+
+	for i := 0; i < structType.NumField(); i++ {
+		if name, ok := jsonName(structType.Field(i)); ok {
+			known[strings.ToLower(name)] = true
+		}
+	}
+
+	unknown := make([]string, 0)
+
+	for name := range object {
+		if !known[strings.ToLower(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// typeName describes the expected type the way a JSON client understands it.
+func typeName(t reflect.Type) string {
+	// Types like uuid.UUID or time.Time are sent as strings
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return "a string"
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,119 @@
+//go:build unit
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"scootinAboot/internal/domain"
+	"scootinAboot/internal/model"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := map[string]struct {
+		body              string
+		wantInvalidParams []model.InvalidParam
+		wantErr           bool
+	}{
+		"successfully decoding body": {
+			body:              `{"UUID":"0dae4f8c-dbbf-4bac-90f2-b80f07255ba5","city":"Montreal","Longitude":70}`,
+			wantInvalidParams: nil,
+			wantErr:           false,
+		},
+		"failed decoding body because it has unknown fields": {
+			body: `{"city":"Montreal","speed":10,"color":"red"}`,
+			wantInvalidParams: []model.InvalidParam{
+				{Name: "color", Reason: reasonUnknown},
+				{Name: "speed", Reason: reasonUnknown},
+			},
+			wantErr: true,
+		},
+		"failed decoding body because field has wrong type": {
+			body:              `{"latitude":"north"}`,
+			wantInvalidParams: []model.InvalidParam{{Name: "latitude", Reason: "has to be a number"}},
+			wantErr:           true,
+		},
+		"failed decoding body because uuid is not a string": {
+			body:              `{"UUID":1}`,
+			wantInvalidParams: []model.InvalidParam{{Name: "UUID", Reason: "has to be a string"}},
+			wantErr:           true,
+		},
+		"failed decoding body because it is not JSON": {
+			body:              `scooter`,
+			wantInvalidParams: nil,
+			wantErr:           true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, version+rentPath, strings.NewReader(tt.body))
+
+			var scooter model.ScooterPost
+
+			err := decodeJSON(request, &scooter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var validationErr *validationError
+
+			if tt.wantInvalidParams == nil {
+				require.False(t, errors.As(err, &validationErr))
+				return
+			}
+
+			require.True(t, errors.As(err, &validationErr))
+			require.Equal(t, tt.wantInvalidParams, validationErr.params)
+		})
+	}
+}
+
+func TestValidateScooterAdminPost(t *testing.T) {
+	lowBattery := -1.0
+
+	tests := map[string]struct {
+		scooter           model.ScooterAdminPost
+		wantInvalidParams []model.InvalidParam
+	}{
+		"valid scooter": {
+			scooter:           model.ScooterAdminPost{Longitude: testLongitude, Latitude: testLatitude, City: testCity},
+			wantInvalidParams: nil,
+		},
+		"every failing field is listed": {
+			scooter: model.ScooterAdminPost{Longitude: 181, Latitude: -91, Status: "broken", Battery: &lowBattery},
+			wantInvalidParams: []model.InvalidParam{
+				{Name: "longitude", Reason: "has to be between -180 and 180"},
+				{Name: "latitude", Reason: "has to be between -90 and 90"},
+				{Name: "city", Reason: reasonRequired},
+				{Name: "status", Reason: "is not a known scooter status"},
+				{Name: "battery", Reason: "has to be between 0 and 100"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateScooterAdminPost(&tt.scooter)
+
+			if tt.wantInvalidParams == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *validationError
+
+			require.True(t, errors.As(err, &validationErr))
+			require.Equal(t, tt.wantInvalidParams, validationErr.params)
+
+			domainErr, ok := domain.As(err)
+			require.True(t, ok)
+			require.Equal(t, domain.KindValidation, domainErr.Kind)
+		})
+	}
+}
@@ -2,19 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"scootinAboot/internal/auth"
 	"scootinAboot/internal/config"
 	"scootinAboot/internal/customer"
+	"scootinAboot/internal/logging"
+	"scootinAboot/internal/metrics"
+	availability "scootinAboot/internal/module/availability/transfer"
+	events "scootinAboot/internal/module/events/transfer"
+	idempotency "scootinAboot/internal/module/idempotency/transfer"
+	pricing "scootinAboot/internal/module/pricing/transfer"
+	ratelimit "scootinAboot/internal/module/ratelimit/transfer"
+	redismodel "scootinAboot/internal/module/redis/model"
 	redisrepository "scootinAboot/internal/module/redis/repository"
 	redisservice "scootinAboot/internal/module/redis/transfer"
 	rental "scootinAboot/internal/module/rental/transfer"
 	tracker "scootinAboot/internal/module/tracker/transfer"
+	webhook "scootinAboot/internal/module/webhook/transfer"
+	zone "scootinAboot/internal/module/zone/transfer"
+	"scootinAboot/internal/tracing"
 	"scootinAboot/internal/transfer/rest/api"
 	"sync"
 	"time"
@@ -28,33 +43,160 @@ func main() {
 		log.Fatal(fmt.Errorf("config retrieval failed: %w", err))
 	}
 
-	logger := log.New(os.Stdout, "CUSTOM ", log.LstdFlags)
+	logger, err := logging.NewLogger(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatal(fmt.Errorf("creating logger failed: %w", err))
+	}
+
+	// Errors of the requests are logged by the API with the default logger
+	slog.SetDefault(logger)
+
+	logger.Info("starting Scootin Aboot")
+
+	tracerProvider, err := tracing.NewTracerProvider(
+		context.Background(),
+		cfg.TracingExporter,
+		cfg.TracingOTLPEndpoint,
+		cfg.Name,
+		os.Stdout,
+	)
+	if err != nil {
+		log.Fatal(fmt.Errorf("creating tracer provider failed: %w", err))
+	}
+
+	tracing.Register(tracerProvider)
 
-	logger.Println("Starting Scootin Aboot")
+	// The spans still buffered are sent before leaving
+	defer func() {
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			logger.Error("shutting down tracer provider failed", logging.KeyError, err)
+		}
+	}()
 
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     "redis:6379",
 		Password: "",
 		DB:       0,
 	})
+	redisClient.AddHook(redisrepository.NewTracingHook())
+
+	if err = metrics.Register(prometheus.DefaultRegisterer); err != nil {
+		log.Fatal(fmt.Errorf("registering metrics failed: %w", err))
+	}
+
+	redisRepository := redisservice.NewInstrumentedRedisRepository(
+		redisrepository.NewRedisRepository(logger, redisClient),
+	)
+	// Changes of scooters are published, so the availability streams follow them
+	redisService := redisservice.NewTracedRedisService(redisservice.NewPublishingRedisService(
+		logger,
+		redisservice.NewRedisService(logger, redisRepository),
+		redisRepository,
+	))
+
+	initializeRedis(redisService)
+
+	eventBus := events.NewRedisBus(logger, redisService, cfg.EventsStream, cfg.EventsStreamMaxLength)
+
+	zones, err := zone.LoadZones(cfg.ZonesDir)
+	if err != nil {
+		log.Fatal(fmt.Errorf("loading zones failed: %w", err))
+	}
+
+	zoneService := zone.NewZoneService(logger, zones)
+
+	trackerService := tracker.NewTracedTrackerService(tracker.NewTrackingService(
+		logger,
+		redisService,
+		zoneService,
+		eventBus,
+		cfg.BatteryDrainPerKm,
+		cfg.LowBatteryThreshold,
+	))
+
+	tariffs, err := pricing.LoadTariffs(cfg.TariffsPath)
+	if err != nil {
+		log.Fatal(fmt.Errorf("loading tariffs failed: %w", err))
+	}
+
+	pricingService := pricing.NewPricingService(logger, tariffs)
+
+	rentalService := rental.NewTracedRentalService(rental.NewInstrumentedRentalService(rental.NewRentalService(
+		logger,
+		redisService,
+		trackerService,
+		pricingService,
+		zoneService,
+		eventBus,
+		cfg.LowBatteryThreshold,
+		time.Duration(cfg.ReservationMinutes)*time.Minute,
+	)))
+
+	idempotencyService := idempotency.NewIdempotencyService(
+		logger,
+		redisService,
+		time.Duration(cfg.IdempotencyKeyHours)*time.Hour,
+		time.Duration(cfg.IdempotencyPendingSeconds)*time.Second,
+	)
+
+	availabilityService := availability.NewAvailabilityService(logger, redisService, cfg.StreamBufferSize)
+
+	webhookService := webhook.NewWebhookService(
+		logger,
+		redisService,
+		eventBus,
+		&http.Client{Timeout: time.Duration(cfg.WebhookTimeoutSeconds) * time.Second},
+		cfg.WebhookMaxAttempts,
+		time.Duration(cfg.WebhookBackoffMilliseconds)*time.Millisecond,
+	)
+
+	// Instances share the deliveries, each of them consumes the webhooks group under its own name
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = cfg.Name
+	}
 
-	initializeRedis(redisClient)
+	if err = webhookService.Start(context.Background(), consumer); err != nil {
+		log.Fatal(fmt.Errorf("starting webhooks failed: %w", err))
+	}
 
-	redisRepository := redisrepository.NewRedisRepository(logger, redisClient)
-	redisService := redisservice.NewRedisService(logger, redisRepository)
+	limits, err := ratelimit.LoadLimits(cfg.RateLimitsPath)
+	if err != nil {
+		log.Fatal(fmt.Errorf("loading rate limits failed: %w", err))
+	}
 
-	trackerService := tracker.NewTrackingService(logger, redisService)
+	rateLimitService := ratelimit.NewRateLimitService(logger, redisService, limits)
 
-	rentalService := rental.NewRentalService(logger, redisService, trackerService)
+	signer, err := auth.NewSigner([]byte(cfg.TokenKey), cfg.Name, time.Duration(cfg.TokenTTLMinutes)*time.Minute)
+	if err != nil {
+		log.Fatal(fmt.Errorf("creating token signer failed: %w", err))
+	}
 
 	router := mux.NewRouter()
 
+	// The handler is set by the API, the router wrapped in the middlewares running before the routing
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.HTTP),
-		Handler: router,
+		Addr:              fmt.Sprintf(":%d", cfg.HTTP),
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeoutSeconds) * time.Second,
+		ReadTimeout:       time.Duration(cfg.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeoutSeconds) * time.Second,
 	}
 
-	server := api.NewServer(logger, httpServer, router, redisService, rentalService)
+	server := api.NewServer(
+		logger,
+		cfg,
+		signer,
+		httpServer,
+		router,
+		redisService,
+		rentalService,
+		trackerService,
+		idempotencyService,
+		availabilityService,
+		webhookService,
+		rateLimitService,
+	)
 
 	go server.Run()
 
@@ -98,7 +240,7 @@ func main() {
 		},
 	}
 
-	clientsService := customer.NewClientService(logger)
+	clientsService := customer.NewClientService(logger, signer)
 
 	waitGroup := &sync.WaitGroup{}
 
@@ -113,76 +255,49 @@ func main() {
 	waitGroup.Wait()
 }
 
-func initializeRedis(redisClient *redis.Client) {
-	if _, err := redisClient.GeoAdd(context.Background(), "Ottawa", &redis.GeoLocation{
-		Name:      "0dae4f8c-dbbf-4bac-90f2-b80f07255ba5",
-		Longitude: 73.5673,
-		Latitude:  45.5017,
-	}).Result(); err != nil {
-		panic(err)
-	}
-
-	if err := redisClient.Set(context.Background(), "0dae4f8c-dbbf-4bac-90f2-b80f07255ba5", true, 0).Err(); err != nil {
-		panic(err)
-	}
-
-	if _, err := redisClient.GeoAdd(context.Background(), "Ottawa", &redis.GeoLocation{
-		Name:      "61637887-385e-47bd-ad8c-5ace4fbd2877",
-		Longitude: 73.5548,
-		Latitude:  45.5088,
-	}).Result(); err != nil {
-		panic(err)
-	}
-
-	if err := redisClient.Set(context.Background(), "61637887-385e-47bd-ad8c-5ace4fbd2877", true, 0).Err(); err != nil {
-		panic(err)
-	}
-
-	if _, err := redisClient.GeoAdd(context.Background(), "Ottawa", &redis.GeoLocation{
-		Name:      "4117b009-5e61-4b3a-aac5-c9d6a75483cb",
-		Longitude: 73.5637,
-		Latitude:  45.4724,
-	}).Result(); err != nil {
-		panic(err)
-	}
-
-	if err := redisClient.Set(context.Background(), "4117b009-5e61-4b3a-aac5-c9d6a75483cb", true, 0).Err(); err != nil {
-		panic(err)
-	}
-
-	if _, err := redisClient.GeoAdd(context.Background(), "Montreal", &redis.GeoLocation{
-		Name:      "bad9f260-e3f5-4375-a4b3-3f6e258eb21f",
-		Longitude: 65.5637,
-		Latitude:  30.5234,
-	}).Result(); err != nil {
-		panic(err)
-	}
-
-	if err := redisClient.Set(context.Background(), "bad9f260-e3f5-4375-a4b3-3f6e258eb21f", true, 0).Err(); err != nil {
-		panic(err)
-	}
-
-	if _, err := redisClient.GeoAdd(context.Background(), "Montreal", &redis.GeoLocation{
-		Name:      "32341255-c86a-4106-94e0-28dd9b3f88f2",
-		Longitude: 65.1207,
-		Latitude:  30.2827,
-	}).Result(); err != nil {
-		panic(err)
+func initializeRedis(redisService redisservice.RedisService) {
+	seedScooters := []struct {
+		city     string
+		location *redis.GeoLocation
+	}{
+		{city: "Ottawa", location: &redis.GeoLocation{
+			Name:      "0dae4f8c-dbbf-4bac-90f2-b80f07255ba5",
+			Longitude: 73.5673,
+			Latitude:  45.5017,
+		}},
+		{city: "Ottawa", location: &redis.GeoLocation{
+			Name:      "61637887-385e-47bd-ad8c-5ace4fbd2877",
+			Longitude: 73.5548,
+			Latitude:  45.5088,
+		}},
+		{city: "Ottawa", location: &redis.GeoLocation{
+			Name:      "4117b009-5e61-4b3a-aac5-c9d6a75483cb",
+			Longitude: 73.5637,
+			Latitude:  45.4724,
+		}},
+		{city: "Montreal", location: &redis.GeoLocation{
+			Name:      "bad9f260-e3f5-4375-a4b3-3f6e258eb21f",
+			Longitude: 65.5637,
+			Latitude:  30.5234,
+		}},
+		{city: "Montreal", location: &redis.GeoLocation{
+			Name:      "32341255-c86a-4106-94e0-28dd9b3f88f2",
+			Longitude: 65.1207,
+			Latitude:  30.2827,
+		}},
+		{city: "Montreal", location: &redis.GeoLocation{
+			Name:      "b55fcd8c-383c-4169-9e4a-1c1bf15fdb76",
+			Longitude: 65.5537,
+			Latitude:  30.5234,
+		}},
 	}
 
-	if err := redisClient.Set(context.Background(), "32341255-c86a-4106-94e0-28dd9b3f88f2", true, 0).Err(); err != nil {
-		panic(err)
-	}
-
-	if _, err := redisClient.GeoAdd(context.Background(), "Montreal", &redis.GeoLocation{
-		Name:      "b55fcd8c-383c-4169-9e4a-1c1bf15fdb76",
-		Longitude: 65.5537,
-		Latitude:  30.5234,
-	}).Result(); err != nil {
-		panic(err)
-	}
+	for _, seed := range seedScooters {
+		metadata := redismodel.NewScooterMetadata(seed.city, redismodel.StatusAvailable, redismodel.FullBattery)
 
-	if err := redisClient.Set(context.Background(), "b55fcd8c-383c-4169-9e4a-1c1bf15fdb76", true, 0).Err(); err != nil {
-		panic(err)
+		err := redisService.CreateScooter(context.Background(), seed.location, metadata)
+		if err != nil && !errors.Is(err, redisservice.ErrScooterAlreadyExists) {
+			panic(err)
+		}
 	}
 }
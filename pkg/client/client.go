@@ -0,0 +1,304 @@
+// Package client is the Go SDK of the scootin' aboot REST API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	version           = "/v1"
+	scootersPath      = "/scooters"
+	rentPath          = "/rent"
+	freePath          = "/free"
+	clientsPath       = "/clients/"
+	rentalsPath       = "/rentals"
+	reservationsPath  = "/reservations"
+	adminScootersPath = "/admin/scooters"
+	adminRentalsPath  = "/admin/rentals"
+	adminStatusPath   = "/admin/status"
+	streamPath        = "/stream"
+	livePath          = "/live"
+	locationPath      = "/location"
+	statusPath        = "/status"
+	batteryPath       = "/battery"
+	adminWebhooksPath = "/admin/webhooks"
+	deadLettersPath   = "/dead-letters"
+	openAPIPath       = "/openapi.json"
+	tokensPath        = "/tokens"
+
+	headerAccept         = "Accept"
+	headerAuthorization  = "Authorization"
+	headerContentType    = "Content-Type"
+	headerIdempotencyKey = "Idempotency-Key"
+	headerRequestID      = "X-Request-ID"
+	headerRetryAfter     = "Retry-After"
+
+	contentTypeJSON        = "application/json"
+	contentTypeProblemJSON = "application/problem+json"
+	contentTypeEventStream = "text/event-stream"
+
+	bearerPrefix = "Bearer "
+
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 100 * time.Millisecond
+	defaultMaxDelay    = 2 * time.Second
+)
+
+// Client calls the API on behalf of one rider or operator. Calls that are safe to repeat, reads and the calls sent
+// with an Idempotency-Key, are retried with exponential backoff when the server is unavailable.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	clientToken string
+	adminToken  string
+
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// Option configures the Client.
+type Option func(c *Client)
+
+// WithHTTPClient replaces http.DefaultClient, e.g. to set timeouts or a transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithClientToken authenticates the rider, it is required by the rider's endpoints. The token is issued by the
+// identity provider of the riders or, in development, by IssueToken.
+func WithClientToken(token string) Option {
+	return func(c *Client) {
+		c.clientToken = token
+	}
+}
+
+// WithAdminToken authenticates the operator or the admin, it is required by the fleet management endpoints. The token
+// has to be issued to the operator or the admin role, the admin token of the instance only bootstraps the latter.
+func WithAdminToken(token string) Option {
+	return func(c *Client) {
+		c.adminToken = token
+	}
+}
+
+// WithRetries sets how many times a call is attempted at most and the bounds of the delay between attempts,
+// one attempt turns the retries off.
+func WithRetries(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.baseDelay = baseDelay
+		c.maxDelay = maxDelay
+	}
+}
+
+// NewClient creates the client of the API running at baseURL, e.g. http://localhost:8081.
+func NewClient(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		httpClient:  http.DefaultClient,
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+		maxDelay:    defaultMaxDelay,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// request describes a single call of the API.
+type request struct {
+	method     string
+	path       string
+	query      url.Values
+	body       interface{}
+	idempotent bool // sent with an Idempotency-Key, so it can be retried even though it changes state
+	admin      bool
+}
+
+// do sends the request, retrying it when it is safe to, and decodes the successful response into result.
+func (c *Client) do(ctx context.Context, req *request, result interface{}) error {
+	var body []byte
+
+	if req.body != nil {
+		var err error
+
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("marshaling request body: %w", err)
+		}
+	}
+
+	// The key is kept across the attempts, so the server replays the response when the first attempt got through
+	idempotencyKey := ""
+	if req.idempotent {
+		idempotencyKey = uuid.NewString()
+	}
+
+	retryable := req.idempotent || req.method == http.MethodGet || req.method == http.MethodPut ||
+		req.method == http.MethodDelete
+
+	maxAttempts := c.maxAttempts
+	if !retryable || maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := c.wait(ctx, attempt, err); waitErr != nil {
+				return waitErr
+			}
+		}
+
+		err = c.send(ctx, req, body, idempotencyKey, result)
+		if !shouldRetry(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (c *Client) send(
+	ctx context.Context,
+	req *request,
+	body []byte,
+	idempotencyKey string,
+	result interface{},
+) error {
+	target := c.baseURL + version + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, req.method, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	httpRequest.Header.Set(headerAccept, contentTypeJSON+", "+contentTypeProblemJSON)
+
+	if body != nil {
+		httpRequest.Header.Set(headerContentType, contentTypeJSON)
+	}
+
+	switch {
+	case req.admin:
+		httpRequest.Header.Set(headerAuthorization, bearerPrefix+c.adminToken)
+	case c.clientToken != "":
+		httpRequest.Header.Set(headerAuthorization, bearerPrefix+c.clientToken)
+	}
+
+	if idempotencyKey != "" {
+		httpRequest.Header.Set(headerIdempotencyKey, idempotencyKey)
+	}
+
+	response, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return &transportError{err: err}
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return &transportError{err: fmt.Errorf("reading response body: %w", err)}
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return newError(response, responseBody)
+	}
+
+	if result == nil || len(responseBody) == 0 {
+		return nil
+	}
+
+	if err = json.Unmarshal(responseBody, result); err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+
+	return nil
+}
+
+// wait sleeps before the next attempt, the delay doubles with every attempt and is jittered, so clients retrying
+// at once don't hit the server together again. The server's Retry-After takes precedence.
+func (c *Client) wait(ctx context.Context, attempt int, lastErr error) error {
+	delay := c.baseDelay << (attempt - 1)
+	if delay > c.maxDelay || delay <= 0 {
+		delay = c.maxDelay
+	}
+
+	if delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	var apiErr *Error
+
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("waiting to retry: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// shouldRetry tells whether the failure may be gone on the next attempt.
+func shouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		// The caller gave up, retrying won't help
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode >= http.StatusInternalServerError ||
+		apiErr.StatusCode == http.StatusTooManyRequests ||
+		apiErr.Code == ErrRequestInProgress.Code
+}
+
+// transportError is a failure to reach the server or to read its response.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return "calling the API: " + e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
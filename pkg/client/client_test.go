@@ -0,0 +1,471 @@
+//go:build unit
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCity        = "Montreal"
+	testAdminToken  = "test_admin_token"
+	testClientToken = "test_client_token"
+)
+
+func TestGetScooters(t *testing.T) {
+	scooters := []Scooter{{UUID: uuid.New(), Longitude: 70, Latitude: 60, Status: "available", Battery: 100}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, version+scootersPath, r.URL.Path)
+		require.Equal(t, bearerPrefix+testClientToken, r.Header.Get(headerAuthorization))
+		require.Equal(t, "70", r.URL.Query().Get("longitude"))
+		require.Equal(t, "60.5", r.URL.Query().Get("latitude"))
+		require.Equal(t, "1000", r.URL.Query().Get("radius"))
+		require.Equal(t, testCity, r.URL.Query().Get("city"))
+		require.False(t, r.URL.Query().Has("status"))
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(scooters))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, WithClientToken(testClientToken))
+
+	longitude, latitude := 70.0, 60.5
+
+	got, err := c.GetScooters(context.Background(), ScootersQuery{
+		Longitude: &longitude,
+		Latitude:  &latitude,
+		Radius:    1000,
+		City:      testCity,
+	})
+	require.NoError(t, err)
+	require.Equal(t, scooters, got)
+}
+
+func TestIssueToken(t *testing.T) {
+	clientUUID := uuid.New()
+
+	token := Token{
+		Token:      testClientToken,
+		TokenType:  "Bearer",
+		ClientUUID: clientUUID,
+		ExpiresAt:  time.Date(2024, time.January, 1, 13, 0, 0, 0, time.UTC),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, version+tokensPath, r.URL.Path)
+		require.Empty(t, r.Header.Get(headerAuthorization))
+
+		var body TokenPost
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, &clientUUID, body.ClientUUID)
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		require.NoError(t, json.NewEncoder(w).Encode(token))
+	}))
+	defer server.Close()
+
+	got, err := NewClient(server.URL).IssueToken(context.Background(), &clientUUID)
+	require.NoError(t, err)
+	require.Equal(t, &token, got)
+}
+
+func TestGetActiveRentals(t *testing.T) {
+	rentals := []Rental{{ID: uuid.New(), ScooterUUID: uuid.New(), City: testCity}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, version+adminRentalsPath, r.URL.Path)
+		require.Equal(t, bearerPrefix+testAdminToken, r.Header.Get(headerAuthorization))
+		require.Equal(t, testCity, r.URL.Query().Get("city"))
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string][]Rental{"rentals": rentals}))
+	}))
+	defer server.Close()
+
+	got, err := NewClient(server.URL, WithAdminToken(testAdminToken)).GetActiveRentals(context.Background(), testCity)
+	require.NoError(t, err)
+	require.Equal(t, rentals, got)
+}
+
+func TestRetries(t *testing.T) {
+	scooterUUID := uuid.New()
+
+	unavailable := func(w http.ResponseWriter) {
+		w.Header().Set(headerContentType, contentTypeProblemJSON)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"type":"urn:scootinaboot:problem:service_unavailable","title":"Service Unavailable",` +
+			`"status":503,"code":"service_unavailable"}`))
+	}
+
+	tests := map[string]struct {
+		call         func(c *Client) error
+		failures     int32
+		wantAttempts int32
+		wantErr      error
+	}{
+		"renting scooter is retried with the same idempotency key": {
+			call: func(c *Client) error {
+				return c.RentScooter(context.Background(), ScooterPost{UUID: scooterUUID, City: testCity})
+			},
+			failures:     2,
+			wantAttempts: 3,
+			wantErr:      nil,
+		},
+		"getting scooter is retried": {
+			call: func(c *Client) error {
+				_, err := c.GetScooter(context.Background(), scooterUUID)
+
+				return err
+			},
+			failures:     1,
+			wantAttempts: 2,
+			wantErr:      nil,
+		},
+		"retries give up after the last attempt": {
+			call: func(c *Client) error {
+				_, err := c.FreeScooter(context.Background(), scooterUUID)
+
+				return err
+			},
+			failures:     10,
+			wantAttempts: 3,
+			wantErr:      ErrServiceUnavailable,
+		},
+		"reserving scooter is not retried, it has no idempotency key": {
+			call: func(c *Client) error {
+				_, err := c.ReserveScooter(context.Background(), scooterUUID)
+
+				return err
+			},
+			failures:     1,
+			wantAttempts: 1,
+			wantErr:      ErrServiceUnavailable,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+
+			idempotencyKeys := make(map[string]bool)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if key := r.Header.Get(headerIdempotencyKey); key != "" {
+					idempotencyKeys[key] = true
+				}
+
+				if atomic.AddInt32(&attempts, 1) <= tt.failures {
+					unavailable(w)
+
+					return
+				}
+
+				w.Header().Set(headerContentType, contentTypeJSON)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL, WithRetries(3, time.Millisecond, 5*time.Millisecond))
+
+			err := tt.call(c)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("call error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.wantAttempts, attempts)
+			require.LessOrEqual(t, len(idempotencyKeys), 1, "every attempt has to reuse the idempotency key")
+		})
+	}
+}
+
+func TestRetriesStopWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerRetryAfter, "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetScooters(ctx, ScootersQuery{City: testCity})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestErrors(t *testing.T) {
+	scooterUUID := uuid.New()
+
+	tests := map[string]struct {
+		status      int
+		contentType string
+		body        string
+		want        *Error
+		wantIs      error
+	}{
+		"problem details": {
+			status:      http.StatusConflict,
+			contentType: contentTypeProblemJSON,
+			body: `{"type":"urn:scootinaboot:problem:scooter_already_rented","title":"Conflict","status":409,` +
+				`"detail":"renting scooter: this scooter is already rented, choose another one","instance":"/v1/rent",` +
+				`"code":"scooter_already_rented","scooterUUID":"` + scooterUUID.String() + `","requestId":"req-1"}`,
+			want: &Error{
+				StatusCode:  http.StatusConflict,
+				Code:        "scooter_already_rented",
+				Detail:      "renting scooter: this scooter is already rented, choose another one",
+				ScooterUUID: scooterUUID.String(),
+				RequestID:   "req-1",
+			},
+			wantIs: ErrScooterAlreadyRented,
+		},
+		"problem details listing invalid fields": {
+			status:      http.StatusUnprocessableEntity,
+			contentType: contentTypeProblemJSON,
+			body: `{"type":"urn:scootinaboot:problem:invalid_request","title":"Unprocessable Entity","status":422,` +
+				`"detail":"validating scooter: request has invalid fields","code":"invalid_request",` +
+				`"invalidParams":[{"name":"city","reason":"is required"}]}`,
+			want: &Error{
+				StatusCode:    http.StatusUnprocessableEntity,
+				Code:          "invalid_request",
+				Detail:        "validating scooter: request has invalid fields",
+				InvalidParams: []InvalidParam{{Name: "city", Reason: "is required"}},
+			},
+			wantIs: ErrInvalidRequest,
+		},
+		"problem details of operator acting in another city": {
+			status:      http.StatusForbidden,
+			contentType: contentTypeProblemJSON,
+			body: `{"type":"urn:scootinaboot:problem:foreign_city","title":"Forbidden","status":403,` +
+				`"detail":"authorizing scooter: operators can act only within their own city","code":"foreign_city"}`,
+			want: &Error{
+				StatusCode: http.StatusForbidden,
+				Code:       "foreign_city",
+				Detail:     "authorizing scooter: operators can act only within their own city",
+			},
+			wantIs: ErrForeignCity,
+		},
+		"legacy body": {
+			status:      http.StatusNotFound,
+			contentType: contentTypeJSON,
+			body: `{"code":"scooter_not_found","Error":"scooter with given UUID was not found",` +
+				`"Message":"renting scooter"}`,
+			want: &Error{
+				StatusCode: http.StatusNotFound,
+				Code:       "scooter_not_found",
+				Detail:     "renting scooter: scooter with given UUID was not found",
+			},
+			wantIs: ErrScooterNotFound,
+		},
+		"body that is not JSON": {
+			status:      http.StatusUnauthorized,
+			contentType: "text/plain",
+			body:        "Unauthorized",
+			want: &Error{
+				StatusCode: http.StatusUnauthorized,
+				Code:       "unauthorized",
+			},
+			wantIs: ErrUnauthorized,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, bearerPrefix+testAdminToken, r.Header.Get(headerAuthorization))
+
+				w.Header().Set(headerContentType, tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL, WithAdminToken(testAdminToken))
+
+			_, err := c.GetScooter(context.Background(), scooterUUID)
+			require.ErrorIs(t, err, tt.wantIs)
+
+			var apiErr *Error
+
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tt.want, apiErr)
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	status := Status{
+		Status:      "ok",
+		Checks:      map[string]string{"redis": "ok"},
+		ActiveRides: 2,
+		StartedAt:   time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, version+adminStatusPath, r.URL.Path)
+		require.Equal(t, bearerPrefix+testAdminToken, r.Header.Get(headerAuthorization))
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(status))
+	}))
+	defer server.Close()
+
+	got, err := NewClient(server.URL, WithAdminToken(testAdminToken)).GetStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &status, got)
+}
+
+func TestStreamScooters(t *testing.T) {
+	added := ScooterEvent{Type: "added", UUID: uuid.New(), Longitude: 70, Latitude: 60, Status: "available"}
+	moved := ScooterEvent{Type: "moved", UUID: added.UUID, Longitude: 70.1, Latitude: 60, Status: "rented"}
+
+	tests := map[string]struct {
+		end     string
+		wantErr error
+	}{
+		"client lagging behind gets resync": {
+			end: "event: resync\ndata: {\"type\":\"urn:scootinaboot:problem:subscriber_lagged\",\"status\":409," +
+				"\"code\":\"subscriber_lagged\"}\n\n",
+			wantErr: ErrSubscriberLagged,
+		},
+		"server closing stream": {
+			wantErr: ErrStreamClosed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(t, version+scootersPath+streamPath, r.URL.Path)
+				require.Equal(t, bearerPrefix+testClientToken, r.Header.Get(headerAuthorization))
+				require.Equal(t, testCity, r.URL.Query().Get("city"))
+				require.Equal(t, "70", r.URL.Query().Get("longitude"))
+				require.Equal(t, "60", r.URL.Query().Get("latitude"))
+				require.Equal(t, "500", r.URL.Query().Get("radius"))
+				require.False(t, r.URL.Query().Has("minLongitude"))
+
+				w.Header().Set(headerContentType, contentTypeEventStream)
+				writeTestEvent(t, w, "snapshot", []ScooterEvent{added})
+				_, _ = w.Write([]byte(": heartbeat\n\n"))
+				writeTestEvent(t, w, moved.Type, moved)
+				_, _ = w.Write([]byte(": heartbeat\n\n" + tt.end))
+			}))
+			defer server.Close()
+
+			longitude, latitude, radius := 70.0, 60.0, 500.0
+
+			stream, err := NewClient(server.URL, WithClientToken(testClientToken)).StreamScooters(
+				context.Background(),
+				ScooterStreamQuery{City: testCity, Longitude: &longitude, Latitude: &latitude, Radius: &radius},
+			)
+			require.NoError(t, err)
+			require.Equal(t, []ScooterEvent{added}, stream.Snapshot)
+
+			var updates []ScooterEvent
+
+			for update := range stream.Updates {
+				updates = append(updates, update)
+			}
+
+			require.Equal(t, []ScooterEvent{moved}, updates)
+			require.ErrorIs(t, stream.Err, tt.wantErr)
+		})
+	}
+}
+
+func TestStreamScootersRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerContentType, contentTypeProblemJSON)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"status":422,"code":"invalid_request","invalidParams":[{"name":"radius"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).StreamScooters(context.Background(), ScooterStreamQuery{City: testCity})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestStreamRide(t *testing.T) {
+	rentalID := uuid.New()
+	at := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	progress := []RideProgress{
+		{ID: rentalID, ElapsedSeconds: 60, Distance: 100, Fare: 1.15, At: at},
+		{ID: rentalID, ElapsedSeconds: 120, Distance: 250, Fare: 1.3, At: at.Add(time.Minute)},
+		{ID: rentalID, ElapsedSeconds: 130, Distance: 260, Fare: 1.3, Ended: true, At: at.Add(70 * time.Second)},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, version+rentalsPath+"/"+rentalID.String()+livePath, r.URL.Path)
+		require.Equal(t, bearerPrefix+testClientToken, r.Header.Get(headerAuthorization))
+
+		w.Header().Set(headerContentType, contentTypeEventStream)
+		writeTestEvent(t, w, "progress", progress[0])
+		_, _ = w.Write([]byte(": heartbeat\n\n"))
+		writeTestEvent(t, w, "progress", progress[1])
+		writeTestEvent(t, w, "ended", progress[2])
+	}))
+	defer server.Close()
+
+	stream, err := NewClient(server.URL, WithClientToken(testClientToken)).StreamRide(context.Background(), rentalID)
+	require.NoError(t, err)
+
+	var got []RideProgress
+
+	for current := range stream.Updates {
+		got = append(got, current)
+	}
+
+	require.Equal(t, progress, got)
+	require.NoError(t, stream.Err)
+}
+
+func TestStreamRideStopsWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerContentType, contentTypeEventStream)
+		writeTestEvent(t, w, "progress", RideProgress{ID: uuid.New()})
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := NewClient(server.URL).StreamRide(ctx, uuid.New())
+	require.NoError(t, err)
+
+	<-stream.Updates
+	cancel()
+
+	for range stream.Updates {
+	}
+
+	require.NoError(t, stream.Err)
+}
+
+func writeTestEvent(t *testing.T, w http.ResponseWriter, name string, data interface{}) {
+	t.Helper()
+
+	encoded, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	_, _ = w.Write([]byte("event: " + name + "\ndata: " + string(encoded) + "\n\n"))
+}
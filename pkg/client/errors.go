@@ -0,0 +1,131 @@
+package client
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"scootinAboot/internal/model"
+)
+
+// Codes the server describes its errors with, compare them with errors.Is(err, client.ErrScooterNotFound).
+var (
+	ErrScooterNotFound       = &Error{Code: "scooter_not_found"}
+	ErrScooterAlreadyExists  = &Error{Code: "scooter_already_exists"}
+	ErrScooterInUse          = &Error{Code: "scooter_in_use"}
+	ErrScooterNotAvailable   = &Error{Code: "scooter_not_available"}
+	ErrScooterLowBattery     = &Error{Code: "scooter_low_battery"}
+	ErrScooterReserved       = &Error{Code: "scooter_reserved"}
+	ErrScooterCityMismatch   = &Error{Code: "scooter_city_mismatch"}
+	ErrScooterAlreadyRented  = &Error{Code: "scooter_already_rented"}
+	ErrScooterNotRented      = &Error{Code: "scooter_not_rented"}
+	ErrInvalidScooterStatus  = &Error{Code: "invalid_scooter_status"}
+	ErrInvalidBatteryLevel   = &Error{Code: "invalid_battery_level"}
+	ErrReservationNotFound   = &Error{Code: "reservation_not_found"}
+	ErrReservationNotOwned   = &Error{Code: "reservation_not_owned"}
+	ErrRentalNotFound        = &Error{Code: "rental_not_found"}
+	ErrRentalNotOwned        = &Error{Code: "rental_not_owned"}
+	ErrForeignRentals        = &Error{Code: "foreign_rentals"}
+	ErrInvalidCursor         = &Error{Code: "invalid_cursor"}
+	ErrOutsideOperatingArea  = &Error{Code: "outside_operating_area"}
+	ErrNoParkingZone         = &Error{Code: "no_parking_zone"}
+	ErrRequestInProgress     = &Error{Code: "request_in_progress"}
+	ErrIdempotencyKeyReused  = &Error{Code: "idempotency_key_reused"}
+	ErrIdempotencyKeyTooLong = &Error{Code: "idempotency_key_too_long"}
+	ErrMissingToken          = &Error{Code: "missing_token"}
+	ErrInvalidToken          = &Error{Code: "invalid_token"}
+	ErrExpiredToken          = &Error{Code: "expired_token"}
+	ErrRoleNotPermitted      = &Error{Code: "role_not_permitted"}
+	ErrForeignCity           = &Error{Code: "foreign_city"}
+	ErrTokenIssuingDisabled  = &Error{Code: "token_issuing_disabled"}
+	ErrInvalidRequest        = &Error{Code: "invalid_request"}
+	ErrServiceUnavailable    = &Error{Code: "service_unavailable"}
+	ErrRateLimited           = &Error{Code: "rate_limited"}
+	ErrRequestTimeout        = &Error{Code: "request_timeout"}
+	ErrBodyTooLarge          = &Error{Code: "request_entity_too_large"}
+	ErrUnauthorized          = &Error{Code: "unauthorized"}
+	ErrWebhookNotFound       = &Error{Code: "webhook_subscription_not_found"}
+	ErrSubscriberLagged      = &Error{Code: "subscriber_lagged"}
+)
+
+// Error is the error response of the server, either problem details or the legacy body.
+type Error struct {
+	StatusCode    int
+	Code          string
+	Detail        string
+	ScooterUUID   string
+	RequestID     string
+	InvalidParams []InvalidParam
+	RetryAfter    time.Duration // how long the server asked to wait before trying again, if it did
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return e.Code
+	}
+
+	return e.Code + ": " + e.Detail
+}
+
+// Is matches errors by their code, so the Err variables can be used as targets of errors.Is.
+func (e *Error) Is(target error) bool {
+	targetErr, ok := target.(*Error)
+
+	return ok && targetErr.Code == e.Code
+}
+
+// newError reads the error response, a body that can't be decoded is described by the status only.
+func newError(response *http.Response, body []byte) *Error {
+	err := &Error{
+		StatusCode: response.StatusCode,
+		Code:       strings.ReplaceAll(strings.ToLower(http.StatusText(response.StatusCode)), " ", "_"),
+		RequestID:  response.Header.Get(headerRequestID),
+	}
+
+	if retryAfter, parseErr := strconv.Atoi(response.Header.Get(headerRetryAfter)); parseErr == nil {
+		err.RetryAfter = time.Duration(retryAfter) * time.Second
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get(headerContentType))
+
+	switch mediaType {
+	case contentTypeProblemJSON:
+		var problem model.Problem
+
+		if json.Unmarshal(body, &problem) == nil && problem.Code != "" {
+			err.describe(&problem)
+		}
+	case contentTypeJSON:
+		var legacy model.LegacyError
+
+		if json.Unmarshal(body, &legacy) == nil && legacy.Code != "" {
+			err.Code = legacy.Code
+			err.Detail = legacy.Message + ": " + legacy.Error
+			err.InvalidParams = legacy.InvalidParams
+		}
+	}
+
+	return err
+}
+
+// problemError reads the problem details sent within an event stream, they come without a response of their own.
+func problemError(problem *model.Problem) *Error {
+	err := &Error{StatusCode: problem.Status}
+	err.describe(problem)
+
+	return err
+}
+
+func (e *Error) describe(problem *model.Problem) {
+	e.Code = problem.Code
+	e.Detail = problem.Detail
+	e.ScooterUUID = problem.ScooterUUID
+	e.InvalidParams = problem.InvalidParams
+
+	if problem.RequestID != "" {
+		e.RequestID = problem.RequestID
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/model"
+)
+
+// The fleet management calls require the token of an operator or the admin, see WithAdminToken. Operators act only
+// within the city of their token, other cities are refused with ErrForeignCity.
+
+// CreateScooter adds the scooter to the fleet, the server picks the UUID when it is not set.
+func (c *Client) CreateScooter(ctx context.Context, scooter FleetScooterPost) (*FleetScooter, error) {
+	var created FleetScooter
+
+	req := &request{method: http.MethodPost, path: adminScootersPath, body: scooter, admin: true}
+
+	if err := c.do(ctx, req, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetScooter returns the scooter of the fleet with its status and battery.
+func (c *Client) GetScooter(ctx context.Context, scooterUUID uuid.UUID) (*FleetScooter, error) {
+	var scooter FleetScooter
+
+	req := &request{method: http.MethodGet, path: adminScooterPath(scooterUUID), admin: true}
+
+	if err := c.do(ctx, req, &scooter); err != nil {
+		return nil, err
+	}
+
+	return &scooter, nil
+}
+
+// DeleteScooter removes the scooter from the fleet.
+func (c *Client) DeleteScooter(ctx context.Context, scooterUUID uuid.UUID) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: adminScooterPath(scooterUUID), admin: true}, nil)
+}
+
+// MoveScooter changes the location of the scooter, e.g. after it was taken to another city.
+func (c *Client) MoveScooter(ctx context.Context, scooterUUID uuid.UUID, location ScooterLocation) error {
+	req := &request{
+		method: http.MethodPut,
+		path:   adminScooterPath(scooterUUID) + locationPath,
+		body:   location,
+		admin:  true,
+	}
+
+	return c.do(ctx, req, nil)
+}
+
+// ChangeScooterStatus sets the status of the scooter, rented and reserved statuses can't be set.
+func (c *Client) ChangeScooterStatus(ctx context.Context, scooterUUID uuid.UUID, status string) error {
+	req := &request{
+		method: http.MethodPut,
+		path:   adminScooterPath(scooterUUID) + statusPath,
+		body:   ScooterStatus{Status: status},
+		admin:  true,
+	}
+
+	return c.do(ctx, req, nil)
+}
+
+// ReportScooterBattery reports the battery level (in percents) read by the scooter's telemetry.
+func (c *Client) ReportScooterBattery(ctx context.Context, scooterUUID uuid.UUID, battery float64) error {
+	req := &request{
+		method: http.MethodPut,
+		path:   adminScooterPath(scooterUUID) + batteryPath,
+		body:   ScooterBattery{Battery: battery},
+		admin:  true,
+	}
+
+	return c.do(ctx, req, nil)
+}
+
+// GetScooterRentals returns a page of the scooter's ride history.
+func (c *Client) GetScooterRentals(
+	ctx context.Context,
+	scooterUUID uuid.UUID,
+	query RentalsQuery,
+) (*RentalsPage, error) {
+	var page RentalsPage
+
+	req := &request{
+		method: http.MethodGet,
+		path:   scootersPath + "/" + scooterUUID.String() + rentalsPath,
+		query:  rentalsValues(query),
+		admin:  true,
+	}
+
+	if err := c.do(ctx, req, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// GetActiveRentals returns the rides in progress within the city, operators may leave the city out to get their own.
+func (c *Client) GetActiveRentals(ctx context.Context, city string) ([]Rental, error) {
+	var rentals model.ActiveRentalsGet
+
+	values := url.Values{}
+
+	if city != "" {
+		values.Set("city", city)
+	}
+
+	req := &request{method: http.MethodGet, path: adminRentalsPath, query: values, admin: true}
+
+	if err := c.do(ctx, req, &rentals); err != nil {
+		return nil, err
+	}
+
+	return rentals.Rentals, nil
+}
+
+// GetStatus reports the instance: its dependencies, the rides in progress and the build it runs.
+func (c *Client) GetStatus(ctx context.Context) (*Status, error) {
+	var status Status
+
+	if err := c.do(ctx, &request{method: http.MethodGet, path: adminStatusPath, admin: true}, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+func adminScooterPath(scooterUUID uuid.UUID) string {
+	return adminScootersPath + "/" + scooterUUID.String()
+}
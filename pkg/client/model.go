@@ -0,0 +1,34 @@
+package client
+
+import (
+	"scootinAboot/internal/model"
+)
+
+// The SDK speaks the same models the server is built with, so the two can't drift apart.
+type (
+	Scooter            = model.ScooterGet
+	ScooterPost        = model.ScooterPost
+	ScootersQuery      = model.ScooterQueryParams
+	Rental             = model.RentalGet
+	RentalEvent        = model.RentalEventGet
+	RentalsPage        = model.RentalsGet
+	RentalsQuery       = model.RentalsQueryParams
+	Reservation        = model.ReservationGet
+	ReservationPost    = model.ReservationPost
+	FleetScooter       = model.ScooterAdminGet
+	FleetScooterPost   = model.ScooterAdminPost
+	ScooterLocation    = model.ScooterLocationPut
+	ScooterStatus      = model.ScooterStatusPut
+	ScooterBattery     = model.ScooterBatteryPut
+	Webhook            = model.WebhookGet
+	WebhookPost        = model.WebhookPost
+	WebhookDeadLetter  = model.WebhookDeadLetterGet
+	Token              = model.TokenGet
+	TokenPost          = model.TokenPost
+	InvalidParam       = model.InvalidParam
+	OpenAPI            = model.OpenAPI
+	ScooterEvent       = model.ScooterEventGet
+	ScooterStreamQuery = model.ScooterStreamQueryParams
+	RideProgress       = model.RideProgressGet
+	Status             = model.StatusGet
+)
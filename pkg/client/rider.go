@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// GetScooters finds scooters within the radius (in meters) around the location, scooters with low battery are left
+// out by the server.
+func (c *Client) GetScooters(ctx context.Context, query ScootersQuery) ([]Scooter, error) {
+	values := url.Values{}
+	values.Set("radius", formatFloat(query.Radius))
+	values.Set("city", query.City)
+
+	// The server requires the location, a query left without it is rejected rather than searching around 0,0
+	for name, value := range map[string]*float64{"longitude": query.Longitude, "latitude": query.Latitude} {
+		if value != nil {
+			values.Set(name, formatFloat(*value))
+		}
+	}
+
+	if query.Status != "" {
+		values.Set("status", query.Status)
+	}
+
+	var scooters []Scooter
+
+	if err := c.do(ctx, &request{method: http.MethodGet, path: scootersPath, query: values}, &scooters); err != nil {
+		return nil, err
+	}
+
+	return scooters, nil
+}
+
+// RentScooter starts the ride, it is sent with an Idempotency-Key, so retrying it never rents the scooter twice.
+func (c *Client) RentScooter(ctx context.Context, scooter ScooterPost) error {
+	return c.do(ctx, &request{method: http.MethodPost, path: rentPath, body: scooter, idempotent: true}, nil)
+}
+
+// FreeScooter finishes the ride and returns it with its fare, retrying it never frees the scooter twice.
+func (c *Client) FreeScooter(ctx context.Context, scooterUUID uuid.UUID) (*Rental, error) {
+	var rental Rental
+
+	req := &request{method: http.MethodPost, path: freePath, body: scooterUUID, idempotent: true}
+
+	if err := c.do(ctx, req, &rental); err != nil {
+		return nil, err
+	}
+
+	return &rental, nil
+}
+
+// GetClientRentals returns a page of the client's ride history, pass RentalsPage.NextCursor as the cursor of the
+// query to get the next one.
+func (c *Client) GetClientRentals(ctx context.Context, clientUUID uuid.UUID, query RentalsQuery) (*RentalsPage, error) {
+	var page RentalsPage
+
+	req := &request{
+		method: http.MethodGet,
+		path:   clientsPath + clientUUID.String() + rentalsPath,
+		query:  rentalsValues(query),
+	}
+
+	if err := c.do(ctx, req, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// ReserveScooter holds the scooter for the client while they walk to it.
+func (c *Client) ReserveScooter(ctx context.Context, scooterUUID uuid.UUID) (*Reservation, error) {
+	var reservation Reservation
+
+	req := &request{method: http.MethodPost, path: reservationsPath, body: ReservationPost{ScooterUUID: scooterUUID}}
+
+	if err := c.do(ctx, req, &reservation); err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// CancelReservation releases the scooter reserved by the client.
+func (c *Client) CancelReservation(ctx context.Context, scooterUUID uuid.UUID) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: reservationsPath + "/" + scooterUUID.String()}, nil)
+}
+
+// GetOpenAPI returns the OpenAPI specification of the API.
+func (c *Client) GetOpenAPI(ctx context.Context) (*OpenAPI, error) {
+	var document OpenAPI
+
+	if err := c.do(ctx, &request{method: http.MethodGet, path: openAPIPath}, &document); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+func rentalsValues(query RentalsQuery) url.Values {
+	values := url.Values{}
+
+	for name, value := range map[string]string{"from": query.From, "to": query.To, "cursor": query.Cursor} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	if query.Limit != 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	return values
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// IssueToken gets the token of the client from the server, a new client is made up when clientUUID is nil. Servers
+// issue tokens in development only, others answer with ErrTokenIssuingDisabled.
+func (c *Client) IssueToken(ctx context.Context, clientUUID *uuid.UUID) (*Token, error) {
+	var token Token
+
+	req := &request{method: http.MethodPost, path: tokensPath, body: TokenPost{ClientUUID: clientUUID}}
+
+	if err := c.do(ctx, req, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// IssueRoleToken gets the token of a new operator or admin from the server, operators need the city they work in.
+// It is served in development only, just like IssueToken.
+func (c *Client) IssueRoleToken(ctx context.Context, role, city string) (*Token, error) {
+	var token Token
+
+	req := &request{method: http.MethodPost, path: tokensPath, body: TokenPost{Role: role, City: city}}
+
+	if err := c.do(ctx, req, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,295 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/model"
+)
+
+const (
+	eventSnapshot = "snapshot"
+	eventResync   = "resync"
+)
+
+// ErrStreamClosed tells the server closed the stream before it was over, e.g. when it shut down. Connect again to
+// follow on.
+var ErrStreamClosed = errors.New("stream closed by the server")
+
+// ScooterStream follows the scooters of an area. Updates is closed when the stream ends, Err tells why then: it stays
+// nil when the context was done, it is ErrSubscriberLagged when the client couldn't keep up with the changes and
+// ErrStreamClosed when the server closed the stream. Start over with StreamScooters and a new snapshot either way.
+// Err may be read once Updates is closed only.
+type ScooterStream struct {
+	Snapshot []ScooterEvent
+	Updates  <-chan ScooterEvent
+	Err      error
+}
+
+// RideStream follows the ride. Updates delivers the progress of the ride and is closed after the progress that ended
+// it, Err tells why it was closed before: it stays nil when the context was done and it is ErrStreamClosed when the
+// server closed the stream. Err may be read once Updates is closed only.
+type RideStream struct {
+	Updates <-chan RideProgress
+	Err     error
+}
+
+// StreamScooters follows the scooters within the circle or the bounding box of the query until ctx is done, the
+// snapshot of the area is read before it returns. Streams are not retried and outlive the timeout of the HTTP client,
+// so leave it unset.
+func (c *Client) StreamScooters(ctx context.Context, query ScooterStreamQuery) (*ScooterStream, error) {
+	values := url.Values{}
+	values.Set("city", query.City)
+
+	for name, value := range map[string]*float64{
+		"longitude":    query.Longitude,
+		"latitude":     query.Latitude,
+		"radius":       query.Radius,
+		"minLongitude": query.MinLongitude,
+		"minLatitude":  query.MinLatitude,
+		"maxLongitude": query.MaxLongitude,
+		"maxLatitude":  query.MaxLatitude,
+	} {
+		if value != nil {
+			values.Set(name, formatFloat(*value))
+		}
+	}
+
+	body, err := c.openStream(ctx, scootersPath+streamPath, values)
+	if err != nil {
+		return nil, err
+	}
+
+	events := newEventReader(body)
+
+	stream := &ScooterStream{}
+
+	snapshot, err := events.next()
+
+	switch {
+	case err != nil:
+		err = &transportError{err: fmt.Errorf("reading snapshot: %w", err)}
+	case snapshot.name != eventSnapshot:
+		err = fmt.Errorf("reading snapshot: unexpected %q event", snapshot.name)
+	default:
+		err = snapshot.decode(&stream.Snapshot)
+	}
+
+	if err != nil {
+		_ = body.Close()
+
+		return nil, err
+	}
+
+	updates := make(chan ScooterEvent)
+	stream.Updates = updates
+
+	go func() {
+		defer close(updates)
+
+		stream.Err = readEvents(ctx, body, events, func(event *serverSentEvent) (bool, error) {
+			if event.name == eventResync {
+				var problem model.Problem
+
+				if err := event.decode(&problem); err != nil {
+					return true, err
+				}
+
+				return true, problemError(&problem)
+			}
+
+			var update ScooterEvent
+
+			if err := event.decode(&update); err != nil {
+				return true, err
+			}
+
+			select {
+			case <-ctx.Done():
+				return true, nil
+			case updates <- update:
+				return false, nil
+			}
+		})
+	}()
+
+	return stream, nil
+}
+
+// StreamRide follows the client's ride until it ends or ctx is done, it starts with the current progress of the ride.
+// Like StreamScooters it is not retried.
+func (c *Client) StreamRide(ctx context.Context, rentalID uuid.UUID) (*RideStream, error) {
+	body, err := c.openStream(ctx, rentalsPath+"/"+rentalID.String()+livePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan RideProgress)
+	stream := &RideStream{Updates: updates}
+
+	go func() {
+		defer close(updates)
+
+		stream.Err = readEvents(ctx, body, newEventReader(body), func(event *serverSentEvent) (bool, error) {
+			var progress RideProgress
+
+			if err := event.decode(&progress); err != nil {
+				return true, err
+			}
+
+			select {
+			case <-ctx.Done():
+				return true, nil
+			case updates <- progress:
+				return progress.Ended, nil
+			}
+		})
+	}()
+
+	return stream, nil
+}
+
+// openStream connects to the event stream, an error response is returned as the *Error of the other calls.
+func (c *Client) openStream(ctx context.Context, path string, query url.Values) (io.ReadCloser, error) {
+	target := c.baseURL + version + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, target, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	httpRequest.Header.Set(headerAccept, contentTypeEventStream+", "+contentTypeProblemJSON)
+
+	if c.clientToken != "" {
+		httpRequest.Header.Set(headerAuthorization, bearerPrefix+c.clientToken)
+	}
+
+	response, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		defer func() {
+			_ = response.Body.Close()
+		}()
+
+		responseBody, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, &transportError{err: fmt.Errorf("reading response body: %w", err)}
+		}
+
+		return nil, newError(response, responseBody)
+	}
+
+	return response.Body, nil
+}
+
+// readEvents hands the events to handle until it is done with the stream, the body is closed then. A stream broken
+// by the done context isn't an error.
+func readEvents(
+	ctx context.Context,
+	body io.Closer,
+	events *eventReader,
+	handle func(event *serverSentEvent) (bool, error),
+) error {
+	defer func() {
+		_ = body.Close()
+	}()
+
+	for {
+		event, err := events.next()
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, io.EOF):
+			return ErrStreamClosed
+		case err != nil:
+			return &transportError{err: fmt.Errorf("reading event stream: %w", err)}
+		}
+
+		if done, err := handle(event); done {
+			return err
+		}
+	}
+}
+
+// serverSentEvent is a single event of the stream, the server sends its data as a single line of JSON.
+type serverSentEvent struct {
+	name string
+	data []byte
+}
+
+func (e *serverSentEvent) decode(result interface{}) error {
+	if err := json.Unmarshal(e.data, result); err != nil {
+		return fmt.Errorf("decoding %s event: %w", e.name, err)
+	}
+
+	return nil
+}
+
+// eventReader reads the server-sent events of the stream, the comments, heartbeats among them, are skipped.
+type eventReader struct {
+	reader *bufio.Reader
+}
+
+func newEventReader(body io.Reader) *eventReader {
+	return &eventReader{reader: bufio.NewReader(body)}
+}
+
+// next reads up to the blank line ending the event, io.EOF tells the stream ended between events.
+func (r *eventReader) next() (*serverSentEvent, error) {
+	event := &serverSentEvent{}
+	started := false
+
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && started {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if started {
+				return event, nil
+			}
+		case strings.HasPrefix(line, ":"):
+			// A comment only keeps the connection open
+		default:
+			started = true
+
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+
+			switch field {
+			case "event":
+				event.name = value
+			case "data":
+				if event.data != nil {
+					event.data = append(event.data, '\n')
+				}
+
+				event.data = append(event.data, value...)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"scootinAboot/internal/model"
+)
+
+// The webhook calls require the token of an admin, see WithAdminToken.
+
+// CreateWebhook subscribes the URL to the events of given types, the deliveries are signed with the secret.
+func (c *Client) CreateWebhook(ctx context.Context, webhook WebhookPost) (*Webhook, error) {
+	var created Webhook
+
+	req := &request{method: http.MethodPost, path: adminWebhooksPath, body: webhook, admin: true}
+
+	if err := c.do(ctx, req, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetWebhooks returns the webhooks without their secrets.
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks model.WebhooksGet
+
+	if err := c.do(ctx, &request{method: http.MethodGet, path: adminWebhooksPath, admin: true}, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks.Webhooks, nil
+}
+
+// DeleteWebhook stops delivering events to the webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	req := &request{method: http.MethodDelete, path: adminWebhooksPath + "/" + webhookID.String(), admin: true}
+
+	return c.do(ctx, req, nil)
+}
+
+// GetWebhookDeadLetters returns up to limit deliveries that were given up on, latest first. Zero limit leaves
+// picking it to the server.
+func (c *Client) GetWebhookDeadLetters(ctx context.Context, limit int) ([]WebhookDeadLetter, error) {
+	var letters model.WebhookDeadLettersGet
+
+	values := url.Values{}
+
+	if limit != 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+
+	req := &request{method: http.MethodGet, path: adminWebhooksPath + deadLettersPath, query: values, admin: true}
+
+	if err := c.do(ctx, req, &letters); err != nil {
+		return nil, err
+	}
+
+	return letters.DeadLetters, nil
+}